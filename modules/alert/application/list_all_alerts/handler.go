@@ -30,19 +30,24 @@ func (h *ListAllAlertsHandler) Handle(ctx context.Context, limit int) (*ListAllA
 	}
 	items := make([]AlertItem, 0, len(alerts))
 	for _, a := range alerts {
-		items = append(items, AlertItem{
-			ID:          a.ID.String(),
-			WorkspaceID: a.WorkspaceID.String(),
-			PageID:      a.PageID.String(),
-			CheckID:     a.CheckID.String(),
-			Title:       a.Title,
-			Description: a.Description,
-			Type:        a.Type,
-			Read:        a.ReadAt != nil,
-			CreatedAt:   a.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			PageName:    a.PageName,
-			PageURL:     a.PageURL,
-		})
+		item := AlertItem{
+			ID:           a.ID.String(),
+			WorkspaceID:  a.WorkspaceID.String(),
+			PageID:       a.PageID.String(),
+			CheckID:      a.CheckID.String(),
+			Title:        a.Title,
+			Description:  a.Description,
+			Type:         a.Type,
+			Read:         a.ReadAt != nil,
+			CreatedAt:    a.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			PageName:     a.PageName,
+			PageURL:      a.PageURL,
+			IncidentSize: a.IncidentSize,
+		}
+		if a.IncidentID != nil {
+			item.IncidentID = a.IncidentID.String()
+		}
+		items = append(items, item)
 	}
 	return &ListAllAlertsResponse{Data: items, Total: total}, nil
 }
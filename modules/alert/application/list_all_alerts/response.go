@@ -1,17 +1,19 @@
 package listallalerts
 
 type AlertItem struct {
-	ID          string `json:"id"`
-	WorkspaceID string `json:"workspace_id"`
-	PageID      string `json:"page_id"`
-	CheckID     string `json:"check_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Type        string `json:"type"`
-	Read        bool   `json:"read"`
-	CreatedAt   string `json:"created_at"`
-	PageName    string `json:"page_name"`
-	PageURL     string `json:"page_url"`
+	ID           string `json:"id"`
+	WorkspaceID  string `json:"workspace_id"`
+	PageID       string `json:"page_id"`
+	CheckID      string `json:"check_id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Type         string `json:"type"`
+	Read         bool   `json:"read"`
+	CreatedAt    string `json:"created_at"`
+	PageName     string `json:"page_name"`
+	PageURL      string `json:"page_url"`
+	IncidentID   string `json:"incident_id,omitempty"`
+	IncidentSize int    `json:"incident_size,omitempty"` // pages that showed the same change
 }
 
 type ListAllAlertsResponse struct {
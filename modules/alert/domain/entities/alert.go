@@ -18,6 +18,7 @@ type Alert struct {
 	Description   string
 	ChangeSummary string // Specific change description from Vision AI
 	Metadata      Metadata
+	IncidentID    *uuid.UUID // set when grouped with alerts for the same change on other pages
 	ReadAt        *time.Time
 	CreatedAt     time.Time
 }
@@ -46,8 +47,9 @@ func (m *Metadata) Scan(value interface{}) error {
 
 type AlertWithPage struct {
 	Alert
-	PageName string
-	PageURL  string
+	PageName     string
+	PageURL      string
+	IncidentSize int // alerts in the alert's incident; 0 when not grouped
 }
 
 func NewAlert(workspaceID, pageID, checkID uuid.UUID, alertType, title, description string) *Alert {
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentWindow is how long after its last alert an incident keeps
+// collecting alerts for the same change, so pages of a site checked in the
+// same schedule run land in one incident.
+const IncidentWindow = time.Hour
+
+// Incident groups alerts for the same content change on several pages of one
+// site. Each recipient is notified of it once, by the first alert that
+// reaches them.
+type Incident struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	Site        string
+	DiffHash    string // hash of the content block diff shared by the alerts
+	OpenedAt    time.Time
+	LastSeenAt  time.Time // when its latest alert joined
+	AlertCount  int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IncidentJoinCutoff returns the oldest last alert an incident may have for
+// an alert raised at t to join it.
+func IncidentJoinCutoff(t time.Time) time.Time {
+	return t.Add(-IncidentWindow)
+}
+
+// IncidentSite returns the site a page belongs to: its lower-cased host
+// without a leading "www.". Returns "" when the URL has no host.
+func IncidentSite(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIncidentSite(t *testing.T) {
+	tests := map[string]string{
+		"https://www.Example.com/pricing": "example.com",
+		"https://docs.example.com/a?b=c":  "docs.example.com",
+		"http://example.com:8080/":        "example.com",
+		"not a url":                       "",
+	}
+	for in, want := range tests {
+		if got := IncidentSite(in); got != want {
+			t.Errorf("IncidentSite(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIncidentJoinCutoff(t *testing.T) {
+	// The same change seen either side of a clock hour joins one incident.
+	lastSeen := time.Date(2026, 10, 18, 10, 59, 0, 0, time.UTC)
+	if next := lastSeen.Add(2 * time.Minute); IncidentJoinCutoff(next).After(lastSeen) {
+		t.Errorf("alert at %s should join the incident last seen at %s", next, lastSeen)
+	}
+	if late := lastSeen.Add(IncidentWindow + time.Minute); !IncidentJoinCutoff(late).After(lastSeen) {
+		t.Errorf("alert at %s should open a new incident", late)
+	}
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+)
+
+type IncidentRepository interface {
+	// OpenOrJoin adds an alert raised at at to the incident for the same
+	// change on the same site whose last alert is within the incident window,
+	// opening one if needed. The bool reports whether the incident was opened
+	// by this call.
+	OpenOrJoin(ctx context.Context, workspaceID uuid.UUID, site, diffHash string, at time.Time) (*entities.Incident, bool, error)
+	// ClaimRecipients records recipients as notified of an incident and
+	// returns the ones no earlier alert of the incident notified.
+	ClaimRecipients(ctx context.Context, incidentID uuid.UUID, recipients []string) ([]string, error)
+}
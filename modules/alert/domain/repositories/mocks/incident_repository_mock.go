@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+)
+
+type MockIncidentRepository struct {
+	OpenOrJoinResult *entities.Incident
+	OpenOrJoinOpened bool
+	OpenOrJoinErr    error
+
+	OpenOrJoinCalls int
+
+	// Notified holds the recipients claimed so far per incident.
+	Notified           map[uuid.UUID]map[string]bool
+	ClaimRecipientsErr error
+}
+
+func (m *MockIncidentRepository) OpenOrJoin(_ context.Context, _ uuid.UUID, _, _ string, _ time.Time) (*entities.Incident, bool, error) {
+	m.OpenOrJoinCalls++
+	return m.OpenOrJoinResult, m.OpenOrJoinOpened, m.OpenOrJoinErr
+}
+
+func (m *MockIncidentRepository) ClaimRecipients(_ context.Context, incidentID uuid.UUID, recipients []string) ([]string, error) {
+	if m.ClaimRecipientsErr != nil {
+		return nil, m.ClaimRecipientsErr
+	}
+	if m.Notified == nil {
+		m.Notified = map[uuid.UUID]map[string]bool{}
+	}
+	if m.Notified[incidentID] == nil {
+		m.Notified[incidentID] = map[string]bool{}
+	}
+	var claimed []string
+	for _, r := range recipients {
+		if !m.Notified[incidentID][r] {
+			m.Notified[incidentID][r] = true
+			claimed = append(claimed, r)
+		}
+	}
+	return claimed, nil
+}
@@ -24,10 +24,10 @@ func (r *AlertPostgresRepository) Create(ctx context.Context, alert *entities.Al
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return err
 	}
-	q := `INSERT INTO alerts (id, workspace_id, page_id, check_id, type, title, description, change_summary, metadata, created_at)
-		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+	q := `INSERT INTO alerts (id, workspace_id, page_id, check_id, type, title, description, change_summary, metadata, incident_id, created_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 		WHERE EXISTS (SELECT 1 FROM checks WHERE id = $4)`
-	_, err := r.db.ExecContext(ctx, q, alert.ID, alert.WorkspaceID, alert.PageID, alert.CheckID, alert.Type, alert.Title, alert.Description, alert.ChangeSummary, alert.Metadata, alert.CreatedAt)
+	_, err := r.db.ExecContext(ctx, q, alert.ID, alert.WorkspaceID, alert.PageID, alert.CheckID, alert.Type, alert.Title, alert.Description, alert.ChangeSummary, alert.Metadata, alert.IncidentID, alert.CreatedAt)
 	return err
 }
 
@@ -37,8 +37,8 @@ func (r *AlertPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*e
 	}
 	var a entities.Alert
 	var readAt sql.NullTime
-	q := `SELECT id, workspace_id, page_id, check_id, type, title, description, COALESCE(change_summary, ''), metadata, read_at, created_at FROM alerts WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, q, id).Scan(&a.ID, &a.WorkspaceID, &a.PageID, &a.CheckID, &a.Type, &a.Title, &a.Description, &a.ChangeSummary, &a.Metadata, &readAt, &a.CreatedAt)
+	q := `SELECT id, workspace_id, page_id, check_id, type, title, description, COALESCE(change_summary, ''), metadata, incident_id, read_at, created_at FROM alerts WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, q, id).Scan(&a.ID, &a.WorkspaceID, &a.PageID, &a.CheckID, &a.Type, &a.Title, &a.Description, &a.ChangeSummary, &a.Metadata, &a.IncidentID, &readAt, &a.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -55,7 +55,7 @@ func (r *AlertPostgresRepository) ListByWorkspace(ctx context.Context, workspace
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return nil, err
 	}
-	q := `SELECT id, workspace_id, page_id, check_id, type, title, description, COALESCE(change_summary, ''), metadata, read_at, created_at FROM alerts WHERE workspace_id = $1 ORDER BY created_at DESC`
+	q := `SELECT id, workspace_id, page_id, check_id, type, title, description, COALESCE(change_summary, ''), metadata, incident_id, read_at, created_at FROM alerts WHERE workspace_id = $1 ORDER BY created_at DESC`
 	rows, err := r.db.QueryContext(ctx, q, workspaceID)
 	if err != nil {
 		return nil, err
@@ -65,7 +65,7 @@ func (r *AlertPostgresRepository) ListByWorkspace(ctx context.Context, workspace
 	for rows.Next() {
 		var a entities.Alert
 		var readAt sql.NullTime
-		if err := rows.Scan(&a.ID, &a.WorkspaceID, &a.PageID, &a.CheckID, &a.Type, &a.Title, &a.Description, &a.ChangeSummary, &a.Metadata, &readAt, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.WorkspaceID, &a.PageID, &a.CheckID, &a.Type, &a.Title, &a.Description, &a.ChangeSummary, &a.Metadata, &a.IncidentID, &readAt, &a.CreatedAt); err != nil {
 			return nil, err
 		}
 		if readAt.Valid {
@@ -98,9 +98,10 @@ func (r *AlertPostgresRepository) ListAll(ctx context.Context, limit int) ([]*en
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return nil, err
 	}
-	q := `SELECT a.id, a.workspace_id, a.page_id, a.check_id, a.type, a.title, a.description, a.metadata, a.read_at, a.created_at,
-	       COALESCE(p.name, '') AS page_name, COALESCE(p.url, '') AS page_url
+	q := `SELECT a.id, a.workspace_id, a.page_id, a.check_id, a.type, a.title, a.description, a.metadata, a.incident_id, a.read_at, a.created_at,
+	       COALESCE(p.name, '') AS page_name, COALESCE(p.url, '') AS page_url, COALESCE(i.alert_count, 0)
 	       FROM alerts a LEFT JOIN pages p ON p.id = a.page_id
+	       LEFT JOIN alert_incidents i ON i.id = a.incident_id
 	       ORDER BY a.created_at DESC LIMIT $1`
 	rows, err := r.db.QueryContext(ctx, q, limit)
 	if err != nil {
@@ -111,7 +112,7 @@ func (r *AlertPostgresRepository) ListAll(ctx context.Context, limit int) ([]*en
 	for rows.Next() {
 		var a entities.AlertWithPage
 		var readAt sql.NullTime
-		if err := rows.Scan(&a.ID, &a.WorkspaceID, &a.PageID, &a.CheckID, &a.Type, &a.Title, &a.Description, &a.Metadata, &readAt, &a.CreatedAt, &a.PageName, &a.PageURL); err != nil {
+		if err := rows.Scan(&a.ID, &a.WorkspaceID, &a.PageID, &a.CheckID, &a.Type, &a.Title, &a.Description, &a.Metadata, &a.IncidentID, &readAt, &a.CreatedAt, &a.PageName, &a.PageURL, &a.IncidentSize); err != nil {
 			return nil, err
 		}
 		if readAt.Valid {
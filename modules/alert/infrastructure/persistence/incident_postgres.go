@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+	"github.com/lib/pq"
+)
+
+type IncidentPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewIncidentPostgresRepository(db *sql.DB, tenant string) *IncidentPostgresRepository {
+	return &IncidentPostgresRepository{db: db, tenant: tenant}
+}
+
+// OpenOrJoin adds an alert raised at at to the newest incident for the same
+// change on the same site whose last alert is within the incident window,
+// opening one if there is none. A transaction-scoped advisory lock on the
+// change keeps concurrent checks of one site from opening duplicates.
+func (r *IncidentPostgresRepository) OpenOrJoin(ctx context.Context, workspaceID uuid.UUID, site, diffHash string, at time.Time) (*entities.Incident, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, false, err
+	}
+	lockKey := workspaceID.String() + "|" + site + "|" + diffHash
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, lockKey); err != nil {
+		return nil, false, err
+	}
+
+	incident := entities.Incident{WorkspaceID: workspaceID, Site: site, DiffHash: diffHash}
+	err = tx.QueryRowContext(ctx, `UPDATE alert_incidents
+		SET alert_count = alert_count + 1, last_seen_at = GREATEST(last_seen_at, $5), updated_at = $5
+		WHERE id = (
+			SELECT id FROM alert_incidents
+			WHERE workspace_id = $1 AND site = $2 AND diff_hash = $3 AND last_seen_at >= $4
+			ORDER BY last_seen_at DESC
+			LIMIT 1
+		)
+		RETURNING id, opened_at, last_seen_at, alert_count, created_at, updated_at`,
+		workspaceID, site, diffHash, entities.IncidentJoinCutoff(at), at).Scan(
+		&incident.ID, &incident.OpenedAt, &incident.LastSeenAt, &incident.AlertCount, &incident.CreatedAt, &incident.UpdatedAt,
+	)
+	opened := false
+	if errors.Is(err, sql.ErrNoRows) {
+		incident.ID = uuid.New()
+		incident.OpenedAt, incident.LastSeenAt = at, at
+		incident.AlertCount = 1
+		incident.CreatedAt, incident.UpdatedAt = at, at
+		_, err = tx.ExecContext(ctx, `INSERT INTO alert_incidents (id, workspace_id, site, diff_hash, opened_at, last_seen_at, alert_count, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5, 1, $5, $5)`,
+			incident.ID, workspaceID, site, diffHash, at)
+		opened = true
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return &incident, opened, nil
+}
+
+// ClaimRecipients records recipients as notified of an incident and returns
+// the ones no earlier alert of the incident notified.
+func (r *IncidentPostgresRepository) ClaimRecipients(ctx context.Context, incidentID uuid.UUID, recipients []string) ([]string, error) {
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `INSERT INTO alert_incident_recipients (incident_id, recipient)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT DO NOTHING
+		RETURNING recipient`, incidentID, pq.Array(recipients))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []string
+	for rows.Next() {
+		var recipient string
+		if err := rows.Scan(&recipient); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, recipient)
+	}
+	return claimed, rows.Err()
+}
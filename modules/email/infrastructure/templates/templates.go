@@ -1,6 +1,10 @@
 package templates
 
-import "fmt"
+import (
+	"fmt"
+	htmlpkg "html"
+	"strings"
+)
 
 func wrap(title, body string) string {
 	return fmt.Sprintf(`<!DOCTYPE html>
@@ -82,3 +86,142 @@ func PasswordReset(firstName, resetURL string) (subject, html string) {
 `, firstName, resetURL))
 	return
 }
+
+// FieldChange is a single before/after row rendered in change tables.
+type FieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// SEOChangeNotification generates an alert email listing changed SEO fields
+// as a before/after table.
+func SEOChangeNotification(pageURL string, changes []FieldChange, dashboardURL string) (subject, html string) {
+	subject = "Pulzifi Alert: SEO metadata changed on your monitored page"
+
+	var rows strings.Builder
+	for _, c := range changes {
+		rows.WriteString(fmt.Sprintf(`<tr>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;"><strong>%s</strong></td>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;color:#B91C1C;">%s</td>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;color:#047857;">%s</td>
+</tr>
+`, htmlpkg.EscapeString(c.Field), displayValue(c.Before), displayValue(c.After)))
+	}
+
+	html = wrap(subject, fmt.Sprintf(`
+<h2>SEO Metadata Changed</h2>
+<p>The following SEO fields changed on the page you're monitoring:</p>
+<p><a href="%s">%s</a></p>
+<table style="width:100%%;border-collapse:collapse;font-size:14px;">
+<tr><th style="padding:8px;border:1px solid #eee;text-align:left;">Field</th><th style="padding:8px;border:1px solid #eee;text-align:left;">Before</th><th style="padding:8px;border:1px solid #eee;text-align:left;">After</th></tr>
+%s</table>
+<p><a href="%s" style="display:inline-block;background:#4F46E5;color:#fff;padding:12px 24px;border-radius:6px;text-decoration:none;">View Dashboard</a></p>
+`, pageURL, pageURL, rows.String(), dashboardURL))
+	return
+}
+
+// displayValue escapes a scraped value for inclusion in an email body and
+// marks empty values explicitly.
+func displayValue(v string) string {
+	if v == "" {
+		return `<em style="color:#999;">(empty)</em>`
+	}
+	return htmlpkg.EscapeString(v)
+}
+
+// BrokenLink is a single row in the broken links alert email.
+type BrokenLink struct {
+	URL        string
+	AnchorText string
+	Location   string
+	Status     string
+}
+
+// BrokenLinksNotification generates an alert email listing links that newly
+// stopped working on a monitored page.
+func BrokenLinksNotification(pageURL string, links []BrokenLink, dashboardURL string) (subject, html string) {
+	subject = fmt.Sprintf("Pulzifi Alert: %d broken link(s) found on your monitored page", len(links))
+
+	var rows strings.Builder
+	for _, l := range links {
+		rows.WriteString(fmt.Sprintf(`<tr>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;word-break:break-all;">%s</td>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;">%s</td>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;">%s</td>
+<td style="padding:8px;border:1px solid #eee;vertical-align:top;color:#B91C1C;">%s</td>
+</tr>
+`, htmlpkg.EscapeString(l.URL), displayValue(l.AnchorText), htmlpkg.EscapeString(l.Location), htmlpkg.EscapeString(l.Status)))
+	}
+
+	html = wrap(subject, fmt.Sprintf(`
+<h2>Broken Links Detected</h2>
+<p>These links stopped working on the page you're monitoring:</p>
+<p><a href="%s">%s</a></p>
+<table style="width:100%%;border-collapse:collapse;font-size:14px;">
+<tr><th style="padding:8px;border:1px solid #eee;text-align:left;">Link</th><th style="padding:8px;border:1px solid #eee;text-align:left;">Anchor text</th><th style="padding:8px;border:1px solid #eee;text-align:left;">Location</th><th style="padding:8px;border:1px solid #eee;text-align:left;">Status</th></tr>
+%s</table>
+<p><a href="%s" style="display:inline-block;background:#4F46E5;color:#fff;padding:12px 24px;border-radius:6px;text-decoration:none;">View Dashboard</a></p>
+`, pageURL, pageURL, rows.String(), dashboardURL))
+	return
+}
+
+// LoginFailedNotification tells subscribers that a page's login script stopped
+// working, so its checks can no longer see the authenticated content.
+func LoginFailedNotification(pageURL, reason, dashboardURL string) (subject, html string) {
+	subject = "Pulzifi Alert: login failing for your monitored page"
+	html = wrap(subject, fmt.Sprintf(`
+<h2>Login Failing</h2>
+<p>We could not log in to monitor this page:</p>
+<p><a href="%s">%s</a></p>
+<p style="color:#B91C1C;">%s</p>
+<p>Checks will keep failing until the credentials or login settings are updated.</p>
+<p><a href="%s" style="display:inline-block;background:#4F46E5;color:#fff;padding:12px 24px;border-radius:6px;text-decoration:none;">View Dashboard</a></p>
+`, pageURL, pageURL, htmlpkg.EscapeString(reason), dashboardURL))
+	return
+}
+
+// BrokenSelector is a selector that stopped matching, with the last
+// screenshot taken while it still worked.
+type BrokenSelector struct {
+	Name                  string // section name; empty for the page's element selector
+	Selector              string
+	LastGoodScreenshotURL string
+	ProposedSelector      string // replacement found by the section's fingerprint
+}
+
+// SelectorBrokenNotification tells subscribers that monitored selectors no
+// longer match anything, so changes in those areas are not being detected.
+func SelectorBrokenNotification(pageURL string, selectors []BrokenSelector, dashboardURL string) (subject, html string) {
+	subject = "Pulzifi Alert: a monitored selector stopped matching"
+
+	var items strings.Builder
+	for _, sel := range selectors {
+		name := "Monitored element"
+		if sel.Name != "" {
+			name = sel.Name
+		}
+		items.WriteString(fmt.Sprintf(`<li style="margin-bottom:16px;"><strong>%s</strong> <code>%s</code>`,
+			htmlpkg.EscapeString(name), htmlpkg.EscapeString(sel.Selector)))
+		if sel.LastGoodScreenshotURL != "" {
+			items.WriteString(fmt.Sprintf(`<br><span style="color:#666;">Last good capture:</span><br><img src="%s" alt="Last good capture" style="max-width:100%%;border:1px solid #eee;margin-top:4px;">`,
+				htmlpkg.EscapeString(sel.LastGoodScreenshotURL)))
+		}
+		if sel.ProposedSelector != "" {
+			items.WriteString(fmt.Sprintf(`<br><span style="color:#666;">The element was found again; suggested selector:</span> <code>%s</code>`,
+				htmlpkg.EscapeString(sel.ProposedSelector)))
+		}
+		items.WriteString("</li>\n")
+	}
+
+	html = wrap(subject, fmt.Sprintf(`
+<h2>Selector Stopped Matching</h2>
+<p>These selectors no longer match anything on the page you're monitoring:</p>
+<p><a href="%s">%s</a></p>
+<ul style="padding-left:20px;">
+%s</ul>
+<p>Changes in these areas are not detected until the selectors are updated.</p>
+<p><a href="%s" style="display:inline-block;background:#4F46E5;color:#fff;padding:12px 24px;border-radius:6px;text-decoration:none;">View Dashboard</a></p>
+`, pageURL, pageURL, items.String(), dashboardURL))
+	return
+}
@@ -20,6 +20,7 @@ export class ExtractPageHandler {
       selector: s.selector,
       selectorXpath: s.selectorXpath,
       selectorOffsets: s.selectorOffsets,
+      fingerprint: s.fingerprint,
     }));
 
     return this.browserService.extract({
@@ -27,6 +28,11 @@ export class ExtractPageHandler {
       blockAdsCookies: request.block_ads_cookies ?? false,
       selector: selectorConfig,
       sections,
+      steps: request.steps,
+      auth: request.auth,
+      profile: request.profile,
+      proxy: request.proxy,
+      archive: request.archive ?? false,
     });
   }
 }
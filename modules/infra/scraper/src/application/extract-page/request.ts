@@ -1,10 +1,16 @@
 import type { SelectorOffsets } from "../../domain/value-objects/selector-config";
+import type { CaptureStep } from "../../domain/value-objects/capture-step";
+import type { PageAuth } from "../../domain/value-objects/page-auth";
+import type { CaptureProfile } from "../../domain/value-objects/capture-profile";
+import type { ProxyConfig } from "../../domain/value-objects/proxy";
+import type { ElementFingerprint } from "../../domain/value-objects/element-fingerprint";
 
 export interface SectionRequest {
   id: string;
   selector?: string;
   selectorXpath?: string;
   selectorOffsets?: SelectorOffsets;
+  fingerprint?: ElementFingerprint;
 }
 
 export interface ExtractPageRequest {
@@ -14,4 +20,9 @@ export interface ExtractPageRequest {
   selector_xpath?: string;
   selector_offsets?: SelectorOffsets;
   sections?: SectionRequest[];
+  steps?: CaptureStep[];
+  auth?: PageAuth;
+  profile?: CaptureProfile;
+  proxy?: ProxyConfig;
+  archive?: boolean;
 }
@@ -4,6 +4,14 @@ export interface SectionResult {
   html: string;
   text: string;
   selector_matched: boolean;
+  /**
+   * Set when the selectors missed but the section's fingerprint matched; the
+   * capture is of that element and its selectors are proposed.
+   */
+  healed?: boolean;
+  proposed_selector?: string;
+  proposed_xpath?: string;
+  match_score?: number;
 }
 
 export interface ExtractionResult {
@@ -13,4 +21,6 @@ export interface ExtractionResult {
   screenshot_base64: string;
   selector_matched: boolean;
   sections?: SectionResult[];
+  /** MHTML archive of the page, when requested and recorded. */
+  mhtml?: string;
 }
@@ -7,6 +7,10 @@ export interface PreviewElement {
   rect: BoundingRect;
   text_preview: string;
   semantic_role: string;
+  /** Identifying attributes (id, class, role, aria-label, data-*). */
+  attributes: Record<string, string>;
+  /** Nearby headings and enclosing landmark regions, e.g. "h2:Pricing", "main". */
+  landmarks: string[];
 }
 
 export interface PreviewResult {
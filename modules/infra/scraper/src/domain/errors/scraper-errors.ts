@@ -36,3 +36,29 @@ export class TimeoutError extends ScraperError {
     this.name = "TimeoutError";
   }
 }
+
+export class CaptureStepError extends ScraperError {
+  constructor(
+    message: string,
+    public readonly step: number,
+    public readonly action: string,
+  ) {
+    super(message, "CAPTURE_STEP_FAILED", 422);
+    this.name = "CaptureStepError";
+  }
+}
+
+export class LoginError extends ScraperError {
+  constructor(message: string) {
+    super(message, "LOGIN_FAILED", 422);
+    this.name = "LoginError";
+  }
+}
+
+/** The outbound proxy could not be reached or refused the connection. */
+export class ProxyError extends ScraperError {
+  constructor(message: string) {
+    super(message, "PROXY_FAILED", 502);
+    this.name = "ProxyError";
+  }
+}
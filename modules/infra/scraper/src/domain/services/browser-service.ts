@@ -1,12 +1,26 @@
 import type { ExtractionResult } from "../entities/extraction-result";
 import type { PreviewResult } from "../entities/preview-result";
 import type { SelectorConfig, SectionConfig } from "../value-objects/selector-config";
+import type { CaptureStep } from "../value-objects/capture-step";
+import type { PageAuth } from "../value-objects/page-auth";
+import type { CaptureProfile } from "../value-objects/capture-profile";
+import type { ProxyConfig } from "../value-objects/proxy";
 
 export interface ExtractOptions {
   url: string;
   blockAdsCookies: boolean;
   selector?: SelectorConfig;
   sections?: SectionConfig[];
+  /** Browser actions run in order after navigation, before scrolling and capture. */
+  steps?: CaptureStep[];
+  /** Credentials applied to the browser context; a login script runs before navigation. */
+  auth?: PageAuth;
+  /** Device, locale and geo emulation; defaults to desktop when omitted. */
+  profile?: CaptureProfile;
+  /** Outbound proxy for every request of the capture; direct when omitted. */
+  proxy?: ProxyConfig;
+  /** Also record an MHTML archive of the rendered page. */
+  archive?: boolean;
 }
 
 export interface PreviewOptions {
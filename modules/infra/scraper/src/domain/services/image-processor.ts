@@ -1,6 +1,7 @@
 export interface IImageProcessor {
   pngToWebpBase64(pngBuffer: Buffer, quality?: number): Promise<string>;
   pngToBase64(pngBuffer: Buffer): string;
+  cropToWidth(pngBuffer: Buffer, width: number): Promise<string>;
   cropToWidthAndConvert(
     pngBuffer: Buffer,
     width: number,
@@ -0,0 +1,23 @@
+export interface Geolocation {
+  latitude: number;
+  longitude: number;
+  accuracy?: number;
+}
+
+/**
+ * Device, locale and geo emulation for one capture. Unset fields keep the
+ * scraper defaults (1440x900 desktop Chrome, en-US, server timezone).
+ */
+export interface CaptureProfile {
+  name: string;
+  viewport_width?: number;
+  viewport_height?: number;
+  device_scale_factor?: number;
+  mobile?: boolean;
+  user_agent?: string;
+  /** BCP 47 tag, e.g. "de-DE"; also sets Accept-Language. */
+  locale?: string;
+  /** IANA timezone, e.g. "Europe/Berlin". */
+  timezone?: string;
+  geolocation?: Geolocation;
+}
@@ -0,0 +1,20 @@
+export type CaptureStepAction =
+  | "click"
+  | "type"
+  | "select"
+  | "scroll_to"
+  | "wait_for_selector"
+  | "wait_for_network_idle"
+  | "wait"
+  | "press";
+
+/**
+ * A scripted browser action run after navigation and before capture.
+ * `wait_ms` is the duration for "wait" and a timeout override for the others.
+ */
+export interface CaptureStep {
+  action: CaptureStepAction;
+  selector?: string;
+  value?: string;
+  wait_ms?: number;
+}
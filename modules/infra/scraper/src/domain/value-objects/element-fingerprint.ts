@@ -0,0 +1,16 @@
+import type { BoundingRect } from "./viewport";
+
+/**
+ * Describes the element a section was drawn on, copied from the preview
+ * element it was created from. Used to find the element again when both of
+ * the section's selectors stop matching.
+ */
+export interface ElementFingerprint {
+  tag: string;
+  text?: string;
+  attributes?: Record<string, string>;
+  /** Document coordinates at the preview viewport. */
+  rect?: BoundingRect;
+  /** Nearby headings and enclosing landmark regions, e.g. "h2:Pricing", "main". */
+  landmarks?: string[];
+}
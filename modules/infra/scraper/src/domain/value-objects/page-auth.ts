@@ -0,0 +1,35 @@
+export interface AuthCookie {
+  name: string;
+  value: string;
+  /** Defaults to the target page's host. */
+  domain?: string;
+  path?: string;
+}
+
+export interface BasicAuth {
+  username: string;
+  password: string;
+}
+
+/** A form login performed in the same browser context before the page is opened. */
+export interface LoginScript {
+  url: string;
+  username_selector: string;
+  password_selector: string;
+  submit_selector: string;
+  /** When set, must become visible after submitting for the login to succeed. */
+  success_selector?: string;
+  username: string;
+  password: string;
+}
+
+/**
+ * Credentials a page is captured with. Values are secrets: they are applied
+ * to the browser context and must never be logged or echoed back.
+ */
+export interface PageAuth {
+  headers?: Record<string, string>;
+  cookies?: AuthCookie[];
+  basic?: BasicAuth;
+  login?: LoginScript;
+}
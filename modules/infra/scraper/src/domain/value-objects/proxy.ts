@@ -0,0 +1,10 @@
+/**
+ * Outbound proxy a capture is routed through. The server includes the scheme,
+ * e.g. "http://10.0.0.5:3128" or "socks5://10.0.0.6:1080". Credentials are
+ * secrets and must never be logged.
+ */
+export interface ProxyConfig {
+  server: string;
+  username?: string;
+  password?: string;
+}
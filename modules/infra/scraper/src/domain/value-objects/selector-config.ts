@@ -1,3 +1,5 @@
+import type { ElementFingerprint } from "./element-fingerprint";
+
 export interface SelectorOffsets {
   top: number;
   right: number;
@@ -16,4 +18,5 @@ export interface SectionConfig {
   selector?: string;
   selectorXpath?: string;
   selectorOffsets?: SelectorOffsets;
+  fingerprint?: ElementFingerprint;
 }
@@ -1,5 +1,8 @@
 import type { Browser, BrowserContext, Page } from "patchright";
-import { DEFAULT_VIEWPORT } from "../../domain/value-objects/viewport";
+import { DEFAULT_VIEWPORT, type Viewport } from "../../domain/value-objects/viewport";
+import type { PageAuth } from "../../domain/value-objects/page-auth";
+import type { CaptureProfile } from "../../domain/value-objects/capture-profile";
+import type { ProxyConfig } from "../../domain/value-objects/proxy";
 import { applyFingerprint } from "./stealth/fingerprint-manager";
 import { enableAdBlocking } from "./blocking/ad-blocker";
 import { removeCookieBanners } from "./blocking/cookie-blocker";
@@ -16,21 +19,57 @@ export interface ContextResult {
   page: Page;
 }
 
+export interface ContextOptions {
+  auth?: PageAuth;
+  /** Page the context is created for; auth is scoped to its origin. */
+  targetUrl?: string;
+  profile?: CaptureProfile;
+  proxy?: ProxyConfig;
+}
+
 /**
  * Creates a new browser context with stealth fingerprinting applied.
+ * When auth is given, basic credentials, cookies and custom headers are
+ * scoped to the target URL's origin so they never reach third parties.
+ * A capture profile sets the viewport, device, locale, timezone and geolocation.
+ * A proxy routes all of the context's traffic, including the login script.
  */
 export async function createStealthContext(
   browser: Browser,
+  options: ContextOptions = {},
 ): Promise<ContextResult> {
+  const { auth, targetUrl, profile, proxy } = options;
+  const origin = targetUrl ? new URL(targetUrl).origin : undefined;
   const context = await browser.newContext({
-    viewport: DEFAULT_VIEWPORT,
+    viewport: profileViewport(profile),
     ignoreHTTPSErrors: true,
     javaScriptEnabled: true,
     bypassCSP: true,
+    httpCredentials: auth?.basic
+      ? { username: auth.basic.username, password: auth.basic.password, origin }
+      : undefined,
+    deviceScaleFactor: profile?.device_scale_factor,
+    isMobile: profile?.mobile,
+    hasTouch: profile?.mobile,
+    userAgent: profile?.user_agent || undefined,
+    locale: profile?.locale || undefined,
+    timezoneId: profile?.timezone || undefined,
+    geolocation: profile?.geolocation,
+    permissions: profile?.geolocation ? ["geolocation"] : undefined,
+    proxy: proxy
+      ? { server: proxy.server, username: proxy.username, password: proxy.password }
+      : undefined,
   });
 
-  // Apply realistic fingerprint
-  await applyFingerprint(context);
+  // Apply realistic fingerprint. An explicit user agent is kept as-is since
+  // the injected fingerprint would replace it.
+  if (!profile?.user_agent) {
+    await applyFingerprint(context, { mobile: profile?.mobile, locale: profile?.locale });
+  }
+
+  if (auth && targetUrl) {
+    await applyAuth(context, auth, targetUrl);
+  }
 
   const page = await context.newPage();
   page.setDefaultTimeout(NAV_TIMEOUT_MS);
@@ -39,6 +78,53 @@ export async function createStealthContext(
   return { context, page };
 }
 
+/** Viewport for a capture profile, falling back to the default desktop size. */
+export function profileViewport(profile?: CaptureProfile): Viewport {
+  return {
+    width: profile?.viewport_width || DEFAULT_VIEWPORT.width,
+    height: profile?.viewport_height || DEFAULT_VIEWPORT.height,
+  };
+}
+
+async function applyAuth(
+  context: BrowserContext,
+  auth: PageAuth,
+  targetUrl: string,
+): Promise<void> {
+  const target = new URL(targetUrl);
+
+  if (auth.cookies && auth.cookies.length > 0) {
+    await context.addCookies(
+      auth.cookies.map((c) => ({
+        name: c.name,
+        value: c.value,
+        domain: c.domain || target.hostname,
+        path: c.path || "/",
+        secure: target.protocol === "https:",
+      })),
+    );
+  }
+
+  const headers = auth.headers;
+  if (headers && Object.keys(headers).length > 0) {
+    await context.route("**/*", (route) => {
+      const request = route.request();
+      if (new URL(request.url()).origin !== target.origin) {
+        return route.continue();
+      }
+      return route.continue({ headers: { ...request.headers(), ...headers } });
+    });
+  }
+
+  log("context", "page auth applied", {
+    url: targetUrl,
+    headers: Object.keys(headers ?? {}).length,
+    cookies: auth.cookies?.length ?? 0,
+    basic: !!auth.basic,
+    login: !!auth.login,
+  });
+}
+
 /**
  * Navigates to a URL with ad blocking, cookie banner removal,
  * and Cloudflare challenge handling.
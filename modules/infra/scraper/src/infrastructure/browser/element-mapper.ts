@@ -301,6 +301,66 @@ export async function mapSemanticElements(
       return TAG_ROLES[tag] || "generic";
     }
 
+    // ────────────── Fingerprint data ──────────────
+
+    const FINGERPRINT_ATTRS = [
+      "role",
+      "aria-label",
+      "data-testid",
+      "data-test-id",
+      "data-cy",
+      "data-section",
+      "data-component",
+      "data-block",
+      "data-id",
+    ];
+    const LANDMARK_TAGS = new Set(["header", "nav", "main", "aside", "footer", "section", "article", "form"]);
+
+    /** Identifying attributes used to recognise the element if its selector breaks. */
+    function getAttributes(el: Element): Record<string, string> {
+      const attrs: Record<string, string> = {};
+      if (el.id && !isAutoGeneratedId(el.id)) attrs.id = el.id;
+      const classes = getMeaningfulClasses(el);
+      if (classes.length > 0) attrs.class = classes.join(" ");
+      for (const name of FINGERPRINT_ATTRS) {
+        const val = el.getAttribute(name);
+        if (val) attrs[name] = val;
+      }
+      return attrs;
+    }
+
+    function headingLabel(h: Element): string {
+      const text = (h.textContent || "").trim().replace(/\s+/g, " ");
+      return `${h.tagName.toLowerCase()}:${text.substring(0, 60)}`;
+    }
+
+    /**
+     * The element's own first heading (or the closest heading before it) and
+     * up to two enclosing landmark regions — context that tends to survive
+     * markup refactors.
+     */
+    function getLandmarks(el: Element): string[] {
+      const landmarks: string[] = [];
+      const own = el.querySelector("h1, h2, h3, h4");
+      if (own) {
+        landmarks.push(headingLabel(own));
+      } else {
+        const headings = Array.from(document.querySelectorAll("h1, h2, h3, h4"));
+        for (let i = headings.length - 1; i >= 0; i--) {
+          if (headings[i].compareDocumentPosition(el) & Node.DOCUMENT_POSITION_FOLLOWING) {
+            landmarks.push(headingLabel(headings[i]));
+            break;
+          }
+        }
+      }
+      for (let p = el.parentElement; p && p !== document.body && landmarks.length < 3; p = p.parentElement) {
+        const tag = p.tagName.toLowerCase();
+        if (!LANDMARK_TAGS.has(tag)) continue;
+        landmarks.push(p.id && !isAutoGeneratedId(p.id) ? `${tag}#${p.id}` : tag);
+      }
+      return landmarks;
+    }
+
     // ────────────── Depth-based DOM walk ──────────────
 
     type Candidate = {
@@ -360,6 +420,8 @@ export async function mapSemanticElements(
       rect: { x: number; y: number; w: number; h: number };
       text_preview: string;
       semantic_role: string;
+      attributes: Record<string, string>;
+      landmarks: string[];
     };
 
     const results: Result[] = [];
@@ -387,6 +449,8 @@ export async function mapSemanticElements(
         },
         text_preview: textPreview,
         semantic_role: role,
+        attributes: getAttributes(el),
+        landmarks: getLandmarks(el),
       });
     }
 
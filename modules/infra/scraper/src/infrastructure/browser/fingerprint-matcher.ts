@@ -0,0 +1,219 @@
+import type { Page, ElementHandle } from "patchright";
+import type { ElementFingerprint } from "../../domain/value-objects/element-fingerprint";
+import { log } from "../logger";
+
+/** Below this score no candidate is trusted to be the section's element. */
+const MIN_MATCH_SCORE = 0.6;
+
+export interface FingerprintMatch {
+  element: ElementHandle;
+  selector: string;
+  xpath: string;
+  score: number;
+}
+
+/**
+ * Finds the element that best fits a section's fingerprint and builds fresh
+ * selectors for it. Each candidate is scored on tag, text, identifying
+ * attributes, position/size and surrounding landmarks; components missing
+ * from the fingerprint are left out of the weighting. Returns null when no
+ * candidate reaches MIN_MATCH_SCORE.
+ */
+export async function findByFingerprint(
+  page: Page,
+  fingerprint: ElementFingerprint,
+): Promise<FingerprintMatch | null> {
+  const handle = await page.evaluateHandle(
+    ({ fp, minScore }) => {
+      const SKIP_TAGS = new Set(["script", "style", "link", "meta", "noscript", "br", "hr", "svg", "path", "head", "template", "iframe"]);
+      const LANDMARK_TAGS = new Set(["header", "nav", "main", "aside", "footer", "section", "article", "form"]);
+      const ID_ATTRS = ["data-testid", "data-test-id", "data-cy", "data-section", "data-component"];
+      const MAX_CANDIDATES = 8000;
+      const LANDMARK_SHORTLIST = 25;
+      const WEIGHTS = { tag: 0.15, text: 0.35, attrs: 0.2, rect: 0.15, landmarks: 0.15 };
+
+      function isAutoGeneratedId(id: string): boolean {
+        return /^[0-9a-f]{8,}$/i.test(id) || /^[a-z]+-[0-9a-f]{4,}/i.test(id) || /^\d+$/.test(id) ||
+          /^:r[0-9a-z]+:$/i.test(id) || /^(radix|react|rc|mui|headless|__next)-/.test(id);
+      }
+
+      function normalize(text: string): string {
+        return text.toLowerCase().replace(/\.\.\.$/, "").replace(/\s+/g, " ").trim();
+      }
+
+      function words(text: string): Set<string> {
+        return new Set(normalize(text).split(" ").filter((w) => w.length > 1));
+      }
+
+      // Dice coefficient of two word sets.
+      function overlap(a: Set<string>, b: Set<string>): number {
+        if (a.size === 0 && b.size === 0) return 1;
+        if (a.size === 0 || b.size === 0) return 0;
+        let shared = 0;
+        a.forEach((w) => { if (b.has(w)) shared++; });
+        return (2 * shared) / (a.size + b.size);
+      }
+
+      function textPreview(el: Element): string {
+        return (el.textContent || "").trim().substring(0, 100);
+      }
+
+      const fpWords = fp.text ? words(fp.text) : null;
+      const fpAttrs = fp.attributes && Object.keys(fp.attributes).length > 0 ? fp.attributes : null;
+
+      function attrScore(el: Element): number {
+        const entries = Object.entries(fpAttrs!);
+        let total = 0;
+        for (const [name, want] of entries) {
+          if (name === "class") {
+            total += overlap(new Set(want.split(" ")), new Set(Array.from(el.classList)));
+          } else if (name === "id") {
+            total += el.id === want ? 1 : 0;
+          } else {
+            total += el.getAttribute(name) === want ? 1 : 0;
+          }
+        }
+        return total / entries.length;
+      }
+
+      function rectScore(el: Element): number {
+        const r = el.getBoundingClientRect();
+        const want = fp.rect!;
+        const x = r.x + window.scrollX;
+        const y = r.y + window.scrollY;
+        const distance = Math.hypot(x - want.x, y - want.y);
+        const position = Math.max(0, 1 - distance / 1000);
+        const size =
+          want.w > 0 && want.h > 0
+            ? (Math.min(r.width, want.w) / Math.max(r.width, want.w)) * (Math.min(r.height, want.h) / Math.max(r.height, want.h))
+            : 0;
+        return (position + size) / 2;
+      }
+
+      function headingLabel(h: Element): string {
+        const text = (h.textContent || "").trim().replace(/\s+/g, " ");
+        return `${h.tagName.toLowerCase()}:${text.substring(0, 60)}`;
+      }
+
+      // Mirrors getLandmarks in element-mapper so labels compare equal.
+      const headings = Array.from(document.querySelectorAll("h1, h2, h3, h4"));
+      function landmarks(el: Element): string[] {
+        const found: string[] = [];
+        const own = el.querySelector("h1, h2, h3, h4");
+        if (own) {
+          found.push(headingLabel(own));
+        } else {
+          for (let i = headings.length - 1; i >= 0; i--) {
+            if (headings[i].compareDocumentPosition(el) & Node.DOCUMENT_POSITION_FOLLOWING) {
+              found.push(headingLabel(headings[i]));
+              break;
+            }
+          }
+        }
+        for (let p = el.parentElement; p && p !== document.body && found.length < 3; p = p.parentElement) {
+          const tag = p.tagName.toLowerCase();
+          if (!LANDMARK_TAGS.has(tag)) continue;
+          found.push(p.id && !isAutoGeneratedId(p.id) ? `${tag}#${p.id}` : tag);
+        }
+        return found;
+      }
+
+      function landmarkScore(el: Element): number {
+        const have = new Set(landmarks(el).map(normalize));
+        const want = fp.landmarks!;
+        return want.filter((l) => have.has(normalize(l))).length / want.length;
+      }
+
+      // ── Score every visible element on the cheap components ──
+      type Scored = { el: Element; sum: number; weight: number };
+      const scored: Scored[] = [];
+      const all = document.body ? document.body.querySelectorAll("*") : [];
+      for (let i = 0; i < all.length && scored.length < MAX_CANDIDATES; i++) {
+        const el = all[i];
+        const tag = el.tagName.toLowerCase();
+        if (SKIP_TAGS.has(tag)) continue;
+        const r = el.getBoundingClientRect();
+        if (r.width < 10 || r.height < 10) continue;
+
+        let sum = WEIGHTS.tag * (tag === fp.tag ? 1 : 0);
+        let weight = WEIGHTS.tag;
+        if (fpWords) {
+          sum += WEIGHTS.text * overlap(fpWords, words(textPreview(el)));
+          weight += WEIGHTS.text;
+        }
+        if (fpAttrs) {
+          sum += WEIGHTS.attrs * attrScore(el);
+          weight += WEIGHTS.attrs;
+        }
+        if (fp.rect) {
+          sum += WEIGHTS.rect * rectScore(el);
+          weight += WEIGHTS.rect;
+        }
+        scored.push({ el, sum, weight });
+      }
+
+      // ── Landmarks are costlier; only the shortlist gets them ──
+      scored.sort((a, b) => b.sum / b.weight - a.sum / a.weight);
+      const shortlist = scored.slice(0, LANDMARK_SHORTLIST);
+      if (fp.landmarks && fp.landmarks.length > 0) {
+        for (const c of shortlist) {
+          c.sum += WEIGHTS.landmarks * landmarkScore(c.el);
+          c.weight += WEIGHTS.landmarks;
+        }
+        shortlist.sort((a, b) => b.sum / b.weight - a.sum / a.weight);
+      }
+      const best = shortlist[0];
+      if (!best || best.sum / best.weight < minScore) return null;
+
+      // ── Fresh selectors for the match ──
+      function cssSelector(el: Element): string {
+        if (el.id && !isAutoGeneratedId(el.id)) return `#${CSS.escape(el.id)}`;
+        for (const attr of ID_ATTRS) {
+          const val = el.getAttribute(attr);
+          if (val) {
+            const sel = `[${attr}="${CSS.escape(val)}"]`;
+            if (document.querySelectorAll(sel).length === 1) return sel;
+          }
+        }
+        const tag = el.tagName.toLowerCase();
+        const parent = el.parentElement;
+        if (!parent || parent === document.documentElement) return tag;
+        const siblings = Array.from(parent.children).filter((s) => s.tagName === el.tagName);
+        const step = siblings.length === 1 ? tag : `${tag}:nth-of-type(${siblings.indexOf(el) + 1})`;
+        return parent === document.body ? `body > ${step}` : `${cssSelector(parent)} > ${step}`;
+      }
+
+      function xpath(el: Element): string {
+        if (el.id && !isAutoGeneratedId(el.id)) return `//*[@id="${el.id}"]`;
+        const parts: string[] = [];
+        for (let cur: Element | null = el; cur && cur !== document.documentElement; cur = cur.parentElement) {
+          const tag = cur.tagName.toLowerCase();
+          const parent: Element | null = cur.parentElement;
+          const siblings = parent ? Array.from(parent.children).filter((s) => s.tagName === cur!.tagName) : [];
+          parts.unshift(siblings.length > 1 ? `${tag}[${siblings.indexOf(cur) + 1}]` : tag);
+        }
+        return "/" + parts.join("/");
+      }
+
+      return {
+        element: best.el,
+        selector: cssSelector(best.el),
+        xpath: xpath(best.el),
+        score: Math.round((best.sum / best.weight) * 1000) / 1000,
+      };
+    },
+    { fp: fingerprint, minScore: MIN_MATCH_SCORE },
+  );
+
+  const match = await handle.evaluate((m) =>
+    m ? { selector: m.selector, xpath: m.xpath, score: m.score } : null,
+  );
+  const element = match ? (await handle.getProperty("element")).asElement() : null;
+  if (!match || !element) {
+    await handle.dispose();
+    log("fingerprint", "no element matched the fingerprint", { tag: fingerprint.tag });
+    return null;
+  }
+  log("fingerprint", "element matched by fingerprint", match);
+  return { element, ...match };
+}
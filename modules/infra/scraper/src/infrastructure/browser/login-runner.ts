@@ -0,0 +1,56 @@
+import type { Page } from "patchright";
+import type { LoginScript } from "../../domain/value-objects/page-auth";
+import { LoginError } from "../../domain/errors/scraper-errors";
+import { log, createTimer } from "../logger";
+
+const LOGIN_STEP_TIMEOUT_MS = 15000;
+const LOGIN_SETTLE_TIMEOUT_MS = 15000;
+
+/**
+ * Logs in through the page's login form so the session cookies are set on the
+ * context before the monitored page is opened. Any failure becomes a
+ * LoginError; credentials never appear in logs or error messages.
+ */
+export async function runLogin(page: Page, login: LoginScript): Promise<void> {
+  const timer = createTimer();
+  let stage = "open login page";
+  try {
+    await page.goto(login.url, {
+      waitUntil: "domcontentloaded",
+      timeout: LOGIN_STEP_TIMEOUT_MS * 2,
+    });
+
+    stage = `fill username "${login.username_selector}"`;
+    await page.locator(login.username_selector).first().fill(login.username, { timeout: LOGIN_STEP_TIMEOUT_MS });
+
+    stage = `fill password "${login.password_selector}"`;
+    await page.locator(login.password_selector).first().fill(login.password, { timeout: LOGIN_STEP_TIMEOUT_MS });
+
+    stage = `click submit "${login.submit_selector}"`;
+    await page.locator(login.submit_selector).first().click({ timeout: LOGIN_STEP_TIMEOUT_MS });
+
+    try {
+      await page.waitForLoadState("networkidle", { timeout: LOGIN_SETTLE_TIMEOUT_MS });
+    } catch {
+      // Long-polling pages never go idle; the checks below decide the outcome.
+    }
+
+    if (login.success_selector) {
+      stage = `wait for success "${login.success_selector}"`;
+      await page.locator(login.success_selector).first().waitFor({ state: "visible", timeout: LOGIN_STEP_TIMEOUT_MS });
+    } else {
+      stage = "verify login";
+      if (await page.locator(login.password_selector).first().isVisible()) {
+        // Without a success marker, a login form that is still showing
+        // means the credentials were rejected.
+        throw new Error("login form still visible after submitting");
+      }
+    }
+  } catch (err: any) {
+    const reason = err?.name === "TimeoutError"
+      ? "timed out"
+      : (err?.message ?? String(err)).split("\n")[0].trim();
+    throw new LoginError(`${stage}: ${reason}`);
+  }
+  log("login", "login completed", { loginUrl: login.url, elapsed: timer.elapsed() });
+}
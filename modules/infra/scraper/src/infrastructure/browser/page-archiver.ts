@@ -0,0 +1,17 @@
+import type { Page } from "patchright";
+
+/**
+ * Records the page as a single MHTML file with Chromium's
+ * Page.captureSnapshot, so its DOM, styles and images can be replayed later
+ * exactly as captured. Runs after rendering and scrolling, so lazy-loaded
+ * content is included.
+ */
+export async function capturePageArchive(page: Page): Promise<string> {
+  const session = await page.context().newCDPSession(page);
+  try {
+    const { data } = await session.send("Page.captureSnapshot", { format: "mhtml" });
+    return data;
+  } finally {
+    await session.detach().catch(() => {});
+  }
+}
@@ -8,18 +8,37 @@ import type {
 import type { ExtractionResult } from "../../domain/entities/extraction-result";
 import type { PreviewResult } from "../../domain/entities/preview-result";
 import type { IImageProcessor } from "../../domain/services/image-processor";
-import { BrowserError, NavigationError } from "../../domain/errors/scraper-errors";
+import { BrowserError, CaptureStepError, LoginError, NavigationError, ProxyError } from "../../domain/errors/scraper-errors";
 import { DEFAULT_VIEWPORT } from "../../domain/value-objects/viewport";
-import { createStealthContext, navigateWithProtections } from "./context-factory";
+import { createStealthContext, navigateWithProtections, profileViewport } from "./context-factory";
 import { extractContent } from "./content-extractor";
 import { extractSections } from "./section-extractor";
 import { mapSemanticElements } from "./element-mapper";
 import { scrollFullPage } from "./page-scroller";
 import { waitForRenderStable } from "./render-waiter";
+import { runCaptureSteps } from "./step-runner";
+import { runLogin } from "./login-runner";
+import { capturePageArchive } from "./page-archiver";
 import { log, logError, createTimer } from "../logger";
 
 const MAX_CONCURRENT = parseInt(process.env.MAX_CONCURRENT_PAGES || "3", 10);
 
+// Chromium network errors raised when the proxy itself, not the target, fails.
+const PROXY_ERRORS = [
+  "ERR_PROXY_CONNECTION_FAILED",
+  "ERR_TUNNEL_CONNECTION_FAILED",
+  "ERR_PROXY_AUTH_UNSUPPORTED",
+  "ERR_PROXY_CERTIFICATE_INVALID",
+  "ERR_SOCKS_CONNECTION_FAILED",
+  "ERR_SOCKS_CONNECTION_HOST_UNREACHABLE",
+  "ERR_NO_SUPPORTED_PROXIES",
+];
+
+function isProxyFailure(err: any): boolean {
+  const message: string = err?.message ?? "";
+  return PROXY_ERRORS.some((code) => message.includes(code));
+}
+
 export class PatchrightBrowserService implements IBrowserService {
   private browser: Browser | null = null;
   private launching = false;
@@ -124,14 +143,33 @@ export class PatchrightBrowserService implements IBrowserService {
     log("extract", "slot acquired", { url });
 
     const stepTimer = createTimer();
-    const { context, page } = await createStealthContext(browser);
-    log("extract", "stealth context created", { url, elapsed: stepTimer.elapsed() });
+    const viewport = profileViewport(options.profile);
+    const { context, page } = await createStealthContext(browser, {
+      auth: options.auth,
+      targetUrl: options.url,
+      profile: options.profile,
+      proxy: options.proxy,
+    });
+    log("extract", "stealth context created", { url, profile: options.profile?.name, elapsed: stepTimer.elapsed() });
 
     try {
+      // Log in first so the session cookies are in place for the page itself.
+      if (options.auth?.login) {
+        await runLogin(page, options.auth.login);
+      }
+
       const navTimer = createTimer();
       await navigateWithProtections(page, options.url, options.blockAdsCookies);
       log("extract", "navigation completed", { url, elapsed: navTimer.elapsed() });
 
+      // Scripted steps (expand "show more", switch tabs, dismiss pickers) run
+      // before scrolling so content they reveal is lazy-loaded and captured.
+      if (options.steps && options.steps.length > 0) {
+        const stepsTimer = createTimer();
+        await runCaptureSteps(page, options.steps);
+        log("extract", "capture steps completed", { url, steps: options.steps.length, elapsed: stepsTimer.elapsed() });
+      }
+
       // Scroll to trigger lazy loading, then wait for stability
       const scrollTimer = createTimer();
       await scrollFullPage(page);
@@ -178,7 +216,7 @@ export class PatchrightBrowserService implements IBrowserService {
                 }
               : clip;
             const buf = await page.screenshot({ clip: adjustedClip, type: "png" });
-            screenshotBase64 = await this.imageProcessor.pngToWebpBase64(Buffer.from(buf));
+            screenshotBase64 = this.imageProcessor.pngToBase64(Buffer.from(buf));
             log("extract", "element screenshot captured", { url, elapsed: screenshotTimer.elapsed(), clip: adjustedClip });
           }
         }
@@ -186,9 +224,9 @@ export class PatchrightBrowserService implements IBrowserService {
 
       if (!screenshotBase64) {
         const buf = await page.screenshot({ fullPage: true, type: "png" });
-        screenshotBase64 = await this.imageProcessor.cropToWidthAndConvert(
+        screenshotBase64 = await this.imageProcessor.cropToWidth(
           Buffer.from(buf),
-          DEFAULT_VIEWPORT.width,
+          viewport.width,
         );
         log("extract", "full-page screenshot captured", { url, elapsed: screenshotTimer.elapsed(), size: screenshotBase64.length });
       }
@@ -202,6 +240,19 @@ export class PatchrightBrowserService implements IBrowserService {
         log("extract", "sections extracted", { url, elapsed: sectionTimer.elapsed(), total: sections?.length ?? 0, matched });
       }
 
+      // A failed archive leaves the capture without one; the screenshot and
+      // content are still the check's record.
+      let mhtml: string | undefined;
+      if (options.archive) {
+        const archiveTimer = createTimer();
+        try {
+          mhtml = await capturePageArchive(page);
+          log("extract", "page archive captured", { url, elapsed: archiveTimer.elapsed(), size: mhtml.length });
+        } catch (err) {
+          logError("extract", "page archive failed", err, { url, elapsed: archiveTimer.elapsed() });
+        }
+      }
+
       log("extract", "extraction completed", { url, totalElapsed: timer.elapsed() });
 
       return {
@@ -211,9 +262,16 @@ export class PatchrightBrowserService implements IBrowserService {
         screenshot_base64: screenshotBase64,
         selector_matched: content.selectorMatched,
         sections,
+        mhtml,
       };
     } catch (err: any) {
       logError("extract", "extraction failed", err, { url, elapsed: timer.elapsed() });
+      if (options.proxy && isProxyFailure(err)) {
+        throw new ProxyError(err.message);
+      }
+      if (err instanceof CaptureStepError || err instanceof LoginError) {
+        throw err;
+      }
       if (err.message?.includes("net::ERR_") || err.message?.includes("Navigation")) {
         throw new NavigationError(err.message, options.url);
       }
@@ -3,11 +3,14 @@ import type { SectionResult } from "../../domain/entities/extraction-result";
 import type { SectionConfig } from "../../domain/value-objects/selector-config";
 import type { IImageProcessor } from "../../domain/services/image-processor";
 import { extractElementContent } from "./content-extractor";
+import { findByFingerprint, type FingerprintMatch } from "./fingerprint-matcher";
 import { log, logError, createTimer } from "../logger";
 
 /**
  * Extracts content and screenshots from multiple page sections.
- * Each section is identified by a CSS selector or XPath.
+ * Each section is identified by a CSS selector or XPath. When both miss and
+ * the section has a fingerprint, the best-matching element is captured
+ * instead and its selectors are returned as a proposal.
  */
 export async function extractSections(
   page: Page,
@@ -19,19 +22,33 @@ export async function extractSections(
 
   for (const section of sections) {
     const sectionTimer = createTimer();
-    const { html, text, matched } = await extractElementContent(page, {
+    let { html, text, matched } = await extractElementContent(page, {
       selector: section.selector,
       selectorXpath: section.selectorXpath,
       selectorOffsets: section.selectorOffsets,
     });
 
+    let healed: FingerprintMatch | null = null;
+    if (!matched && section.fingerprint) {
+      healed = await findByFingerprint(page, section.fingerprint).catch((err) => {
+        logError("sections", `section "${section.id}" fingerprint matching failed`, err);
+        return null;
+      });
+      if (healed) {
+        html = await healed.element.innerHTML();
+        text = await healed.element.innerText().catch(() => "");
+      }
+    }
+
     let screenshotBase64 = "";
-    if (matched) {
-      const element = section.selector
-        ? await page.$(section.selector)
-        : section.selectorXpath
-          ? (await page.$$(`:xpath=${section.selectorXpath}`))[0] || null
-          : null;
+    if (matched || healed) {
+      const element = healed
+        ? healed.element
+        : section.selector
+          ? await page.$(section.selector)
+          : section.selectorXpath
+            ? (await page.$$(`:xpath=${section.selectorXpath}`))[0] || null
+            : null;
 
       if (element) {
         try {
@@ -55,9 +72,7 @@ export async function extractSections(
                 clip: adjustedClip,
                 type: "png",
               });
-              screenshotBase64 = await imageProcessor.pngToWebpBase64(
-                Buffer.from(screenshotBuffer),
-              );
+              screenshotBase64 = imageProcessor.pngToBase64(Buffer.from(screenshotBuffer));
               log("sections", `section "${section.id}" screenshot captured (clip)`, { elapsed: sectionTimer.elapsed(), clip: adjustedClip });
             }
           }
@@ -65,9 +80,7 @@ export async function extractSections(
           // Default: use element.screenshot() which handles scrolling and clipping automatically
           if (!screenshotBase64) {
             const screenshotBuffer = await element.screenshot({ type: "png" });
-            screenshotBase64 = await imageProcessor.pngToWebpBase64(
-              Buffer.from(screenshotBuffer),
-            );
+            screenshotBase64 = imageProcessor.pngToBase64(Buffer.from(screenshotBuffer));
             log("sections", `section "${section.id}" screenshot captured (element)`, { elapsed: sectionTimer.elapsed() });
           }
         } catch (err) {
@@ -76,7 +89,7 @@ export async function extractSections(
       }
     }
 
-    log("sections", `section "${section.id}" done`, { matched, htmlLength: html.length, textLength: text.length, hasScreenshot: screenshotBase64.length > 0, elapsed: sectionTimer.elapsed() });
+    log("sections", `section "${section.id}" done`, { matched, healed: !!healed, htmlLength: html.length, textLength: text.length, hasScreenshot: screenshotBase64.length > 0, elapsed: sectionTimer.elapsed() });
 
     results.push({
       id: section.id,
@@ -84,6 +97,12 @@ export async function extractSections(
       html,
       text,
       selector_matched: matched,
+      ...(healed && {
+        healed: true,
+        proposed_selector: healed.selector,
+        proposed_xpath: healed.xpath,
+        match_score: healed.score,
+      }),
     });
   }
 
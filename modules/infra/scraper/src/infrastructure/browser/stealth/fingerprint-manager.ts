@@ -5,17 +5,25 @@ import type { BrowserContext } from "patchright";
 const generator = new FingerprintGenerator();
 const injector = new FingerprintInjector();
 
+export interface FingerprintOptions {
+  mobile?: boolean;
+  locale?: string;
+}
+
 /**
  * Generates a realistic browser fingerprint and injects it
- * into a browser context for anti-detection.
+ * into a browser context for anti-detection. Mobile and locale options keep
+ * the fingerprint consistent with the emulated device and language.
  */
 export async function applyFingerprint(
   context: BrowserContext,
+  options: FingerprintOptions = {},
 ): Promise<void> {
   const fingerprint = generator.getFingerprint({
     browsers: ["chrome"],
-    operatingSystems: ["linux"],
-    devices: ["desktop"],
+    operatingSystems: options.mobile ? ["android"] : ["linux"],
+    devices: [options.mobile ? "mobile" : "desktop"],
+    locales: options.locale ? [options.locale] : undefined,
   });
 
   await injector.attachFingerprintToPlaywright(context as any, fingerprint);
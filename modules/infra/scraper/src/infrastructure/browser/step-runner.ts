@@ -0,0 +1,76 @@
+import type { Page } from "patchright";
+import type { CaptureStep } from "../../domain/value-objects/capture-step";
+import { CaptureStepError } from "../../domain/errors/scraper-errors";
+import { log } from "../logger";
+
+const DEFAULT_STEP_TIMEOUT_MS = 10000;
+const NETWORK_IDLE_TIMEOUT_MS = 15000;
+
+/**
+ * Runs capture steps in order. The first failing step aborts the run with a
+ * CaptureStepError naming the step (1-based) so the check can report it.
+ */
+export async function runCaptureSteps(
+  page: Page,
+  steps: CaptureStep[],
+): Promise<void> {
+  for (let i = 0; i < steps.length; i++) {
+    const step = steps[i];
+    const n = i + 1;
+    try {
+      await runStep(page, step);
+      log("steps", `step ${n}/${steps.length} completed`, { action: step.action, selector: step.selector });
+    } catch (err: any) {
+      const target = step.selector ? ` "${step.selector}"` : "";
+      const reason = err?.name === "TimeoutError"
+        ? `timed out after ${step.wait_ms || DEFAULT_STEP_TIMEOUT_MS}ms`
+        : firstLine(err?.message ?? String(err));
+      throw new CaptureStepError(`${step.action}${target}: ${reason}`, n, step.action);
+    }
+  }
+}
+
+async function runStep(page: Page, step: CaptureStep): Promise<void> {
+  const timeout = step.wait_ms || DEFAULT_STEP_TIMEOUT_MS;
+  const locator = () => page.locator(step.selector!).first();
+
+  switch (step.action) {
+    case "click":
+      await locator().click({ timeout });
+      return;
+    case "type":
+      await locator().fill(step.value ?? "", { timeout });
+      return;
+    case "select":
+      await locator().selectOption(step.value!, { timeout });
+      return;
+    case "scroll_to":
+      await locator().scrollIntoViewIfNeeded({ timeout });
+      return;
+    case "wait_for_selector":
+      await locator().waitFor({ state: "visible", timeout });
+      return;
+    case "wait_for_network_idle":
+      await page.waitForLoadState("networkidle", {
+        timeout: step.wait_ms || NETWORK_IDLE_TIMEOUT_MS,
+      });
+      return;
+    case "wait":
+      await page.waitForTimeout(step.wait_ms ?? 0);
+      return;
+    case "press":
+      if (step.selector) {
+        await locator().press(step.value!, { timeout });
+      } else {
+        await page.keyboard.press(step.value!);
+      }
+      return;
+    default:
+      throw new Error(`unknown action "${(step as CaptureStep).action}"`);
+  }
+}
+
+/** Playwright errors include a multi-line call log; keep only the summary. */
+function firstLine(message: string): string {
+  return message.split("\n")[0].trim();
+}
@@ -0,0 +1,124 @@
+import { describe, expect, test } from "bun:test";
+import { createApp } from "./app";
+import { ExtractPageHandler } from "../../application/extract-page/handler";
+import { PreviewPageHandler } from "../../application/preview-page/handler";
+import { HealthCheckHandler } from "../../application/health-check/handler";
+import type { ExtractOptions, IBrowserService } from "../../domain/services/browser-service";
+import type { ExtractionResult } from "../../domain/entities/extraction-result";
+import type { PreviewResult } from "../../domain/entities/preview-result";
+import { PROTOCOL_HEADER } from "./multipart";
+
+const ARCHIVE = "From: <Saved by Blink>\r\nMIME-Version: 1.0\r\n";
+
+/** Records the options the app passes down and archives only when asked. */
+class FakeBrowserService implements IBrowserService {
+  calls: ExtractOptions[] = [];
+
+  async extract(options: ExtractOptions): Promise<ExtractionResult> {
+    this.calls.push(options);
+    return {
+      title: "Home",
+      html: "<html></html>",
+      text: "",
+      screenshot_base64: Buffer.from("png").toString("base64"),
+      selector_matched: true,
+      mhtml: options.archive ? ARCHIVE : undefined,
+    };
+  }
+
+  async preview(): Promise<PreviewResult> {
+    throw new Error("not used");
+  }
+
+  isHealthy(): boolean {
+    return true;
+  }
+
+  async shutdown(): Promise<void> {}
+}
+
+function setup() {
+  const browser = new FakeBrowserService();
+  const app = createApp(
+    new ExtractPageHandler(browser),
+    new PreviewPageHandler(browser),
+    new HealthCheckHandler(browser),
+  );
+  const extract = (body: object, headers: Record<string, string> = {}) =>
+    app.request("/extract", {
+      method: "POST",
+      headers: { "Content-Type": "application/json", ...headers },
+      body: JSON.stringify(body),
+    });
+  return { browser, extract };
+}
+
+describe("POST /extract archive", () => {
+  test("returns the MHTML archive when the worker asks for one", async () => {
+    const { browser, extract } = setup();
+    const res = await extract({ url: "https://example.com", archive: true });
+
+    expect(res.status).toBe(200);
+    expect(browser.calls[0].archive).toBe(true);
+    const body = await res.json();
+    expect(body.mhtml).toBe(ARCHIVE);
+  });
+
+  test("records no archive by default", async () => {
+    const { browser, extract } = setup();
+    const res = await extract({ url: "https://example.com" });
+
+    expect(browser.calls[0].archive).toBe(false);
+    const body = await res.json();
+    expect(body.mhtml).toBeUndefined();
+  });
+});
+
+interface Part {
+  name: string;
+  contentType: string;
+  body: string;
+}
+
+/** Splits a multipart/mixed response into its parts. */
+async function readParts(res: Response): Promise<Part[]> {
+  const boundary = res.headers.get("Content-Type")!.match(/boundary=(.+)$/)![1];
+  const text = await res.text();
+  return text
+    .split(`--${boundary}`)
+    .slice(1, -1)
+    .map((raw) => {
+      const [head, ...rest] = raw.replace(/^\r\n/, "").split("\r\n\r\n");
+      return {
+        name: head.match(/name="([^"]+)"/)![1],
+        contentType: head.match(/Content-Type: (.+)/)![1].trim(),
+        body: rest.join("\r\n\r\n").replace(/\r\n$/, ""),
+      };
+    });
+}
+
+describe("POST /extract multipart protocol", () => {
+  test("sends the archive in its own part, next to a PNG screenshot", async () => {
+    const { extract } = setup();
+    const res = await extract({ url: "https://example.com", archive: true }, { [PROTOCOL_HEADER]: "2" });
+
+    expect(res.headers.get("Content-Type")).toStartWith("multipart/mixed");
+    const parts = await readParts(res);
+    expect(parts.map((p) => p.name)).toEqual(["result", "screenshot", "mhtml"]);
+
+    const meta = JSON.parse(parts[0].body);
+    expect(meta.title).toBe("Home");
+    expect(meta.mhtml).toBeUndefined();
+    expect(meta.screenshot_base64).toBeUndefined();
+    expect(parts[1]).toMatchObject({ contentType: "image/png", body: "png" });
+    expect(parts[2]).toMatchObject({ contentType: "multipart/related", body: ARCHIVE });
+  });
+
+  test("has no archive part without an archive", async () => {
+    const { extract } = setup();
+    const res = await extract({ url: "https://example.com" }, { [PROTOCOL_HEADER]: "2" });
+
+    const parts = await readParts(res);
+    expect(parts.map((p) => p.name)).toEqual(["result", "screenshot"]);
+  });
+});
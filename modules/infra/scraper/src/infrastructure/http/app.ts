@@ -3,10 +3,11 @@ import { streamSSE } from "hono/streaming";
 import { ExtractPageHandler } from "../../application/extract-page/handler";
 import { PreviewPageHandler } from "../../application/preview-page/handler";
 import { HealthCheckHandler } from "../../application/health-check/handler";
-import { ScraperError } from "../../domain/errors/scraper-errors";
+import { CaptureStepError, ScraperError } from "../../domain/errors/scraper-errors";
 import type { ExtractPageRequest } from "../../application/extract-page/request";
 import type { PreviewPageRequest } from "../../application/preview-page/request";
 import { log, logError, createRequestId, createTimer } from "../logger";
+import { PROTOCOL_HEADER, encodeMultipart, wantsMultipart } from "./multipart";
 
 export function createApp(
   extractHandler: ExtractPageHandler,
@@ -34,12 +35,19 @@ export function createApp(
     }
 
     const sectionsCount = body.sections?.length ?? 0;
+    const multipart = wantsMultipart(c.req.header(PROTOCOL_HEADER));
     log("http", "extract request received", {
       reqId,
       url: body.url,
       hasSelector: !!(body.selector || body.selector_xpath),
       sections: sectionsCount,
+      steps: body.steps?.length ?? 0,
+      auth: !!body.auth,
+      profile: body.profile?.name,
+      proxy: !!body.proxy,
+      archive: body.archive ?? false,
       blockAdsCookies: body.block_ads_cookies ?? false,
+      multipart,
     });
 
     try {
@@ -54,9 +62,17 @@ export function createApp(
         screenshotSize: result.screenshot_base64.length,
         selectorMatched: result.selector_matched,
         sectionsExtracted: result.sections?.length ?? 0,
+        mhtmlLength: result.mhtml?.length ?? 0,
       });
+      if (multipart) {
+        return encodeMultipart(result);
+      }
       return c.json(result);
     } catch (err) {
+      if (err instanceof CaptureStepError) {
+        logError("http", `extract failed [${err.code}]`, err, { reqId, url: body.url, step: err.step, elapsed: timer.elapsed() });
+        return c.json({ error: err.message, code: err.code, step: err.step, action: err.action }, err.statusCode as any);
+      }
       if (err instanceof ScraperError) {
         logError("http", `extract failed [${err.code}]`, err, { reqId, url: body.url, elapsed: timer.elapsed() });
         return c.json({ error: err.message, code: err.code }, err.statusCode as any);
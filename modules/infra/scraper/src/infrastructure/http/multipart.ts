@@ -0,0 +1,64 @@
+import type { ExtractionResult } from "../../domain/entities/extraction-result";
+
+/**
+ * Extraction results travel in one of two protocols. Version 1 is a JSON body
+ * with base64 screenshots. Version 2 is a multipart/mixed body: a JSON
+ * "result" part without the screenshots and archive, followed by raw
+ * "screenshot", "section:<id>" and "mhtml" parts, so the worker never decodes
+ * base64 or holds the archive inside JSON. Workers ask
+ * for version 2 with the X-Extractor-Protocol header; older workers don't send
+ * it and keep getting JSON.
+ */
+export const PROTOCOL_HEADER = "X-Extractor-Protocol";
+export const PROTOCOL_MULTIPART = 2;
+
+// Extraction screenshots are lossless PNG; see SharpImageProcessor.cropToWidth.
+const SCREENSHOT_CONTENT_TYPE = "image/png";
+const ARCHIVE_CONTENT_TYPE = "multipart/related";
+
+export function wantsMultipart(protocolHeader: string | undefined): boolean {
+  return parseInt(protocolHeader ?? "", 10) >= PROTOCOL_MULTIPART;
+}
+
+export function encodeMultipart(result: ExtractionResult): Response {
+  const boundary = `pulzifi-${crypto.randomUUID()}`;
+  const parts: (string | Uint8Array)[] = [];
+  const addPart = (name: string, contentType: string, data: string | Uint8Array) => {
+    parts.push(
+      `--${boundary}\r\n` +
+        `Content-Disposition: form-data; name="${name}"\r\n` +
+        `Content-Type: ${contentType}\r\n\r\n`,
+      data,
+      "\r\n",
+    );
+  };
+
+  const { screenshot_base64, sections, mhtml, ...meta } = result;
+  addPart(
+    "result",
+    "application/json",
+    JSON.stringify({
+      ...meta,
+      sections: sections?.map(({ screenshot_base64: _, ...section }) => section),
+    }),
+  );
+  if (screenshot_base64) {
+    addPart("screenshot", SCREENSHOT_CONTENT_TYPE, Buffer.from(screenshot_base64, "base64"));
+  }
+  for (const section of sections ?? []) {
+    if (section.screenshot_base64) {
+      addPart(`section:${section.id}`, SCREENSHOT_CONTENT_TYPE, Buffer.from(section.screenshot_base64, "base64"));
+    }
+  }
+  if (mhtml) {
+    addPart("mhtml", ARCHIVE_CONTENT_TYPE, mhtml);
+  }
+  parts.push(`--${boundary}--\r\n`);
+
+  return new Response(new Blob(parts), {
+    headers: {
+      "Content-Type": `multipart/mixed; boundary=${boundary}`,
+      [PROTOCOL_HEADER]: String(PROTOCOL_MULTIPART),
+    },
+  });
+}
@@ -27,24 +27,36 @@ export class SharpImageProcessor implements IImageProcessor {
     width: number,
     quality?: number,
   ): Promise<string> {
-    const metadata = await sharp(pngBuffer).metadata();
-    const imgWidth = metadata.width ?? width;
-    const imgHeight = metadata.height ?? 1;
-
-    // Only crop if the image is wider than desired
-    const s =
-      imgWidth > width
-        ? sharp(pngBuffer).extract({
-            left: 0,
-            top: 0,
-            width,
-            height: imgHeight,
-          })
-        : sharp(pngBuffer);
-
+    const s = await this.crop(pngBuffer, width);
     const webpBuffer = await s
       .webp({ quality: quality ?? SCREENSHOT_QUALITY })
       .toBuffer();
     return webpBuffer.toString("base64");
   }
+
+  /**
+   * Crops a PNG to a specific width (keeping full height) and keeps it PNG.
+   * Extraction screenshots stay lossless: the worker hashes and diffs pixels.
+   */
+  async cropToWidth(pngBuffer: Buffer, width: number): Promise<string> {
+    const s = await this.crop(pngBuffer, width);
+    const png = await s.png().toBuffer();
+    return png.toString("base64");
+  }
+
+  // Only crops if the image is wider than desired.
+  private async crop(pngBuffer: Buffer, width: number): Promise<sharp.Sharp> {
+    const metadata = await sharp(pngBuffer).metadata();
+    const imgWidth = metadata.width ?? width;
+    const imgHeight = metadata.height ?? 1;
+
+    return imgWidth > width
+      ? sharp(pngBuffer).extract({
+          left: 0,
+          top: 0,
+          width,
+          height: imgHeight,
+        })
+      : sharp(pngBuffer);
+  }
 }
@@ -9,6 +9,7 @@ type Request struct {
 	PageURL             string
 	PrevText            string
 	NewText             string
+	DiffText string // STUB
 	SchemaName          string
 	EnabledInsightTypes []string // subset to generate; defaults to ["marketing","market_analysis"]
 }
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
@@ -16,6 +15,8 @@ import (
 	insightAI "github.com/jcsoftdev/pulzifi-back/modules/insight/infrastructure/ai"
 	"github.com/jcsoftdev/pulzifi-back/modules/insight/infrastructure/persistence"
 	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	snapshotrepos "github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/repositories"
+	snapshotstorage "github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/storage"
 	sharedAI "github.com/jcsoftdev/pulzifi-back/shared/ai"
 	"github.com/jcsoftdev/pulzifi-back/shared/config"
 	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
@@ -31,6 +32,7 @@ type Module struct {
 	db             *sql.DB
 	insightHandler *generateinsights.GenerateInsightsHandler
 	broker         *pubsub.InsightBroker
+	snapshots      snapshotrepos.ObjectStorage
 }
 
 // NewModule creates a new instance of the Insight module
@@ -49,6 +51,12 @@ func NewModuleWithDB(db *sql.DB, broker *pubsub.InsightBroker) router.ModuleRegi
 		m.insightHandler = generateinsights.NewGenerateInsightsHandler(generator, db)
 	}
 
+	if objectStorage, err := snapshotstorage.NewObjectStorage(cfg); err != nil {
+		logger.Warn("Object storage unavailable, insights will be generated without snapshot text", zap.Error(err))
+	} else {
+		m.snapshots = objectStorage
+	}
+
 	return m
 }
 
@@ -179,16 +187,16 @@ func (m *Module) handleGenerateInsight(w http.ResponseWriter, r *http.Request) {
 			logger.Error("Failed to list checks for insight generation", zap.Error(err))
 			return
 		}
-		var prevHTMLURL string
+		var prevHTMLKey string
 		for _, c := range allChecks {
 			if c.ID != checkID && c.Status == "success" {
-				prevHTMLURL = c.HTMLSnapshotURL
+				prevHTMLKey = c.HTMLSnapshotURL
 				break
 			}
 		}
 
-		newText := fetchHTMLText(check.HTMLSnapshotURL)
-		prevText := fetchHTMLText(prevHTMLURL)
+		newText := m.fetchHTMLText(ctx, check.HTMLSnapshotURL)
+		prevText := m.fetchHTMLText(ctx, prevHTMLKey)
 
 		configRepo := monPersistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
 		pageConfig, _ := configRepo.GetByPageID(ctx, pageID)
@@ -227,19 +235,15 @@ func (m *Module) handleGenerateInsight(w http.ResponseWriter, r *http.Request) {
 	}()
 }
 
-// fetchHTMLText downloads HTML from url and extracts plain text.
-func fetchHTMLText(url string) string {
-	if url == "" {
-		return ""
-	}
-	resp, err := http.Get(url) //nolint:noctx
-	if err != nil {
-		logger.Error("Failed to fetch HTML for text extraction", zap.String("url", url), zap.Error(err))
+// fetchHTMLText downloads an HTML snapshot by its object key and extracts
+// plain text.
+func (m *Module) fetchHTMLText(ctx context.Context, key string) string {
+	if key == "" || m.snapshots == nil {
 		return ""
 	}
-	defer resp.Body.Close()
-	content, err := io.ReadAll(resp.Body)
+	content, err := m.snapshots.Download(ctx, key)
 	if err != nil {
+		logger.Error("Failed to fetch HTML for text extraction", zap.String("key", key), zap.Error(err))
 		return ""
 	}
 	return sharedHTML.ExtractText(string(content))
@@ -0,0 +1,236 @@
+package exporttimelapse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// minWidth is the narrowest frame width accepted.
+const minWidth = 160
+
+var (
+	ErrInvalidRequest = errors.New("invalid time-lapse request")
+	ErrJobNotFound    = errors.New("time-lapse job not found")
+	ErrForbidden      = errors.New("not a member of the page's workspace")
+)
+
+// Runner renders a job's time-lapse in the background.
+type Runner interface {
+	RenderTimelapse(ctx context.Context, schemaName string, jobID uuid.UUID) error
+}
+
+// SnapshotSigner issues short-lived URLs for stored snapshots to members of
+// the page's workspace.
+type SnapshotSigner interface {
+	CanAccessPage(ctx context.Context, pageID uuid.UUID) (bool, error)
+	Sign(ctx context.Context, key string) string
+}
+
+// ExportTimelapseHandler starts background renders of a page's screenshots
+// over a date range and hands out the rendered images.
+type ExportTimelapseHandler struct {
+	jobs   repositories.TimelapseRepository
+	signer SnapshotSigner
+	runner Runner
+	tenant string
+}
+
+func NewExportTimelapseHandler(jobs repositories.TimelapseRepository, signer SnapshotSigner, runner Runner, tenant string) *ExportTimelapseHandler {
+	return &ExportTimelapseHandler{jobs: jobs, signer: signer, runner: runner, tenant: tenant}
+}
+
+// Start records a pending job and renders it in the background.
+func (h *ExportTimelapseHandler) Start(ctx context.Context, pageID uuid.UUID, req *StartTimelapseRequest) (*TimelapseJobResponse, error) {
+	if req.From.IsZero() || req.To.IsZero() || !req.To.After(req.From) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidRequest)
+	}
+	if req.To.Sub(req.From) > entities.TimelapseMaxRange {
+		return nil, fmt.Errorf("%w: a time-lapse covers at most %d days", ErrInvalidRequest, int(entities.TimelapseMaxRange.Hours()/24))
+	}
+	format := req.Format
+	if format == "" {
+		format = entities.TimelapseGIF
+	}
+	if !entities.ValidTimelapseFormat(format) {
+		return nil, fmt.Errorf("%w: format %q must be one of gif, apng or strip", ErrInvalidRequest, format)
+	}
+	width := req.Width
+	if width == 0 {
+		width = entities.TimelapseDefaultWidth
+	}
+	if width < minWidth || width > entities.TimelapseMaxWidth {
+		return nil, fmt.Errorf("%w: width must be between %d and %d", ErrInvalidRequest, minWidth, entities.TimelapseMaxWidth)
+	}
+	if err := h.authorize(ctx, pageID); err != nil {
+		return nil, err
+	}
+
+	job := entities.NewTimelapseJob(pageID, req.SectionID, req.From, req.To, format, width)
+	job.DateStamp = req.DateStamp
+	job.HighlightDiff = req.HighlightDiff
+	if err := h.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := h.runner.RenderTimelapse(context.Background(), h.tenant, job.ID); err != nil {
+			logger.Error("Time-lapse render failed", zap.Error(err), zap.String("job_id", job.ID.String()))
+		}
+	}()
+
+	return h.toJobResponse(ctx, job), nil
+}
+
+// Get returns a job with a signed URL of its image once rendered.
+func (h *ExportTimelapseHandler) Get(ctx context.Context, jobID uuid.UUID) (*TimelapseJobResponse, error) {
+	job, err := h.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	if err := h.authorize(ctx, job.PageID); err != nil {
+		return nil, err
+	}
+	return h.toJobResponse(ctx, job), nil
+}
+
+// List returns a page's jobs, newest first.
+func (h *ExportTimelapseHandler) List(ctx context.Context, pageID uuid.UUID) (*ListTimelapsesResponse, error) {
+	if err := h.authorize(ctx, pageID); err != nil {
+		return nil, err
+	}
+	jobs, err := h.jobs.ListByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListTimelapsesResponse{Jobs: make([]*TimelapseJobResponse, len(jobs))}
+	for i, job := range jobs {
+		resp.Jobs[i] = h.toJobResponse(ctx, job)
+	}
+	return resp, nil
+}
+
+func (h *ExportTimelapseHandler) authorize(ctx context.Context, pageID uuid.UUID) error {
+	member, err := h.signer.CanAccessPage(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// HandleStartHTTP is the HTTP handler for POST /monitoring/checks/page/{pageId}/timelapses
+func (h *ExportTimelapseHandler) HandleStartHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	var req StartTimelapseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Start(r.Context(), pageID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRequest):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			logger.Error("Failed to start time-lapse", zap.Error(err), zap.String("page_id", pageID.String()))
+			http.Error(w, "failed to start time-lapse", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleListHTTP is the HTTP handler for GET /monitoring/checks/page/{pageId}/timelapses
+func (h *ExportTimelapseHandler) HandleListHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.List(r.Context(), pageID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		logger.Error("Failed to list time-lapses", zap.Error(err))
+		http.Error(w, "failed to list time-lapses", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleGetHTTP is the HTTP handler for GET /monitoring/checks/timelapses/{id}
+func (h *ExportTimelapseHandler) HandleGetHTTP(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Get(r.Context(), jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			logger.Error("Failed to get time-lapse", zap.Error(err), zap.String("job_id", jobID.String()))
+			http.Error(w, "failed to get time-lapse", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ExportTimelapseHandler) toJobResponse(ctx context.Context, job *entities.TimelapseJob) *TimelapseJobResponse {
+	return &TimelapseJobResponse{
+		ID:            job.ID,
+		PageID:        job.PageID,
+		SectionID:     job.SectionID,
+		From:          job.From,
+		To:            job.To,
+		Format:        job.Format,
+		Width:         job.Width,
+		DateStamp:     job.DateStamp,
+		HighlightDiff: job.HighlightDiff,
+		Status:        job.Status,
+		Frames:        job.Frames,
+		ResultURL:     h.signer.Sign(ctx, job.ResultURL),
+		ErrorMessage:  job.ErrorMessage,
+		CreatedAt:     job.CreatedAt,
+		CompletedAt:   job.CompletedAt,
+	}
+}
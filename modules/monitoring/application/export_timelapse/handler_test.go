@@ -0,0 +1,121 @@
+package exporttimelapse
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+)
+
+type fakeRunner chan uuid.UUID
+
+func (f fakeRunner) RenderTimelapse(_ context.Context, _ string, jobID uuid.UUID) error {
+	f <- jobID
+	return nil
+}
+
+type fakeSigner struct{ member bool }
+
+func (s fakeSigner) CanAccessPage(context.Context, uuid.UUID) (bool, error) { return s.member, nil }
+
+func (s fakeSigner) Sign(_ context.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+	return "https://signed/" + key
+}
+
+func TestExportTimelapseHandler_Start(t *testing.T) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	week := from.Add(7 * 24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		req        StartTimelapseRequest
+		member     bool
+		wantErr    error
+		wantFormat string
+		wantWidth  int
+	}{
+		{name: "defaults to a gif", req: StartTimelapseRequest{From: from, To: week}, member: true, wantFormat: entities.TimelapseGIF, wantWidth: entities.TimelapseDefaultWidth},
+		{name: "strip with options", req: StartTimelapseRequest{From: from, To: week, Format: "strip", Width: 400, DateStamp: true}, member: true, wantFormat: entities.TimelapseStrip, wantWidth: 400},
+		{name: "missing range", req: StartTimelapseRequest{}, member: true, wantErr: ErrInvalidRequest},
+		{name: "range too long", req: StartTimelapseRequest{From: from, To: from.Add(entities.TimelapseMaxRange + time.Hour)}, member: true, wantErr: ErrInvalidRequest},
+		{name: "unknown format", req: StartTimelapseRequest{From: from, To: week, Format: "webm"}, member: true, wantErr: ErrInvalidRequest},
+		{name: "too wide", req: StartTimelapseRequest{From: from, To: week, Width: entities.TimelapseMaxWidth + 1}, member: true, wantErr: ErrInvalidRequest},
+		{name: "not a member", req: StartTimelapseRequest{From: from, To: week}, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mocks.MockTimelapseRepository{}
+			runner := make(fakeRunner, 1)
+			resp, err := NewExportTimelapseHandler(jobs, fakeSigner{member: tt.member}, runner, "tenant").Start(context.Background(), uuid.New(), &tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if jobs.Created != nil {
+					t.Error("job created for an invalid request")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Status != entities.TimelapsePending || resp.Format != tt.wantFormat || resp.Width != tt.wantWidth {
+				t.Fatalf("resp = %+v", resp)
+			}
+			if jobs.Created == nil || jobs.Created.DateStamp != tt.req.DateStamp {
+				t.Fatalf("created = %+v", jobs.Created)
+			}
+			select {
+			case id := <-runner:
+				if id != resp.ID {
+					t.Errorf("runner got job %s, want %s", id, resp.ID)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("job was not run")
+			}
+		})
+	}
+}
+
+func TestExportTimelapseHandler_Get(t *testing.T) {
+	job := entities.NewTimelapseJob(uuid.New(), nil, time.Now().Add(-time.Hour), time.Now(), entities.TimelapseAPNG, 800)
+	job.Complete("page/timelapses/job.png", 12)
+
+	tests := []struct {
+		name    string
+		job     *entities.TimelapseJob
+		member  bool
+		wantErr error
+	}{
+		{name: "signs the result", job: job, member: true},
+		{name: "unknown job", member: true, wantErr: ErrJobNotFound},
+		{name: "not a member", job: job, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mocks.MockTimelapseRepository{GetByIDResult: tt.job}
+			resp, err := NewExportTimelapseHandler(jobs, fakeSigner{member: tt.member}, make(fakeRunner), "tenant").Get(context.Background(), uuid.New())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Status != entities.TimelapseCompleted || resp.Frames != 12 || resp.ResultURL != "https://signed/page/timelapses/job.png" {
+				t.Errorf("resp = %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package exporttimelapse
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartTimelapseRequest selects the screenshots of a time-lapse: the full
+// page's, or one section's when SectionID is set, checked between From and
+// To, and how they are rendered.
+type StartTimelapseRequest struct {
+	From          time.Time  `json:"from"`
+	To            time.Time  `json:"to"`
+	SectionID     *uuid.UUID `json:"section_id,omitempty"`
+	Format        string     `json:"format,omitempty"`         // gif (default), apng or strip
+	Width         int        `json:"width,omitempty"`          // frame width in pixels; default 800
+	DateStamp     bool       `json:"date_stamp,omitempty"`     // stamp each frame with its check date
+	HighlightDiff bool       `json:"highlight_diff,omitempty"` // tint what changed since the previous frame
+}
@@ -0,0 +1,31 @@
+package exporttimelapse
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimelapseJobResponse is a time-lapse job and, once it completed, a signed
+// URL of the rendered image.
+type TimelapseJobResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	PageID        uuid.UUID  `json:"page_id"`
+	SectionID     *uuid.UUID `json:"section_id,omitempty"`
+	From          time.Time  `json:"from"`
+	To            time.Time  `json:"to"`
+	Format        string     `json:"format"`
+	Width         int        `json:"width"`
+	DateStamp     bool       `json:"date_stamp"`
+	HighlightDiff bool       `json:"highlight_diff"`
+	Status        string     `json:"status"`
+	Frames        int        `json:"frames"`
+	ResultURL     string     `json:"result_url,omitempty"`
+	ErrorMessage  string     `json:"error_message,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+type ListTimelapsesResponse struct {
+	Jobs []*TimelapseJobResponse `json:"jobs"`
+}
@@ -0,0 +1,147 @@
+package getcheckdiff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// Output formats for a check's content diff.
+const (
+	FormatJSON    = "json"    // structured block and word operations
+	FormatUnified = "unified" // unified-diff style text
+	FormatHTML    = "html"    // redline fragment with <del>/<ins>
+)
+
+var (
+	ErrCheckNotFound       = errors.New("check not found")
+	ErrNoPreviousCheck     = errors.New("check has no previous successful check to compare with")
+	ErrSnapshotUnavailable = errors.New("html snapshot unavailable")
+	ErrInvalidFormat       = errors.New("invalid diff format")
+	ErrForbidden           = errors.New("not a member of the page's workspace")
+)
+
+// SnapshotReader downloads stored HTML snapshots.
+type SnapshotReader interface {
+	Download(ctx context.Context, key string) ([]byte, error)
+}
+
+// PageAuthorizer tells whether the caller is a member of a page's workspace.
+type PageAuthorizer interface {
+	CanAccessPage(ctx context.Context, pageID uuid.UUID) (bool, error)
+}
+
+// GetCheckDiffHandler compares a check's HTML snapshot with the one before it
+// in the same scope, block by block.
+type GetCheckDiffHandler struct {
+	repo      repositories.CheckRepository
+	snapshots SnapshotReader
+	access    PageAuthorizer
+}
+
+func NewGetCheckDiffHandler(repo repositories.CheckRepository, snapshots SnapshotReader, access PageAuthorizer) *GetCheckDiffHandler {
+	return &GetCheckDiffHandler{repo: repo, snapshots: snapshots, access: access}
+}
+
+func (h *GetCheckDiffHandler) Handle(ctx context.Context, checkID uuid.UUID) (*CheckDiffResponse, error) {
+	check, err := h.repo.GetByID(ctx, checkID)
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		return nil, ErrCheckNotFound
+	}
+	member, err := h.access.CanAccessPage(ctx, check.PageID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrForbidden
+	}
+	prev, err := h.repo.GetPreviousSuccessfulBefore(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		return nil, ErrNoPreviousCheck
+	}
+
+	prevHTML, err := h.download(ctx, prev.HTMLSnapshotURL)
+	if err != nil {
+		return nil, err
+	}
+	currHTML, err := h.download(ctx, check.HTMLSnapshotURL)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := sharedHTML.DiffContentBlocks(sharedHTML.ExtractContentBlocks(prevHTML), sharedHTML.ExtractContentBlocks(currHTML))
+	return &CheckDiffResponse{CheckID: check.ID, PreviousCheckID: prev.ID, Diff: diff}, nil
+}
+
+func (h *GetCheckDiffHandler) download(ctx context.Context, key string) (string, error) {
+	if key == "" || h.snapshots == nil {
+		return "", ErrSnapshotUnavailable
+	}
+	data, err := h.snapshots.Download(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSnapshotUnavailable, err)
+	}
+	return string(data), nil
+}
+
+// HandleHTTP is the HTTP handler for GET /monitoring/checks/{id}/diff.
+// ?format= selects json (default), unified or html.
+func (h *GetCheckDiffHandler) HandleHTTP(w http.ResponseWriter, r *http.Request) {
+	checkID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid check id", http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatUnified && format != FormatHTML {
+		http.Error(w, fmt.Sprintf("%v: %q must be one of json, unified or html", ErrInvalidFormat, format), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Handle(r.Context(), checkID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCheckNotFound), errors.Is(err, ErrNoPreviousCheck):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrSnapshotUnavailable):
+			logger.Warn("Check diff snapshot unavailable", zap.Error(err), zap.String("check_id", checkID.String()))
+			http.Error(w, ErrSnapshotUnavailable.Error(), http.StatusUnprocessableEntity)
+		default:
+			logger.Error("Failed to diff check", zap.Error(err), zap.String("check_id", checkID.String()))
+			http.Error(w, "failed to diff check", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	switch format {
+	case FormatUnified:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(sharedHTML.FormatUnifiedDiff(resp.Diff)))
+	case FormatHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(sharedHTML.FormatHTMLRedline(resp.Diff)))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
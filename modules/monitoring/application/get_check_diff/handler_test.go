@@ -0,0 +1,93 @@
+package getcheckdiff
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+)
+
+type fakeSnapshots map[string]string
+
+func (f fakeSnapshots) Download(_ context.Context, key string) ([]byte, error) {
+	html, ok := f[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return []byte(html), nil
+}
+
+type fakeAccess struct{ member bool }
+
+func (a fakeAccess) CanAccessPage(context.Context, uuid.UUID) (bool, error) { return a.member, nil }
+
+func TestGetCheckDiffHandler_Handle(t *testing.T) {
+	check := &entities.Check{ID: uuid.New(), HTMLSnapshotURL: "curr.html"}
+	prev := &entities.Check{ID: uuid.New(), HTMLSnapshotURL: "prev.html"}
+	snapshots := fakeSnapshots{
+		"prev.html": `<body><h1>Pricing</h1><p>Pro costs $29 per month</p></body>`,
+		"curr.html": `<body><h1>Pricing</h1><p>Pro costs $39 per month</p></body>`,
+	}
+
+	tests := []struct {
+		name    string
+		check   *entities.Check
+		prev    *entities.Check
+		wantErr error
+	}{
+		{name: "diffs against the previous check", check: check, prev: prev},
+		{name: "unknown check", wantErr: ErrCheckNotFound},
+		{name: "first check", check: check, wantErr: ErrNoPreviousCheck},
+		{name: "missing snapshot", check: check, prev: &entities.Check{ID: uuid.New()}, wantErr: ErrSnapshotUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockCheckRepository{GetByIDResult: tt.check, GetPreviousBeforeResult: tt.prev}
+			resp, err := NewGetCheckDiffHandler(repo, snapshots, fakeAccess{member: true}).Handle(context.Background(), uuid.New())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.PreviousCheckID != prev.ID || resp.Diff.Modified != 1 || resp.Diff.Ops[0].Op != sharedHTML.DiffModify {
+				t.Errorf("resp = %+v, diff = %+v", resp, resp.Diff)
+			}
+		})
+	}
+}
+
+func TestGetCheckDiffHandler_HandleHTTP_Forbidden(t *testing.T) {
+	check := &entities.Check{ID: uuid.New(), PageID: uuid.New(), HTMLSnapshotURL: "curr.html"}
+	repo := &mocks.MockCheckRepository{
+		GetByIDResult:           check,
+		GetPreviousBeforeResult: &entities.Check{ID: uuid.New(), HTMLSnapshotURL: "prev.html"},
+	}
+	snapshots := fakeSnapshots{"prev.html": "<p>before</p>", "curr.html": "<p>after</p>"}
+	h := NewGetCheckDiffHandler(repo, snapshots, fakeAccess{member: false})
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", check.ID.String())
+	req := httptest.NewRequest(http.MethodGet, "/monitoring/checks/"+check.ID.String()+"/diff", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rec := httptest.NewRecorder()
+
+	h.HandleHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if body := rec.Body.String(); body != ErrForbidden.Error()+"\n" {
+		t.Errorf("body = %q, want only the forbidden error", body)
+	}
+}
@@ -0,0 +1,12 @@
+package getcheckdiff
+
+import (
+	"github.com/google/uuid"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+)
+
+type CheckDiffResponse struct {
+	CheckID         uuid.UUID               `json:"check_id"`
+	PreviousCheckID uuid.UUID               `json:"previous_check_id"`
+	Diff            *sharedHTML.ContentDiff `json:"diff"`
+}
@@ -37,6 +37,29 @@ func (h *GetMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.UUI
 		}
 	}
 
+	captureSteps := make([]CaptureStepDTO, len(config.CaptureSteps))
+	for i, step := range config.CaptureSteps {
+		captureSteps[i] = CaptureStepDTO(step)
+	}
+
+	captureProfiles := make([]CaptureProfileDTO, len(config.CaptureProfiles))
+	for i, p := range config.CaptureProfiles {
+		captureProfiles[i] = CaptureProfileDTO{
+			Name:              p.Name,
+			ViewportWidth:     p.ViewportWidth,
+			ViewportHeight:    p.ViewportHeight,
+			DeviceScaleFactor: p.DeviceScaleFactor,
+			Mobile:            p.Mobile,
+			UserAgent:         p.UserAgent,
+			Locale:            p.Locale,
+			Timezone:          p.Timezone,
+		}
+		if p.Geolocation != nil {
+			geo := GeolocationDTO(*p.Geolocation)
+			captureProfiles[i].Geolocation = &geo
+		}
+	}
+
 	return &GetMonitoringConfigResponse{
 		ID:                     config.ID,
 		PageID:                 config.PageID,
@@ -51,6 +74,17 @@ func (h *GetMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.UUI
 		CSSSelector:            config.CSSSelector,
 		XPathSelector:          config.XPathSelector,
 		SelectorOffsets:        selectorOffsetsDTO,
+		SelectorFallback:       config.SelectorFallback,
+		SelectorBrokenAt:       config.SelectorHealth.BrokenAt,
+		CheckBrokenLinks:       config.CheckBrokenLinks,
+		CaptureSteps:           captureSteps,
+		CaptureProfiles:        captureProfiles,
+		ProxyRegion:            config.ProxyRoute.Region,
+		ProxyPool:              config.ProxyRoute.Pool,
+		FetchEngine:            config.FetchEngineOrDefault(),
+		SuppressRecurrences:    config.SuppressRecurrences,
+		LegalHold:              config.LegalHold,
+		Archive:                config.Archive,
 		CreatedAt:              config.CreatedAt,
 		UpdatedAt:              config.UpdatedAt,
 	}, nil
@@ -13,6 +13,31 @@ type SelectorOffsetsDTO struct {
 	Left   int `json:"left"`
 }
 
+type CaptureStepDTO struct {
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+	Value    string `json:"value,omitempty"`
+	WaitMs   int    `json:"wait_ms,omitempty"`
+}
+
+type GeolocationDTO struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"`
+}
+
+type CaptureProfileDTO struct {
+	Name              string          `json:"name"`
+	ViewportWidth     int             `json:"viewport_width,omitempty"`
+	ViewportHeight    int             `json:"viewport_height,omitempty"`
+	DeviceScaleFactor float64         `json:"device_scale_factor,omitempty"`
+	Mobile            bool            `json:"mobile,omitempty"`
+	UserAgent         string          `json:"user_agent,omitempty"`
+	Locale            string          `json:"locale,omitempty"`
+	Timezone          string          `json:"timezone,omitempty"`
+	Geolocation       *GeolocationDTO `json:"geolocation,omitempty"`
+}
+
 type GetMonitoringConfigResponse struct {
 	ID                     uuid.UUID           `json:"id"`
 	PageID                 uuid.UUID           `json:"page_id"`
@@ -27,6 +52,17 @@ type GetMonitoringConfigResponse struct {
 	CSSSelector            string              `json:"css_selector"`
 	XPathSelector          string              `json:"xpath_selector"`
 	SelectorOffsets        *SelectorOffsetsDTO `json:"selector_offsets,omitempty"`
+	SelectorFallback       bool                `json:"selector_fallback"`
+	SelectorBrokenAt       *time.Time          `json:"selector_broken_at,omitempty"`
+	CheckBrokenLinks       bool                `json:"check_broken_links"`
+	CaptureSteps           []CaptureStepDTO    `json:"capture_steps"`
+	CaptureProfiles        []CaptureProfileDTO `json:"capture_profiles"`
+	ProxyRegion            string              `json:"proxy_region"`
+	ProxyPool              string              `json:"proxy_pool"`
+	FetchEngine            string              `json:"fetch_engine"`
+	SuppressRecurrences    bool                `json:"suppress_recurrences"`
+	LegalHold              bool                `json:"legal_hold"`
+	Archive                bool                `json:"archive"`
 	CreatedAt              time.Time           `json:"created_at"`
 	UpdatedAt              time.Time           `json:"updated_at"`
 }
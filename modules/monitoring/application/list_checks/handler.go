@@ -3,6 +3,7 @@ package listchecks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -13,16 +14,36 @@ import (
 	"go.uber.org/zap"
 )
 
+var ErrForbidden = errors.New("not a member of the page's workspace")
+
+// SnapshotSigner issues short-lived URLs for stored snapshots to members of
+// the page's workspace.
+type SnapshotSigner interface {
+	CanAccessPage(ctx context.Context, pageID uuid.UUID) (bool, error)
+	Sign(ctx context.Context, key string) string
+}
+
 type ListChecksHandler struct {
-	repo repositories.CheckRepository
+	repo   repositories.CheckRepository
+	signer SnapshotSigner
 }
 
 func NewListChecksHandler(repo repositories.CheckRepository) *ListChecksHandler {
 	return &ListChecksHandler{repo: repo}
 }
 
+// NewListChecksHandlerWithSigner creates a handler that only lists checks to
+// members of the page's workspace and replaces snapshot keys with signed URLs.
+func NewListChecksHandlerWithSigner(repo repositories.CheckRepository, signer SnapshotSigner) *ListChecksHandler {
+	return &ListChecksHandler{repo: repo, signer: signer}
+}
+
 func (h *ListChecksHandler) Handle(ctx context.Context, pageID uuid.UUID) (*ListChecksResponse, error) {
-	// Fetch parent checks (section_id IS NULL).
+	if err := h.authorize(ctx, pageID); err != nil {
+		return nil, err
+	}
+
+	// Fetch parent checks (section_id and profile_name IS NULL).
 	parentChecks, err := h.repo.ListByPage(ctx, pageID)
 	if err != nil {
 		return nil, err
@@ -41,32 +62,86 @@ func (h *ListChecksHandler) Handle(ctx context.Context, pageID uuid.UUID) (*List
 		}
 	}
 
-	return buildResponseWithSections(parentChecks, sectionsByParent), nil
+	// Fetch all capture profile checks for the page and group them the same way.
+	profileChecks, err := h.repo.ListProfileChecksByPage(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	profilesByParent := make(map[uuid.UUID][]*entities.Check)
+	for _, pc := range profileChecks {
+		if pc.ParentCheckID != nil {
+			profilesByParent[*pc.ParentCheckID] = append(profilesByParent[*pc.ParentCheckID], pc)
+		}
+	}
+
+	return h.sign(ctx, buildResponseWithChildren(parentChecks, sectionsByParent, profilesByParent)), nil
 }
 
 // HandleBySection returns checks filtered by section. sectionID nil means full-page checks only.
 func (h *ListChecksHandler) HandleBySection(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID) (*ListChecksResponse, error) {
+	if err := h.authorize(ctx, pageID); err != nil {
+		return nil, err
+	}
+
 	checks, err := h.repo.ListByPageAndSection(ctx, pageID, sectionID)
 	if err != nil {
 		return nil, err
 	}
 
-	return buildResponse(checks), nil
+	return h.sign(ctx, buildResponse(checks)), nil
 }
 
-func toCheckResponse(check *entities.Check) *CheckResponse {
+func (h *ListChecksHandler) authorize(ctx context.Context, pageID uuid.UUID) error {
+	if h.signer == nil {
+		return nil
+	}
+	ok, err := h.signer.CanAccessPage(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (h *ListChecksHandler) sign(ctx context.Context, resp *ListChecksResponse) *ListChecksResponse {
+	if h.signer != nil {
+		for _, check := range resp.Checks {
+			check.SignURLs(ctx, h.signer)
+		}
+	}
+	return resp
+}
+
+// NewCheckResponse converts a check to the DTO returned by the API and pushed
+// to SSE subscribers. Snapshot URLs are left as stored keys; see SignURLs.
+func NewCheckResponse(check *entities.Check) *CheckResponse {
 	return &CheckResponse{
-		ID:              check.ID,
-		PageID:          check.PageID,
-		SectionID:       check.SectionID,
-		ParentCheckID:   check.ParentCheckID,
-		Status:          check.Status,
-		ScreenshotURL:   check.ScreenshotURL,
-		HTMLSnapshotURL: check.HTMLSnapshotURL,
-		ChangeDetected:  check.ChangeDetected,
-		ChangeType:      check.ChangeType,
-		ErrorMessage:    check.ErrorMessage,
-		CheckedAt:       check.CheckedAt,
+		ID:               check.ID,
+		PageID:           check.PageID,
+		SectionID:        check.SectionID,
+		ParentCheckID:    check.ParentCheckID,
+		ProfileName:      check.ProfileName,
+		ProxyID:          check.ProxyID,
+		FetchEngine:      check.FetchEngine,
+		SelectorFallback: check.SelectorFallback,
+		Status:           check.Status,
+		ScreenshotURL:    check.ScreenshotURL,
+		HTMLSnapshotURL:  check.HTMLSnapshotURL,
+		DocumentURL:      check.DocumentURL,
+		ThumbnailURL:     check.ThumbnailURL,
+		PreviewURL:       check.PreviewURL,
+		ArchiveURL:       check.ArchiveURL,
+		ChangeDetected:   check.ChangeDetected,
+		ChangeType:       check.ChangeType,
+		Recurrence:       check.Recurrence,
+		Flapping:         check.Flapping,
+		Pinned:           check.Pinned,
+		PurgedAt:         check.PurgedAt,
+		ErrorMessage:     check.ErrorMessage,
+		CheckedAt:        check.CheckedAt,
 	}
 }
 
@@ -75,21 +150,27 @@ func buildResponse(checks []*entities.Check) *ListChecksResponse {
 		Checks: make([]*CheckResponse, len(checks)),
 	}
 	for i, check := range checks {
-		response.Checks[i] = toCheckResponse(check)
+		response.Checks[i] = NewCheckResponse(check)
 	}
 	return response
 }
 
-func buildResponseWithSections(parentChecks []*entities.Check, sectionsByParent map[uuid.UUID][]*entities.Check) *ListChecksResponse {
+func buildResponseWithChildren(parentChecks []*entities.Check, sectionsByParent, profilesByParent map[uuid.UUID][]*entities.Check) *ListChecksResponse {
 	response := &ListChecksResponse{
 		Checks: make([]*CheckResponse, len(parentChecks)),
 	}
 	for i, check := range parentChecks {
-		cr := toCheckResponse(check)
+		cr := NewCheckResponse(check)
 		if sections, ok := sectionsByParent[check.ID]; ok {
 			cr.Sections = make([]*CheckResponse, len(sections))
 			for j, sc := range sections {
-				cr.Sections[j] = toCheckResponse(sc)
+				cr.Sections[j] = NewCheckResponse(sc)
+			}
+		}
+		if profiles, ok := profilesByParent[check.ID]; ok {
+			cr.Profiles = make([]*CheckResponse, len(profiles))
+			for j, pc := range profiles {
+				cr.Profiles[j] = NewCheckResponse(pc)
 			}
 		}
 		response.Checks[i] = cr
@@ -116,6 +197,10 @@ func (h *ListChecksHandler) HandleHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		resp, err = h.HandleBySection(r.Context(), pageID, &sectionID)
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		if err != nil {
 			logger.Error("Failed to list checks by section", zap.Error(err))
 			http.Error(w, "Failed to list checks", http.StatusInternalServerError)
@@ -123,6 +208,10 @@ func (h *ListChecksHandler) HandleHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		resp, err = h.Handle(r.Context(), pageID)
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		if err != nil {
 			logger.Error("Failed to list checks", zap.Error(err))
 			http.Error(w, "Failed to list checks", http.StatusInternalServerError)
@@ -73,3 +73,72 @@ func TestListChecksHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestListChecksHandler_HandleGroupsProfiles(t *testing.T) {
+	pageID := uuid.New()
+	parent := &entities.Check{ID: uuid.New(), PageID: pageID, Status: "success", CheckedAt: time.Now()}
+	mobile := &entities.Check{ID: uuid.New(), PageID: pageID, ParentCheckID: &parent.ID, ProfileName: "mobile", Status: "success", CheckedAt: time.Now()}
+
+	repo := &mocks.MockCheckRepository{
+		ListByPageResult:              []*entities.Check{parent},
+		ListProfileChecksByPageResult: []*entities.Check{mobile},
+	}
+
+	resp, err := NewListChecksHandler(repo).Handle(context.Background(), pageID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Checks) != 1 {
+		t.Fatalf("checks count: want 1, got %d", len(resp.Checks))
+	}
+	profiles := resp.Checks[0].Profiles
+	if len(profiles) != 1 || profiles[0].ProfileName != "mobile" {
+		t.Errorf("profiles = %+v, want the mobile check", profiles)
+	}
+	if len(resp.Checks[0].Sections) != 0 {
+		t.Errorf("profile check was grouped as a section")
+	}
+}
+
+type fakeSigner struct{ member bool }
+
+func (s fakeSigner) CanAccessPage(context.Context, uuid.UUID) (bool, error) { return s.member, nil }
+
+func (s fakeSigner) Sign(_ context.Context, key string) string {
+	if key == "" {
+		return ""
+	}
+	return "https://signed/" + key
+}
+
+func TestListChecksHandler_SignsSnapshotURLs(t *testing.T) {
+	pageID := uuid.New()
+	parent := &entities.Check{ID: uuid.New(), PageID: pageID, Status: "success", ScreenshotURL: "p/1.png", HTMLSnapshotURL: "p/1.html", ThumbnailURL: "p/t.jpg", ArchiveURL: "p/1.mhtml", CheckedAt: time.Now()}
+	section := &entities.Check{ID: uuid.New(), PageID: pageID, ParentCheckID: &parent.ID, SectionID: &pageID, Status: "success", ScreenshotURL: "p/sections/s/1.png", CheckedAt: time.Now()}
+	repo := &mocks.MockCheckRepository{
+		ListByPageResult:              []*entities.Check{parent},
+		ListSectionChecksByPageResult: []*entities.Check{section},
+	}
+
+	resp, err := NewListChecksHandlerWithSigner(repo, fakeSigner{member: true}).Handle(context.Background(), pageID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := resp.Checks[0]
+	if got.ScreenshotURL != "https://signed/p/1.png" || got.HTMLSnapshotURL != "https://signed/p/1.html" || got.DocumentURL != "" {
+		t.Errorf("parent URLs = %q, %q, %q", got.ScreenshotURL, got.HTMLSnapshotURL, got.DocumentURL)
+	}
+	if got.ThumbnailURL != "https://signed/p/t.jpg" || got.PreviewURL != "" {
+		t.Errorf("derivative URLs = %q, %q", got.ThumbnailURL, got.PreviewURL)
+	}
+	if got.ArchiveURL != "https://signed/p/1.mhtml" {
+		t.Errorf("archive URL = %q", got.ArchiveURL)
+	}
+	if len(got.Sections) != 1 || got.Sections[0].ScreenshotURL != "https://signed/p/sections/s/1.png" {
+		t.Errorf("section URLs not signed: %+v", got.Sections)
+	}
+
+	if _, err := NewListChecksHandlerWithSigner(repo, fakeSigner{}).Handle(context.Background(), pageID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("non-member err = %v, want ErrForbidden", err)
+	}
+}
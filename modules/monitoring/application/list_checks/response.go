@@ -1,24 +1,55 @@
 package listchecks
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CheckResponse struct {
-	ID              uuid.UUID        `json:"id"`
-	PageID          uuid.UUID        `json:"page_id"`
-	SectionID       *uuid.UUID       `json:"section_id,omitempty"`
-	ParentCheckID   *uuid.UUID       `json:"parent_check_id,omitempty"`
-	Status          string           `json:"status"`
-	ScreenshotURL   string           `json:"screenshot_url"`
-	HTMLSnapshotURL string           `json:"html_snapshot_url"`
-	ChangeDetected  bool             `json:"change_detected"`
-	ChangeType      string           `json:"change_type"`
-	ErrorMessage    string           `json:"error_message,omitempty"`
-	CheckedAt       time.Time        `json:"checked_at"`
-	Sections        []*CheckResponse `json:"sections,omitempty"`
+	ID               uuid.UUID        `json:"id"`
+	PageID           uuid.UUID        `json:"page_id"`
+	SectionID        *uuid.UUID       `json:"section_id,omitempty"`
+	ParentCheckID    *uuid.UUID       `json:"parent_check_id,omitempty"`
+	ProfileName      string           `json:"profile_name,omitempty"`
+	ProxyID          *uuid.UUID       `json:"proxy_id,omitempty"`
+	FetchEngine      string           `json:"fetch_engine,omitempty"`
+	SelectorFallback bool             `json:"selector_fallback,omitempty"`
+	Status           string           `json:"status"`
+	ScreenshotURL    string           `json:"screenshot_url"`
+	HTMLSnapshotURL  string           `json:"html_snapshot_url"`
+	DocumentURL      string           `json:"document_url,omitempty"`
+	ThumbnailURL     string           `json:"thumbnail_url,omitempty"` // small compressed screenshot for lists
+	PreviewURL       string           `json:"preview_url,omitempty"`   // medium compressed screenshot
+	ArchiveURL       string           `json:"archive_url,omitempty"`   // MHTML archive of the page
+	ChangeDetected   bool             `json:"change_detected"`
+	ChangeType       string           `json:"change_type"`
+	Recurrence       bool             `json:"recurrence,omitempty"` // change back to a recently seen content state
+	Flapping         bool             `json:"flapping,omitempty"`   // content alternates between a few states
+	Pinned           bool             `json:"pinned,omitempty"`     // baseline kept by retention purges
+	PurgedAt         *time.Time       `json:"purged_at,omitempty"`  // stored captures were deleted by retention
+	ErrorMessage     string           `json:"error_message,omitempty"`
+	CheckedAt        time.Time        `json:"checked_at"`
+	Sections         []*CheckResponse `json:"sections,omitempty"`
+	Profiles         []*CheckResponse `json:"profiles,omitempty"`
+}
+
+// SignURLs replaces the stored snapshot keys of the check and its section and
+// profile checks with signed URLs.
+func (c *CheckResponse) SignURLs(ctx context.Context, signer SnapshotSigner) {
+	c.ScreenshotURL = signer.Sign(ctx, c.ScreenshotURL)
+	c.HTMLSnapshotURL = signer.Sign(ctx, c.HTMLSnapshotURL)
+	c.DocumentURL = signer.Sign(ctx, c.DocumentURL)
+	c.ThumbnailURL = signer.Sign(ctx, c.ThumbnailURL)
+	c.PreviewURL = signer.Sign(ctx, c.PreviewURL)
+	c.ArchiveURL = signer.Sign(ctx, c.ArchiveURL)
+	for _, child := range c.Sections {
+		child.SignURLs(ctx, signer)
+	}
+	for _, child := range c.Profiles {
+		child.SignURLs(ctx, signer)
+	}
 }
 
 type ListChecksResponse struct {
@@ -0,0 +1,238 @@
+package managecredentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"github.com/jcsoftdev/pulzifi-back/shared/secrets"
+	"go.uber.org/zap"
+)
+
+// ErrCredentialNotFound is returned when a page has no credential.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// ManageCredentialsHandler reads, replaces and removes the credential a page is
+// monitored with. Secrets are encrypted with the tenant's key before they are
+// stored and never leave this handler in a response.
+type ManageCredentialsHandler struct {
+	repo   repositories.PageCredentialRepository
+	cipher entities.SecretCipher
+	tenant string
+}
+
+// NewManageCredentialsHandler creates a new handler scoped to tenant.
+func NewManageCredentialsHandler(repo repositories.PageCredentialRepository, cipher entities.SecretCipher, tenant string) *ManageCredentialsHandler {
+	return &ManageCredentialsHandler{
+		repo:   repo,
+		cipher: cipher,
+		tenant: tenant,
+	}
+}
+
+// Get returns the page's credential metadata.
+func (h *ManageCredentialsHandler) Get(ctx context.Context, pageID uuid.UUID) (*CredentialResponse, error) {
+	credential, err := h.repo.GetByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, ErrCredentialNotFound
+	}
+
+	stored, err := credential.OpenSecrets(h.cipher, h.tenant)
+	if err != nil {
+		// Still describe the credential; the flags just can't be filled in.
+		logger.Warn("Failed to decrypt page credential", zap.Error(err), zap.String("page_id", pageID.String()))
+		stored = &entities.CredentialSecrets{}
+	}
+	return toCredentialResponse(credential, stored), nil
+}
+
+// Save validates, encrypts and stores the page's credential.
+func (h *ManageCredentialsHandler) Save(ctx context.Context, pageID uuid.UUID, req *SaveCredentialRequest) (*CredentialResponse, error) {
+	incoming := &entities.CredentialSecrets{
+		Headers:  req.Headers,
+		Username: req.Username,
+		Password: req.Password,
+	}
+	for _, c := range req.Cookies {
+		incoming.Cookies = append(incoming.Cookies, entities.CredentialCookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path})
+	}
+	var script *entities.LoginScript
+	if req.LoginScript != nil {
+		script = &entities.LoginScript{
+			URL:              req.LoginScript.URL,
+			UsernameSelector: req.LoginScript.UsernameSelector,
+			PasswordSelector: req.LoginScript.PasswordSelector,
+			SubmitSelector:   req.LoginScript.SubmitSelector,
+			SuccessSelector:  req.LoginScript.SuccessSelector,
+		}
+	}
+
+	existing, err := h.repo.GetByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.AuthType == req.AuthType {
+		stored, err := existing.OpenSecrets(h.cipher, h.tenant)
+		if err != nil {
+			return nil, err
+		}
+		keepStoredSecrets(incoming, stored)
+	}
+
+	if err := entities.ValidateCredential(req.AuthType, script, incoming); err != nil {
+		return nil, err
+	}
+
+	credential := entities.NewPageCredential(pageID, req.AuthType)
+	if req.AuthType == entities.AuthTypeLoginScript {
+		credential.LoginScript = script
+	}
+	if err := credential.SealSecrets(h.cipher, h.tenant, incoming); err != nil {
+		return nil, err
+	}
+	if err := h.repo.Upsert(ctx, credential); err != nil {
+		return nil, err
+	}
+	return toCredentialResponse(credential, incoming), nil
+}
+
+// Delete removes the page's credential; checks go back to anonymous access.
+func (h *ManageCredentialsHandler) Delete(ctx context.Context, pageID uuid.UUID) error {
+	return h.repo.DeleteByPageID(ctx, pageID)
+}
+
+// keepStoredSecrets fills secret values the client left empty from the stored ones.
+func keepStoredSecrets(incoming, stored *entities.CredentialSecrets) {
+	for name, value := range incoming.Headers {
+		if value == "" {
+			incoming.Headers[name] = stored.Headers[name]
+		}
+	}
+	storedCookies := make(map[string]string, len(stored.Cookies))
+	for _, c := range stored.Cookies {
+		storedCookies[c.Name] = c.Value
+	}
+	for i, c := range incoming.Cookies {
+		if c.Value == "" {
+			incoming.Cookies[i].Value = storedCookies[c.Name]
+		}
+	}
+	if incoming.Username == "" {
+		incoming.Username = stored.Username
+	}
+	if incoming.Password == "" {
+		incoming.Password = stored.Password
+	}
+}
+
+// HandleGetHTTP is the HTTP handler for GET /credentials/page/{pageId}
+func (h *ManageCredentialsHandler) HandleGetHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Get(r.Context(), pageID)
+	if err != nil {
+		if errors.Is(err, ErrCredentialNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to get page credential", zap.Error(err))
+		http.Error(w, "failed to get credential", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleSaveHTTP is the HTTP handler for PUT /credentials/page/{pageId}
+func (h *ManageCredentialsHandler) HandleSaveHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	var req SaveCredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Save(r.Context(), pageID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidCredential):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, secrets.ErrNoKey):
+			http.Error(w, "credential encryption is not configured", http.StatusServiceUnavailable)
+		default:
+			logger.Error("Failed to save page credential", zap.Error(err))
+			http.Error(w, "failed to save credential", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleDeleteHTTP is the HTTP handler for DELETE /credentials/page/{pageId}
+func (h *ManageCredentialsHandler) HandleDeleteHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Delete(r.Context(), pageID); err != nil {
+		logger.Error("Failed to delete page credential", zap.Error(err))
+		http.Error(w, "failed to delete credential", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toCredentialResponse(c *entities.PageCredential, stored *entities.CredentialSecrets) *CredentialResponse {
+	resp := &CredentialResponse{
+		PageID:          c.PageID,
+		AuthType:        c.AuthType,
+		HeaderNames:     c.HeaderNames,
+		CookieNames:     c.CookieNames,
+		HasUsername:     stored.Username != "",
+		HasPassword:     stored.Password != "",
+		LastLoginStatus: c.LastLoginStatus,
+		LastLoginError:  c.LastLoginError,
+		LastLoginAt:     c.LastLoginAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+	if resp.HeaderNames == nil {
+		resp.HeaderNames = []string{}
+	}
+	if resp.CookieNames == nil {
+		resp.CookieNames = []string{}
+	}
+	if c.LoginScript != nil {
+		resp.LoginScript = &LoginScriptDTO{
+			URL:              c.LoginScript.URL,
+			UsernameSelector: c.LoginScript.UsernameSelector,
+			PasswordSelector: c.LoginScript.PasswordSelector,
+			SubmitSelector:   c.LoginScript.SubmitSelector,
+			SuccessSelector:  c.LoginScript.SuccessSelector,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,144 @@
+package managecredentials
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+	"github.com/jcsoftdev/pulzifi-back/shared/secrets"
+)
+
+const testTenant = "tenant_test"
+
+func newTestVault(t *testing.T) *secrets.Vault {
+	t.Helper()
+	v, err := secrets.NewVault(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	if err != nil {
+		t.Fatalf("NewVault: %v", err)
+	}
+	return v
+}
+
+func TestManageCredentialsHandler_Save(t *testing.T) {
+	pageID := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     *SaveCredentialRequest
+		wantErr error
+	}{
+		{
+			name: "headers",
+			req:  &SaveCredentialRequest{AuthType: "headers", Headers: map[string]string{"Authorization": "Bearer s3cret"}},
+		},
+		{
+			name: "basic auth",
+			req:  &SaveCredentialRequest{AuthType: "basic", Username: "monitor", Password: "s3cret"},
+		},
+		{
+			name: "login script",
+			req: &SaveCredentialRequest{
+				AuthType: "login_script",
+				Username: "monitor@example.com",
+				Password: "s3cret",
+				LoginScript: &LoginScriptDTO{
+					URL:              "https://example.com/login",
+					UsernameSelector: "#email",
+					PasswordSelector: "#password",
+					SubmitSelector:   "button[type=submit]",
+				},
+			},
+		},
+		{
+			name:    "login script without selectors",
+			req:     &SaveCredentialRequest{AuthType: "login_script", Username: "u", Password: "p", LoginScript: &LoginScriptDTO{URL: "https://example.com/login"}},
+			wantErr: entities.ErrInvalidCredential,
+		},
+		{
+			name:    "unknown auth type",
+			req:     &SaveCredentialRequest{AuthType: "kerberos"},
+			wantErr: entities.ErrInvalidCredential,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockPageCredentialRepository{}
+			h := NewManageCredentialsHandler(repo, newTestVault(t), testTenant)
+
+			resp, err := h.Save(context.Background(), pageID, tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Save() err = %v, want %v", err, tt.wantErr)
+				}
+				if repo.UpsertCalls != 0 {
+					t.Error("invalid credential was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Save() unexpected error: %v", err)
+			}
+
+			if strings.Contains(repo.Upserted.SecretCiphertext, "s3cret") {
+				t.Error("secret stored in plaintext")
+			}
+			body, _ := json.Marshal(resp)
+			if strings.Contains(string(body), "s3cret") {
+				t.Errorf("response leaks a secret: %s", body)
+			}
+		})
+	}
+}
+
+func TestManageCredentialsHandler_SaveKeepsStoredSecrets(t *testing.T) {
+	pageID := uuid.New()
+	vault := newTestVault(t)
+
+	existing := entities.NewPageCredential(pageID, entities.AuthTypeBasic)
+	if err := existing.SealSecrets(vault, testTenant, &entities.CredentialSecrets{Username: "monitor", Password: "old-secret"}); err != nil {
+		t.Fatalf("SealSecrets: %v", err)
+	}
+	repo := &mocks.MockPageCredentialRepository{GetByPageIDResult: existing}
+	h := NewManageCredentialsHandler(repo, vault, testTenant)
+
+	resp, err := h.Save(context.Background(), pageID, &SaveCredentialRequest{AuthType: "basic", Username: "renamed"})
+	if err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	if !resp.HasPassword {
+		t.Error("expected stored password to be kept")
+	}
+
+	stored, err := repo.Upserted.OpenSecrets(vault, testTenant)
+	if err != nil {
+		t.Fatalf("OpenSecrets: %v", err)
+	}
+	if stored.Username != "renamed" || stored.Password != "old-secret" {
+		t.Errorf("stored secrets = %+v", stored)
+	}
+}
+
+func TestManageCredentialsHandler_SaveWithoutKey(t *testing.T) {
+	vault, _ := secrets.NewVault("")
+	h := NewManageCredentialsHandler(&mocks.MockPageCredentialRepository{}, vault, testTenant)
+
+	_, err := h.Save(context.Background(), uuid.New(), &SaveCredentialRequest{AuthType: "basic", Username: "u", Password: "p"})
+	if !errors.Is(err, secrets.ErrNoKey) {
+		t.Fatalf("Save() err = %v, want ErrNoKey", err)
+	}
+}
+
+func TestManageCredentialsHandler_GetNotFound(t *testing.T) {
+	h := NewManageCredentialsHandler(&mocks.MockPageCredentialRepository{}, newTestVault(t), testTenant)
+
+	if _, err := h.Get(context.Background(), uuid.New()); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("Get() err = %v, want ErrCredentialNotFound", err)
+	}
+}
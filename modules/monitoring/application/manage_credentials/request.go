@@ -0,0 +1,30 @@
+package managecredentials
+
+// CookieDTO is a cookie to set before the page loads.
+type CookieDTO struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// LoginScriptDTO describes the form login performed before each check.
+type LoginScriptDTO struct {
+	URL              string `json:"url"`
+	UsernameSelector string `json:"username_selector"`
+	PasswordSelector string `json:"password_selector"`
+	SubmitSelector   string `json:"submit_selector"`
+	SuccessSelector  string `json:"success_selector,omitempty"`
+}
+
+// SaveCredentialRequest replaces the credential of a page.
+// Secret values left empty keep their stored value when the auth type is
+// unchanged, so clients can edit a credential without re-entering every secret.
+type SaveCredentialRequest struct {
+	AuthType    string            `json:"auth_type"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Cookies     []CookieDTO       `json:"cookies,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Password    string            `json:"password,omitempty"`
+	LoginScript *LoginScriptDTO   `json:"login_script,omitempty"`
+}
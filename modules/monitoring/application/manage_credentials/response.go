@@ -0,0 +1,22 @@
+package managecredentials
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CredentialResponse describes a page credential without any secret values.
+type CredentialResponse struct {
+	PageID          uuid.UUID       `json:"page_id"`
+	AuthType        string          `json:"auth_type"`
+	HeaderNames     []string        `json:"header_names"`
+	CookieNames     []string        `json:"cookie_names"`
+	HasUsername     bool            `json:"has_username"`
+	HasPassword     bool            `json:"has_password"`
+	LoginScript     *LoginScriptDTO `json:"login_script,omitempty"`
+	LastLoginStatus string          `json:"last_login_status,omitempty"`
+	LastLoginError  string          `json:"last_login_error,omitempty"`
+	LastLoginAt     *time.Time      `json:"last_login_at,omitempty"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,307 @@
+package manageproxies
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"github.com/jcsoftdev/pulzifi-back/shared/secrets"
+	"go.uber.org/zap"
+)
+
+// ErrProxyNotFound is returned when a proxy does not exist.
+var ErrProxyNotFound = errors.New("proxy not found")
+
+// ManageProxiesHandler maintains the tenant's proxy registry and the default
+// proxy route of each workspace. Proxy passwords are encrypted with the
+// tenant's key and never returned.
+type ManageProxiesHandler struct {
+	repo   repositories.ProxyRepository
+	cipher entities.SecretCipher
+	tenant string
+}
+
+// NewManageProxiesHandler creates a new handler scoped to tenant.
+func NewManageProxiesHandler(repo repositories.ProxyRepository, cipher entities.SecretCipher, tenant string) *ManageProxiesHandler {
+	return &ManageProxiesHandler{
+		repo:   repo,
+		cipher: cipher,
+		tenant: tenant,
+	}
+}
+
+// List returns every proxy with its health state.
+func (h *ManageProxiesHandler) List(ctx context.Context) (*ListProxiesResponse, error) {
+	proxies, err := h.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &ListProxiesResponse{Proxies: make([]*ProxyResponse, len(proxies))}
+	for i, p := range proxies {
+		resp.Proxies[i] = toProxyResponse(p, now)
+	}
+	return resp, nil
+}
+
+// Create validates and stores a new proxy.
+func (h *ManageProxiesHandler) Create(ctx context.Context, req *SaveProxyRequest) (*ProxyResponse, error) {
+	proxy := entities.NewProxy(req.Name, req.Protocol, req.Host, req.Port)
+	applyRequest(proxy, req)
+
+	if err := entities.ValidateProxy(proxy, req.Password != ""); err != nil {
+		return nil, err
+	}
+	if err := proxy.SealPassword(h.cipher, h.tenant, req.Password); err != nil {
+		return nil, err
+	}
+	if err := h.repo.Create(ctx, proxy); err != nil {
+		return nil, err
+	}
+	return toProxyResponse(proxy, time.Now()), nil
+}
+
+// Update replaces a proxy's settings. Saving a proxy clears its failure
+// streak and quarantine, so a fixed proxy goes straight back into rotation.
+func (h *ManageProxiesHandler) Update(ctx context.Context, id uuid.UUID, req *SaveProxyRequest) (*ProxyResponse, error) {
+	proxy, err := h.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if proxy == nil {
+		return nil, ErrProxyNotFound
+	}
+
+	keepPassword := req.Password == "" && req.Username == proxy.Username && proxy.PasswordCiphertext != ""
+	proxy.Name = req.Name
+	proxy.Protocol = req.Protocol
+	proxy.Host = req.Host
+	proxy.Port = req.Port
+	applyRequest(proxy, req)
+
+	if err := entities.ValidateProxy(proxy, keepPassword || req.Password != ""); err != nil {
+		return nil, err
+	}
+	if !keepPassword {
+		if err := proxy.SealPassword(h.cipher, h.tenant, req.Password); err != nil {
+			return nil, err
+		}
+	}
+
+	proxy.ConsecutiveFailures = 0
+	proxy.QuarantinedUntil = nil
+	proxy.LastError = ""
+	if err := h.repo.Update(ctx, proxy); err != nil {
+		return nil, err
+	}
+	return toProxyResponse(proxy, time.Now()), nil
+}
+
+// Delete removes a proxy. Past checks keep their history without the link.
+func (h *ManageProxiesHandler) Delete(ctx context.Context, id uuid.UUID) error {
+	return h.repo.Delete(ctx, id)
+}
+
+// GetWorkspaceRoute returns the workspace's default proxy route.
+func (h *ManageProxiesHandler) GetWorkspaceRoute(ctx context.Context, workspaceID uuid.UUID) (*WorkspaceProxyRouteDTO, error) {
+	route, err := h.repo.GetWorkspaceRoute(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if route == nil {
+		return &WorkspaceProxyRouteDTO{}, nil
+	}
+	return &WorkspaceProxyRouteDTO{ProxyRegion: route.Region, ProxyPool: route.Pool}, nil
+}
+
+// SetWorkspaceRoute replaces the workspace's default proxy route.
+func (h *ManageProxiesHandler) SetWorkspaceRoute(ctx context.Context, workspaceID uuid.UUID, req *WorkspaceProxyRouteDTO) (*WorkspaceProxyRouteDTO, error) {
+	route := entities.ProxyRoute{Region: req.ProxyRegion, Pool: req.ProxyPool}
+	if err := entities.ValidateProxyRoute(route); err != nil {
+		return nil, err
+	}
+	if err := h.repo.SetWorkspaceRoute(ctx, workspaceID, route); err != nil {
+		return nil, err
+	}
+	return &WorkspaceProxyRouteDTO{ProxyRegion: route.Region, ProxyPool: route.Pool}, nil
+}
+
+func applyRequest(proxy *entities.Proxy, req *SaveProxyRequest) {
+	proxy.Username = req.Username
+	proxy.Region = req.Region
+	proxy.Pool = req.Pool
+	proxy.Enabled = req.Enabled == nil || *req.Enabled
+}
+
+// HandleListHTTP is the HTTP handler for GET /proxies
+func (h *ManageProxiesHandler) HandleListHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.List(r.Context())
+	if err != nil {
+		logger.Error("Failed to list proxies", zap.Error(err))
+		http.Error(w, "failed to list proxies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleCreateHTTP is the HTTP handler for POST /proxies
+func (h *ManageProxiesHandler) HandleCreateHTTP(w http.ResponseWriter, r *http.Request) {
+	var req SaveProxyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Create(r.Context(), &req)
+	if err != nil {
+		writeSaveError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleUpdateHTTP is the HTTP handler for PUT /proxies/{id}
+func (h *ManageProxiesHandler) HandleUpdateHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid proxy id", http.StatusBadRequest)
+		return
+	}
+
+	var req SaveProxyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Update(r.Context(), id, &req)
+	if err != nil {
+		writeSaveError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleDeleteHTTP is the HTTP handler for DELETE /proxies/{id}
+func (h *ManageProxiesHandler) HandleDeleteHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid proxy id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Delete(r.Context(), id); err != nil {
+		logger.Error("Failed to delete proxy", zap.Error(err))
+		http.Error(w, "failed to delete proxy", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetWorkspaceRouteHTTP is the HTTP handler for GET /proxies/workspace/{workspaceId}
+func (h *ManageProxiesHandler) HandleGetWorkspaceRouteHTTP(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := uuid.Parse(chi.URLParam(r, "workspaceId"))
+	if err != nil {
+		http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.GetWorkspaceRoute(r.Context(), workspaceID)
+	if err != nil {
+		logger.Error("Failed to get workspace proxy route", zap.Error(err))
+		http.Error(w, "failed to get workspace proxy route", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleSetWorkspaceRouteHTTP is the HTTP handler for PUT /proxies/workspace/{workspaceId}
+func (h *ManageProxiesHandler) HandleSetWorkspaceRouteHTTP(w http.ResponseWriter, r *http.Request) {
+	workspaceID, err := uuid.Parse(chi.URLParam(r, "workspaceId"))
+	if err != nil {
+		http.Error(w, "invalid workspace_id", http.StatusBadRequest)
+		return
+	}
+
+	var req WorkspaceProxyRouteDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.SetWorkspaceRoute(r.Context(), workspaceID, &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidProxy) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.Error("Failed to set workspace proxy route", zap.Error(err))
+		http.Error(w, "failed to set workspace proxy route", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeSaveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entities.ErrInvalidProxy):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrProxyNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, secrets.ErrNoKey):
+		http.Error(w, "credential encryption is not configured", http.StatusServiceUnavailable)
+	default:
+		logger.Error("Failed to save proxy", zap.Error(err))
+		http.Error(w, "failed to save proxy", http.StatusInternalServerError)
+	}
+}
+
+func toProxyResponse(p *entities.Proxy, now time.Time) *ProxyResponse {
+	status := ProxyStatusHealthy
+	switch {
+	case !p.Enabled:
+		status = ProxyStatusDisabled
+	case p.Quarantined(now):
+		status = ProxyStatusQuarantined
+	}
+	return &ProxyResponse{
+		ID:                  p.ID,
+		Name:                p.Name,
+		Protocol:            p.Protocol,
+		Host:                p.Host,
+		Port:                p.Port,
+		Username:            p.Username,
+		HasPassword:         p.PasswordCiphertext != "",
+		Region:              p.Region,
+		Pool:                p.Pool,
+		Enabled:             p.Enabled,
+		Status:              status,
+		ConsecutiveFailures: p.ConsecutiveFailures,
+		QuarantinedUntil:    p.QuarantinedUntil,
+		LastUsedAt:          p.LastUsedAt,
+		LastSuccessAt:       p.LastSuccessAt,
+		LastFailureAt:       p.LastFailureAt,
+		LastError:           p.LastError,
+		CreatedAt:           p.CreatedAt,
+		UpdatedAt:           p.UpdatedAt,
+	}
+}
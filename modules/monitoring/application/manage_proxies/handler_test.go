@@ -0,0 +1,142 @@
+package manageproxies
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+	"github.com/jcsoftdev/pulzifi-back/shared/secrets"
+)
+
+const testTenant = "tenant_test"
+
+func newTestVault(t *testing.T) *secrets.Vault {
+	t.Helper()
+	v, err := secrets.NewVault(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	if err != nil {
+		t.Fatalf("NewVault: %v", err)
+	}
+	return v
+}
+
+func TestManageProxiesHandler_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *SaveProxyRequest
+		wantErr error
+	}{
+		{
+			name: "http proxy with credentials",
+			req:  &SaveProxyRequest{Name: "US east", Protocol: "http", Host: "10.0.0.5", Port: 3128, Username: "monitor", Password: "s3cret", Region: "us"},
+		},
+		{
+			name: "socks5 proxy in a pool",
+			req:  &SaveProxyRequest{Name: "DE residential", Protocol: "socks5", Host: "proxy.internal", Port: 1080, Region: "de", Pool: "residential"},
+		},
+		{
+			name:    "socks5 proxy with credentials",
+			req:     &SaveProxyRequest{Name: "DE", Protocol: "socks5", Host: "proxy.internal", Port: 1080, Username: "u", Password: "s3cret"},
+			wantErr: entities.ErrInvalidProxy,
+		},
+		{
+			name:    "missing port",
+			req:     &SaveProxyRequest{Name: "US", Protocol: "http", Host: "10.0.0.5"},
+			wantErr: entities.ErrInvalidProxy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockProxyRepository{}
+			h := NewManageProxiesHandler(repo, newTestVault(t), testTenant)
+
+			resp, err := h.Create(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() err = %v, want %v", err, tt.wantErr)
+				}
+				if repo.CreateCalls != 0 {
+					t.Error("invalid proxy was stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() unexpected error: %v", err)
+			}
+
+			if strings.Contains(repo.Created.PasswordCiphertext, "s3cret") {
+				t.Error("password stored in plaintext")
+			}
+			if resp.Status != ProxyStatusHealthy || !resp.Enabled {
+				t.Errorf("new proxy status = %q, enabled = %v", resp.Status, resp.Enabled)
+			}
+			body, _ := json.Marshal(resp)
+			if strings.Contains(string(body), "s3cret") {
+				t.Errorf("response leaks the password: %s", body)
+			}
+		})
+	}
+}
+
+func TestManageProxiesHandler_UpdateLiftsQuarantine(t *testing.T) {
+	vault := newTestVault(t)
+	existing := entities.NewProxy("US east", entities.ProxyProtocolHTTP, "10.0.0.5", 3128)
+	existing.Username = "monitor"
+	if err := existing.SealPassword(vault, testTenant, "old-secret"); err != nil {
+		t.Fatalf("SealPassword: %v", err)
+	}
+	until := time.Now().Add(time.Hour)
+	existing.ConsecutiveFailures = 5
+	existing.QuarantinedUntil = &until
+
+	repo := &mocks.MockProxyRepository{GetByIDResult: existing}
+	h := NewManageProxiesHandler(repo, vault, testTenant)
+
+	resp, err := h.Update(context.Background(), existing.ID, &SaveProxyRequest{Name: "US east", Protocol: "http", Host: "10.0.0.6", Port: 3128, Username: "monitor"})
+	if err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	if resp.Status != ProxyStatusHealthy || resp.ConsecutiveFailures != 0 {
+		t.Errorf("status = %q, failures = %d; want healthy with no failures", resp.Status, resp.ConsecutiveFailures)
+	}
+
+	password, err := repo.Updated.OpenPassword(vault, testTenant)
+	if err != nil {
+		t.Fatalf("OpenPassword: %v", err)
+	}
+	if password != "old-secret" {
+		t.Errorf("stored password = %q, want it kept", password)
+	}
+}
+
+func TestManageProxiesHandler_UpdateNotFound(t *testing.T) {
+	h := NewManageProxiesHandler(&mocks.MockProxyRepository{}, newTestVault(t), testTenant)
+
+	_, err := h.Update(context.Background(), uuid.New(), &SaveProxyRequest{Name: "US", Protocol: "http", Host: "10.0.0.5", Port: 3128})
+	if !errors.Is(err, ErrProxyNotFound) {
+		t.Fatalf("Update() err = %v, want ErrProxyNotFound", err)
+	}
+}
+
+func TestManageProxiesHandler_SetWorkspaceRoute(t *testing.T) {
+	repo := &mocks.MockProxyRepository{}
+	h := NewManageProxiesHandler(repo, newTestVault(t), testTenant)
+
+	if _, err := h.SetWorkspaceRoute(context.Background(), uuid.New(), &WorkspaceProxyRouteDTO{ProxyRegion: "de", ProxyPool: "residential"}); err != nil {
+		t.Fatalf("SetWorkspaceRoute() unexpected error: %v", err)
+	}
+	if repo.RouteSet == nil || repo.RouteSet.Region != "de" || repo.RouteSet.Pool != "residential" {
+		t.Errorf("route set = %+v", repo.RouteSet)
+	}
+
+	if _, err := h.SetWorkspaceRoute(context.Background(), uuid.New(), &WorkspaceProxyRouteDTO{ProxyRegion: "DE/Frankfurt"}); !errors.Is(err, entities.ErrInvalidProxy) {
+		t.Errorf("SetWorkspaceRoute() err = %v, want ErrInvalidProxy", err)
+	}
+}
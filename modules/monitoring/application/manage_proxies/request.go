@@ -0,0 +1,22 @@
+package manageproxies
+
+// SaveProxyRequest creates or replaces a proxy. On update, an empty password
+// keeps the stored one as long as the username is unchanged.
+type SaveProxyRequest struct {
+	Name     string `json:"name"`
+	Protocol string `json:"protocol"` // http, https or socks5
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Pool     string `json:"pool,omitempty"`
+	Enabled  *bool  `json:"enabled,omitempty"` // defaults to true
+}
+
+// WorkspaceProxyRouteDTO is the default proxy route for a workspace's pages.
+// Empty values mean pages connect directly unless they set their own route.
+type WorkspaceProxyRouteDTO struct {
+	ProxyRegion string `json:"proxy_region"`
+	ProxyPool   string `json:"proxy_pool"`
+}
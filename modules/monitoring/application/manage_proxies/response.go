@@ -0,0 +1,41 @@
+package manageproxies
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Proxy health states reported by the API.
+const (
+	ProxyStatusHealthy     = "healthy"
+	ProxyStatusQuarantined = "quarantined"
+	ProxyStatusDisabled    = "disabled"
+)
+
+// ProxyResponse describes a proxy without its password.
+type ProxyResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	Name                string     `json:"name"`
+	Protocol            string     `json:"protocol"`
+	Host                string     `json:"host"`
+	Port                int        `json:"port"`
+	Username            string     `json:"username,omitempty"`
+	HasPassword         bool       `json:"has_password"`
+	Region              string     `json:"region,omitempty"`
+	Pool                string     `json:"pool,omitempty"`
+	Enabled             bool       `json:"enabled"`
+	Status              string     `json:"status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	QuarantinedUntil    *time.Time `json:"quarantined_until,omitempty"`
+	LastUsedAt          *time.Time `json:"last_used_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type ListProxiesResponse struct {
+	Proxies []*ProxyResponse `json:"proxies"`
+}
@@ -3,6 +3,8 @@ package managesections
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -13,20 +15,50 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrSectionNotFound is returned when a section does not exist on the page.
+	ErrSectionNotFound = errors.New("section not found")
+	// ErrSuggestionNotFound is returned when an accepted suggestion does not
+	// exist on the page, or was already accepted.
+	ErrSuggestionNotFound = errors.New("section suggestion not found")
+	// ErrNoSuggestionsSelected is returned when accepting an empty selection.
+	ErrNoSuggestionsSelected = errors.New("no suggestions selected")
+)
+
+// SnapshotSigner issues short-lived URLs for stored screenshots to members of
+// the page's workspace.
+type SnapshotSigner interface {
+	CanAccessPage(ctx context.Context, pageID uuid.UUID) (bool, error)
+	Sign(ctx context.Context, key string) string
+}
+
 // ManageSectionsHandler handles CRUD operations for monitored sections.
 type ManageSectionsHandler struct {
-	sectionRepo repositories.MonitoredSectionRepository
-	configRepo  repositories.MonitoringConfigRepository
+	sectionRepo    repositories.MonitoredSectionRepository
+	configRepo     repositories.MonitoringConfigRepository
+	suggestionRepo repositories.SectionSuggestionRepository
+	signer         SnapshotSigner
 }
 
 // NewManageSectionsHandler creates a new handler.
-func NewManageSectionsHandler(sectionRepo repositories.MonitoredSectionRepository, configRepo repositories.MonitoringConfigRepository) *ManageSectionsHandler {
+func NewManageSectionsHandler(
+	sectionRepo repositories.MonitoredSectionRepository,
+	configRepo repositories.MonitoringConfigRepository,
+	suggestionRepo repositories.SectionSuggestionRepository,
+) *ManageSectionsHandler {
 	return &ManageSectionsHandler{
-		sectionRepo: sectionRepo,
-		configRepo:  configRepo,
+		sectionRepo:    sectionRepo,
+		configRepo:     configRepo,
+		suggestionRepo: suggestionRepo,
 	}
 }
 
+// SetSnapshotSigner makes List replace proposal screenshot keys with signed
+// URLs, or drop them for users outside the page's workspace.
+func (h *ManageSectionsHandler) SetSnapshotSigner(signer SnapshotSigner) {
+	h.signer = signer
+}
+
 // List returns all sections for a page.
 func (h *ManageSectionsHandler) List(ctx context.Context, pageID uuid.UUID) (*ListSectionsResponse, error) {
 	sections, err := h.sectionRepo.ListByPageID(ctx, pageID)
@@ -40,6 +72,21 @@ func (h *ManageSectionsHandler) List(ctx context.Context, pageID uuid.UUID) (*Li
 	for i, s := range sections {
 		resp.Sections[i] = toSectionResponse(s)
 	}
+	if h.signer != nil {
+		member, err := h.signer.CanAccessPage(ctx, pageID)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Sections {
+			if p := s.SelectorProposal; p != nil {
+				if member {
+					p.ScreenshotURL = h.signer.Sign(ctx, p.ScreenshotURL)
+				} else {
+					p.ScreenshotURL = ""
+				}
+			}
+		}
+	}
 	return resp, nil
 }
 
@@ -71,6 +118,13 @@ func (h *ManageSectionsHandler) SaveAll(ctx context.Context, pageID uuid.UUID, r
 		domainSections[i] = entities.NewMonitoredSection(
 			pageID, dto.Name, dto.CSSSelector, dto.XPathSelector, offsets, rect, dto.ViewportWidth, dto.SortOrder,
 		)
+		domainSections[i].Fingerprint = toFingerprint(dto.Fingerprint, rect)
+	}
+
+	if len(domainSections) > 0 {
+		if err := h.ensureSectionsAllowed(ctx, pageID); err != nil {
+			return nil, err
+		}
 	}
 
 	// Replace all sections
@@ -78,39 +132,127 @@ func (h *ManageSectionsHandler) SaveAll(ctx context.Context, pageID uuid.UUID, r
 		return nil, err
 	}
 
-	// Update monitoring config selector_type
-	config, err := h.configRepo.GetByPageID(ctx, pageID)
+	if err := h.updateSelectorType(ctx, pageID, len(domainSections) > 0); err != nil {
+		return nil, err
+	}
+
+	// Return the saved sections
+	return h.List(ctx, pageID)
+}
+
+// ListSuggestions returns the sections suggested for a page, best ranked first.
+func (h *ManageSectionsHandler) ListSuggestions(ctx context.Context, pageID uuid.UUID) (*ListSuggestionsResponse, error) {
+	suggestions, err := h.suggestionRepo.ListByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListSuggestionsResponse{
+		Suggestions: make([]*SuggestionResponse, len(suggestions)),
+	}
+	for i, s := range suggestions {
+		resp.Suggestions[i] = toSuggestionResponse(s)
+	}
+	return resp, nil
+}
+
+// AcceptSuggestions adds the chosen suggestions to the page's sections, after
+// the existing ones, and switches the page to sections mode.
+func (h *ManageSectionsHandler) AcceptSuggestions(ctx context.Context, pageID uuid.UUID, req *AcceptSuggestionsRequest) (*ListSectionsResponse, error) {
+	if len(req.SuggestionIDs) == 0 {
+		return nil, ErrNoSuggestionsSelected
+	}
+	suggestions, err := h.suggestionRepo.ListByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entities.SectionSuggestion, len(suggestions))
+	for _, s := range suggestions {
+		byID[s.ID] = s
+	}
+
+	existing, err := h.sectionRepo.ListByPageID(ctx, pageID)
 	if err != nil {
 		return nil, err
 	}
-	if config != nil {
-		if len(domainSections) > 0 {
-			config.SelectorType = "sections"
-		} else {
-			config.SelectorType = "full_page"
+	nextOrder := 0
+	for _, s := range existing {
+		nextOrder = max(nextOrder, s.SortOrder+1)
+	}
+
+	accepted := make([]*entities.SectionSuggestion, len(req.SuggestionIDs))
+	for i, id := range req.SuggestionIDs {
+		suggestion, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSuggestionNotFound, id)
 		}
-		// Clear legacy single-selector fields when switching to multi-section
-		config.CSSSelector = ""
-		config.XPathSelector = ""
-		config.SelectorOffsets = nil
-		if err := h.configRepo.Update(ctx, config); err != nil {
+		accepted[i] = suggestion
+	}
+	if err := h.ensureSectionsAllowed(ctx, pageID); err != nil {
+		return nil, err
+	}
+	for _, suggestion := range accepted {
+		if err := h.sectionRepo.Create(ctx, suggestion.ToSection(nextOrder)); err != nil {
 			return nil, err
 		}
+		nextOrder++
 	}
 
-	// Return the saved sections
-	saved, err := h.sectionRepo.ListByPageID(ctx, pageID)
-	if err != nil {
+	if err := h.suggestionRepo.Delete(ctx, req.SuggestionIDs); err != nil {
+		return nil, err
+	}
+	if err := h.updateSelectorType(ctx, pageID, true); err != nil {
 		return nil, err
 	}
+	return h.List(ctx, pageID)
+}
 
-	resp := &ListSectionsResponse{
-		Sections: make([]*SectionResponse, len(saved)),
+// ensureSectionsAllowed checks the page's config can switch to sections mode.
+func (h *ManageSectionsHandler) ensureSectionsAllowed(ctx context.Context, pageID uuid.UUID) error {
+	config, err := h.configRepo.GetByPageID(ctx, pageID)
+	if err != nil || config == nil {
+		return err
 	}
-	for i, s := range saved {
-		resp.Sections[i] = toSectionResponse(s)
+	sectionsConfig := *config
+	sectionsConfig.SelectorType = "sections"
+	return entities.ValidateCaptureProfileMode(&sectionsConfig)
+}
+
+// updateSelectorType sets the monitoring config selector_type to "sections"
+// when the page has sections, or back to "full_page" when it has none.
+func (h *ManageSectionsHandler) updateSelectorType(ctx context.Context, pageID uuid.UUID, hasSections bool) error {
+	config, err := h.configRepo.GetByPageID(ctx, pageID)
+	if err != nil || config == nil {
+		return err
 	}
-	return resp, nil
+	if hasSections {
+		config.SelectorType = "sections"
+	} else {
+		config.SelectorType = "full_page"
+	}
+	// Clear legacy single-selector fields when switching to multi-section
+	config.CSSSelector = ""
+	config.XPathSelector = ""
+	config.SelectorOffsets = nil
+	return h.configRepo.Update(ctx, config)
+}
+
+// AcceptProposal switches a section to the selector proposed for it.
+func (h *ManageSectionsHandler) AcceptProposal(ctx context.Context, pageID, sectionID uuid.UUID) (*SectionResponse, error) {
+	section, err := h.sectionRepo.GetByID(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if section == nil || section.PageID != pageID {
+		return nil, ErrSectionNotFound
+	}
+	if err := section.AcceptSelectorProposal(); err != nil {
+		return nil, err
+	}
+	if err := h.sectionRepo.Update(ctx, section); err != nil {
+		return nil, err
+	}
+	return toSectionResponse(section), nil
 }
 
 // DeleteSection removes a single section by ID.
@@ -153,6 +295,10 @@ func (h *ManageSectionsHandler) HandleSaveHTTP(w http.ResponseWriter, r *http.Re
 
 	resp, err := h.SaveAll(r.Context(), pageID, &req)
 	if err != nil {
+		if errors.Is(err, entities.ErrInvalidCaptureProfile) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		logger.Error("Failed to save sections", zap.Error(err))
 		http.Error(w, "failed to save sections", http.StatusInternalServerError)
 		return
@@ -162,6 +308,90 @@ func (h *ManageSectionsHandler) HandleSaveHTTP(w http.ResponseWriter, r *http.Re
 	json.NewEncoder(w).Encode(resp)
 }
 
+// HandleAcceptProposalHTTP is the HTTP handler for POST /pages/{pageId}/sections/{sectionId}/accept-proposal
+func (h *ManageSectionsHandler) HandleAcceptProposalHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+	sectionID, err := uuid.Parse(chi.URLParam(r, "sectionId"))
+	if err != nil {
+		http.Error(w, "invalid section_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.AcceptProposal(r.Context(), pageID, sectionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSectionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, entities.ErrNoSelectorProposal):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			logger.Error("Failed to accept selector proposal", zap.Error(err))
+			http.Error(w, "failed to accept selector proposal", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleListSuggestionsHTTP is the HTTP handler for GET /pages/{pageId}/sections/suggestions
+func (h *ManageSectionsHandler) HandleListSuggestionsHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.ListSuggestions(r.Context(), pageID)
+	if err != nil {
+		logger.Error("Failed to list section suggestions", zap.Error(err))
+		http.Error(w, "failed to list section suggestions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleAcceptSuggestionsHTTP is the HTTP handler for POST /pages/{pageId}/sections/suggestions/accept
+func (h *ManageSectionsHandler) HandleAcceptSuggestionsHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	var req AcceptSuggestionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.AcceptSuggestions(r.Context(), pageID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNoSuggestionsSelected):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrSuggestionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, entities.ErrInvalidCaptureProfile):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			logger.Error("Failed to accept section suggestions", zap.Error(err))
+			http.Error(w, "failed to accept section suggestions", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
 // HandleDeleteHTTP is the HTTP handler for DELETE /pages/{pageId}/sections/{sectionId}
 func (h *ManageSectionsHandler) HandleDeleteHTTP(w http.ResponseWriter, r *http.Request) {
 	sectionID, err := uuid.Parse(chi.URLParam(r, "sectionId"))
@@ -181,15 +411,16 @@ func (h *ManageSectionsHandler) HandleDeleteHTTP(w http.ResponseWriter, r *http.
 
 func toSectionResponse(s *entities.MonitoredSection) *SectionResponse {
 	resp := &SectionResponse{
-		ID:            s.ID,
-		PageID:        s.PageID,
-		Name:          s.Name,
-		CSSSelector:   s.CSSSelector,
-		XPathSelector: s.XPathSelector,
-		SortOrder:     s.SortOrder,
-		ViewportWidth: s.ViewportWidth,
-		CreatedAt:     s.CreatedAt,
-		UpdatedAt:     s.UpdatedAt,
+		ID:               s.ID,
+		PageID:           s.PageID,
+		Name:             s.Name,
+		CSSSelector:      s.CSSSelector,
+		XPathSelector:    s.XPathSelector,
+		SortOrder:        s.SortOrder,
+		ViewportWidth:    s.ViewportWidth,
+		SelectorBrokenAt: s.SelectorHealth.BrokenAt,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
 	}
 	if s.SelectorOffsets != nil {
 		resp.SelectorOffsets = &SectionOffsetsDTO{
@@ -207,5 +438,52 @@ func toSectionResponse(s *entities.MonitoredSection) *SectionResponse {
 			H: s.Rect.H,
 		}
 	}
+	if p := s.SelectorProposal; p != nil {
+		resp.SelectorProposal = &ProposalResponse{
+			CSSSelector:   p.CSSSelector,
+			XPathSelector: p.XPathSelector,
+			Score:         p.Score,
+			ScreenshotURL: p.ScreenshotURL,
+			ProposedAt:    p.ProposedAt,
+		}
+	}
+	return resp
+}
+
+func toSuggestionResponse(s *entities.SectionSuggestion) *SuggestionResponse {
+	resp := &SuggestionResponse{
+		ID:            s.ID,
+		Kind:          s.Kind,
+		Name:          s.Name,
+		CSSSelector:   s.CSSSelector,
+		XPathSelector: s.XPathSelector,
+		ViewportWidth: s.ViewportWidth,
+		Score:         s.Score,
+	}
+	if s.Rect != nil {
+		resp.Rect = &SectionRectDTO{X: s.Rect.X, Y: s.Rect.Y, W: s.Rect.W, H: s.Rect.H}
+	}
+	if s.Fingerprint != nil {
+		resp.TextPreview = s.Fingerprint.Text
+	}
 	return resp
 }
+
+// toFingerprint converts a section's fingerprint, defaulting its rect to the
+// section's own.
+func toFingerprint(dto *FingerprintDTO, rect *entities.SectionRect) *entities.ElementFingerprint {
+	if dto == nil {
+		return nil
+	}
+	fingerprint := &entities.ElementFingerprint{
+		Tag:        dto.Tag,
+		Text:       dto.Text,
+		Attributes: dto.Attributes,
+		Rect:       rect,
+		Landmarks:  dto.Landmarks,
+	}
+	if dto.Rect != nil {
+		fingerprint.Rect = &entities.SectionRect{X: dto.Rect.X, Y: dto.Rect.Y, W: dto.Rect.W, H: dto.Rect.H}
+	}
+	return fingerprint
+}
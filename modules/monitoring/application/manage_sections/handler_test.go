@@ -0,0 +1,103 @@
+package managesections
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+)
+
+func TestManageSectionsHandler_AcceptSuggestions(t *testing.T) {
+	pageID := uuid.New()
+	pricing := &entities.SectionSuggestion{
+		ID: uuid.New(), PageID: pageID, Kind: entities.SuggestionKindPricing, Name: "Pricing",
+		CSSSelector: "#plans", Rect: &entities.SectionRect{W: 1440, H: 600}, ViewportWidth: 1440, Score: 0.95,
+		Fingerprint: &entities.ElementFingerprint{Tag: "section", Text: "Starter $9/mo"},
+	}
+	footer := &entities.SectionSuggestion{
+		ID: uuid.New(), PageID: pageID, Kind: entities.SuggestionKindFooter, Name: "Footer", CSSSelector: "footer", Score: 0.2,
+	}
+	existing := entities.NewMonitoredSection(pageID, "Header", "header", "", nil, nil, 1440, 4)
+
+	tests := []struct {
+		name        string
+		ids         []uuid.UUID
+		wantErr     error
+		wantCreated int
+	}{
+		{name: "accepts chosen suggestions after existing sections", ids: []uuid.UUID{pricing.ID, footer.ID}, wantCreated: 2},
+		{name: "empty selection", ids: nil, wantErr: ErrNoSuggestionsSelected},
+		{name: "unknown suggestion", ids: []uuid.UUID{uuid.New()}, wantErr: ErrSuggestionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sectionRepo := &mocks.MockMonitoredSectionRepository{ListByPageIDResult: []*entities.MonitoredSection{existing}}
+			configRepo := &mocks.MockMonitoringConfigRepository{
+				GetByPageIDResult: &entities.MonitoringConfig{PageID: pageID, SelectorType: "full_page"},
+			}
+			suggestionRepo := &mocks.MockSectionSuggestionRepository{ListByPageIDResult: []*entities.SectionSuggestion{pricing, footer}}
+			h := NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
+
+			_, err := h.AcceptSuggestions(context.Background(), pageID, &AcceptSuggestionsRequest{SuggestionIDs: tt.ids})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(sectionRepo.Created) != tt.wantCreated {
+				t.Fatalf("created %d sections, want %d", len(sectionRepo.Created), tt.wantCreated)
+			}
+			if tt.wantErr != nil {
+				if configRepo.UpdateCalls != 0 || len(suggestionRepo.Deleted) != 0 {
+					t.Error("a rejected selection should not change the page")
+				}
+				return
+			}
+
+			first := sectionRepo.Created[0]
+			if first.Name != "Pricing" || first.CSSSelector != "#plans" || first.SortOrder != 5 {
+				t.Errorf("first section = %q %q order %d, want Pricing #plans order 5", first.Name, first.CSSSelector, first.SortOrder)
+			}
+			if first.Fingerprint == nil || first.Fingerprint.Text != "Starter $9/mo" {
+				t.Error("accepted section should keep the suggestion's fingerprint")
+			}
+			if sectionRepo.Created[1].SortOrder != 6 {
+				t.Errorf("second section order = %d, want 6", sectionRepo.Created[1].SortOrder)
+			}
+			if len(suggestionRepo.Deleted) != 2 {
+				t.Errorf("deleted %d suggestions, want 2", len(suggestionRepo.Deleted))
+			}
+			if configRepo.GetByPageIDResult.SelectorType != "sections" || configRepo.UpdateCalls != 1 {
+				t.Errorf("selector type = %q after %d updates, want sections", configRepo.GetByPageIDResult.SelectorType, configRepo.UpdateCalls)
+			}
+		})
+	}
+}
+
+func TestManageSectionsHandler_RejectsSectionsWithCaptureProfiles(t *testing.T) {
+	pageID := uuid.New()
+	sectionRepo := &mocks.MockMonitoredSectionRepository{}
+	configRepo := &mocks.MockMonitoringConfigRepository{
+		GetByPageIDResult: &entities.MonitoringConfig{
+			PageID:          pageID,
+			SelectorType:    "full_page",
+			CaptureProfiles: []entities.CaptureProfile{{Name: "mobile", Mobile: true}},
+		},
+	}
+	h := NewManageSectionsHandler(sectionRepo, configRepo, &mocks.MockSectionSuggestionRepository{})
+
+	req := &SaveSectionsRequest{Sections: []SectionDTO{{Name: "Pricing", CSSSelector: "#plans"}}}
+	if _, err := h.SaveAll(context.Background(), pageID, req); !errors.Is(err, entities.ErrInvalidCaptureProfile) {
+		t.Fatalf("err = %v, want %v", err, entities.ErrInvalidCaptureProfile)
+	}
+	if sectionRepo.Replaced != nil || configRepo.UpdateCalls != 0 {
+		t.Error("a rejected save should not change the page")
+	}
+
+	// Clearing the sections is always allowed.
+	if _, err := h.SaveAll(context.Background(), pageID, &SaveSectionsRequest{}); err != nil {
+		t.Fatalf("clearing sections: %v", err)
+	}
+}
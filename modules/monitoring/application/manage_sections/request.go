@@ -1,5 +1,7 @@
 package managesections
 
+import "github.com/google/uuid"
+
 // SectionOffsetsDTO represents pixel offsets for an element bounding box.
 type SectionOffsetsDTO struct {
 	Top    int `json:"top"`
@@ -16,6 +18,16 @@ type SectionRectDTO struct {
 	H int `json:"h"`
 }
 
+// FingerprintDTO describes the element a section was drawn on. The frontend
+// copies it from the preview element the section was created from.
+type FingerprintDTO struct {
+	Tag        string            `json:"tag"`
+	Text       string            `json:"text,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Rect       *SectionRectDTO   `json:"rect,omitempty"`
+	Landmarks  []string          `json:"landmarks,omitempty"`
+}
+
 // SectionDTO represents a single monitored section in requests.
 type SectionDTO struct {
 	ID              string             `json:"id,omitempty"`
@@ -26,6 +38,12 @@ type SectionDTO struct {
 	Rect            *SectionRectDTO    `json:"rect,omitempty"`
 	ViewportWidth   int                `json:"viewport_width,omitempty"`
 	SortOrder       int                `json:"sort_order"`
+	Fingerprint     *FingerprintDTO    `json:"fingerprint,omitempty"`
+}
+
+// AcceptSuggestionsRequest turns the chosen suggestions into sections.
+type AcceptSuggestionsRequest struct {
+	SuggestionIDs []uuid.UUID `json:"suggestion_ids"`
 }
 
 // SaveSectionsRequest replaces all sections for a page.
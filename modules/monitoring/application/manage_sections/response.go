@@ -8,17 +8,48 @@ import (
 
 // SectionResponse is the API response for a single monitored section.
 type SectionResponse struct {
-	ID              uuid.UUID          `json:"id"`
-	PageID          uuid.UUID          `json:"page_id"`
-	Name            string             `json:"name"`
-	CSSSelector     string             `json:"css_selector"`
-	XPathSelector   string             `json:"xpath_selector"`
-	SelectorOffsets *SectionOffsetsDTO `json:"selector_offsets,omitempty"`
-	Rect            *SectionRectDTO    `json:"rect,omitempty"`
-	ViewportWidth   int                `json:"viewport_width,omitempty"`
-	SortOrder       int                `json:"sort_order"`
-	CreatedAt       time.Time          `json:"created_at"`
-	UpdatedAt       time.Time          `json:"updated_at"`
+	ID               uuid.UUID          `json:"id"`
+	PageID           uuid.UUID          `json:"page_id"`
+	Name             string             `json:"name"`
+	CSSSelector      string             `json:"css_selector"`
+	XPathSelector    string             `json:"xpath_selector"`
+	SelectorOffsets  *SectionOffsetsDTO `json:"selector_offsets,omitempty"`
+	Rect             *SectionRectDTO    `json:"rect,omitempty"`
+	ViewportWidth    int                `json:"viewport_width,omitempty"`
+	SortOrder        int                `json:"sort_order"`
+	SelectorBrokenAt *time.Time         `json:"selector_broken_at,omitempty"`
+	SelectorProposal *ProposalResponse  `json:"selector_proposal,omitempty"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// ProposalResponse is a replacement selector found by the section's
+// fingerprint after its own selectors stopped matching.
+type ProposalResponse struct {
+	CSSSelector   string    `json:"css_selector"`
+	XPathSelector string    `json:"xpath_selector,omitempty"`
+	Score         float64   `json:"score"`
+	ScreenshotURL string    `json:"screenshot_url,omitempty"`
+	ProposedAt    time.Time `json:"proposed_at"`
+}
+
+// SuggestionResponse is a region suggested for monitoring. Score ranks how
+// likely the region is to change meaningfully, from 0 to 1.
+type SuggestionResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	Kind          string          `json:"kind"`
+	Name          string          `json:"name"`
+	CSSSelector   string          `json:"css_selector"`
+	XPathSelector string          `json:"xpath_selector,omitempty"`
+	Rect          *SectionRectDTO `json:"rect,omitempty"`
+	ViewportWidth int             `json:"viewport_width,omitempty"`
+	TextPreview   string          `json:"text_preview,omitempty"`
+	Score         float64         `json:"score"`
+}
+
+// ListSuggestionsResponse wraps a page's section suggestions.
+type ListSuggestionsResponse struct {
+	Suggestions []*SuggestionResponse `json:"suggestions"`
 }
 
 // ListSectionsResponse wraps a list of sections.
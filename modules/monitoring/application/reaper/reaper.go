@@ -0,0 +1,214 @@
+package reaper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// Interval is how often unfinished checks are inspected.
+	Interval = time.Minute
+	// DispatchDeadline is how long a check may wait for a worker to pick it
+	// up before its job is considered lost.
+	DispatchDeadline = 10 * time.Minute
+	// HeartbeatTimeout is how long a running check may go without a
+	// heartbeat from its worker before the worker is considered gone.
+	HeartbeatTimeout = 3 * time.Minute
+	// RunDeadline is how long a check may run in total, heartbeats or not,
+	// so a hung extractor doesn't keep it running.
+	RunDeadline = 20 * time.Minute
+	// MaxDispatches is how many times a check is dispatched before it is
+	// failed instead of re-dispatched.
+	MaxDispatches = 2
+	// batchSize is how many stuck checks are handled per tenant and run.
+	batchSize = 100
+)
+
+// StuckCheck is a pending or running check with its page's URL and dispatch
+// state.
+type StuckCheck struct {
+	Check       *entities.Check
+	URL         string
+	HeartbeatAt *time.Time // last heartbeat of the worker running it; nil when never picked up
+	Dispatches  int
+}
+
+// Repository finds and resolves one tenant's stuck checks.
+type Repository interface {
+	// ListStuck returns up to limit checks still pending or running that
+	// were dispatched before cutoff.
+	ListStuck(ctx context.Context, cutoff time.Time, limit int) ([]StuckCheck, error)
+	// Redispatch counts another dispatch of a check and restarts its
+	// deadlines. It returns false when a worker claimed the check, it left
+	// pending otherwise, or another reaper re-dispatched it first.
+	Redispatch(ctx context.Context, checkID uuid.UUID, dispatches int) (bool, error)
+	// Fail marks a check as error, returning false when it was already
+	// resolved.
+	Fail(ctx context.Context, checkID uuid.UUID, reason string) (bool, error)
+	// RefundUsage gives back the check quota the check consumed.
+	RefundUsage(ctx context.Context, checkID uuid.UUID) error
+}
+
+type RepositoryFactory interface {
+	ListTenants(ctx context.Context) ([]string, error)
+	GetReaperRepository(tenant string) Repository
+}
+
+// JobDispatcher queues a check for a worker.
+type JobDispatcher interface {
+	Dispatch(ctx context.Context, checkID uuid.UUID, url string, schemaName string) error
+}
+
+// Stats counts one tenant's run.
+type Stats struct {
+	Redispatched int
+	Failed       int
+}
+
+// Reaper resolves checks left unfinished: a check no worker picked up is
+// dispatched again, up to MaxDispatches times, and a check whose worker
+// stopped sending heartbeats or that ran past RunDeadline is marked error.
+// Failed checks get their quota back.
+type Reaper struct {
+	repos      RepositoryFactory
+	dispatcher JobDispatcher
+	onFailed   func(check *entities.Check)
+	now        func() time.Time
+}
+
+func NewReaper(repos RepositoryFactory, dispatcher JobDispatcher) *Reaper {
+	return &Reaper{repos: repos, dispatcher: dispatcher, now: time.Now}
+}
+
+// SetOnCheckFailed registers a callback invoked with every check the reaper
+// marks as error, used to push SSE updates.
+func (r *Reaper) SetOnCheckFailed(fn func(check *entities.Check)) {
+	r.onFailed = fn
+}
+
+// Start reaps every tenant now and then every Interval until ctx is done.
+func (r *Reaper) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(Interval)
+		defer ticker.Stop()
+		for {
+			r.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("Stuck check reaper started", zap.Duration("interval", Interval))
+}
+
+// RunOnce reaps every tenant and logs what it did.
+func (r *Reaper) RunOnce(ctx context.Context) {
+	tenants, err := r.repos.ListTenants(ctx)
+	if err != nil {
+		logger.Error("Stuck check reaper failed to list tenants", zap.Error(err))
+		return
+	}
+	for _, tenant := range tenants {
+		stats, err := r.ReapTenant(ctx, tenant)
+		if err != nil {
+			logger.Error("Stuck check reaper failed", zap.String("tenant", tenant), zap.Error(err))
+		}
+		if stats.Redispatched > 0 || stats.Failed > 0 {
+			logger.Warn("Stuck checks reaped",
+				zap.String("tenant", tenant),
+				zap.Int("redispatched", stats.Redispatched),
+				zap.Int("failed", stats.Failed))
+		}
+	}
+}
+
+// ReapTenant resolves one tenant's stuck checks.
+func (r *Reaper) ReapTenant(ctx context.Context, tenant string) (Stats, error) {
+	var stats Stats
+	repo := r.repos.GetReaperRepository(tenant)
+	now := r.now()
+	stuck, err := repo.ListStuck(ctx, now.Add(-HeartbeatTimeout), batchSize)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, s := range stuck {
+		age := now.Sub(s.Check.CheckedAt)
+		var reason string
+		switch {
+		case s.HeartbeatAt == nil && age < DispatchDeadline:
+			continue // still queued
+		case s.HeartbeatAt == nil && s.Dispatches < MaxDispatches:
+			ok, err := r.redispatch(ctx, repo, tenant, s)
+			if err != nil {
+				reason = fmt.Sprintf("re-dispatch failed: %v", err)
+				break
+			}
+			if ok {
+				stats.Redispatched++
+			}
+			continue
+		case s.HeartbeatAt == nil:
+			reason = fmt.Sprintf("no worker picked up the check after %d dispatches", s.Dispatches)
+		case now.Sub(*s.HeartbeatAt) >= HeartbeatTimeout:
+			reason = fmt.Sprintf("worker stopped responding while running the check (last heartbeat %s ago)", now.Sub(*s.HeartbeatAt).Round(time.Second))
+		case age >= RunDeadline:
+			reason = fmt.Sprintf("check did not finish within %s", RunDeadline)
+		default:
+			continue // running and reporting
+		}
+
+		failed, err := r.fail(ctx, repo, s.Check, reason)
+		if err != nil {
+			return stats, err
+		}
+		if failed {
+			stats.Failed++
+		}
+	}
+	return stats, nil
+}
+
+func (r *Reaper) redispatch(ctx context.Context, repo Repository, tenant string, s StuckCheck) (bool, error) {
+	ok, err := repo.Redispatch(ctx, s.Check.ID, s.Dispatches)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := r.dispatcher.Dispatch(ctx, s.Check.ID, s.URL, tenant); err != nil {
+		return false, err
+	}
+	logger.Info("Re-dispatched stuck check",
+		zap.String("check_id", s.Check.ID.String()),
+		zap.String("page_id", s.Check.PageID.String()),
+		zap.Int("dispatch", s.Dispatches+1))
+	return true, nil
+}
+
+func (r *Reaper) fail(ctx context.Context, repo Repository, check *entities.Check, reason string) (bool, error) {
+	ok, err := repo.Fail(ctx, check.ID, reason)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := repo.RefundUsage(ctx, check.ID); err != nil {
+		logger.Error("Failed to refund quota of stuck check", zap.String("check_id", check.ID.String()), zap.Error(err))
+	}
+	logger.Warn("Failed stuck check",
+		zap.String("check_id", check.ID.String()),
+		zap.String("page_id", check.PageID.String()),
+		zap.String("reason", reason))
+
+	check.Status = "error"
+	check.ErrorMessage = reason
+	if r.onFailed != nil {
+		r.onFailed(check)
+	}
+	return true, nil
+}
@@ -0,0 +1,158 @@
+package reaper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type fakeRepository struct {
+	stuck        []StuckCheck
+	cutoff       time.Time
+	redispatched []uuid.UUID
+	failed       map[uuid.UUID]string
+	refunded     []uuid.UUID
+}
+
+func (r *fakeRepository) ListStuck(_ context.Context, cutoff time.Time, limit int) ([]StuckCheck, error) {
+	r.cutoff = cutoff
+	if len(r.stuck) > limit {
+		return r.stuck[:limit], nil
+	}
+	return r.stuck, nil
+}
+
+func (r *fakeRepository) Redispatch(_ context.Context, checkID uuid.UUID, _ int) (bool, error) {
+	r.redispatched = append(r.redispatched, checkID)
+	return true, nil
+}
+
+func (r *fakeRepository) Fail(_ context.Context, checkID uuid.UUID, reason string) (bool, error) {
+	if _, ok := r.failed[checkID]; ok {
+		return false, nil
+	}
+	r.failed[checkID] = reason
+	return true, nil
+}
+
+func (r *fakeRepository) RefundUsage(_ context.Context, checkID uuid.UUID) error {
+	r.refunded = append(r.refunded, checkID)
+	return nil
+}
+
+type fakeFactory struct{ repo *fakeRepository }
+
+func (f fakeFactory) ListTenants(context.Context) ([]string, error) { return []string{"tenant"}, nil }
+func (f fakeFactory) GetReaperRepository(string) Repository         { return f.repo }
+
+type fakeDispatcher struct {
+	dispatched []uuid.UUID
+	err        error
+}
+
+func (d *fakeDispatcher) Dispatch(_ context.Context, checkID uuid.UUID, _ string, schemaName string) error {
+	if d.err != nil {
+		return d.err
+	}
+	if schemaName != "tenant" {
+		return errors.New("wrong tenant")
+	}
+	d.dispatched = append(d.dispatched, checkID)
+	return nil
+}
+
+func TestReaper_ReapTenant(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	stuck := func(age time.Duration, heartbeat *time.Time, dispatches int) StuckCheck {
+		// Workers claim a check, moving it to running, when they pick it up.
+		status := "pending"
+		if heartbeat != nil {
+			status = "running"
+		}
+		return StuckCheck{
+			Check:       &entities.Check{ID: uuid.New(), PageID: uuid.New(), Status: status, CheckedAt: now.Add(-age)},
+			URL:         "https://example.com",
+			HeartbeatAt: heartbeat,
+			Dispatches:  dispatches,
+		}
+	}
+
+	queued := stuck(5*time.Minute, nil, 1)
+	lost := stuck(15*time.Minute, nil, 1)
+	exhausted := stuck(15*time.Minute, nil, MaxDispatches)
+	dead := stuck(8*time.Minute, ago(5*time.Minute), 1)
+	hung := stuck(25*time.Minute, ago(10*time.Second), 1)
+	running := stuck(6*time.Minute, ago(10*time.Second), 1)
+
+	repo := &fakeRepository{
+		stuck:  []StuckCheck{queued, lost, exhausted, dead, hung, running},
+		failed: map[uuid.UUID]string{},
+	}
+	dispatcher := &fakeDispatcher{}
+	r := NewReaper(fakeFactory{repo}, dispatcher)
+	r.now = func() time.Time { return now }
+	var published []uuid.UUID
+	r.SetOnCheckFailed(func(check *entities.Check) {
+		if check.Status != "error" || check.ErrorMessage == "" {
+			t.Errorf("published check %s without error state", check.ID)
+		}
+		published = append(published, check.ID)
+	})
+
+	stats, err := r.ReapTenant(context.Background(), "tenant")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (Stats{Redispatched: 1, Failed: 3}); stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	if !repo.cutoff.Equal(now.Add(-HeartbeatTimeout)) {
+		t.Errorf("cutoff = %v", repo.cutoff)
+	}
+	if len(dispatcher.dispatched) != 1 || dispatcher.dispatched[0] != lost.Check.ID {
+		t.Errorf("dispatched = %v, want only the lost check", dispatcher.dispatched)
+	}
+	for _, s := range []StuckCheck{exhausted, dead, hung} {
+		if repo.failed[s.Check.ID] == "" {
+			t.Errorf("check %s not failed", s.Check.ID)
+		}
+	}
+	for _, s := range []StuckCheck{queued, lost, running} {
+		if reason, ok := repo.failed[s.Check.ID]; ok {
+			t.Errorf("check %s failed: %s", s.Check.ID, reason)
+		}
+	}
+	if len(repo.refunded) != 3 || len(published) != 3 {
+		t.Errorf("refunded = %v, published = %v; want the 3 failed checks", repo.refunded, published)
+	}
+}
+
+func TestReaper_FailsWhenRedispatchFails(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	check := &entities.Check{ID: uuid.New(), Status: "pending", CheckedAt: now.Add(-DispatchDeadline)}
+	repo := &fakeRepository{
+		stuck:  []StuckCheck{{Check: check, Dispatches: 1}},
+		failed: map[uuid.UUID]string{},
+	}
+	r := NewReaper(fakeFactory{repo}, &fakeDispatcher{err: errors.New("queue full")})
+	r.now = func() time.Time { return now }
+
+	stats, err := r.ReapTenant(context.Background(), "tenant")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats != (Stats{Failed: 1}) {
+		t.Errorf("stats = %+v", stats)
+	}
+	if got := repo.failed[check.ID]; got != "re-dispatch failed: queue full" {
+		t.Errorf("reason = %q", got)
+	}
+}
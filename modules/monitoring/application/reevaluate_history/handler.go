@@ -0,0 +1,297 @@
+package reevaluatehistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidRange      = errors.New("invalid date range")
+	ErrJobNotFound       = errors.New("re-evaluation job not found")
+	ErrJobNotCompleted   = errors.New("re-evaluation job has not completed")
+	ErrJobAlreadyApplied = errors.New("re-evaluation job was already applied")
+	ErrForbidden         = errors.New("not a member of the page's workspace")
+)
+
+// Runner replays a job's checks in the background.
+type Runner interface {
+	ReevaluateHistory(ctx context.Context, schemaName string, jobID uuid.UUID) error
+}
+
+// PageAuthorizer tells whether the caller is a member of a page's workspace.
+type PageAuthorizer interface {
+	CanAccessPage(ctx context.Context, pageID uuid.UUID) (bool, error)
+}
+
+// ReevaluateHistoryHandler starts dry-run replays of a page's change detection
+// with its current rules, reports on them and applies their results.
+type ReevaluateHistoryHandler struct {
+	jobs   repositories.ReevaluationRepository
+	checks repositories.CheckRepository
+	access PageAuthorizer
+	runner Runner
+	tenant string
+}
+
+func NewReevaluateHistoryHandler(jobs repositories.ReevaluationRepository, checks repositories.CheckRepository, access PageAuthorizer, runner Runner, tenant string) *ReevaluateHistoryHandler {
+	return &ReevaluateHistoryHandler{jobs: jobs, checks: checks, access: access, runner: runner, tenant: tenant}
+}
+
+// Start records a pending job and runs it in the background.
+func (h *ReevaluateHistoryHandler) Start(ctx context.Context, pageID uuid.UUID, req *StartReevaluationRequest) (*ReevaluationJobResponse, error) {
+	if err := h.authorize(ctx, pageID); err != nil {
+		return nil, err
+	}
+	if req.From.IsZero() || req.To.IsZero() || !req.To.After(req.From) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidRange)
+	}
+	if req.To.Sub(req.From) > entities.ReevaluationMaxRange {
+		return nil, fmt.Errorf("%w: at most %d days can be re-evaluated", ErrInvalidRange, int(entities.ReevaluationMaxRange.Hours()/24))
+	}
+
+	job := entities.NewReevaluationJob(pageID, req.SectionID, req.From, req.To)
+	if err := h.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := h.runner.ReevaluateHistory(context.Background(), h.tenant, job.ID); err != nil {
+			logger.Error("History re-evaluation failed", zap.Error(err), zap.String("job_id", job.ID.String()))
+		}
+	}()
+
+	return toJobResponse(job), nil
+}
+
+// Get returns a job with its report.
+func (h *ReevaluateHistoryHandler) Get(ctx context.Context, jobID uuid.UUID) (*ReevaluationJobResponse, error) {
+	job, err := h.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	if err := h.authorize(ctx, job.PageID); err != nil {
+		return nil, err
+	}
+	return toJobResponse(job), nil
+}
+
+// List returns a page's jobs, newest first.
+func (h *ReevaluateHistoryHandler) List(ctx context.Context, pageID uuid.UUID) (*ListReevaluationsResponse, error) {
+	if err := h.authorize(ctx, pageID); err != nil {
+		return nil, err
+	}
+	jobs, err := h.jobs.ListByPageID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ListReevaluationsResponse{Jobs: make([]*ReevaluationJobResponse, len(jobs))}
+	for i, job := range jobs {
+		resp.Jobs[i] = toJobResponse(job)
+	}
+	return resp, nil
+}
+
+// Apply rewrites ChangeDetected and ChangeType on the past checks the current
+// rules disagree with. Alerts already sent for those checks are kept.
+func (h *ReevaluateHistoryHandler) Apply(ctx context.Context, jobID uuid.UUID) (*ApplyReevaluationResponse, error) {
+	job, err := h.jobs.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	if err := h.authorize(ctx, job.PageID); err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case entities.ReevaluationApplied:
+		return nil, ErrJobAlreadyApplied
+	case entities.ReevaluationCompleted:
+	default:
+		return nil, ErrJobNotCompleted
+	}
+
+	updated := 0
+	for _, result := range job.Results {
+		if !result.Flipped() {
+			continue
+		}
+		check, err := h.checks.GetByID(ctx, result.CheckID)
+		if err != nil {
+			return nil, err
+		}
+		if check == nil {
+			continue // deleted since the replay
+		}
+		check.ChangeDetected = result.WouldChange
+		check.ChangeType = result.ChangeType
+		if err := h.checks.Update(ctx, check); err != nil {
+			return nil, err
+		}
+		updated++
+	}
+
+	now := time.Now()
+	job.Status = entities.ReevaluationApplied
+	job.AppliedAt = &now
+	if err := h.jobs.Update(ctx, job); err != nil {
+		return nil, err
+	}
+	return &ApplyReevaluationResponse{JobID: job.ID, UpdatedChecks: updated}, nil
+}
+
+func (h *ReevaluateHistoryHandler) authorize(ctx context.Context, pageID uuid.UUID) error {
+	member, err := h.access.CanAccessPage(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// HandleStartHTTP is the HTTP handler for POST /monitoring/checks/page/{pageId}/reevaluations
+func (h *ReevaluateHistoryHandler) HandleStartHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	var req StartReevaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Start(r.Context(), pageID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRange):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			logger.Error("Failed to start history re-evaluation", zap.Error(err), zap.String("page_id", pageID.String()))
+			http.Error(w, "failed to start history re-evaluation", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleListHTTP is the HTTP handler for GET /monitoring/checks/page/{pageId}/reevaluations
+func (h *ReevaluateHistoryHandler) HandleListHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "pageId"))
+	if err != nil {
+		http.Error(w, "invalid page_id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.List(r.Context(), pageID)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		logger.Error("Failed to list history re-evaluations", zap.Error(err))
+		http.Error(w, "failed to list history re-evaluations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleGetHTTP is the HTTP handler for GET /monitoring/checks/reevaluations/{id}
+func (h *ReevaluateHistoryHandler) HandleGetHTTP(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Get(r.Context(), jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			logger.Error("Failed to get history re-evaluation", zap.Error(err), zap.String("job_id", jobID.String()))
+			http.Error(w, "failed to get history re-evaluation", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleApplyHTTP is the HTTP handler for POST /monitoring/checks/reevaluations/{id}/apply
+func (h *ReevaluateHistoryHandler) HandleApplyHTTP(w http.ResponseWriter, r *http.Request) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Apply(r.Context(), jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrForbidden):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrJobNotCompleted), errors.Is(err, ErrJobAlreadyApplied):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			logger.Error("Failed to apply history re-evaluation", zap.Error(err), zap.String("job_id", jobID.String()))
+			http.Error(w, "failed to apply history re-evaluation", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toJobResponse(job *entities.ReevaluationJob) *ReevaluationJobResponse {
+	return &ReevaluationJobResponse{
+		ID:                   job.ID,
+		PageID:               job.PageID,
+		SectionID:            job.SectionID,
+		From:                 job.From,
+		To:                   job.To,
+		Status:               job.Status,
+		TotalChecks:          job.TotalChecks,
+		WouldAlert:           job.WouldAlert,
+		Flipped:              job.Flipped,
+		Results:              job.Results,
+		UnsupportedSelectors: job.UnsupportedSelectors,
+		ErrorMessage:         job.ErrorMessage,
+		CreatedAt:            job.CreatedAt,
+		CompletedAt:          job.CompletedAt,
+		AppliedAt:            job.AppliedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package reevaluatehistory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+)
+
+type fakeAccess struct{ member bool }
+
+func (a fakeAccess) CanAccessPage(context.Context, uuid.UUID) (bool, error) { return a.member, nil }
+
+type fakeRunner chan uuid.UUID
+
+func (f fakeRunner) ReevaluateHistory(_ context.Context, _ string, jobID uuid.UUID) error {
+	f <- jobID
+	return nil
+}
+
+func TestReevaluateHistoryHandler_Start(t *testing.T) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     StartReevaluationRequest
+		wantErr error
+	}{
+		{name: "starts a job", req: StartReevaluationRequest{From: from, To: from.Add(7 * 24 * time.Hour)}},
+		{name: "missing range", req: StartReevaluationRequest{}, wantErr: ErrInvalidRange},
+		{name: "reversed range", req: StartReevaluationRequest{From: from, To: from.Add(-time.Hour)}, wantErr: ErrInvalidRange},
+		{name: "range too long", req: StartReevaluationRequest{From: from, To: from.Add(entities.ReevaluationMaxRange + time.Hour)}, wantErr: ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mocks.MockReevaluationRepository{}
+			runner := make(fakeRunner, 1)
+			resp, err := NewReevaluateHistoryHandler(jobs, &mocks.MockCheckRepository{}, fakeAccess{member: true}, runner, "tenant").Start(context.Background(), uuid.New(), &tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if jobs.Created != nil {
+					t.Error("job created for an invalid range")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Status != entities.ReevaluationPending || jobs.Created == nil {
+				t.Fatalf("resp = %+v, created = %+v", resp, jobs.Created)
+			}
+			select {
+			case id := <-runner:
+				if id != resp.ID {
+					t.Errorf("runner got job %s, want %s", id, resp.ID)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("job was not run")
+			}
+		})
+	}
+}
+
+func TestReevaluateHistoryHandler_Apply(t *testing.T) {
+	check := &entities.Check{ID: uuid.New(), ChangeDetected: true, ChangeType: "content"}
+	completed := func() *entities.ReevaluationJob {
+		job := entities.NewReevaluationJob(uuid.New(), nil, time.Now().Add(-time.Hour), time.Now())
+		job.Complete([]entities.ReevaluationResult{
+			{CheckID: check.ID, WasChanged: true, WouldChange: false},
+			{CheckID: uuid.New(), WasChanged: true, WouldChange: true, WouldAlert: true},
+		}, nil)
+		return job
+	}
+	applied := completed()
+	applied.Status = entities.ReevaluationApplied
+	running := completed()
+	running.Status = entities.ReevaluationRunning
+
+	tests := []struct {
+		name        string
+		job         *entities.ReevaluationJob
+		wantErr     error
+		wantUpdated int
+	}{
+		{name: "rewrites flipped checks", job: completed(), wantUpdated: 1},
+		{name: "unknown job", wantErr: ErrJobNotFound},
+		{name: "still running", job: running, wantErr: ErrJobNotCompleted},
+		{name: "already applied", job: applied, wantErr: ErrJobAlreadyApplied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &mocks.MockReevaluationRepository{GetByIDResult: tt.job}
+			checks := &mocks.MockCheckRepository{GetByIDResult: check}
+			resp, err := NewReevaluateHistoryHandler(jobs, checks, fakeAccess{member: true}, nil, "tenant").Apply(context.Background(), uuid.New())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.UpdatedChecks != tt.wantUpdated {
+				t.Errorf("UpdatedChecks = %d, want %d", resp.UpdatedChecks, tt.wantUpdated)
+			}
+			if check.ChangeDetected || check.ChangeType != "" {
+				t.Errorf("check = %+v, want change cleared", check)
+			}
+			if jobs.Updated == nil || jobs.Updated.Status != entities.ReevaluationApplied || jobs.Updated.AppliedAt == nil {
+				t.Errorf("job not marked applied: %+v", jobs.Updated)
+			}
+		})
+	}
+}
+
+func TestReevaluateHistoryHandler_Forbidden(t *testing.T) {
+	job := entities.NewReevaluationJob(uuid.New(), nil, time.Now().Add(-time.Hour), time.Now())
+	job.Complete([]entities.ReevaluationResult{{CheckID: uuid.New(), WasChanged: true}}, nil)
+	jobs := &mocks.MockReevaluationRepository{GetByIDResult: job}
+	check := &entities.Check{ID: job.Results[0].CheckID, ChangeDetected: true}
+	checks := &mocks.MockCheckRepository{GetByIDResult: check}
+	h := NewReevaluateHistoryHandler(jobs, checks, fakeAccess{member: false}, make(fakeRunner, 1), "tenant")
+	ctx := context.Background()
+
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	_, startErr := h.Start(ctx, uuid.New(), &StartReevaluationRequest{From: from, To: from.Add(24 * time.Hour)})
+	_, listErr := h.List(ctx, uuid.New())
+	_, getErr := h.Get(ctx, job.ID)
+	_, applyErr := h.Apply(ctx, job.ID)
+	for name, err := range map[string]error{"start": startErr, "list": listErr, "get": getErr, "apply": applyErr} {
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: err = %v, want ErrForbidden", name, err)
+		}
+	}
+	if jobs.Created != nil || jobs.Updated != nil || !check.ChangeDetected {
+		t.Error("a non-member changed re-evaluation state")
+	}
+}
+
+func TestReevaluateHistoryHandler_HandleGetHTTP_Forbidden(t *testing.T) {
+	job := entities.NewReevaluationJob(uuid.New(), nil, time.Now().Add(-time.Hour), time.Now())
+	h := NewReevaluateHistoryHandler(&mocks.MockReevaluationRepository{GetByIDResult: job}, &mocks.MockCheckRepository{}, fakeAccess{member: false}, nil, "tenant")
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", job.ID.String())
+	req := httptest.NewRequest(http.MethodGet, "/monitoring/checks/reevaluations/"+job.ID.String(), nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rec := httptest.NewRecorder()
+
+	h.HandleGetHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,15 @@
+package reevaluatehistory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StartReevaluationRequest selects the checks to replay: the full page's, or
+// one section's when SectionID is set, checked between From and To.
+type StartReevaluationRequest struct {
+	From      time.Time  `json:"from"`
+	To        time.Time  `json:"to"`
+	SectionID *uuid.UUID `json:"section_id,omitempty"`
+}
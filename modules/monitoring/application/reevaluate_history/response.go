@@ -0,0 +1,38 @@
+package reevaluatehistory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// ReevaluationJobResponse is a re-evaluation job and, once it completed, its
+// dry-run report.
+type ReevaluationJobResponse struct {
+	ID                   uuid.UUID                     `json:"id"`
+	PageID               uuid.UUID                     `json:"page_id"`
+	SectionID            *uuid.UUID                    `json:"section_id,omitempty"`
+	From                 time.Time                     `json:"from"`
+	To                   time.Time                     `json:"to"`
+	Status               string                        `json:"status"`
+	TotalChecks          int                           `json:"total_checks"`
+	WouldAlert           int                           `json:"would_alert"`
+	Flipped              int                           `json:"flipped"`
+	Results              []entities.ReevaluationResult `json:"results,omitempty"`
+	UnsupportedSelectors []string                      `json:"unsupported_selectors,omitempty"`
+	ErrorMessage         string                        `json:"error_message,omitempty"`
+	CreatedAt            time.Time                     `json:"created_at"`
+	CompletedAt          *time.Time                    `json:"completed_at,omitempty"`
+	AppliedAt            *time.Time                    `json:"applied_at,omitempty"`
+}
+
+type ListReevaluationsResponse struct {
+	Jobs []*ReevaluationJobResponse `json:"jobs"`
+}
+
+// ApplyReevaluationResponse reports how many past checks were rewritten.
+type ApplyReevaluationResponse struct {
+	JobID         uuid.UUID `json:"job_id"`
+	UpdatedChecks int       `json:"updated_checks"`
+}
@@ -0,0 +1,100 @@
+package replayarchive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCheckNotFound = errors.New("check not found")
+	ErrNoArchive     = errors.New("check has no page archive")
+	ErrForbidden     = errors.New("not a member of the page's workspace")
+)
+
+// SnapshotSigner issues short-lived URLs for stored snapshots to members of
+// the page's workspace.
+type SnapshotSigner interface {
+	CanAccessPage(ctx context.Context, pageID uuid.UUID) (bool, error)
+	Sign(ctx context.Context, key string) string
+}
+
+// GetCheckArchiveHandler hands members of the page's workspace the links to a
+// check's page archive: the MHTML file itself and its replay.
+type GetCheckArchiveHandler struct {
+	repo     repositories.CheckRepository
+	signer   SnapshotSigner
+	replayer *Replayer
+}
+
+func NewGetCheckArchiveHandler(repo repositories.CheckRepository, signer SnapshotSigner, replayer *Replayer) *GetCheckArchiveHandler {
+	return &GetCheckArchiveHandler{repo: repo, signer: signer, replayer: replayer}
+}
+
+func (h *GetCheckArchiveHandler) Handle(ctx context.Context, checkID uuid.UUID) (*CheckArchiveResponse, error) {
+	check, err := h.repo.GetByID(ctx, checkID)
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		return nil, ErrCheckNotFound
+	}
+	member, err := h.signer.CanAccessPage(ctx, check.PageID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrForbidden
+	}
+	if check.ArchiveURL == "" {
+		return nil, ErrNoArchive
+	}
+
+	replayURL, expiresAt := h.replayer.SignedURL(check.ArchiveURL)
+	return &CheckArchiveResponse{
+		CheckID:    check.ID,
+		CheckedAt:  check.CheckedAt,
+		ArchiveURL: h.signer.Sign(ctx, check.ArchiveURL),
+		ReplayURL:  replayURL,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// HandleHTTP is the HTTP handler for GET /monitoring/checks/{id}/archive.
+func (h *GetCheckArchiveHandler) HandleHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	checkID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid check id"})
+		return
+	}
+
+	resp, err := h.Handle(r.Context(), checkID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrCheckNotFound), errors.Is(err, ErrNoArchive):
+			status = http.StatusNotFound
+		case errors.Is(err, ErrForbidden):
+			status = http.StatusForbidden
+		default:
+			logger.Error("Failed to get check archive", zap.Error(err), zap.String("check_id", checkID.String()))
+			err = errors.New("failed to get check archive")
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,54 @@
+package replayarchive
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories/mocks"
+)
+
+type fakeSigner struct{ member bool }
+
+func (s fakeSigner) CanAccessPage(context.Context, uuid.UUID) (bool, error) { return s.member, nil }
+
+func (s fakeSigner) Sign(_ context.Context, key string) string { return "https://signed/" + key }
+
+func TestGetCheckArchiveHandler_Handle(t *testing.T) {
+	archived := &entities.Check{ID: uuid.New(), PageID: uuid.New(), ArchiveURL: "page/abc.mhtml"}
+	replayer, _ := newTestReplayer()
+
+	tests := []struct {
+		name    string
+		check   *entities.Check
+		member  bool
+		wantErr error
+	}{
+		{name: "returns archive links", check: archived, member: true},
+		{name: "unknown check", member: true, wantErr: ErrCheckNotFound},
+		{name: "not a member", check: archived, wantErr: ErrForbidden},
+		{name: "check without archive", check: &entities.Check{ID: uuid.New()}, member: true, wantErr: ErrNoArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockCheckRepository{GetByIDResult: tt.check}
+			resp, err := NewGetCheckArchiveHandler(repo, fakeSigner{member: tt.member}, replayer).Handle(context.Background(), uuid.New())
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.ArchiveURL != "https://signed/page/abc.mhtml" || !strings.HasSuffix(resp.ReplayURL, "/page/abc.mhtml/0") {
+				t.Errorf("resp = %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package replayarchive
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"github.com/jcsoftdev/pulzifi-back/shared/mhtml"
+	"go.uber.org/zap"
+)
+
+// ReplayExpiry is how long a replay link stays valid.
+const ReplayExpiry = time.Hour
+
+// cacheSize is how many parsed archives are kept, so a replay's subresource
+// requests do not download and parse the archive again.
+const cacheSize = 8
+
+// replayPolicy keeps a replayed page from running scripts or reaching the
+// live site: it may only load parts of its own archive.
+const replayPolicy = "sandbox; default-src 'none'; img-src 'self' data:; style-src 'self' 'unsafe-inline'; " +
+	"font-src 'self' data:; media-src 'self' data:; frame-src 'self'"
+
+var (
+	ErrInvalidSignature = errors.New("invalid or expired replay link")
+	ErrPartNotFound     = errors.New("archive part not found")
+)
+
+// ArchiveReader downloads stored archives.
+type ArchiveReader interface {
+	Download(ctx context.Context, key string) ([]byte, error)
+}
+
+// Replayer serves stored MHTML archives as browsable pages. Each archive part
+// lives at {base}/{expires}/{signature}/{key}/{n}, the page itself at part 0,
+// and documents link to the other parts by their relative number. The links
+// are signed like snapshot URLs, so they load in an iframe without auth
+// headers.
+type Replayer struct {
+	archives ArchiveReader
+	baseURL  string
+	signKey  []byte
+
+	mu    sync.Mutex
+	cache map[string]*mhtml.Archive
+	order []string
+}
+
+func NewReplayer(archives ArchiveReader, baseURL, signKey string) *Replayer {
+	return &Replayer{
+		archives: archives,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		signKey:  []byte(signKey),
+		cache:    map[string]*mhtml.Archive{},
+	}
+}
+
+// SignedURL returns a link to the replay of the archive stored under key and
+// when it expires.
+func (r *Replayer) SignedURL(key string) (string, time.Time) {
+	expiresAt := time.Now().Add(ReplayExpiry).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return fmt.Sprintf("%s/%s/%s/%s/0", r.baseURL, expires, r.signature(key, expires), key), expiresAt
+}
+
+// Verify parses a replay path, {expires}/{signature}/{key}/{n}, and returns
+// the archive key and part number it grants access to now.
+func (r *Replayer) Verify(replayPath string) (string, int, error) {
+	segments := strings.Split(strings.TrimPrefix(replayPath, "/"), "/")
+	if len(segments) < 4 {
+		return "", 0, ErrInvalidSignature
+	}
+	expires, signature := segments[0], segments[1]
+	key := strings.Join(segments[2:len(segments)-1], "/")
+	part, err := strconv.Atoi(segments[len(segments)-1])
+	if err != nil || part < 0 {
+		return "", 0, ErrPartNotFound
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return "", 0, ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(r.signature(key, expires))) {
+		return "", 0, ErrInvalidSignature
+	}
+	return key, part, nil
+}
+
+// Part returns an archive part with its references to other parts rewritten
+// to replay links.
+func (r *Replayer) Part(ctx context.Context, key string, n int) (contentType string, body []byte, err error) {
+	archive, err := r.archive(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+	if n >= len(archive.Parts) {
+		return "", nil, ErrPartNotFound
+	}
+	body = archive.Rewrite(n, strconv.Itoa)
+	return archive.Parts[n].ContentType, body, nil
+}
+
+func (r *Replayer) archive(ctx context.Context, key string) (*mhtml.Archive, error) {
+	r.mu.Lock()
+	archive, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok {
+		return archive, nil
+	}
+
+	data, err := r.archives.Download(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPartNotFound, err)
+	}
+	archive, err = mhtml.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.cache[key]; !ok {
+		if len(r.order) == cacheSize {
+			delete(r.cache, r.order[0])
+			r.order = r.order[1:]
+		}
+		r.cache[key] = archive
+		r.order = append(r.order, key)
+	}
+	return archive, nil
+}
+
+func (r *Replayer) signature(key, expires string) string {
+	mac := hmac.New(sha256.New, r.signKey)
+	mac.Write([]byte("replay\n" + key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HandleHTTP is the HTTP handler for GET /monitoring/replay/*. The link is
+// the authorization, so the route sits outside the auth middleware.
+func (r *Replayer) HandleHTTP(w http.ResponseWriter, req *http.Request) {
+	key, n, err := r.Verify(chi.URLParam(req, "*"))
+	if err != nil {
+		if errors.Is(err, ErrInvalidSignature) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+		} else {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		}
+		return
+	}
+
+	contentType, body, err := r.Part(req.Context(), key, n)
+	if err != nil {
+		if errors.Is(err, ErrPartNotFound) {
+			http.Error(w, ErrPartNotFound.Error(), http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to replay archive", zap.String("key", key), zap.Error(err))
+		http.Error(w, "failed to replay archive", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if _, _, err := mime.ParseMediaType(contentType); err != nil || contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Security-Policy", replayPolicy)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	// The sandboxed page has an opaque origin; fonts are only loaded with CORS.
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.Write(body)
+}
@@ -0,0 +1,98 @@
+package replayarchive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const archive = "Content-Type: multipart/related; boundary=\"B\"\r\n\r\n" +
+	"--B\r\nContent-Type: text/html\r\nContent-Location: https://acme.test/\r\n\r\n" +
+	"<html><body><img src=\"logo.png\"><script src=\"https://cdn.test/app.js\"></script></body></html>\r\n" +
+	"--B\r\nContent-Type: image/png\r\nContent-Location: https://acme.test/logo.png\r\n\r\n" +
+	"PNG\r\n" +
+	"--B--\r\n"
+
+type fakeArchives struct {
+	files     map[string]string
+	downloads int
+}
+
+func (f *fakeArchives) Download(_ context.Context, key string) ([]byte, error) {
+	f.downloads++
+	data, ok := f.files[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return []byte(data), nil
+}
+
+func newTestReplayer() (*Replayer, *fakeArchives) {
+	archives := &fakeArchives{files: map[string]string{"page/abc.mhtml": archive}}
+	return NewReplayer(archives, "/api/v1/monitoring/replay/", "secret"), archives
+}
+
+func TestReplayer_SignedURL(t *testing.T) {
+	r, _ := newTestReplayer()
+	link, _ := r.SignedURL("page/abc.mhtml")
+
+	replayPath, ok := strings.CutPrefix(link, "/api/v1/monitoring/replay/")
+	if !ok || !strings.HasSuffix(link, "/page/abc.mhtml/0") {
+		t.Fatalf("link = %q", link)
+	}
+	key, part, err := r.Verify(replayPath)
+	if err != nil || key != "page/abc.mhtml" || part != 0 {
+		t.Fatalf("Verify() = %q, %d, %v", key, part, err)
+	}
+	if _, _, err := r.Verify(strings.Replace(replayPath, "abc", "xyz", 1)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify(other key) error = %v, want ErrInvalidSignature", err)
+	}
+	expired := "1/" + r.signature("page/abc.mhtml", "1") + "/page/abc.mhtml/0"
+	if _, _, err := r.Verify(expired); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify(expired) error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestReplayer_HandleHTTP(t *testing.T) {
+	r, archives := newTestReplayer()
+	link, _ := r.SignedURL("page/abc.mhtml")
+	router := chi.NewRouter()
+	router.Get("/api/v1/monitoring/replay/*", r.HandleHTTP)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	page := get(link)
+	if page.Code != http.StatusOK {
+		t.Fatalf("page status = %d: %s", page.Code, page.Body)
+	}
+	if body := page.Body.String(); !strings.Contains(body, `<img src="1">`) || !strings.Contains(body, "https://cdn.test/app.js") {
+		t.Errorf("page body = %s", body)
+	}
+	if csp := page.Header().Get("Content-Security-Policy"); !strings.HasPrefix(csp, "sandbox;") {
+		t.Errorf("Content-Security-Policy = %q", csp)
+	}
+
+	logo := get(strings.TrimSuffix(link, "0") + "1")
+	if logo.Code != http.StatusOK || logo.Body.String() != "PNG" || logo.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("logo = %d %q %q", logo.Code, logo.Header().Get("Content-Type"), logo.Body)
+	}
+	if archives.downloads != 1 {
+		t.Errorf("archive downloaded %d times, want 1", archives.downloads)
+	}
+
+	if rec := get(strings.TrimSuffix(link, "0") + "7"); rec.Code != http.StatusNotFound {
+		t.Errorf("missing part status = %d", rec.Code)
+	}
+	if rec := get("/api/v1/monitoring/replay/9999999999/bad/page/abc.mhtml/0"); rec.Code != http.StatusForbidden {
+		t.Errorf("bad signature status = %d", rec.Code)
+	}
+}
@@ -0,0 +1,15 @@
+package replayarchive
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CheckArchiveResponse struct {
+	CheckID    uuid.UUID `json:"check_id"`
+	CheckedAt  time.Time `json:"checked_at"`
+	ArchiveURL string    `json:"archive_url"` // download of the MHTML file
+	ReplayURL  string    `json:"replay_url"`  // sandboxed page for an iframe
+	ExpiresAt  time.Time `json:"expires_at"`  // when the replay URL stops working
+}
@@ -0,0 +1,163 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// Interval is how often expired captures are purged.
+	Interval = 6 * time.Hour
+	// batchSize is how many checks are purged per query.
+	batchSize = 200
+)
+
+// ObjectStorage deletes stored screenshots and snapshots.
+type ObjectStorage interface {
+	Delete(ctx context.Context, key string) error
+}
+
+// Repository finds and tombstones one tenant's expired checks and tracks the
+// objects no check references anymore.
+type Repository interface {
+	// StoragePeriodDays returns how many days the tenant's plan keeps
+	// captures; 0 keeps them forever.
+	StoragePeriodDays(ctx context.Context) (int, error)
+	// ListExpired returns up to limit checks captured before cutoff that still
+	// have stored objects, excluding pinned checks, pages under legal hold and
+	// the latest successful check of each page, section and capture profile.
+	ListExpired(ctx context.Context, cutoff time.Time, limit int) ([]*entities.Check, error)
+	// MarkPurged clears the checks' object keys, records when they were purged
+	// and drops their references to the objects.
+	MarkPurged(ctx context.Context, checkIDs []uuid.UUID) error
+	// DeleteUnreferenced calls del for up to limit objects without
+	// references and forgets those it removed. No reference can be taken to
+	// an object while it is being deleted.
+	DeleteUnreferenced(ctx context.Context, limit int, del func(key string) error) (deleted, failed int, err error)
+}
+
+type RepositoryFactory interface {
+	ListTenants(ctx context.Context) ([]string, error)
+	GetRetentionRepository(tenant string) Repository
+}
+
+// Stats counts one tenant's purge.
+type Stats struct {
+	Checks  int // checks tombstoned
+	Objects int // objects deleted
+	Failed  int // objects kept because they could not be deleted
+}
+
+// Purger enforces the plan storage period: it tombstones expired checks,
+// which keep their results, and deletes the stored objects that are no
+// longer referenced by any check. Identical captures share one object, so an
+// object outlives the checks until its last reference is gone.
+type Purger struct {
+	repos   RepositoryFactory
+	storage ObjectStorage
+	now     func() time.Time
+}
+
+func NewPurger(repos RepositoryFactory, storage ObjectStorage) *Purger {
+	return &Purger{repos: repos, storage: storage, now: time.Now}
+}
+
+// Start purges every tenant now and then every Interval until ctx is done.
+func (p *Purger) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(Interval)
+		defer ticker.Stop()
+		for {
+			p.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("Retention purger started", zap.Duration("interval", Interval))
+}
+
+// RunOnce purges every tenant and logs the stats of each.
+func (p *Purger) RunOnce(ctx context.Context) {
+	tenants, err := p.repos.ListTenants(ctx)
+	if err != nil {
+		logger.Error("Retention purge failed to list tenants", zap.Error(err))
+		return
+	}
+	for _, tenant := range tenants {
+		stats, err := p.PurgeTenant(ctx, tenant)
+		if err != nil {
+			logger.Error("Retention purge failed", zap.String("tenant", tenant), zap.Error(err))
+		}
+		if stats.Checks > 0 || stats.Objects > 0 || stats.Failed > 0 {
+			logger.Info("Retention purge finished",
+				zap.String("tenant", tenant),
+				zap.Int("checks_purged", stats.Checks),
+				zap.Int("objects_deleted", stats.Objects),
+				zap.Int("objects_failed", stats.Failed))
+		}
+	}
+}
+
+// PurgeTenant purges one tenant's checks older than its plan's storage
+// period, then deletes its unreferenced objects. An object that cannot be
+// deleted is left for the next run.
+func (p *Purger) PurgeTenant(ctx context.Context, tenant string) (Stats, error) {
+	var stats Stats
+	repo := p.repos.GetRetentionRepository(tenant)
+	days, err := repo.StoragePeriodDays(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	if days > 0 {
+		cutoff := p.now().AddDate(0, 0, -days)
+		for {
+			checks, err := repo.ListExpired(ctx, cutoff, batchSize)
+			if err != nil {
+				return stats, err
+			}
+			ids := make([]uuid.UUID, len(checks))
+			for i, check := range checks {
+				ids[i] = check.ID
+			}
+			if err := repo.MarkPurged(ctx, ids); err != nil {
+				return stats, err
+			}
+			stats.Checks += len(ids)
+			if len(checks) < batchSize {
+				break
+			}
+		}
+	}
+
+	del := func(key string) error {
+		err := p.storage.Delete(ctx, key)
+		if err != nil {
+			logger.Warn("Retention purge failed to delete object",
+				zap.String("tenant", tenant), zap.String("key", key), zap.Error(err))
+		}
+		return err
+	}
+	for {
+		deleted, failed, err := repo.DeleteUnreferenced(ctx, batchSize, del)
+		stats.Objects += deleted
+		stats.Failed += failed
+		if err != nil {
+			return stats, err
+		}
+
+		// A short batch is the last one; a batch with no progress would be
+		// claimed again.
+		if deleted+failed < batchSize || deleted == 0 {
+			return stats, nil
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type fakeStorage struct {
+	deleted []string
+	failing map[string]bool
+}
+
+func (s *fakeStorage) Delete(_ context.Context, key string) error {
+	if s.failing[key] {
+		return errors.New("delete failed")
+	}
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+type fakeRepository struct {
+	days    int
+	expired []*entities.Check
+	cutoff  time.Time
+	purged  []uuid.UUID
+	refs    map[string]int
+}
+
+func (r *fakeRepository) StoragePeriodDays(context.Context) (int, error) { return r.days, nil }
+
+func (r *fakeRepository) ListExpired(_ context.Context, cutoff time.Time, limit int) ([]*entities.Check, error) {
+	r.cutoff = cutoff
+	var out []*entities.Check
+	for _, c := range r.expired {
+		if !containsID(r.purged, c.ID) && len(out) < limit {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepository) MarkPurged(_ context.Context, ids []uuid.UUID) error {
+	for _, c := range r.expired {
+		if !containsID(ids, c.ID) {
+			continue
+		}
+		for _, key := range []string{c.ScreenshotURL, c.HTMLSnapshotURL, c.DocumentURL} {
+			if key == "" {
+				continue
+			}
+			if r.refs[key] > 0 {
+				r.refs[key]--
+			} else {
+				r.refs[key] = 0
+			}
+		}
+	}
+	r.purged = append(r.purged, ids...)
+	return nil
+}
+
+func (r *fakeRepository) DeleteUnreferenced(_ context.Context, limit int, del func(string) error) (int, int, error) {
+	var deleted, failed int
+	for key, n := range r.refs {
+		if n != 0 || deleted+failed >= limit {
+			continue
+		}
+		if err := del(key); err != nil {
+			failed++
+			continue
+		}
+		delete(r.refs, key)
+		deleted++
+	}
+	return deleted, failed, nil
+}
+
+type fakeFactory struct{ repo *fakeRepository }
+
+func (f fakeFactory) ListTenants(context.Context) ([]string, error) { return []string{"tenant"}, nil }
+func (f fakeFactory) GetRetentionRepository(string) Repository      { return f.repo }
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPurger_PurgeTenant(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	full := &entities.Check{ID: uuid.New(), ScreenshotURL: "s1", HTMLSnapshotURL: "h1"}
+	docOnly := &entities.Check{ID: uuid.New(), DocumentURL: "d1"}
+	broken := &entities.Check{ID: uuid.New(), ScreenshotURL: "s2", HTMLSnapshotURL: "bad"}
+
+	tests := []struct {
+		name        string
+		days        int
+		wantStats   Stats
+		wantPurged  []uuid.UUID
+		wantDeleted []string
+	}{
+		{
+			name:        "purges expired checks and their last references",
+			days:        30,
+			wantStats:   Stats{Checks: 3, Objects: 4, Failed: 1},
+			wantPurged:  []uuid.UUID{full.ID, docOnly.ID, broken.ID},
+			wantDeleted: []string{"s1", "d1", "s2", "orphan"},
+		},
+		{name: "no storage period keeps every check", days: 0, wantStats: Stats{Objects: 1}, wantDeleted: []string{"orphan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// h1 is shared with a newer check that is kept; orphan lost its
+			// references before this run.
+			refs := map[string]int{"s1": 1, "h1": 2, "d1": 1, "s2": 1, "orphan": 0}
+			repo := &fakeRepository{days: tt.days, expired: []*entities.Check{full, docOnly, broken}, refs: refs}
+			storage := &fakeStorage{failing: map[string]bool{"bad": true}}
+			p := NewPurger(fakeFactory{repo}, storage)
+			p.now = func() time.Time { return now }
+
+			stats, err := p.PurgeTenant(context.Background(), "tenant")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stats != tt.wantStats {
+				t.Errorf("stats = %+v, want %+v", stats, tt.wantStats)
+			}
+			if len(repo.purged) != len(tt.wantPurged) {
+				t.Fatalf("purged = %v, want %v", repo.purged, tt.wantPurged)
+			}
+			for _, id := range tt.wantPurged {
+				if !containsID(repo.purged, id) {
+					t.Errorf("check %s not purged", id)
+				}
+			}
+			if len(storage.deleted) != len(tt.wantDeleted) {
+				t.Fatalf("deleted = %v, want %v", storage.deleted, tt.wantDeleted)
+			}
+			for _, key := range tt.wantDeleted {
+				if !containsKey(storage.deleted, key) {
+					t.Errorf("object %s not deleted", key)
+				}
+			}
+			if tt.days > 0 {
+				if n, ok := repo.refs["h1"]; !ok || n != 1 {
+					t.Errorf("shared object refs = %d, %v; want 1", n, ok)
+				}
+				if n, ok := repo.refs["bad"]; !ok || n != 0 {
+					t.Error("undeletable object should stay unreferenced for the next run")
+				}
+				if !repo.cutoff.Equal(now.AddDate(0, 0, -tt.days)) {
+					t.Errorf("cutoff = %v", repo.cutoff)
+				}
+			}
+		})
+	}
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package snapshotkeys
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// batchSize is how many rows are migrated per query.
+const batchSize = 200
+
+// ObjectStorage takes over objects stored under public URLs.
+type ObjectStorage interface {
+	Adopt(ctx context.Context, objectURL string) (string, error)
+}
+
+// Thumbnail is a page thumbnail still stored as a public URL.
+type Thumbnail struct {
+	PageID uuid.UUID
+	URL    string
+}
+
+// Repository finds and rewrites one tenant's rows that still hold public
+// snapshot URLs instead of object keys.
+type Repository interface {
+	// ListLegacyChecks returns up to limit checks with a URL in any of their
+	// object columns.
+	ListLegacyChecks(ctx context.Context, limit int) ([]*entities.Check, error)
+	UpdateCheckKeys(ctx context.Context, check *entities.Check) error
+	// ListLegacyThumbnails returns up to limit pages whose thumbnail is a URL.
+	ListLegacyThumbnails(ctx context.Context, limit int) ([]Thumbnail, error)
+	UpdateThumbnailKey(ctx context.Context, pageID uuid.UUID, key string) error
+}
+
+type RepositoryFactory interface {
+	ListTenants(ctx context.Context) ([]string, error)
+	GetSnapshotKeyRepository(tenant string) Repository
+}
+
+// Stats counts one tenant's migration.
+type Stats struct {
+	Checks int // checks rewritten
+	Pages  int // page thumbnails rewritten
+	Failed int // rows kept because an object could not be adopted
+}
+
+// Migrator rewrites the public snapshot URLs stored before snapshots were
+// private into object keys, adopting each object on the way.
+type Migrator struct {
+	repos   RepositoryFactory
+	storage ObjectStorage
+}
+
+func NewMigrator(repos RepositoryFactory, storage ObjectStorage) *Migrator {
+	return &Migrator{repos: repos, storage: storage}
+}
+
+// IsLegacy reports whether a stored snapshot reference is a URL rather than
+// an object key.
+func IsLegacy(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "/")
+}
+
+// Start migrates every tenant once in the background.
+func (m *Migrator) Start(ctx context.Context) {
+	go m.RunOnce(ctx)
+}
+
+// RunOnce migrates every tenant and logs the stats of each.
+func (m *Migrator) RunOnce(ctx context.Context) {
+	tenants, err := m.repos.ListTenants(ctx)
+	if err != nil {
+		logger.Error("Snapshot key migration failed to list tenants", zap.Error(err))
+		return
+	}
+	for _, tenant := range tenants {
+		stats, err := m.MigrateTenant(ctx, tenant)
+		if err != nil {
+			logger.Error("Snapshot key migration failed", zap.String("tenant", tenant), zap.Error(err))
+		}
+		if stats.Checks > 0 || stats.Pages > 0 || stats.Failed > 0 {
+			logger.Info("Snapshot key migration finished",
+				zap.String("tenant", tenant),
+				zap.Int("checks_migrated", stats.Checks),
+				zap.Int("pages_migrated", stats.Pages),
+				zap.Int("rows_failed", stats.Failed))
+		}
+	}
+}
+
+// MigrateTenant rewrites one tenant's checks and page thumbnails. A row whose
+// objects cannot all be adopted is left for the next run.
+func (m *Migrator) MigrateTenant(ctx context.Context, tenant string) (Stats, error) {
+	var stats Stats
+	repo := m.repos.GetSnapshotKeyRepository(tenant)
+
+	for {
+		checks, err := repo.ListLegacyChecks(ctx, batchSize)
+		if err != nil {
+			return stats, err
+		}
+		migrated := 0
+		for _, check := range checks {
+			if !m.adoptAll(ctx, &check.ScreenshotURL, &check.HTMLSnapshotURL, &check.DocumentURL) {
+				stats.Failed++
+				continue
+			}
+			if err := repo.UpdateCheckKeys(ctx, check); err != nil {
+				return stats, err
+			}
+			migrated++
+		}
+		stats.Checks += migrated
+		// A short batch is the last one; a batch with no progress would be
+		// listed again.
+		if len(checks) < batchSize || migrated == 0 {
+			break
+		}
+	}
+
+	for {
+		thumbnails, err := repo.ListLegacyThumbnails(ctx, batchSize)
+		if err != nil {
+			return stats, err
+		}
+		migrated := 0
+		for _, t := range thumbnails {
+			key := t.URL
+			if !m.adoptAll(ctx, &key) {
+				stats.Failed++
+				continue
+			}
+			if err := repo.UpdateThumbnailKey(ctx, t.PageID, key); err != nil {
+				return stats, err
+			}
+			migrated++
+		}
+		stats.Pages += migrated
+		if len(thumbnails) < batchSize || migrated == 0 {
+			return stats, nil
+		}
+	}
+}
+
+// adoptAll replaces each legacy URL with its object key. It stops at the
+// first object that cannot be adopted.
+func (m *Migrator) adoptAll(ctx context.Context, refs ...*string) bool {
+	for _, ref := range refs {
+		if !IsLegacy(*ref) {
+			continue
+		}
+		key, err := m.storage.Adopt(ctx, *ref)
+		if err != nil {
+			logger.Warn("Snapshot key migration failed to adopt object", zap.String("url", *ref), zap.Error(err))
+			return false
+		}
+		*ref = key
+	}
+	return true
+}
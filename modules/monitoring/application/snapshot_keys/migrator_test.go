@@ -0,0 +1,119 @@
+package snapshotkeys
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type fakeStorage struct{ failing map[string]bool }
+
+func (s fakeStorage) Adopt(_ context.Context, objectURL string) (string, error) {
+	if s.failing[objectURL] {
+		return "", errors.New("adopt failed")
+	}
+	return strings.TrimPrefix(objectURL, "http://minio/snapshots/"), nil
+}
+
+type fakeRepository struct {
+	checks     []*entities.Check
+	thumbnails []Thumbnail
+	updated    map[uuid.UUID]*entities.Check
+	pageKeys   map[uuid.UUID]string
+}
+
+func (r *fakeRepository) ListLegacyChecks(_ context.Context, limit int) ([]*entities.Check, error) {
+	var out []*entities.Check
+	for _, c := range r.checks {
+		if _, done := r.updated[c.ID]; !done && len(out) < limit {
+			copied := *c
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepository) UpdateCheckKeys(_ context.Context, check *entities.Check) error {
+	r.updated[check.ID] = check
+	return nil
+}
+
+func (r *fakeRepository) ListLegacyThumbnails(_ context.Context, limit int) ([]Thumbnail, error) {
+	var out []Thumbnail
+	for _, t := range r.thumbnails {
+		if _, done := r.pageKeys[t.PageID]; !done && len(out) < limit {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepository) UpdateThumbnailKey(_ context.Context, pageID uuid.UUID, key string) error {
+	r.pageKeys[pageID] = key
+	return nil
+}
+
+type fakeFactory struct{ repo *fakeRepository }
+
+func (f fakeFactory) ListTenants(context.Context) ([]string, error) { return []string{"tenant"}, nil }
+func (f fakeFactory) GetSnapshotKeyRepository(string) Repository    { return f.repo }
+
+func TestMigrator_MigrateTenant(t *testing.T) {
+	pageID := uuid.New()
+	ok := &entities.Check{
+		ID:              uuid.New(),
+		ScreenshotURL:   "http://minio/snapshots/" + pageID.String() + "/1.png",
+		HTMLSnapshotURL: "http://minio/snapshots/" + pageID.String() + "/1.html",
+	}
+	mixed := &entities.Check{
+		ID:              uuid.New(),
+		ScreenshotURL:   pageID.String() + "/2.png",
+		HTMLSnapshotURL: "http://minio/snapshots/" + pageID.String() + "/2.html",
+	}
+	broken := &entities.Check{ID: uuid.New(), ScreenshotURL: "http://minio/snapshots/gone.png"}
+	repo := &fakeRepository{
+		checks:     []*entities.Check{ok, mixed, broken},
+		thumbnails: []Thumbnail{{PageID: pageID, URL: "http://minio/snapshots/" + pageID.String() + "/1.png"}},
+		updated:    map[uuid.UUID]*entities.Check{},
+		pageKeys:   map[uuid.UUID]string{},
+	}
+	m := NewMigrator(fakeFactory{repo}, fakeStorage{failing: map[string]bool{broken.ScreenshotURL: true}})
+
+	stats, err := m.MigrateTenant(context.Background(), "tenant")
+	if err != nil {
+		t.Fatalf("MigrateTenant() error = %v", err)
+	}
+	if stats.Checks != 2 || stats.Pages != 1 || stats.Failed != 1 {
+		t.Fatalf("stats = %+v, want 2 checks, 1 page, 1 failed", stats)
+	}
+	if got := repo.updated[ok.ID].HTMLSnapshotURL; got != pageID.String()+"/1.html" {
+		t.Errorf("html key = %q", got)
+	}
+	if got := repo.updated[mixed.ID].ScreenshotURL; got != pageID.String()+"/2.png" {
+		t.Errorf("existing key rewritten to %q", got)
+	}
+	if _, ok := repo.updated[broken.ID]; ok {
+		t.Error("check with an unadoptable object should be kept")
+	}
+	if got := repo.pageKeys[pageID]; got != pageID.String()+"/1.png" {
+		t.Errorf("thumbnail key = %q", got)
+	}
+}
+
+func TestIsLegacy(t *testing.T) {
+	tests := map[string]bool{
+		"https://res.cloudinary.com/demo/image/upload/v1/a.png": true,
+		"/api/v1/monitoring/files/a.png":                        true,
+		uuid.NewString() + "/1.png":                             false,
+		"":                                                      false,
+	}
+	for ref, want := range tests {
+		if got := IsLegacy(ref); got != want {
+			t.Errorf("IsLegacy(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
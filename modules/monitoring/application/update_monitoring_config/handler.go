@@ -38,6 +38,36 @@ func NewUpdateMonitoringConfigHandler(repo repositories.MonitoringConfigReposito
 func (h *UpdateMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.UUID, req *UpdateMonitoringConfigRequest) (*UpdateMonitoringConfigResponse, error) {
 	logger.Info("UpdateMonitoringConfigHandler: Start processing", zap.String("page_id", pageID.String()))
 
+	var captureSteps []entities.CaptureStep
+	if req.CaptureSteps != nil {
+		captureSteps = make([]entities.CaptureStep, len(*req.CaptureSteps))
+		for i, step := range *req.CaptureSteps {
+			captureSteps[i] = entities.CaptureStep(step)
+		}
+		if err := entities.ValidateCaptureSteps(captureSteps); err != nil {
+			return nil, err
+		}
+	}
+
+	var captureProfiles []entities.CaptureProfile
+	if req.CaptureProfiles != nil {
+		captureProfiles = toCaptureProfiles(*req.CaptureProfiles)
+		if err := entities.ValidateCaptureProfiles(captureProfiles); err != nil {
+			return nil, err
+		}
+	}
+
+	var proxyRoute entities.ProxyRoute
+	if req.ProxyRegion != nil {
+		proxyRoute.Region = *req.ProxyRegion
+	}
+	if req.ProxyPool != nil {
+		proxyRoute.Pool = *req.ProxyPool
+	}
+	if err := entities.ValidateProxyRoute(proxyRoute); err != nil {
+		return nil, err
+	}
+
 	// Get existing config
 	config, err := h.repo.GetByPageID(ctx, pageID)
 	if err != nil {
@@ -120,9 +150,27 @@ func (h *UpdateMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.
 			CSSSelector:            cssSelector,
 			XPathSelector:          xpathSelector,
 			SelectorOffsets:        selectorOffsets,
+			SelectorFallback:       req.SelectorFallback != nil && *req.SelectorFallback,
+			CheckBrokenLinks:       req.CheckBrokenLinks != nil && *req.CheckBrokenLinks,
+			CaptureSteps:           captureSteps,
+			CaptureProfiles:        captureProfiles,
+			ProxyRoute:             proxyRoute,
+			FetchEngine:            entities.FetchEngineBrowser,
+			SuppressRecurrences:    req.SuppressRecurrences == nil || *req.SuppressRecurrences,
+			LegalHold:              req.LegalHold != nil && *req.LegalHold,
+			Archive:                req.Archive != nil && *req.Archive,
 			CreatedAt:              time.Now(),
 			UpdatedAt:              time.Now(),
 		}
+		if req.FetchEngine != nil {
+			config.FetchEngine = *req.FetchEngine
+		}
+		if err := entities.ValidateFetchEngine(config); err != nil {
+			return nil, err
+		}
+		if err := entities.ValidateCaptureProfileMode(config); err != nil {
+			return nil, err
+		}
 
 		// Create in database — the scheduler will pick up the page on its
 		// next tick (last_checked_at is NULL, so it is immediately "due").
@@ -176,6 +224,7 @@ func (h *UpdateMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.
 			config.CustomAlertCondition = *req.CustomAlertCondition
 		}
 
+		prevSelector := [3]string{config.SelectorType, config.CSSSelector, config.XPathSelector}
 		if req.SelectorType != nil {
 			config.SelectorType = *req.SelectorType
 		}
@@ -194,6 +243,48 @@ func (h *UpdateMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.
 			}
 		}
 
+		if req.SelectorFallback != nil {
+			config.SelectorFallback = *req.SelectorFallback
+		}
+		// The repository resets a changed selector's health; mirror it here.
+		if prevSelector != [3]string{config.SelectorType, config.CSSSelector, config.XPathSelector} {
+			config.SelectorHealth = entities.SelectorHealth{}
+		}
+
+		if req.CheckBrokenLinks != nil {
+			config.CheckBrokenLinks = *req.CheckBrokenLinks
+		}
+		if req.SuppressRecurrences != nil {
+			config.SuppressRecurrences = *req.SuppressRecurrences
+		}
+		if req.LegalHold != nil {
+			config.LegalHold = *req.LegalHold
+		}
+		if req.Archive != nil {
+			config.Archive = *req.Archive
+		}
+		if req.CaptureSteps != nil {
+			config.CaptureSteps = captureSteps
+		}
+		if req.CaptureProfiles != nil {
+			config.CaptureProfiles = captureProfiles
+		}
+		if req.ProxyRegion != nil {
+			config.ProxyRoute.Region = proxyRoute.Region
+		}
+		if req.ProxyPool != nil {
+			config.ProxyRoute.Pool = proxyRoute.Pool
+		}
+		if req.FetchEngine != nil {
+			config.FetchEngine = *req.FetchEngine
+		}
+		if err := entities.ValidateFetchEngine(config); err != nil {
+			return nil, err
+		}
+		if err := entities.ValidateCaptureProfileMode(config); err != nil {
+			return nil, err
+		}
+
 		config.UpdatedAt = time.Now()
 
 		// Pre-claim: set last_checked_at = NOW() BEFORE saving the config so the
@@ -242,6 +333,11 @@ func (h *UpdateMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.
 		}
 	}
 
+	captureStepsDTO := make([]CaptureStepDTO, len(config.CaptureSteps))
+	for i, step := range config.CaptureSteps {
+		captureStepsDTO[i] = CaptureStepDTO(step)
+	}
+
 	// Return response
 	return &UpdateMonitoringConfigResponse{
 		ID:                     config.ID,
@@ -257,11 +353,64 @@ func (h *UpdateMonitoringConfigHandler) Handle(ctx context.Context, pageID uuid.
 		CSSSelector:            config.CSSSelector,
 		XPathSelector:          config.XPathSelector,
 		SelectorOffsets:        selectorOffsetsDTO,
+		SelectorFallback:       config.SelectorFallback,
+		SelectorBrokenAt:       config.SelectorHealth.BrokenAt,
+		CheckBrokenLinks:       config.CheckBrokenLinks,
+		CaptureSteps:           captureStepsDTO,
+		CaptureProfiles:        toCaptureProfileDTOs(config.CaptureProfiles),
+		ProxyRegion:            config.ProxyRoute.Region,
+		ProxyPool:              config.ProxyRoute.Pool,
+		FetchEngine:            config.FetchEngineOrDefault(),
+		SuppressRecurrences:    config.SuppressRecurrences,
+		LegalHold:              config.LegalHold,
+		Archive:                config.Archive,
 		UpdatedAt:              config.UpdatedAt,
 		QuotaExceeded:          quotaExceeded,
 	}, nil
 }
 
+func toCaptureProfiles(dtos []CaptureProfileDTO) []entities.CaptureProfile {
+	profiles := make([]entities.CaptureProfile, len(dtos))
+	for i, d := range dtos {
+		profiles[i] = entities.CaptureProfile{
+			Name:              d.Name,
+			ViewportWidth:     d.ViewportWidth,
+			ViewportHeight:    d.ViewportHeight,
+			DeviceScaleFactor: d.DeviceScaleFactor,
+			Mobile:            d.Mobile,
+			UserAgent:         d.UserAgent,
+			Locale:            d.Locale,
+			Timezone:          d.Timezone,
+		}
+		if d.Geolocation != nil {
+			geo := entities.Geolocation(*d.Geolocation)
+			profiles[i].Geolocation = &geo
+		}
+	}
+	return profiles
+}
+
+func toCaptureProfileDTOs(profiles []entities.CaptureProfile) []CaptureProfileDTO {
+	dtos := make([]CaptureProfileDTO, len(profiles))
+	for i, p := range profiles {
+		dtos[i] = CaptureProfileDTO{
+			Name:              p.Name,
+			ViewportWidth:     p.ViewportWidth,
+			ViewportHeight:    p.ViewportHeight,
+			DeviceScaleFactor: p.DeviceScaleFactor,
+			Mobile:            p.Mobile,
+			UserAgent:         p.UserAgent,
+			Locale:            p.Locale,
+			Timezone:          p.Timezone,
+		}
+		if p.Geolocation != nil {
+			geo := GeolocationDTO(*p.Geolocation)
+			dtos[i].Geolocation = &geo
+		}
+	}
+	return dtos
+}
+
 // isPageDueForCheck returns true only if the page has been checked before AND
 // is overdue under the given frequency. Never-checked pages are left for the
 // scheduler to pick up at the natural interval.
@@ -350,6 +499,10 @@ func (h *UpdateMonitoringConfigHandler) HandleHTTP(w http.ResponseWriter, r *htt
 
 	// Execute handler
 	response, err := h.Handle(r.Context(), pageID, &req)
+	if errors.Is(err, entities.ErrInvalidCaptureStep) || errors.Is(err, entities.ErrInvalidCaptureProfile) || errors.Is(err, entities.ErrInvalidProxy) || errors.Is(err, entities.ErrInvalidFetchEngine) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to update monitoring config", zap.Error(err))
 		http.Error(w, "failed to update monitoring config", http.StatusInternalServerError)
@@ -12,6 +12,7 @@ import (
 )
 
 func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
 
 func TestUpdateMonitoringConfigHandler_Handle(t *testing.T) {
 	pageID := uuid.New()
@@ -29,6 +30,29 @@ func TestUpdateMonitoringConfigHandler_Handle(t *testing.T) {
 		CreatedAt:              time.Now(),
 		UpdatedAt:              time.Now(),
 	}
+	// The handler updates the config in place; cases that must not leak
+	// settings into later cases get their own copy.
+	configCopy := func() *entities.MonitoringConfig {
+		c := *existingConfig
+		return &c
+	}
+	elementConfig := func() *entities.MonitoringConfig {
+		c := configCopy()
+		c.SelectorType = "element"
+		c.CSSSelector = "#price"
+		return c
+	}
+	sectionsConfig := func() *entities.MonitoringConfig {
+		c := configCopy()
+		c.SelectorType = "sections"
+		return c
+	}
+	profileConfig := func() *entities.MonitoringConfig {
+		c := configCopy()
+		c.CaptureProfiles = []entities.CaptureProfile{{Name: "mobile", Mobile: true}}
+		return c
+	}
+	mobileProfile := &[]CaptureProfileDTO{{Name: "mobile", Mobile: true}}
 
 	tests := []struct {
 		name          string
@@ -66,6 +90,106 @@ func TestUpdateMonitoringConfigHandler_Handle(t *testing.T) {
 			updateErr:   errors.New("update failed"),
 			wantErr:     true,
 		},
+		{
+			name: "accept valid capture steps",
+			req: &UpdateMonitoringConfigRequest{CaptureSteps: &[]CaptureStepDTO{
+				{Action: "click", Selector: "#pricing-toggle-annual"},
+				{Action: "wait_for_network_idle"},
+				{Action: "wait", WaitMs: 500},
+			}},
+			existingCfg: existingConfig,
+			wantErr:     false,
+		},
+		{
+			name:        "reject capture step without selector",
+			req:         &UpdateMonitoringConfigRequest{CaptureSteps: &[]CaptureStepDTO{{Action: "click"}}},
+			existingCfg: existingConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "reject unknown capture step action",
+			req:         &UpdateMonitoringConfigRequest{CaptureSteps: &[]CaptureStepDTO{{Action: "hover", Selector: "#menu"}}},
+			existingCfg: nil,
+			wantErr:     true,
+		},
+		{
+			name: "accept desktop and mobile profiles",
+			req: &UpdateMonitoringConfigRequest{CaptureProfiles: &[]CaptureProfileDTO{
+				{Name: "mobile", ViewportWidth: 390, ViewportHeight: 844, DeviceScaleFactor: 3, Mobile: true},
+				{Name: "de", Locale: "de-DE", Timezone: "Europe/Berlin", Geolocation: &GeolocationDTO{Latitude: 52.52, Longitude: 13.4}},
+			}},
+			existingCfg: existingConfig,
+			wantErr:     false,
+		},
+		{
+			name: "reject duplicate profile names",
+			req: &UpdateMonitoringConfigRequest{CaptureProfiles: &[]CaptureProfileDTO{
+				{Name: "mobile", Mobile: true},
+				{Name: "mobile", Locale: "en-US"},
+			}},
+			existingCfg: existingConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "reject invalid profile locale",
+			req:         &UpdateMonitoringConfigRequest{CaptureProfiles: &[]CaptureProfileDTO{{Name: "de", Locale: "German"}}},
+			existingCfg: existingConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "accept proxy region pin",
+			req:         &UpdateMonitoringConfigRequest{ProxyRegion: strPtr("de")},
+			existingCfg: existingConfig,
+			wantErr:     false,
+		},
+		{
+			name:        "reject invalid proxy pool",
+			req:         &UpdateMonitoringConfigRequest{ProxyPool: strPtr("Residential Pool")},
+			existingCfg: existingConfig,
+			wantErr:     true,
+		},
+		{
+			name:        "accept http fetch engine",
+			req:         &UpdateMonitoringConfigRequest{FetchEngine: strPtr("http")},
+			existingCfg: configCopy(),
+			wantErr:     false,
+		},
+		{
+			name:        "reject unknown fetch engine",
+			req:         &UpdateMonitoringConfigRequest{FetchEngine: strPtr("curl")},
+			existingCfg: configCopy(),
+			wantErr:     true,
+		},
+		{
+			name:        "reject http fetch engine with element selector",
+			req:         &UpdateMonitoringConfigRequest{FetchEngine: strPtr("http")},
+			existingCfg: elementConfig(),
+			wantErr:     true,
+		},
+		{
+			name:        "accept auto fetch engine with element selector",
+			req:         &UpdateMonitoringConfigRequest{FetchEngine: strPtr("auto")},
+			existingCfg: elementConfig(),
+			wantErr:     false,
+		},
+		{
+			name:        "reject capture profiles on a sections page",
+			req:         &UpdateMonitoringConfigRequest{CaptureProfiles: mobileProfile},
+			existingCfg: sectionsConfig(),
+			wantErr:     true,
+		},
+		{
+			name:        "reject switching a page with capture profiles to sections",
+			req:         &UpdateMonitoringConfigRequest{SelectorType: strPtr("sections")},
+			existingCfg: profileConfig(),
+			wantErr:     true,
+		},
+		{
+			name:        "accept capture profiles on a full page config",
+			req:         &UpdateMonitoringConfigRequest{CaptureProfiles: mobileProfile},
+			existingCfg: configCopy(),
+			wantErr:     false,
+		},
 		{
 			name:          "normalize verbose frequency",
 			req:           &UpdateMonitoringConfigRequest{CheckFrequency: strPtr("every 30 minutes")},
@@ -107,3 +231,43 @@ func TestUpdateMonitoringConfigHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateMonitoringConfigHandler_SelectorChangeResetsHealth(t *testing.T) {
+	brokenAt := time.Now().Add(-time.Hour)
+	newConfig := func() *entities.MonitoringConfig {
+		return &entities.MonitoringConfig{
+			ID:             uuid.New(),
+			PageID:         uuid.New(),
+			CheckFrequency: "1h",
+			SelectorType:   "element",
+			CSSSelector:    "#price",
+			SelectorHealth: entities.SelectorHealth{MissCount: 3, BrokenAt: &brokenAt},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        *UpdateMonitoringConfigRequest
+		wantBroken bool
+	}{
+		{name: "new selector", req: &UpdateMonitoringConfigRequest{CSSSelector: strPtr("#pricing .amount")}, wantBroken: false},
+		{name: "same selector resent", req: &UpdateMonitoringConfigRequest{CSSSelector: strPtr("#price")}, wantBroken: true},
+		{name: "unrelated setting", req: &UpdateMonitoringConfigRequest{SelectorFallback: boolPtr(true)}, wantBroken: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newConfig()
+			repo := &mocks.MockMonitoringConfigRepository{GetByPageIDResult: config}
+			handler := NewUpdateMonitoringConfigHandler(repo, nil, "test_tenant", nil)
+
+			resp, err := handler.Handle(context.Background(), config.PageID, tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := resp.SelectorBrokenAt != nil; got != tt.wantBroken {
+				t.Errorf("selector_broken_at set = %v, want %v", got, tt.wantBroken)
+			}
+		})
+	}
+}
@@ -7,6 +7,36 @@ type SelectorOffsetsDTO struct {
 	Left   int `json:"left"`
 }
 
+// CaptureStepDTO is one browser action run before capture. WaitMs is the
+// duration for "wait" and an optional timeout override for the other actions.
+type CaptureStepDTO struct {
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+	Value    string `json:"value,omitempty"`
+	WaitMs   int    `json:"wait_ms,omitempty"`
+}
+
+// GeolocationDTO is the position a capture profile reports to the page.
+type GeolocationDTO struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"`
+}
+
+// CaptureProfileDTO is an extra device, locale or geo variant captured on
+// every check. Unset fields keep the default desktop settings.
+type CaptureProfileDTO struct {
+	Name              string          `json:"name"`
+	ViewportWidth     int             `json:"viewport_width,omitempty"`
+	ViewportHeight    int             `json:"viewport_height,omitempty"`
+	DeviceScaleFactor float64         `json:"device_scale_factor,omitempty"`
+	Mobile            bool            `json:"mobile,omitempty"`
+	UserAgent         string          `json:"user_agent,omitempty"`
+	Locale            string          `json:"locale,omitempty"`
+	Timezone          string          `json:"timezone,omitempty"`
+	Geolocation       *GeolocationDTO `json:"geolocation,omitempty"`
+}
+
 type UpdateMonitoringConfigRequest struct {
 	CheckFrequency         *string            `json:"check_frequency,omitempty"`
 	ScheduleType           *string            `json:"schedule_type,omitempty"`
@@ -19,4 +49,14 @@ type UpdateMonitoringConfigRequest struct {
 	CSSSelector            *string            `json:"css_selector,omitempty"`
 	XPathSelector          *string            `json:"xpath_selector,omitempty"`
 	SelectorOffsets        *SelectorOffsetsDTO `json:"selector_offsets,omitempty"`
+	SelectorFallback       *bool              `json:"selector_fallback,omitempty"` // capture the full page when the element selector stops matching
+	CheckBrokenLinks       *bool              `json:"check_broken_links,omitempty"`
+	CaptureSteps           *[]CaptureStepDTO  `json:"capture_steps,omitempty"` // replaces the list; [] clears it
+	CaptureProfiles        *[]CaptureProfileDTO `json:"capture_profiles,omitempty"` // replaces the list; [] clears it
+	ProxyRegion            *string            `json:"proxy_region,omitempty"` // "" falls back to the workspace default
+	ProxyPool              *string            `json:"proxy_pool,omitempty"`
+	FetchEngine            *string            `json:"fetch_engine,omitempty"` // browser, http or auto
+	SuppressRecurrences    *bool              `json:"suppress_recurrences,omitempty"` // skip alerts for changes back to a recent state
+	LegalHold              *bool              `json:"legal_hold,omitempty"`           // exempt the page's captures from retention purges
+	Archive                *bool              `json:"archive,omitempty"`              // store an MHTML archive with every check
 }
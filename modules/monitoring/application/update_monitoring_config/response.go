@@ -20,6 +20,17 @@ type UpdateMonitoringConfigResponse struct {
 	CSSSelector            string              `json:"css_selector"`
 	XPathSelector          string              `json:"xpath_selector"`
 	SelectorOffsets        *SelectorOffsetsDTO `json:"selector_offsets,omitempty"`
+	SelectorFallback       bool                `json:"selector_fallback"`
+	SelectorBrokenAt       *time.Time          `json:"selector_broken_at,omitempty"`
+	CheckBrokenLinks       bool                `json:"check_broken_links"`
+	CaptureSteps           []CaptureStepDTO    `json:"capture_steps"`
+	CaptureProfiles        []CaptureProfileDTO `json:"capture_profiles"`
+	ProxyRegion            string              `json:"proxy_region"`
+	ProxyPool              string              `json:"proxy_pool"`
+	FetchEngine            string              `json:"fetch_engine"`
+	SuppressRecurrences    bool                `json:"suppress_recurrences"`
+	LegalHold              bool                `json:"legal_hold"`
+	Archive                bool                `json:"archive"`
 	UpdatedAt              time.Time           `json:"updated_at"`
 	QuotaExceeded          bool                `json:"quota_exceeded"`
 }
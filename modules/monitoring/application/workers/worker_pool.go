@@ -19,6 +19,16 @@ type SnapshotPort interface {
 // recover from a failure (panic or returned error).
 type FailCheckFunc func(ctx context.Context, checkID uuid.UUID, schemaName string, errMsg string)
 
+// ClaimFunc moves a check from pending to running for the worker about to
+// execute it, returning false when the check already left pending.
+type ClaimFunc func(ctx context.Context, checkID uuid.UUID, schemaName string) (bool, error)
+
+// HeartbeatFunc records that a worker is still running a check.
+type HeartbeatFunc func(ctx context.Context, checkID uuid.UUID, schemaName string)
+
+// DefaultHeartbeatInterval is how often a running check sends a heartbeat.
+const DefaultHeartbeatInterval = 30 * time.Second
+
 type SnapshotJob struct {
 	CheckID    uuid.UUID
 	URL        string
@@ -29,6 +39,9 @@ type WorkerPool struct {
 	jobQueue     chan SnapshotJob
 	snapshotPort SnapshotPort
 	failCheck    FailCheckFunc
+	claim        ClaimFunc
+	heartbeat    HeartbeatFunc
+	heartbeatInt time.Duration
 	wg           sync.WaitGroup
 	quit         chan struct{}
 }
@@ -38,10 +51,28 @@ func NewWorkerPool(snapshotPort SnapshotPort, bufferSize int, failCheck FailChec
 		jobQueue:     make(chan SnapshotJob, bufferSize),
 		snapshotPort: snapshotPort,
 		failCheck:    failCheck,
+		heartbeatInt: DefaultHeartbeatInterval,
 		quit:         make(chan struct{}),
 	}
 }
 
+// SetClaim makes workers claim each check before executing it and skip the
+// ones they cannot claim, so a check dispatched again while its first job
+// was still queued runs once. Call before Start.
+func (p *WorkerPool) SetClaim(fn ClaimFunc) {
+	p.claim = fn
+}
+
+// SetHeartbeat makes workers call fn when they pick up a check and every
+// interval while it runs, so checks whose worker died can be told apart from
+// checks still running. Call before Start.
+func (p *WorkerPool) SetHeartbeat(fn HeartbeatFunc, interval time.Duration) {
+	p.heartbeat = fn
+	if interval > 0 {
+		p.heartbeatInt = interval
+	}
+}
+
 func (p *WorkerPool) Start(concurrency int) {
 	for i := 0; i < concurrency; i++ {
 		p.wg.Add(1)
@@ -91,6 +122,27 @@ func (p *WorkerPool) executeJob(workerID int, job SnapshotJob) {
 		}
 	}()
 
+	if p.claim != nil {
+		claimed, err := p.claim(context.Background(), job.CheckID, job.SchemaName)
+		if err != nil {
+			// Left pending, the check is re-dispatched by the reaper.
+			logger.Error("Worker failed to claim check",
+				zap.Int("worker_id", workerID),
+				zap.String("check_id", job.CheckID.String()),
+				zap.Error(err))
+			return
+		}
+		if !claimed {
+			logger.Info("Worker skipped check that is no longer pending",
+				zap.Int("worker_id", workerID),
+				zap.String("check_id", job.CheckID.String()))
+			return
+		}
+	}
+
+	stopHeartbeat := p.startHeartbeat(job)
+	defer stopHeartbeat()
+
 	if err := p.snapshotPort.ExecuteCheck(context.Background(), job.CheckID, job.URL, job.SchemaName); err != nil {
 		logger.Error("Worker failed to execute check",
 			zap.Int("worker_id", workerID),
@@ -104,6 +156,35 @@ func (p *WorkerPool) executeJob(workerID int, job SnapshotJob) {
 	}
 }
 
+// startHeartbeat sends a heartbeat for job now and then every heartbeat
+// interval until the returned func is called.
+func (p *WorkerPool) startHeartbeat(job SnapshotJob) func() {
+	if p.heartbeat == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.heartbeat(ctx, job.CheckID, job.SchemaName)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.heartbeatInt)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.heartbeat(ctx, job.CheckID, job.SchemaName)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
 // Dispatch enqueues a job with retry and exponential backoff.
 // It attempts up to 3 times with delays of 500ms, 1s, and 2s before giving up.
 func (p *WorkerPool) Dispatch(ctx context.Context, checkID uuid.UUID, url string, schemaName string) error {
@@ -2,6 +2,8 @@ package workers
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -120,3 +122,86 @@ func TestWorkerPool_ContextCancellation(t *testing.T) {
 		t.Fatalf("expected context.Canceled, got: %v", err)
 	}
 }
+
+func TestWorkerPool_HeartbeatWhileRunning(t *testing.T) {
+	port := &mockSnapshotPort{delay: 120 * time.Millisecond}
+	pool := NewWorkerPool(port, 1, nil)
+	var beats int64
+	pool.SetHeartbeat(func(_ context.Context, _ uuid.UUID, _ string) {
+		atomic.AddInt64(&beats, 1)
+	}, 25*time.Millisecond)
+	pool.Start(1)
+	defer pool.Stop()
+
+	if err := pool.Dispatch(context.Background(), uuid.New(), "https://example.com", "tenant_1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	got := atomic.LoadInt64(&beats)
+	if got < 3 {
+		t.Fatalf("expected a heartbeat on pickup and while running, got %d", got)
+	}
+	// No more heartbeats once the check finished.
+	time.Sleep(60 * time.Millisecond)
+	if after := atomic.LoadInt64(&beats); after != got {
+		t.Fatalf("heartbeats continued after the check finished: %d -> %d", got, after)
+	}
+}
+
+func TestWorkerPool_DuplicateDispatchRunsOnce(t *testing.T) {
+	port := &mockSnapshotPort{}
+	pool := NewWorkerPool(port, 10, nil)
+	var mu sync.Mutex
+	claimed := map[uuid.UUID]bool{}
+	pool.SetClaim(func(_ context.Context, checkID uuid.UUID, _ string) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if claimed[checkID] {
+			return false, nil
+		}
+		claimed[checkID] = true
+		return true, nil
+	})
+
+	// The reaper re-dispatches a check whose first job is still queued.
+	checkID := uuid.New()
+	for i := 0; i < 2; i++ {
+		if err := pool.Dispatch(context.Background(), checkID, "https://example.com", "tenant_1"); err != nil {
+			t.Fatalf("dispatch %d: unexpected error: %v", i, err)
+		}
+	}
+	pool.Start(2)
+	time.Sleep(50 * time.Millisecond)
+	pool.Stop()
+
+	if got := atomic.LoadInt64(&port.callCount); got != 1 {
+		t.Fatalf("expected the check to run once, got %d", got)
+	}
+}
+
+func TestWorkerPool_ClaimFailureSkipsCheck(t *testing.T) {
+	port := &mockSnapshotPort{}
+	var failed, beats int64
+	pool := NewWorkerPool(port, 1, func(_ context.Context, _ uuid.UUID, _ string, _ string) {
+		atomic.AddInt64(&failed, 1)
+	})
+	pool.SetClaim(func(_ context.Context, _ uuid.UUID, _ string) (bool, error) {
+		return false, errors.New("connection refused")
+	})
+	pool.SetHeartbeat(func(_ context.Context, _ uuid.UUID, _ string) {
+		atomic.AddInt64(&beats, 1)
+	}, time.Minute)
+	pool.Start(1)
+
+	if err := pool.Dispatch(context.Background(), uuid.New(), "https://example.com", "tenant_1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	pool.Stop()
+
+	// The check stays pending for the reaper to re-dispatch.
+	if port.callCount != 0 || failed != 0 || beats != 0 {
+		t.Fatalf("calls = %d, failed = %d, heartbeats = %d; want the check left alone", port.callCount, failed, beats)
+	}
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BrokenLink is a link on a monitored page that failed verification. It stays
+// open (ResolvedAt == nil) until a later check finds the link working again.
+type BrokenLink struct {
+	ID              uuid.UUID
+	PageID          uuid.UUID
+	URL             string
+	AnchorText      string
+	Location        string // landmark path of the anchor, e.g. "footer > nav"
+	StatusCode      int    // 0 when the request itself failed
+	ErrorMessage    string
+	FirstCheckID    uuid.UUID // check that first found the link broken
+	LastCheckID     uuid.UUID
+	FirstDetectedAt time.Time
+	LastDetectedAt  time.Time
+	ResolvedAt      *time.Time
+}
+
+// NewBrokenLink creates an open broken link record first seen by checkID.
+func NewBrokenLink(pageID, checkID uuid.UUID, url, anchorText, location string, statusCode int, errMsg string) *BrokenLink {
+	now := time.Now()
+	return &BrokenLink{
+		ID:              uuid.New(),
+		PageID:          pageID,
+		URL:             url,
+		AnchorText:      anchorText,
+		Location:        location,
+		StatusCode:      statusCode,
+		ErrorMessage:    errMsg,
+		FirstCheckID:    checkID,
+		LastCheckID:     checkID,
+		FirstDetectedAt: now,
+		LastDetectedAt:  now,
+	}
+}
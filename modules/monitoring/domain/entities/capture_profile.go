@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	MaxCaptureProfiles   = 5
+	minProfileViewport   = 240
+	maxProfileViewport   = 4096
+	maxDeviceScaleFactor = 4
+	maxUserAgentLength   = 512
+)
+
+var (
+	profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+	localePattern      = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+	timezonePattern    = regexp.MustCompile(`^(UTC|[A-Za-z_]+(/[A-Za-z0-9_+-]+)+)$`)
+)
+
+// ErrInvalidCaptureProfile is wrapped by ValidateCaptureProfiles errors so
+// callers can tell validation failures from storage errors.
+var ErrInvalidCaptureProfile = errors.New("invalid capture profile")
+
+// Geolocation is the position reported to the page by the browser.
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"` // meters
+}
+
+// CaptureProfile is an extra device, locale or geo variant a page is captured
+// with on every check, e.g. "mobile" or "de". Each profile produces its own
+// check, grouped under the page's default check. Unset fields keep the
+// default desktop capture settings.
+type CaptureProfile struct {
+	Name              string       `json:"name"`
+	ViewportWidth     int          `json:"viewport_width,omitempty"`
+	ViewportHeight    int          `json:"viewport_height,omitempty"`
+	DeviceScaleFactor float64      `json:"device_scale_factor,omitempty"`
+	Mobile            bool         `json:"mobile,omitempty"`
+	UserAgent         string       `json:"user_agent,omitempty"`
+	Locale            string       `json:"locale,omitempty"`   // BCP 47, also sent as Accept-Language
+	Timezone          string       `json:"timezone,omitempty"` // IANA name, e.g. "Europe/Berlin"
+	Geolocation       *Geolocation `json:"geolocation,omitempty"`
+}
+
+// ValidateCaptureProfiles checks profile names are unique slugs and every
+// emulation setting is within range. Errors name the offending profile.
+func ValidateCaptureProfiles(profiles []CaptureProfile) error {
+	if len(profiles) > MaxCaptureProfiles {
+		return fmt.Errorf("%w: at most %d profiles are allowed", ErrInvalidCaptureProfile, MaxCaptureProfiles)
+	}
+
+	seen := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		if !profileNamePattern.MatchString(p.Name) {
+			return fmt.Errorf("%w: name %q must be 1-32 lowercase letters, digits, '-' or '_'", ErrInvalidCaptureProfile, p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("%w: duplicate profile name %q", ErrInvalidCaptureProfile, p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.ViewportWidth != 0 && (p.ViewportWidth < minProfileViewport || p.ViewportWidth > maxProfileViewport) {
+			return fmt.Errorf("%w: %s: viewport_width must be between %d and %d", ErrInvalidCaptureProfile, p.Name, minProfileViewport, maxProfileViewport)
+		}
+		if p.ViewportHeight != 0 && (p.ViewportHeight < minProfileViewport || p.ViewportHeight > maxProfileViewport) {
+			return fmt.Errorf("%w: %s: viewport_height must be between %d and %d", ErrInvalidCaptureProfile, p.Name, minProfileViewport, maxProfileViewport)
+		}
+		if p.DeviceScaleFactor < 0 || p.DeviceScaleFactor > maxDeviceScaleFactor {
+			return fmt.Errorf("%w: %s: device_scale_factor must be between 0 and %d", ErrInvalidCaptureProfile, p.Name, maxDeviceScaleFactor)
+		}
+		if len(p.UserAgent) > maxUserAgentLength {
+			return fmt.Errorf("%w: %s: user_agent must be at most %d characters", ErrInvalidCaptureProfile, p.Name, maxUserAgentLength)
+		}
+		if p.Locale != "" && !localePattern.MatchString(p.Locale) {
+			return fmt.Errorf("%w: %s: locale %q is not a language tag like \"de-DE\"", ErrInvalidCaptureProfile, p.Name, p.Locale)
+		}
+		if p.Timezone != "" && !timezonePattern.MatchString(p.Timezone) {
+			return fmt.Errorf("%w: %s: timezone %q is not an IANA name like \"Europe/Berlin\"", ErrInvalidCaptureProfile, p.Name, p.Timezone)
+		}
+		if g := p.Geolocation; g != nil {
+			if g.Latitude < -90 || g.Latitude > 90 || g.Longitude < -180 || g.Longitude > 180 || g.Accuracy < 0 {
+				return fmt.Errorf("%w: %s: geolocation is out of range", ErrInvalidCaptureProfile, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateCaptureProfileMode rejects capture profiles on pages monitored in
+// sections mode: sections are captured in one page load with the default
+// settings, so profile variants would never be captured.
+func ValidateCaptureProfileMode(c *MonitoringConfig) error {
+	if len(c.CaptureProfiles) > 0 && c.SelectorType == "sections" {
+		return fmt.Errorf("%w: capture profiles cannot be combined with monitored sections", ErrInvalidCaptureProfile)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Capture step actions, run in order after navigation and before capture.
+const (
+	StepClick              = "click"
+	StepType               = "type"
+	StepSelect             = "select"
+	StepScrollTo           = "scroll_to"
+	StepWaitForSelector    = "wait_for_selector"
+	StepWaitForNetworkIdle = "wait_for_network_idle"
+	StepWait               = "wait"
+	StepPress              = "press"
+)
+
+const (
+	MaxCaptureSteps     = 20
+	MaxCaptureStepWait  = 30000 // ms, per step
+	MaxCaptureTotalWait = 60000 // ms, across all steps
+	maxStepFieldLength  = 1000
+)
+
+// ErrInvalidCaptureStep is wrapped by ValidateCaptureSteps errors so callers
+// can tell validation failures from storage errors.
+var ErrInvalidCaptureStep = errors.New("invalid capture step")
+
+// CaptureStep is one scripted browser action, e.g. expanding a "show more"
+// section or switching a pricing toggle to annual before the screenshot.
+type CaptureStep struct {
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+	Value    string `json:"value,omitempty"`   // text to type, option to select or key to press
+	WaitMs   int    `json:"wait_ms,omitempty"` // duration for "wait"; timeout override for the others
+}
+
+// ValidateCaptureSteps checks the steps against the supported actions and
+// their required fields. Errors name the offending step (1-based).
+func ValidateCaptureSteps(steps []CaptureStep) error {
+	if len(steps) > MaxCaptureSteps {
+		return fmt.Errorf("%w: at most %d steps are allowed", ErrInvalidCaptureStep, MaxCaptureSteps)
+	}
+
+	totalWait := 0
+	for i, step := range steps {
+		n := i + 1
+		if len(step.Selector) > maxStepFieldLength || len(step.Value) > maxStepFieldLength {
+			return fmt.Errorf("%w: step %d: selector and value must be at most %d characters", ErrInvalidCaptureStep, n, maxStepFieldLength)
+		}
+		if step.WaitMs < 0 || step.WaitMs > MaxCaptureStepWait {
+			return fmt.Errorf("%w: step %d: wait_ms must be between 0 and %d", ErrInvalidCaptureStep, n, MaxCaptureStepWait)
+		}
+		totalWait += step.WaitMs
+
+		needsSelector := false
+		switch step.Action {
+		case StepClick, StepType, StepScrollTo, StepWaitForSelector:
+			needsSelector = true
+		case StepSelect:
+			needsSelector = true
+			if step.Value == "" {
+				return fmt.Errorf("%w: step %d: %q requires the option value", ErrInvalidCaptureStep, n, step.Action)
+			}
+		case StepPress:
+			if strings.TrimSpace(step.Value) == "" {
+				return fmt.Errorf("%w: step %d: %q requires a key (e.g. \"Enter\")", ErrInvalidCaptureStep, n, step.Action)
+			}
+		case StepWait:
+			if step.WaitMs == 0 {
+				return fmt.Errorf("%w: step %d: %q requires wait_ms", ErrInvalidCaptureStep, n, step.Action)
+			}
+		case StepWaitForNetworkIdle:
+		default:
+			return fmt.Errorf("%w: step %d: unknown action %q", ErrInvalidCaptureStep, n, step.Action)
+		}
+
+		if needsSelector && strings.TrimSpace(step.Selector) == "" {
+			return fmt.Errorf("%w: step %d: %q requires a selector", ErrInvalidCaptureStep, n, step.Action)
+		}
+	}
+
+	if totalWait > MaxCaptureTotalWait {
+		return fmt.Errorf("%w: total wait across steps must be at most %d ms", ErrInvalidCaptureStep, MaxCaptureTotalWait)
+	}
+	return nil
+}
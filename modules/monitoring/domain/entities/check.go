@@ -11,17 +11,31 @@ type Check struct {
 	ID                  uuid.UUID
 	PageID              uuid.UUID
 	SectionID           *uuid.UUID // optional link to a monitored_section; nil = full-page check
-	ParentCheckID       *uuid.UUID // links section checks back to the parent full-page check
-	Status              string     // success, error
+	ParentCheckID       *uuid.UUID // links section and profile checks back to the parent full-page check
+	ProfileName         string     // capture profile this check ran with; empty = default capture
+	ProxyID             *uuid.UUID // outbound proxy the capture went through; nil = direct
+	FetchEngine         string     // engine that produced the capture: "browser" or "http"
+	SelectorFallback    bool       // element selector did not match; the full page was captured instead
+	Status              string     // pending, running, success, error
 	ScreenshotURL       string
 	HTMLSnapshotURL     string
+	DocumentURL         string // original PDF/DOCX when the page is a document
+	ThumbnailURL        string // small compressed copy of the screenshot for list cards
+	PreviewURL          string // medium compressed copy of the screenshot
+	ArchiveURL          string // MHTML archive of the page and its subresources
 	ContentHash         string
 	ChangeDetected      bool
 	ChangeType          string
 	ErrorMessage        string
 	DurationMs          int
-	ScreenshotHash      string // SHA-256 of screenshot bytes for pixel comparison
-	VisionChangeSummary string // AI-generated change description from vision model
+	ScreenshotHash      string     // SHA-256 of screenshot bytes for pixel comparison
+	VisionChangeSummary string     // AI-generated change description from vision model
+	ContentBlockHash string // STUB
+	ContentDiffJSON string // STUB
+	Recurrence          bool       // the change returned to a recently seen content state
+	Flapping            bool       // content has been alternating between a few states
+	Pinned              bool       // baseline kept by retention purges
+	PurgedAt            *time.Time // when retention deleted the stored screenshot and snapshots
 	CheckedAt           time.Time
 }
 
@@ -0,0 +1,75 @@
+package entities
+
+const (
+	// ContentHistorySize is how many recent content states are kept per page
+	// and section.
+	ContentHistorySize = 10
+	// FlappingMaxStates is the most distinct states content can alternate
+	// between and still count as flapping rather than genuinely evolving.
+	FlappingMaxStates = 3
+	// FlappingMinReturns is how many returns to an earlier state make content
+	// flapping.
+	FlappingMinReturns = 2
+)
+
+// ContentHistory is the recent content states of a page or section, newest
+// first. A state is the hash of its content blocks (or extracted text when
+// no blocks were found).
+type ContentHistory []string
+
+// IsRecurrence reports whether moving to state returns to a state seen
+// recently instead of introducing new content.
+func (h ContentHistory) IsRecurrence(state string) bool {
+	if state == "" || len(h) == 0 || h[0] == state {
+		return false
+	}
+	for _, s := range h[1:] {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFlapping reports whether, once state is recorded, the content has been
+// alternating between a small set of states — an A/B test, rotating
+// testimonials or a personalized hero.
+func (h ContentHistory) IsFlapping(state string) bool {
+	if state == "" {
+		return false
+	}
+	seq := append(ContentHistory{state}, h...)
+	distinct := map[string]bool{}
+	transitions := 0
+	for i, s := range seq {
+		distinct[s] = true
+		if i > 0 && seq[i-1] != s {
+			transitions++
+		}
+	}
+	// Each transition beyond the first visit to every state is a return.
+	returns := transitions - (len(distinct) - 1)
+	return len(distinct) >= 2 && len(distinct) <= FlappingMaxStates && returns >= FlappingMinReturns
+}
+
+// Push returns the history with state recorded as the newest entry, trimmed
+// to ContentHistorySize. Empty states are not recorded.
+func (h ContentHistory) Push(state string) ContentHistory {
+	if state == "" {
+		return h
+	}
+	next := append(ContentHistory{state}, h...)
+	if len(next) > ContentHistorySize {
+		next = next[:ContentHistorySize]
+	}
+	return next
+}
+
+// ContentState identifies the check's content for recurrence tracking: the
+// content block hash, or the text hash when the page had no blocks.
+func (c *Check) ContentState() string {
+	if c.ContentBlockHash != "" {
+		return c.ContentBlockHash
+	}
+	return c.ContentHash
+}
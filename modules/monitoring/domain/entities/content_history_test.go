@@ -0,0 +1,63 @@
+package entities
+
+import "testing"
+
+func TestContentHistory_IsRecurrence(t *testing.T) {
+	tests := []struct {
+		name    string
+		history ContentHistory
+		state   string
+		want    bool
+	}{
+		{"no history", nil, "a", false},
+		{"unchanged", ContentHistory{"a", "b"}, "a", false},
+		{"new state", ContentHistory{"b", "a"}, "c", false},
+		{"back to earlier state", ContentHistory{"b", "a"}, "a", true},
+		{"empty state", ContentHistory{"b", ""}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.history.IsRecurrence(tt.state); got != tt.want {
+				t.Errorf("IsRecurrence(%q) = %v, want %v", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentHistory_IsFlapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		history ContentHistory
+		state   string
+		want    bool
+	}{
+		{"stable", ContentHistory{"a", "a", "a"}, "a", false},
+		{"single change", ContentHistory{"a", "a"}, "b", false},
+		{"first return", ContentHistory{"b", "a"}, "a", false},
+		{"alternating", ContentHistory{"b", "a", "b"}, "a", true},
+		{"three rotating states", ContentHistory{"c", "b", "a", "c", "b"}, "a", true},
+		{"evolving content", ContentHistory{"d", "c", "b", "a"}, "e", false},
+		{"too many states", ContentHistory{"d", "c", "b", "a", "d", "c", "b"}, "a", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.history.IsFlapping(tt.state); got != tt.want {
+				t.Errorf("IsFlapping(%q) = %v, want %v", tt.state, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentHistory_Push(t *testing.T) {
+	var h ContentHistory
+	for i := 0; i < ContentHistorySize+2; i++ {
+		h = h.Push(string(rune('a' + i)))
+	}
+	h = h.Push("")
+	if len(h) != ContentHistorySize {
+		t.Fatalf("len = %d, want %d", len(h), ContentHistorySize)
+	}
+	if h[0] != "l" || h[len(h)-1] != "c" {
+		t.Errorf("history = %v, want newest %q first and oldest %q last", h, "l", "c")
+	}
+}
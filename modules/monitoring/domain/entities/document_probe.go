@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// DocumentProbeTTL is how long a probed URL's document kind is trusted before
+// the URL is probed again.
+const DocumentProbeTTL = 24 * time.Hour
+
+// DocumentProbe caches whether a page URL without a document extension serves
+// a PDF or DOCX file, so checks don't send a HEAD request every time.
+type DocumentProbe struct {
+	URL      string     // URL that was probed
+	Kind     string     // "pdf" or "docx"; "" for a web page
+	ProbedAt *time.Time // nil until probed
+}
+
+// Fresh reports whether the cached kind still applies to url.
+func (p DocumentProbe) Fresh(url string, now time.Time) bool {
+	return p.ProbedAt != nil && p.URL == url && now.Sub(*p.ProbedAt) < DocumentProbeTTL
+}
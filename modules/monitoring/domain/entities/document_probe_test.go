@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDocumentProbe_Fresh(t *testing.T) {
+	now := time.Now()
+	probedAt := now.Add(-time.Hour)
+	p := DocumentProbe{URL: "https://example.com/report", Kind: "pdf", ProbedAt: &probedAt}
+
+	if (DocumentProbe{}).Fresh(p.URL, now) {
+		t.Error("an unprobed page should not be fresh")
+	}
+	if !p.Fresh(p.URL, now) {
+		t.Error("a recent probe of the same URL should be fresh")
+	}
+	if p.Fresh("https://example.com/other", now) {
+		t.Error("a probe of another URL should not apply")
+	}
+	if p.Fresh(p.URL, probedAt.Add(DocumentProbeTTL)) {
+		t.Error("a probe older than the TTL should not be fresh")
+	}
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoSelectorProposal is returned when accepting a proposal for a section
+// that has none.
+var ErrNoSelectorProposal = errors.New("section has no selector proposal")
+
+// ElementFingerprint describes the element a section was drawn on, taken from
+// the preview element the user picked. When both selectors stop matching, the
+// extractor searches the page for the element that best fits it.
+type ElementFingerprint struct {
+	Tag        string            `json:"tag"`
+	Text       string            `json:"text,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Rect       *SectionRect      `json:"rect,omitempty"`
+	Landmarks  []string          `json:"landmarks,omitempty"`
+}
+
+// SelectorProposal is a replacement selector for a section whose selectors
+// no longer match, found by matching the section's fingerprint.
+type SelectorProposal struct {
+	CSSSelector   string    `json:"css_selector"`
+	XPathSelector string    `json:"xpath_selector,omitempty"`
+	Score         float64   `json:"score"`
+	ScreenshotURL string    `json:"screenshot_url,omitempty"`
+	ProposedAt    time.Time `json:"proposed_at"`
+}
+
+// AcceptSelectorProposal switches the section to its proposed selectors.
+func (s *MonitoredSection) AcceptSelectorProposal() error {
+	if s.SelectorProposal == nil {
+		return ErrNoSelectorProposal
+	}
+	s.CSSSelector = s.SelectorProposal.CSSSelector
+	s.XPathSelector = s.SelectorProposal.XPathSelector
+	s.SelectorProposal = nil
+	s.SelectorHealth = SelectorHealth{}
+	return nil
+}
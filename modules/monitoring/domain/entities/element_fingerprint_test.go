@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAcceptSelectorProposal(t *testing.T) {
+	section := NewMonitoredSection(uuid.New(), "Pricing", ".pricing", "//div[2]", nil, nil, 1440, 0)
+	if err := section.AcceptSelectorProposal(); !errors.Is(err, ErrNoSelectorProposal) {
+		t.Fatalf("err = %v, want ErrNoSelectorProposal", err)
+	}
+
+	brokenAt := time.Now()
+	section.SelectorHealth = SelectorHealth{MissCount: 3, BrokenAt: &brokenAt}
+	section.SelectorProposal = &SelectorProposal{CSSSelector: "#plans", XPathSelector: `//*[@id="plans"]`, Score: 0.82}
+
+	if err := section.AcceptSelectorProposal(); err != nil {
+		t.Fatalf("AcceptSelectorProposal() error = %v", err)
+	}
+	if section.CSSSelector != "#plans" || section.XPathSelector != `//*[@id="plans"]` {
+		t.Errorf("selectors = %q, %q; want the proposed ones", section.CSSSelector, section.XPathSelector)
+	}
+	if section.SelectorProposal != nil {
+		t.Error("proposal should be cleared once accepted")
+	}
+	if section.SelectorHealth.Broken() || section.SelectorHealth.MissCount != 0 {
+		t.Errorf("health = %+v, want reset", section.SelectorHealth)
+	}
+}
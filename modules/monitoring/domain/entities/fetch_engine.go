@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Fetch engines a page can be captured with.
+const (
+	FetchEngineBrowser = "browser" // headless browser via the extractor (default)
+	FetchEngineHTTP    = "http"    // plain HTTP GET, no screenshot
+	FetchEngineAuto    = "auto"    // HTTP first, browser when the page needs JavaScript
+)
+
+// ErrInvalidFetchEngine is returned when a fetch engine is unknown or cannot
+// serve the page's other monitoring settings.
+var ErrInvalidFetchEngine = errors.New("invalid fetch engine")
+
+// FetchEngineOrDefault returns the configured engine, or the browser when unset.
+func (c *MonitoringConfig) FetchEngineOrDefault() string {
+	if c.FetchEngine == "" {
+		return FetchEngineBrowser
+	}
+	return c.FetchEngine
+}
+
+// NeedsBrowser reports whether the page's settings can only be honored by the
+// browser: element or section selectors, ignored elements and capture steps
+// all act on the rendered DOM, and only the browser records page archives.
+func (c *MonitoringConfig) NeedsBrowser() bool {
+	return c.SelectorType == "element" || c.SelectorType == "sections" ||
+		len(c.IgnoreSelectors) > 0 || len(c.CaptureSteps) > 0 || c.Archive
+}
+
+// ValidateFetchEngine checks the engine name and, for the "http" engine, that
+// no setting requires the browser. "auto" is always accepted; it simply stays
+// on the browser for such pages.
+func ValidateFetchEngine(c *MonitoringConfig) error {
+	switch c.FetchEngineOrDefault() {
+	case FetchEngineBrowser, FetchEngineAuto:
+		return nil
+	case FetchEngineHTTP:
+		if c.NeedsBrowser() {
+			return fmt.Errorf("%w: the http engine cannot be combined with selectors, ignored elements, capture steps or archives", ErrInvalidFetchEngine)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q must be one of browser, http or auto", ErrInvalidFetchEngine, c.FetchEngine)
+	}
+}
@@ -25,8 +25,13 @@ type MonitoredSection struct {
 	Rect            *SectionRect
 	ViewportWidth   int
 	SortOrder       int
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	SelectorHealth  SelectorHealth
+	// Fingerprint locates the element again when both selectors fail; the
+	// replacement selector found that way waits in SelectorProposal.
+	Fingerprint      *ElementFingerprint
+	SelectorProposal *SelectorProposal
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 // NewMonitoredSection creates a new monitored section.
@@ -28,6 +28,18 @@ type MonitoringConfig struct {
 	CSSSelector            string
 	XPathSelector          string
 	SelectorOffsets        *SelectorOffsets // pixel offsets for element bounding box
+	IgnoreSelectors []string // STUB
+	SelectorFallback       bool             // element mode: capture the full page when the selector stops matching
+	SelectorHealth         SelectorHealth   // element mode: consecutive selector misses
+	CheckBrokenLinks       bool             // verify page links after each check (off by default)
+	CaptureSteps           []CaptureStep    // browser actions run before capture, in order
+	CaptureProfiles        []CaptureProfile // extra device/locale/geo variants captured on every check
+	ProxyRoute             ProxyRoute       // outbound proxy region/pool; zero = workspace default
+	FetchEngine            string           // "browser" (default), "http" or "auto"
+	SuppressRecurrences    bool             // skip alerts for changes back to a recently seen state
+	LegalHold              bool             // keep every capture regardless of the plan's storage period
+	Archive                bool             // store an MHTML archive of the page with every check
+	DocumentProbe          DocumentProbe    // cached document kind of the page URL
 	CreatedAt              time.Time
 	UpdatedAt              time.Time
 }
@@ -43,6 +55,7 @@ func NewMonitoringConfig(pageID uuid.UUID, frequency, scheduleType, timezone str
 		EnabledInsightTypes:   []string{"marketing", "market_analysis"},
 		EnabledAlertConditions: []string{"any_changes"},
 		CustomAlertCondition:  "",
+		SuppressRecurrences:   true,
 		CreatedAt:             time.Now(),
 		UpdatedAt:             time.Now(),
 	}
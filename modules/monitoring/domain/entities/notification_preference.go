@@ -12,7 +12,7 @@ type NotificationPreference struct {
 	WorkspaceID  *uuid.UUID // null if page_id is set
 	PageID       *uuid.UUID // null if workspace_id is set
 	EmailEnabled bool
-	ChangeTypes  []string // ["page_change", "error", "performance_drop"]
+	ChangeTypes  []string // ["page_change", "seo_change", "broken_links", "login_failed", "selector_broken", "error", "performance_drop"]
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
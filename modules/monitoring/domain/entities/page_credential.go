@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Auth types a page credential can use.
+const (
+	AuthTypeHeaders     = "headers"
+	AuthTypeCookies     = "cookies"
+	AuthTypeBasic       = "basic"
+	AuthTypeLoginScript = "login_script"
+)
+
+// Login statuses tracked on a credential.
+const (
+	LoginStatusOK     = "ok"
+	LoginStatusFailed = "failed"
+)
+
+// ErrInvalidCredential is returned when a credential fails validation.
+var ErrInvalidCredential = errors.New("invalid page credential")
+
+// CredentialCookie is a cookie set in the browser before the page is loaded.
+// Domain and Path default to the page's host and "/".
+type CredentialCookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// CredentialSecrets holds the sensitive parts of a page credential. It is only
+// ever stored encrypted and is never returned by the API.
+type CredentialSecrets struct {
+	Headers  map[string]string  `json:"headers,omitempty"`
+	Cookies  []CredentialCookie `json:"cookies,omitempty"`
+	Username string             `json:"username,omitempty"`
+	Password string             `json:"password,omitempty"`
+}
+
+// LoginScript describes a form login performed before the page is captured.
+type LoginScript struct {
+	URL              string `json:"url"`
+	UsernameSelector string `json:"username_selector"`
+	PasswordSelector string `json:"password_selector"`
+	SubmitSelector   string `json:"submit_selector"`
+	// SuccessSelector, when set, must appear after submitting for the login to count as successful.
+	SuccessSelector string `json:"success_selector,omitempty"`
+}
+
+// PageCredential lets a page be monitored behind authentication. The secret
+// values live encrypted in SecretCiphertext; everything else is metadata that
+// is safe to show.
+type PageCredential struct {
+	ID               uuid.UUID
+	PageID           uuid.UUID
+	AuthType         string
+	LoginScript      *LoginScript
+	HeaderNames      []string
+	CookieNames      []string
+	SecretCiphertext string
+	LastLoginStatus  string
+	LastLoginError   string
+	LastLoginAt      *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewPageCredential creates a credential for a page.
+func NewPageCredential(pageID uuid.UUID, authType string) *PageCredential {
+	now := time.Now()
+	return &PageCredential{
+		ID:        uuid.New(),
+		PageID:    pageID,
+		AuthType:  authType,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SecretCipher encrypts credential secrets with a tenant-scoped key.
+type SecretCipher interface {
+	Seal(tenant string, plaintext, associatedData []byte) (string, error)
+	Open(tenant, sealed string, associatedData []byte) ([]byte, error)
+}
+
+// SealSecrets encrypts secrets into the credential, bound to the page so the
+// ciphertext cannot be moved to another page, and records the header and
+// cookie names as displayable metadata.
+func (c *PageCredential) SealSecrets(cipher SecretCipher, tenant string, secrets *CredentialSecrets) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	sealed, err := cipher.Seal(tenant, plaintext, []byte(c.PageID.String()))
+	if err != nil {
+		return err
+	}
+	c.SecretCiphertext = sealed
+
+	c.HeaderNames = make([]string, 0, len(secrets.Headers))
+	for name := range secrets.Headers {
+		c.HeaderNames = append(c.HeaderNames, name)
+	}
+	sort.Strings(c.HeaderNames)
+	c.CookieNames = make([]string, 0, len(secrets.Cookies))
+	for _, cookie := range secrets.Cookies {
+		c.CookieNames = append(c.CookieNames, cookie.Name)
+	}
+	return nil
+}
+
+// OpenSecrets decrypts the credential's secrets.
+func (c *PageCredential) OpenSecrets(cipher SecretCipher, tenant string) (*CredentialSecrets, error) {
+	plaintext, err := cipher.Open(tenant, c.SecretCiphertext, []byte(c.PageID.String()))
+	if err != nil {
+		return nil, err
+	}
+	var secrets CredentialSecrets
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, err
+	}
+	return &secrets, nil
+}
+
+// UsesLogin reports whether checks must run the login script first.
+func (c *PageCredential) UsesLogin() bool {
+	return c.AuthType == AuthTypeLoginScript
+}
+
+// ValidateCredential checks that the secrets and login script are complete for authType.
+func ValidateCredential(authType string, script *LoginScript, secrets *CredentialSecrets) error {
+	if secrets == nil {
+		secrets = &CredentialSecrets{}
+	}
+	switch authType {
+	case AuthTypeHeaders:
+		if len(secrets.Headers) == 0 {
+			return fmt.Errorf("%w: at least one header is required", ErrInvalidCredential)
+		}
+		for name := range secrets.Headers {
+			if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\r\n") {
+				return fmt.Errorf("%w: invalid header name %q", ErrInvalidCredential, name)
+			}
+		}
+	case AuthTypeCookies:
+		if len(secrets.Cookies) == 0 {
+			return fmt.Errorf("%w: at least one cookie is required", ErrInvalidCredential)
+		}
+		for i, c := range secrets.Cookies {
+			if strings.TrimSpace(c.Name) == "" {
+				return fmt.Errorf("%w: cookie %d: name is required", ErrInvalidCredential, i+1)
+			}
+		}
+	case AuthTypeBasic:
+		if secrets.Username == "" {
+			return fmt.Errorf("%w: username is required", ErrInvalidCredential)
+		}
+	case AuthTypeLoginScript:
+		if script == nil {
+			return fmt.Errorf("%w: login script is required", ErrInvalidCredential)
+		}
+		u, err := url.Parse(script.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: login url must be an absolute http(s) URL", ErrInvalidCredential)
+		}
+		if script.UsernameSelector == "" || script.PasswordSelector == "" || script.SubmitSelector == "" {
+			return fmt.Errorf("%w: username, password and submit selectors are required", ErrInvalidCredential)
+		}
+		if secrets.Username == "" || secrets.Password == "" {
+			return fmt.Errorf("%w: username and password are required", ErrInvalidCredential)
+		}
+	default:
+		return fmt.Errorf("%w: unknown auth type %q", ErrInvalidCredential, authType)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Protocols an outbound proxy can speak.
+const (
+	ProxyProtocolHTTP   = "http"
+	ProxyProtocolHTTPS  = "https"
+	ProxyProtocolSOCKS5 = "socks5"
+)
+
+const (
+	// ProxyQuarantineThreshold is the number of consecutive failures after
+	// which a proxy is taken out of rotation.
+	ProxyQuarantineThreshold = 3
+	// ProxyQuarantineDuration is how long a quarantined proxy is skipped before
+	// it is tried again.
+	ProxyQuarantineDuration = 30 * time.Minute
+)
+
+// proxyLabelPattern matches region and pool labels, e.g. "us", "de-fra", "residential".
+var proxyLabelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// ErrInvalidProxy is returned when a proxy or proxy route fails validation.
+var ErrInvalidProxy = errors.New("invalid proxy")
+
+// Proxy is an outbound HTTP or SOCKS5 endpoint checks can be routed through.
+// Region and Pool are labels used to pick a proxy for a page; the health
+// fields are maintained by the snapshot worker.
+type Proxy struct {
+	ID                  uuid.UUID
+	Name                string
+	Protocol            string
+	Host                string
+	Port                int
+	Username            string
+	PasswordCiphertext  string // sealed with the tenant key; empty when there is no password
+	Region              string
+	Pool                string
+	Enabled             bool
+	ConsecutiveFailures int
+	QuarantinedUntil    *time.Time
+	LastUsedAt          *time.Time
+	LastSuccessAt       *time.Time
+	LastFailureAt       *time.Time
+	LastError           string
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// NewProxy creates an enabled proxy.
+func NewProxy(name, protocol, host string, port int) *Proxy {
+	now := time.Now()
+	return &Proxy{
+		ID:        uuid.New(),
+		Name:      name,
+		Protocol:  protocol,
+		Host:      host,
+		Port:      port,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Server returns the proxy address in the scheme://host:port form browsers expect.
+func (p *Proxy) Server() string {
+	return p.Protocol + "://" + net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+}
+
+// SealPassword encrypts the proxy password, bound to the proxy ID. An empty
+// password clears the stored one.
+func (p *Proxy) SealPassword(cipher SecretCipher, tenant, password string) error {
+	if password == "" {
+		p.PasswordCiphertext = ""
+		return nil
+	}
+	sealed, err := cipher.Seal(tenant, []byte(password), []byte(p.ID.String()))
+	if err != nil {
+		return err
+	}
+	p.PasswordCiphertext = sealed
+	return nil
+}
+
+// OpenPassword decrypts the proxy password.
+func (p *Proxy) OpenPassword(cipher SecretCipher, tenant string) (string, error) {
+	if p.PasswordCiphertext == "" {
+		return "", nil
+	}
+	plaintext, err := cipher.Open(tenant, p.PasswordCiphertext, []byte(p.ID.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Quarantined reports whether the proxy is sitting out after repeated failures.
+func (p *Proxy) Quarantined(now time.Time) bool {
+	return p.QuarantinedUntil != nil && now.Before(*p.QuarantinedUntil)
+}
+
+// Available reports whether the proxy can be handed to a check.
+func (p *Proxy) Available(now time.Time) bool {
+	return p.Enabled && !p.Quarantined(now)
+}
+
+// RecordSuccess resets the failure streak and lifts any quarantine.
+func (p *Proxy) RecordSuccess(now time.Time) {
+	p.ConsecutiveFailures = 0
+	p.QuarantinedUntil = nil
+	p.LastSuccessAt = &now
+	p.LastError = ""
+}
+
+// RecordFailure counts a proxy failure and quarantines the proxy once the
+// streak reaches ProxyQuarantineThreshold. A proxy that fails again right
+// after its quarantine ends goes straight back in. Returns true when this
+// failure started a quarantine.
+func (p *Proxy) RecordFailure(now time.Time, reason string) bool {
+	p.ConsecutiveFailures++
+	p.LastFailureAt = &now
+	p.LastError = reason
+	if p.ConsecutiveFailures < ProxyQuarantineThreshold || p.Quarantined(now) {
+		return false
+	}
+	until := now.Add(ProxyQuarantineDuration)
+	p.QuarantinedUntil = &until
+	return true
+}
+
+// ProxyRoute selects the proxies a page's checks go through. An empty route
+// means checks connect directly. Region pins checks to proxies with that
+// region label; Pool rotates through the proxies of that pool. Both can be
+// set to rotate through a pool within one region.
+type ProxyRoute struct {
+	Region string `json:"region,omitempty"`
+	Pool   string `json:"pool,omitempty"`
+}
+
+// IsZero reports whether the route is unset.
+func (r ProxyRoute) IsZero() bool {
+	return r.Region == "" && r.Pool == ""
+}
+
+// Matches reports whether the proxy carries the route's labels.
+func (r ProxyRoute) Matches(p *Proxy) bool {
+	return (r.Region == "" || p.Region == r.Region) && (r.Pool == "" || p.Pool == r.Pool)
+}
+
+// String describes the route for logs and error messages.
+func (r ProxyRoute) String() string {
+	var parts []string
+	if r.Region != "" {
+		parts = append(parts, "region "+r.Region)
+	}
+	if r.Pool != "" {
+		parts = append(parts, "pool "+r.Pool)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ResolveProxyRoute returns the page's route when set and the workspace
+// default otherwise.
+func ResolveProxyRoute(page ProxyRoute, workspace *ProxyRoute) ProxyRoute {
+	if !page.IsZero() || workspace == nil {
+		return page
+	}
+	return *workspace
+}
+
+// SelectProxy picks the available proxy matching route that was used least
+// recently, so consecutive checks rotate through the matching proxies.
+// Proxies in exclude are skipped. Returns nil when none is available.
+func SelectProxy(proxies []*Proxy, route ProxyRoute, now time.Time, exclude ...uuid.UUID) *Proxy {
+	var best *Proxy
+	for _, p := range proxies {
+		if !p.Available(now) || !route.Matches(p) || containsUUID(exclude, p.ID) {
+			continue
+		}
+		if best == nil || usedBefore(p, best) {
+			best = p
+		}
+	}
+	return best
+}
+
+// usedBefore orders never-used proxies first, then by last use.
+func usedBefore(a, b *Proxy) bool {
+	if a.LastUsedAt == nil {
+		return b.LastUsedAt != nil
+	}
+	return b.LastUsedAt != nil && a.LastUsedAt.Before(*b.LastUsedAt)
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateProxy checks the proxy endpoint and labels. Chromium cannot
+// authenticate to SOCKS5 proxies, so those must not carry credentials.
+func ValidateProxy(p *Proxy, hasPassword bool) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidProxy)
+	}
+	switch p.Protocol {
+	case ProxyProtocolHTTP, ProxyProtocolHTTPS:
+	case ProxyProtocolSOCKS5:
+		if p.Username != "" || hasPassword {
+			return fmt.Errorf("%w: socks5 proxies with credentials are not supported", ErrInvalidProxy)
+		}
+	default:
+		return fmt.Errorf("%w: unknown protocol %q", ErrInvalidProxy, p.Protocol)
+	}
+	if p.Host == "" || (strings.ContainsAny(p.Host, "/:@ ") && net.ParseIP(p.Host) == nil) {
+		return fmt.Errorf("%w: host must be a hostname or IP address", ErrInvalidProxy)
+	}
+	if p.Port < 1 || p.Port > 65535 {
+		return fmt.Errorf("%w: port must be between 1 and 65535", ErrInvalidProxy)
+	}
+	if hasPassword && p.Username == "" {
+		return fmt.Errorf("%w: a password requires a username", ErrInvalidProxy)
+	}
+	return ValidateProxyRoute(ProxyRoute{Region: p.Region, Pool: p.Pool})
+}
+
+// ValidateProxyRoute checks that route labels are lowercase slugs.
+func ValidateProxyRoute(route ProxyRoute) error {
+	if route.Region != "" && !proxyLabelPattern.MatchString(route.Region) {
+		return fmt.Errorf("%w: region %q must be 1-32 lowercase letters, digits, '-' or '_'", ErrInvalidProxy, route.Region)
+	}
+	if route.Pool != "" && !proxyLabelPattern.MatchString(route.Pool) {
+		return fmt.Errorf("%w: pool %q must be 1-32 lowercase letters, digits, '-' or '_'", ErrInvalidProxy, route.Pool)
+	}
+	return nil
+}
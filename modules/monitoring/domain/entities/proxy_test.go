@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectProxy(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	later := now.Add(-time.Minute)
+	quarantineEnd := now.Add(time.Minute)
+
+	usRecent := &Proxy{Name: "us-1", Region: "us", Enabled: true, LastUsedAt: &later}
+	usStale := &Proxy{Name: "us-2", Region: "us", Enabled: true, LastUsedAt: &earlier}
+	usQuarantined := &Proxy{Name: "us-3", Region: "us", Enabled: true, QuarantinedUntil: &quarantineEnd}
+	deResidential := &Proxy{Name: "de-1", Region: "de", Pool: "residential", Enabled: true}
+	deDisabled := &Proxy{Name: "de-2", Region: "de", Pool: "residential", Enabled: false}
+	proxies := []*Proxy{usRecent, usStale, usQuarantined, deResidential, deDisabled}
+	for _, p := range proxies {
+		p.ID = NewProxy(p.Name, ProxyProtocolHTTP, "proxy.internal", 3128).ID
+	}
+
+	tests := []struct {
+		name  string
+		route ProxyRoute
+		want  *Proxy
+	}{
+		{"least recently used in region", ProxyRoute{Region: "us"}, usStale},
+		{"pool", ProxyRoute{Pool: "residential"}, deResidential},
+		{"region and pool", ProxyRoute{Region: "de", Pool: "residential"}, deResidential},
+		{"no match", ProxyRoute{Region: "fr"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectProxy(proxies, tt.route, now); got != tt.want {
+				t.Errorf("SelectProxy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := SelectProxy(proxies, ProxyRoute{Region: "us"}, now, usStale.ID); got != usRecent {
+		t.Errorf("SelectProxy() with exclusion = %v, want %v", got, usRecent)
+	}
+	if got := SelectProxy(proxies, ProxyRoute{Region: "us"}, quarantineEnd.Add(time.Second), usStale.ID, usRecent.ID); got != usQuarantined {
+		t.Errorf("SelectProxy() after quarantine = %v, want %v", got, usQuarantined)
+	}
+}
+
+func TestProxyQuarantine(t *testing.T) {
+	now := time.Now()
+	p := NewProxy("us-1", ProxyProtocolHTTP, "proxy.internal", 3128)
+
+	for i := 1; i < ProxyQuarantineThreshold; i++ {
+		if p.RecordFailure(now, "connection refused") {
+			t.Fatalf("quarantined after %d failures", i)
+		}
+	}
+	if !p.RecordFailure(now, "connection refused") {
+		t.Fatal("expected quarantine at threshold")
+	}
+	if p.Available(now) {
+		t.Error("quarantined proxy is available")
+	}
+	if !p.Available(now.Add(ProxyQuarantineDuration)) {
+		t.Error("proxy still unavailable after quarantine ended")
+	}
+
+	// A proxy that fails again on probation goes straight back into quarantine.
+	retry := now.Add(ProxyQuarantineDuration + time.Second)
+	if !p.RecordFailure(retry, "connection refused") {
+		t.Error("expected immediate re-quarantine")
+	}
+
+	p.RecordSuccess(retry)
+	if !p.Available(retry) || p.ConsecutiveFailures != 0 {
+		t.Errorf("success did not reset health: %+v", p)
+	}
+}
+
+func TestValidateProxy(t *testing.T) {
+	tests := []struct {
+		name        string
+		proxy       *Proxy
+		hasPassword bool
+		wantErr     bool
+	}{
+		{"http with credentials", &Proxy{Name: "a", Protocol: "http", Host: "10.0.0.5", Port: 3128, Username: "u"}, true, false},
+		{"ipv6 host", &Proxy{Name: "a", Protocol: "https", Host: "2001:db8::1", Port: 443}, false, false},
+		{"socks5 without credentials", &Proxy{Name: "a", Protocol: "socks5", Host: "proxy.internal", Port: 1080}, false, false},
+		{"socks5 with credentials", &Proxy{Name: "a", Protocol: "socks5", Host: "proxy.internal", Port: 1080, Username: "u"}, true, true},
+		{"unknown protocol", &Proxy{Name: "a", Protocol: "ftp", Host: "proxy.internal", Port: 21}, false, true},
+		{"host with scheme", &Proxy{Name: "a", Protocol: "http", Host: "http://proxy.internal", Port: 3128}, false, true},
+		{"port out of range", &Proxy{Name: "a", Protocol: "http", Host: "proxy.internal", Port: 70000}, false, true},
+		{"invalid region", &Proxy{Name: "a", Protocol: "http", Host: "proxy.internal", Port: 3128, Region: "US East"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProxy(tt.proxy, tt.hasPassword)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProxy() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
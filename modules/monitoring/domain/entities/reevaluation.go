@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Re-evaluation job statuses.
+const (
+	ReevaluationPending   = "pending"
+	ReevaluationRunning   = "running"
+	ReevaluationCompleted = "completed"
+	ReevaluationFailed    = "failed"
+	ReevaluationApplied   = "applied"
+)
+
+const (
+	// ReevaluationMaxChecks caps how many checks one job replays.
+	ReevaluationMaxChecks = 500
+	// ReevaluationMaxRange is the longest date range a job may cover.
+	ReevaluationMaxRange = 90 * 24 * time.Hour
+)
+
+// ReevaluationJob replays change detection with a page's current rules over
+// the stored captures of a date range. Its results are a dry run until they
+// are applied to the checks.
+type ReevaluationJob struct {
+	ID                   uuid.UUID
+	PageID               uuid.UUID
+	SectionID            *uuid.UUID // nil replays the full-page checks
+	From                 time.Time
+	To                   time.Time
+	Status               string
+	TotalChecks          int
+	WouldAlert           int
+	Flipped              int // checks whose ChangeDetected would differ
+	Results              []ReevaluationResult
+	UnsupportedSelectors []string // ignore selectors that could not be re-applied
+	ErrorMessage         string
+	CreatedAt            time.Time
+	CompletedAt          *time.Time
+	AppliedAt            *time.Time
+}
+
+// ReevaluationResult is how one past check fares under the current rules.
+type ReevaluationResult struct {
+	CheckID     uuid.UUID `json:"check_id"`
+	CheckedAt   time.Time `json:"checked_at"`
+	WasChanged  bool      `json:"was_changed"`
+	WouldChange bool      `json:"would_change"`
+	WouldAlert  bool      `json:"would_alert"`
+	ChangeType  string    `json:"change_type,omitempty"`
+	Changes     int       `json:"changes,omitempty"` // content block operations
+	Recurrence  bool      `json:"recurrence,omitempty"`
+}
+
+// Flipped reports whether the current rules disagree with the stored result.
+func (r ReevaluationResult) Flipped() bool {
+	return r.WasChanged != r.WouldChange
+}
+
+func NewReevaluationJob(pageID uuid.UUID, sectionID *uuid.UUID, from, to time.Time) *ReevaluationJob {
+	return &ReevaluationJob{
+		ID:        uuid.New(),
+		PageID:    pageID,
+		SectionID: sectionID,
+		From:      from,
+		To:        to,
+		Status:    ReevaluationPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Complete stores the replay's results and tallies them.
+func (j *ReevaluationJob) Complete(results []ReevaluationResult, unsupportedSelectors []string) {
+	j.Results = results
+	j.UnsupportedSelectors = unsupportedSelectors
+	j.TotalChecks = len(results)
+	j.WouldAlert, j.Flipped = 0, 0
+	for _, r := range results {
+		if r.WouldAlert {
+			j.WouldAlert++
+		}
+		if r.Flipped() {
+			j.Flipped++
+		}
+	}
+	now := time.Now()
+	j.Status = ReevaluationCompleted
+	j.CompletedAt = &now
+}
+
+// Fail marks the job as failed with the reason.
+func (j *ReevaluationJob) Fail(msg string) {
+	now := time.Now()
+	j.Status = ReevaluationFailed
+	j.ErrorMessage = msg
+	j.CompletedAt = &now
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of regions a section can be suggested for.
+const (
+	SuggestionKindPricing    = "pricing"
+	SuggestionKindChangelog  = "changelog"
+	SuggestionKindTable      = "table"
+	SuggestionKindHero       = "hero"
+	SuggestionKindArticle    = "article"
+	SuggestionKindNavigation = "navigation"
+	SuggestionKindFooter     = "footer"
+)
+
+// SectionSuggestion is a region of a page proposed for monitoring, found from
+// the page's structure when it was added. Score ranks how likely the region
+// is to change meaningfully, from 0 to 1.
+type SectionSuggestion struct {
+	ID            uuid.UUID
+	PageID        uuid.UUID
+	Kind          string
+	Name          string
+	CSSSelector   string
+	XPathSelector string
+	Rect          *SectionRect
+	ViewportWidth int
+	Fingerprint   *ElementFingerprint
+	Score         float64
+	CreatedAt     time.Time
+}
+
+// ToSection turns an accepted suggestion into a monitored section.
+func (s *SectionSuggestion) ToSection(sortOrder int) *MonitoredSection {
+	section := NewMonitoredSection(s.PageID, s.Name, s.CSSSelector, s.XPathSelector, nil, s.Rect, s.ViewportWidth, sortOrder)
+	section.Fingerprint = s.Fingerprint
+	return section
+}
@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+// SelectorBrokenThreshold is the number of consecutive checks a selector must
+// miss before it is reported broken. A single miss is often just a slow load.
+const SelectorBrokenThreshold = 2
+
+// SelectorHealth tracks whether a page's element selector, or a section's
+// selector, still matches the live page.
+type SelectorHealth struct {
+	MissCount int        // consecutive checks the selector did not match
+	BrokenAt  *time.Time // when the selector was reported broken; nil while healthy
+}
+
+// Broken reports whether a selector_broken alert has been raised and the
+// selector has not matched since.
+func (h *SelectorHealth) Broken() bool {
+	return h.BrokenAt != nil
+}
+
+// RecordMatch resets the miss streak. Returns true when the selector was
+// reported broken and has now recovered.
+func (h *SelectorHealth) RecordMatch() bool {
+	recovered := h.Broken()
+	h.MissCount = 0
+	h.BrokenAt = nil
+	return recovered
+}
+
+// RecordMiss counts a check the selector did not match. Returns true when the
+// streak reaches SelectorBrokenThreshold, so the selector is reported broken
+// once rather than on every check.
+func (h *SelectorHealth) RecordMiss(now time.Time) bool {
+	h.MissCount++
+	if h.Broken() || h.MissCount < SelectorBrokenThreshold {
+		return false
+	}
+	h.BrokenAt = &now
+	return true
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectorHealth(t *testing.T) {
+	now := time.Now()
+	var h SelectorHealth
+
+	if h.RecordMiss(now) {
+		t.Fatal("first miss should not report the selector broken")
+	}
+	if !h.RecordMiss(now) {
+		t.Fatal("reaching the threshold should report the selector broken")
+	}
+	if !h.Broken() || h.MissCount != SelectorBrokenThreshold {
+		t.Fatalf("health = %+v, want broken after %d misses", h, SelectorBrokenThreshold)
+	}
+	if h.RecordMiss(now.Add(time.Hour)) {
+		t.Error("an already broken selector should not be reported again")
+	}
+	if !h.BrokenAt.Equal(now) {
+		t.Errorf("BrokenAt moved to %v, want the first report %v", h.BrokenAt, now)
+	}
+
+	if !h.RecordMatch() {
+		t.Error("a match after a broken report should count as recovered")
+	}
+	if h.Broken() || h.MissCount != 0 {
+		t.Errorf("health = %+v, want reset after a match", h)
+	}
+	if h.RecordMatch() {
+		t.Error("a match on a healthy selector is not a recovery")
+	}
+}
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Time-lapse job statuses.
+const (
+	TimelapsePending   = "pending"
+	TimelapseRunning   = "running"
+	TimelapseCompleted = "completed"
+	TimelapseFailed    = "failed"
+)
+
+// Time-lapse formats.
+const (
+	TimelapseGIF   = "gif"   // animated GIF
+	TimelapseAPNG  = "apng"  // animated PNG
+	TimelapseStrip = "strip" // frames side by side in one image
+)
+
+const (
+	// TimelapseMaxFrames caps how many screenshots one time-lapse shows.
+	TimelapseMaxFrames = 120
+	// TimelapseMaxRange is the longest date range a time-lapse may cover.
+	TimelapseMaxRange = 366 * 24 * time.Hour
+	// TimelapseDefaultWidth and TimelapseMaxWidth bound the frame width.
+	TimelapseDefaultWidth = 800
+	TimelapseMaxWidth     = 1600
+)
+
+// TimelapseJob renders a page's or section's screenshots over a date range as
+// one animated or side-by-side image in the background.
+type TimelapseJob struct {
+	ID            uuid.UUID
+	PageID        uuid.UUID
+	SectionID     *uuid.UUID // nil renders the full-page checks
+	From          time.Time
+	To            time.Time
+	Format        string
+	Width         int
+	DateStamp     bool // stamp each frame with its check date
+	HighlightDiff bool // tint what changed since the previous frame
+	Status        string
+	Frames        int
+	ResultURL     string // object key of the rendered image
+	ErrorMessage  string
+	CreatedAt     time.Time
+	CompletedAt   *time.Time
+}
+
+// ValidTimelapseFormat reports whether format is a supported time-lapse
+// format.
+func ValidTimelapseFormat(format string) bool {
+	return format == TimelapseGIF || format == TimelapseAPNG || format == TimelapseStrip
+}
+
+func NewTimelapseJob(pageID uuid.UUID, sectionID *uuid.UUID, from, to time.Time, format string, width int) *TimelapseJob {
+	return &TimelapseJob{
+		ID:        uuid.New(),
+		PageID:    pageID,
+		SectionID: sectionID,
+		From:      from,
+		To:        to,
+		Format:    format,
+		Width:     width,
+		Status:    TimelapsePending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Complete records the rendered image.
+func (j *TimelapseJob) Complete(resultKey string, frames int) {
+	now := time.Now()
+	j.Status = TimelapseCompleted
+	j.ResultURL = resultKey
+	j.Frames = frames
+	j.CompletedAt = &now
+}
+
+// Fail marks the job as failed with the reason.
+func (j *TimelapseJob) Fail(msg string) {
+	now := time.Now()
+	j.Status = TimelapseFailed
+	j.ErrorMessage = msg
+	j.CompletedAt = &now
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// BrokenLinkRepository defines operations for tracking broken links per page.
+type BrokenLinkRepository interface {
+	// ListOpenByPage returns links currently considered broken (not resolved).
+	ListOpenByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.BrokenLink, error)
+	Create(ctx context.Context, link *entities.BrokenLink) error
+	// MarkSeen records that an open broken link was found broken again by checkID.
+	MarkSeen(ctx context.Context, id, checkID uuid.UUID, statusCode int, errMsg string) error
+	// Resolve closes broken links that verified as working again.
+	Resolve(ctx context.Context, ids []uuid.UUID) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
@@ -14,7 +15,7 @@ type CheckRepository interface {
 	ListByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.Check, error)
 	// ListByPageAndSection returns checks for a page filtered by section. If sectionID is nil, returns only full-page checks.
 	ListByPageAndSection(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID) ([]*entities.Check, error)
-	// ListByParentCheckID returns all section checks that belong to a parent check.
+	// ListByParentCheckID returns all section and profile checks that belong to a parent check.
 	ListByParentCheckID(ctx context.Context, parentCheckID uuid.UUID) ([]*entities.Check, error)
 	// ListSectionChecksByPage returns all section checks for a page (section_id IS NOT NULL).
 	ListSectionChecksByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.Check, error)
@@ -23,4 +24,18 @@ type CheckRepository interface {
 	GetPreviousSuccessfulByPage(ctx context.Context, pageID, excludeCheckID uuid.UUID) (*entities.Check, error)
 	// GetPreviousSuccessfulBySection returns the most recent successful check for the same section.
 	GetPreviousSuccessfulBySection(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, excludeCheckID uuid.UUID) (*entities.Check, error)
+	// ListProfileChecksByPage returns all capture profile checks for a page (profile_name IS NOT NULL).
+	ListProfileChecksByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.Check, error)
+	// GetPreviousSuccessfulByProfile returns the most recent successful check captured with the same profile.
+	GetPreviousSuccessfulByProfile(ctx context.Context, pageID uuid.UUID, profileName string, excludeCheckID uuid.UUID) (*entities.Check, error)
+	// GetPreviousSuccessfulBefore returns the successful check that preceded the given one
+	// in the same scope: the full page, one section or one capture profile.
+	GetPreviousSuccessfulBefore(ctx context.Context, check *entities.Check) (*entities.Check, error)
+	// ListSuccessfulInRange returns up to limit successful checks of the full page, or of one
+	// section when sectionID is set, checked within [from, to], oldest first.
+	ListSuccessfulInRange(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, from, to time.Time, limit int) ([]*entities.Check, error)
+	// SetPinned pins or unpins a check as a baseline kept by retention purges.
+	SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error
+	// GetLastSelectorMatch returns the most recent successful check captured with the element selector matching.
+	GetLastSelectorMatch(ctx context.Context, pageID uuid.UUID) (*entities.Check, error)
 }
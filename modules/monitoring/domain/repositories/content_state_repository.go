@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// ContentStateRepository keeps the recent content states of pages and sections.
+type ContentStateRepository interface {
+	// ListRecent returns the latest states for a page, or one of its sections
+	// when sectionID is set, newest first.
+	ListRecent(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, limit int) (entities.ContentHistory, error)
+	// Record appends a check's state and trims the history to ContentHistorySize.
+	Record(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, checkID uuid.UUID, state string) error
+}
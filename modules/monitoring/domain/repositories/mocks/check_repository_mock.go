@@ -2,30 +2,42 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
 )
 
 type MockCheckRepository struct {
-	CreateErr                    error
-	GetByIDResult                *entities.Check
-	GetByIDErr                   error
-	ListByPageResult             []*entities.Check
-	ListByPageErr                error
-	ListByPageAndSectionResult   []*entities.Check
-	ListByPageAndSectionErr      error
-	ListByParentCheckIDResult      []*entities.Check
-	ListByParentCheckIDErr         error
-	ListSectionChecksByPageResult  []*entities.Check
-	ListSectionChecksByPageErr     error
-	GetLatestResult              *entities.Check
-	GetLatestErr                 error
-	UpdateErr                    error
-	GetPreviousResult            *entities.Check
-	GetPreviousErr               error
-	GetPreviousBySectionResult   *entities.Check
-	GetPreviousBySectionErr      error
+	CreateErr                     error
+	GetByIDResult                 *entities.Check
+	GetByIDErr                    error
+	ListByPageResult              []*entities.Check
+	ListByPageErr                 error
+	ListByPageAndSectionResult    []*entities.Check
+	ListByPageAndSectionErr       error
+	ListByParentCheckIDResult     []*entities.Check
+	ListByParentCheckIDErr        error
+	ListSectionChecksByPageResult []*entities.Check
+	ListSectionChecksByPageErr    error
+	GetLatestResult               *entities.Check
+	GetLatestErr                  error
+	UpdateErr                     error
+	GetPreviousResult             *entities.Check
+	GetPreviousErr                error
+	GetPreviousBySectionResult    *entities.Check
+	GetPreviousBySectionErr       error
+	ListProfileChecksByPageResult []*entities.Check
+	ListProfileChecksByPageErr    error
+	GetPreviousByProfileResult    *entities.Check
+	GetPreviousByProfileErr       error
+	GetLastSelectorMatchResult    *entities.Check
+	GetLastSelectorMatchErr       error
+	GetPreviousBeforeResult       *entities.Check
+	GetPreviousBeforeErr          error
+	ListInRangeResult             []*entities.Check
+	ListInRangeErr                error
+	SetPinnedErr                  error
 
 	CreateFn func(ctx context.Context, check *entities.Check) error
 
@@ -75,3 +87,27 @@ func (m *MockCheckRepository) ListSectionChecksByPage(_ context.Context, _ uuid.
 func (m *MockCheckRepository) GetPreviousSuccessfulBySection(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ uuid.UUID) (*entities.Check, error) {
 	return m.GetPreviousBySectionResult, m.GetPreviousBySectionErr
 }
+
+func (m *MockCheckRepository) ListProfileChecksByPage(_ context.Context, _ uuid.UUID) ([]*entities.Check, error) {
+	return m.ListProfileChecksByPageResult, m.ListProfileChecksByPageErr
+}
+
+func (m *MockCheckRepository) GetPreviousSuccessfulByProfile(_ context.Context, _ uuid.UUID, _ string, _ uuid.UUID) (*entities.Check, error) {
+	return m.GetPreviousByProfileResult, m.GetPreviousByProfileErr
+}
+
+func (m *MockCheckRepository) GetLastSelectorMatch(_ context.Context, _ uuid.UUID) (*entities.Check, error) {
+	return m.GetLastSelectorMatchResult, m.GetLastSelectorMatchErr
+}
+
+func (m *MockCheckRepository) GetPreviousSuccessfulBefore(_ context.Context, _ *entities.Check) (*entities.Check, error) {
+	return m.GetPreviousBeforeResult, m.GetPreviousBeforeErr
+}
+
+func (m *MockCheckRepository) ListSuccessfulInRange(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _, _ time.Time, _ int) ([]*entities.Check, error) {
+	return m.ListInRangeResult, m.ListInRangeErr
+}
+
+func (m *MockCheckRepository) SetPinned(_ context.Context, _ uuid.UUID, _ bool) error {
+	return m.SetPinnedErr
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockContentStateRepository struct {
+	ListRecentResult entities.ContentHistory
+	ListRecentErr    error
+	RecordErr        error
+
+	Recorded []string
+}
+
+func (m *MockContentStateRepository) ListRecent(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ int) (entities.ContentHistory, error) {
+	return m.ListRecentResult, m.ListRecentErr
+}
+
+func (m *MockContentStateRepository) Record(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _ uuid.UUID, state string) error {
+	m.Recorded = append(m.Recorded, state)
+	return m.RecordErr
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockMonitoredSectionRepository struct {
+	CreateErr                 error
+	GetByIDResult             *entities.MonitoredSection
+	GetByIDErr                error
+	ListByPageIDResult        []*entities.MonitoredSection
+	ListByPageIDErr           error
+	UpdateErr                 error
+	UpdateSelectorHealthErr   error
+	UpdateSelectorProposalErr error
+	DeleteErr                 error
+	ReplaceAllErr             error
+
+	Created  []*entities.MonitoredSection
+	Updated  *entities.MonitoredSection
+	Replaced []*entities.MonitoredSection
+}
+
+func (m *MockMonitoredSectionRepository) Create(_ context.Context, section *entities.MonitoredSection) error {
+	m.Created = append(m.Created, section)
+	return m.CreateErr
+}
+
+func (m *MockMonitoredSectionRepository) GetByID(_ context.Context, _ uuid.UUID) (*entities.MonitoredSection, error) {
+	return m.GetByIDResult, m.GetByIDErr
+}
+
+func (m *MockMonitoredSectionRepository) ListByPageID(_ context.Context, _ uuid.UUID) ([]*entities.MonitoredSection, error) {
+	return m.ListByPageIDResult, m.ListByPageIDErr
+}
+
+func (m *MockMonitoredSectionRepository) Update(_ context.Context, section *entities.MonitoredSection) error {
+	m.Updated = section
+	return m.UpdateErr
+}
+
+func (m *MockMonitoredSectionRepository) UpdateSelectorHealth(_ context.Context, _ uuid.UUID, _ entities.SelectorHealth) error {
+	return m.UpdateSelectorHealthErr
+}
+
+func (m *MockMonitoredSectionRepository) UpdateSelectorProposal(_ context.Context, _ uuid.UUID, _ *entities.SelectorProposal) error {
+	return m.UpdateSelectorProposalErr
+}
+
+func (m *MockMonitoredSectionRepository) Delete(_ context.Context, _ uuid.UUID) error {
+	return m.DeleteErr
+}
+
+func (m *MockMonitoredSectionRepository) ReplaceAll(_ context.Context, _ uuid.UUID, sections []*entities.MonitoredSection) error {
+	m.Replaced = sections
+	return m.ReplaceAllErr
+}
@@ -21,6 +21,9 @@ type MockMonitoringConfigRepository struct {
 	MarkPageDueNowErr    error
 	GetLastCheckedAtResult *time.Time
 	GetLastCheckedAtErr    error
+	UpdateSelectorHealthErr error
+	SelectorHealth          *entities.SelectorHealth
+	DocumentProbe           *entities.DocumentProbe
 
 	CreateFn func(ctx context.Context, config *entities.MonitoringConfig) error
 
@@ -46,6 +49,16 @@ func (m *MockMonitoringConfigRepository) Update(_ context.Context, _ *entities.M
 	return m.UpdateErr
 }
 
+func (m *MockMonitoringConfigRepository) UpdateSelectorHealth(_ context.Context, _ uuid.UUID, health entities.SelectorHealth) error {
+	m.SelectorHealth = &health
+	return m.UpdateSelectorHealthErr
+}
+
+func (m *MockMonitoringConfigRepository) UpdateDocumentProbe(_ context.Context, _ uuid.UUID, probe entities.DocumentProbe) error {
+	m.DocumentProbe = &probe
+	return nil
+}
+
 func (m *MockMonitoringConfigRepository) GetDueSnapshotTasks(_ context.Context) ([]entities.SnapshotTask, error) {
 	return m.GetDueTasksResult, m.GetDueTasksErr
 }
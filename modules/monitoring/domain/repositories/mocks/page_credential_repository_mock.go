@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockPageCredentialRepository struct {
+	GetByPageIDResult    *entities.PageCredential
+	GetByPageIDErr       error
+	UpsertErr            error
+	DeleteErr            error
+	UpdateLoginStatusErr error
+
+	Upserted *entities.PageCredential
+
+	UpsertCalls int
+	DeleteCalls int
+}
+
+func (m *MockPageCredentialRepository) GetByPageID(_ context.Context, _ uuid.UUID) (*entities.PageCredential, error) {
+	return m.GetByPageIDResult, m.GetByPageIDErr
+}
+
+func (m *MockPageCredentialRepository) Upsert(_ context.Context, credential *entities.PageCredential) error {
+	m.UpsertCalls++
+	m.Upserted = credential
+	return m.UpsertErr
+}
+
+func (m *MockPageCredentialRepository) DeleteByPageID(_ context.Context, _ uuid.UUID) error {
+	m.DeleteCalls++
+	return m.DeleteErr
+}
+
+func (m *MockPageCredentialRepository) UpdateLoginStatus(_ context.Context, _ uuid.UUID, _, _ string) error {
+	return m.UpdateLoginStatusErr
+}
@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockProxyRepository struct {
+	ListResult              []*entities.Proxy
+	ListErr                 error
+	GetByIDResult           *entities.Proxy
+	GetByIDErr              error
+	CreateErr               error
+	UpdateErr               error
+	DeleteErr               error
+	MarkUsedErr             error
+	UpdateHealthErr         error
+	GetWorkspaceRouteResult *entities.ProxyRoute
+	GetWorkspaceRouteErr    error
+	SetWorkspaceRouteErr    error
+
+	Created     *entities.Proxy
+	Updated     *entities.Proxy
+	RouteSet    *entities.ProxyRoute
+	CreateCalls int
+	UpdateCalls int
+	DeleteCalls int
+}
+
+func (m *MockProxyRepository) List(_ context.Context) ([]*entities.Proxy, error) {
+	return m.ListResult, m.ListErr
+}
+
+func (m *MockProxyRepository) GetByID(_ context.Context, _ uuid.UUID) (*entities.Proxy, error) {
+	return m.GetByIDResult, m.GetByIDErr
+}
+
+func (m *MockProxyRepository) Create(_ context.Context, proxy *entities.Proxy) error {
+	m.CreateCalls++
+	m.Created = proxy
+	return m.CreateErr
+}
+
+func (m *MockProxyRepository) Update(_ context.Context, proxy *entities.Proxy) error {
+	m.UpdateCalls++
+	m.Updated = proxy
+	return m.UpdateErr
+}
+
+func (m *MockProxyRepository) Delete(_ context.Context, _ uuid.UUID) error {
+	m.DeleteCalls++
+	return m.DeleteErr
+}
+
+func (m *MockProxyRepository) MarkUsed(_ context.Context, _ uuid.UUID, _ time.Time) error {
+	return m.MarkUsedErr
+}
+
+func (m *MockProxyRepository) UpdateHealth(_ context.Context, _ *entities.Proxy) error {
+	return m.UpdateHealthErr
+}
+
+func (m *MockProxyRepository) GetWorkspaceRoute(_ context.Context, _ uuid.UUID) (*entities.ProxyRoute, error) {
+	return m.GetWorkspaceRouteResult, m.GetWorkspaceRouteErr
+}
+
+func (m *MockProxyRepository) SetWorkspaceRoute(_ context.Context, _ uuid.UUID, route entities.ProxyRoute) error {
+	m.RouteSet = &route
+	return m.SetWorkspaceRouteErr
+}
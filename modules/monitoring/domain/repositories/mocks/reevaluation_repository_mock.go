@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockReevaluationRepository struct {
+	CreateErr          error
+	GetByIDResult      *entities.ReevaluationJob
+	GetByIDErr         error
+	ListByPageIDResult []*entities.ReevaluationJob
+	ListByPageIDErr    error
+	UpdateErr          error
+
+	Created *entities.ReevaluationJob
+	Updated *entities.ReevaluationJob
+}
+
+func (m *MockReevaluationRepository) Create(_ context.Context, job *entities.ReevaluationJob) error {
+	m.Created = job
+	return m.CreateErr
+}
+
+func (m *MockReevaluationRepository) GetByID(_ context.Context, _ uuid.UUID) (*entities.ReevaluationJob, error) {
+	return m.GetByIDResult, m.GetByIDErr
+}
+
+func (m *MockReevaluationRepository) ListByPageID(_ context.Context, _ uuid.UUID) ([]*entities.ReevaluationJob, error) {
+	return m.ListByPageIDResult, m.ListByPageIDErr
+}
+
+func (m *MockReevaluationRepository) Update(_ context.Context, job *entities.ReevaluationJob) error {
+	m.Updated = job
+	return m.UpdateErr
+}
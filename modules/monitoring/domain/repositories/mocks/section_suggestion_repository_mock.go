@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockSectionSuggestionRepository struct {
+	ReplaceForPageErr  error
+	ListByPageIDResult []*entities.SectionSuggestion
+	ListByPageIDErr    error
+	DeleteErr          error
+
+	Replaced []*entities.SectionSuggestion
+	Deleted  []uuid.UUID
+}
+
+func (m *MockSectionSuggestionRepository) ReplaceForPage(_ context.Context, _ uuid.UUID, suggestions []*entities.SectionSuggestion) error {
+	m.Replaced = suggestions
+	return m.ReplaceForPageErr
+}
+
+func (m *MockSectionSuggestionRepository) ListByPageID(_ context.Context, _ uuid.UUID) ([]*entities.SectionSuggestion, error) {
+	return m.ListByPageIDResult, m.ListByPageIDErr
+}
+
+func (m *MockSectionSuggestionRepository) Delete(_ context.Context, ids []uuid.UUID) error {
+	m.Deleted = append(m.Deleted, ids...)
+	return m.DeleteErr
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type MockTimelapseRepository struct {
+	CreateErr          error
+	GetByIDResult      *entities.TimelapseJob
+	GetByIDErr         error
+	ListByPageIDResult []*entities.TimelapseJob
+	ListByPageIDErr    error
+	UpdateErr          error
+
+	Created *entities.TimelapseJob
+	Updated *entities.TimelapseJob
+}
+
+func (m *MockTimelapseRepository) Create(_ context.Context, job *entities.TimelapseJob) error {
+	m.Created = job
+	return m.CreateErr
+}
+
+func (m *MockTimelapseRepository) GetByID(_ context.Context, _ uuid.UUID) (*entities.TimelapseJob, error) {
+	return m.GetByIDResult, m.GetByIDErr
+}
+
+func (m *MockTimelapseRepository) ListByPageID(_ context.Context, _ uuid.UUID) ([]*entities.TimelapseJob, error) {
+	return m.ListByPageIDResult, m.ListByPageIDErr
+}
+
+func (m *MockTimelapseRepository) Update(_ context.Context, job *entities.TimelapseJob) error {
+	m.Updated = job
+	return m.UpdateErr
+}
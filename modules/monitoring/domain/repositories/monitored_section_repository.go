@@ -13,6 +13,11 @@ type MonitoredSectionRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.MonitoredSection, error)
 	ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.MonitoredSection, error)
 	Update(ctx context.Context, section *entities.MonitoredSection) error
+	// UpdateSelectorHealth records the section selector's miss streak.
+	UpdateSelectorHealth(ctx context.Context, id uuid.UUID, health entities.SelectorHealth) error
+	// UpdateSelectorProposal stores, or clears when nil, the replacement
+	// selector proposed for a section.
+	UpdateSelectorProposal(ctx context.Context, id uuid.UUID, proposal *entities.SelectorProposal) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	// ReplaceAll atomically replaces all sections for a page.
 	ReplaceAll(ctx context.Context, pageID uuid.UUID, sections []*entities.MonitoredSection) error
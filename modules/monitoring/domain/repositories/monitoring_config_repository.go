@@ -13,6 +13,10 @@ type MonitoringConfigRepository interface {
 	Create(ctx context.Context, config *entities.MonitoringConfig) error
 	GetByPageID(ctx context.Context, pageID uuid.UUID) (*entities.MonitoringConfig, error)
 	Update(ctx context.Context, config *entities.MonitoringConfig) error
+	// UpdateSelectorHealth records the element selector's miss streak without touching the rest of the config.
+	UpdateSelectorHealth(ctx context.Context, pageID uuid.UUID, health entities.SelectorHealth) error
+	// UpdateDocumentProbe caches what the page URL served when probed without touching the rest of the config.
+	UpdateDocumentProbe(ctx context.Context, pageID uuid.UUID, probe entities.DocumentProbe) error
 	BulkUpdateFrequency(ctx context.Context, pageIDs []uuid.UUID, frequency string) error
 	GetDueSnapshotTasks(ctx context.Context) ([]entities.SnapshotTask, error)
 	GetPageURL(ctx context.Context, pageID uuid.UUID) (string, error)
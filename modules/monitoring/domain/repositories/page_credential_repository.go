@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// PageCredentialRepository defines operations for the credential attached to a page.
+type PageCredentialRepository interface {
+	// GetByPageID returns the page's credential, or nil when it has none.
+	GetByPageID(ctx context.Context, pageID uuid.UUID) (*entities.PageCredential, error)
+	// Upsert creates or replaces the page's credential.
+	Upsert(ctx context.Context, credential *entities.PageCredential) error
+	DeleteByPageID(ctx context.Context, pageID uuid.UUID) error
+	// UpdateLoginStatus records the outcome of the latest login attempt.
+	UpdateLoginStatus(ctx context.Context, pageID uuid.UUID, status, errMsg string) error
+}
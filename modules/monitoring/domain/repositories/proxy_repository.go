@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// ProxyRepository defines operations for the tenant's outbound proxy registry.
+type ProxyRepository interface {
+	List(ctx context.Context) ([]*entities.Proxy, error)
+	// GetByID returns the proxy, or nil when it does not exist.
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Proxy, error)
+	Create(ctx context.Context, proxy *entities.Proxy) error
+	Update(ctx context.Context, proxy *entities.Proxy) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// MarkUsed records that a check was just routed through the proxy.
+	MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+	// UpdateHealth stores the proxy's failure streak, quarantine and last result.
+	UpdateHealth(ctx context.Context, proxy *entities.Proxy) error
+	// GetWorkspaceRoute returns the workspace's default route, or nil when it has none.
+	GetWorkspaceRoute(ctx context.Context, workspaceID uuid.UUID) (*entities.ProxyRoute, error)
+	// SetWorkspaceRoute replaces the workspace's default route; a zero route removes it.
+	SetWorkspaceRoute(ctx context.Context, workspaceID uuid.UUID, route entities.ProxyRoute) error
+}
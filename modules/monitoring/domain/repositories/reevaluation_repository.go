@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// ReevaluationRepository stores history re-evaluation jobs and their reports.
+type ReevaluationRepository interface {
+	Create(ctx context.Context, job *entities.ReevaluationJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ReevaluationJob, error)
+	// ListByPageID returns a page's jobs, newest first, without their results.
+	ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.ReevaluationJob, error)
+	Update(ctx context.Context, job *entities.ReevaluationJob) error
+}
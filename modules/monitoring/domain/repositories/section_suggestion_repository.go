@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// SectionSuggestionRepository stores the sections suggested for a page.
+type SectionSuggestionRepository interface {
+	// ReplaceForPage atomically replaces a page's suggestions.
+	ReplaceForPage(ctx context.Context, pageID uuid.UUID, suggestions []*entities.SectionSuggestion) error
+	// ListByPageID returns a page's suggestions, best ranked first.
+	ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.SectionSuggestion, error)
+	Delete(ctx context.Context, ids []uuid.UUID) error
+}
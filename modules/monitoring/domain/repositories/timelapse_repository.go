@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// TimelapseRepository stores time-lapse render jobs.
+type TimelapseRepository interface {
+	Create(ctx context.Context, job *entities.TimelapseJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.TimelapseJob, error)
+	// ListByPageID returns a page's jobs, newest first.
+	ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.TimelapseJob, error)
+	Update(ctx context.Context, job *entities.TimelapseJob) error
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// MaxSectionSuggestions caps how many regions are suggested for one page.
+const MaxSectionSuggestions = 8
+
+// SectionCandidate is a region of a rendered page, as found by the
+// extractor's preview.
+type SectionCandidate struct {
+	Selector     string
+	XPath        string
+	Tag          string
+	SemanticRole string
+	Text         string
+	Rect         entities.SectionRect
+	Attributes   map[string]string
+	Landmarks    []string
+}
+
+var (
+	priceRe     = regexp.MustCompile(`[$€£¥]\s?\d|\d\s?(usd|eur|gbp)\b|/\s?(mo|month|yr|year)\b|per (month|year|user|seat)`)
+	pricingRe   = regexp.MustCompile(`pricing|price|plans?\b|subscription`)
+	changelogRe = regexp.MustCompile(`changelog|release notes|what'?s new|releases|version history`)
+	versionRe   = regexp.MustCompile(`\bv?\d+\.\d+(\.\d+)?\b`)
+	heroRe      = regexp.MustCompile(`\bhero\b|jumbotron|banner`)
+)
+
+// kindNames are the section names suggestions are given.
+var kindNames = map[string]string{
+	entities.SuggestionKindPricing:    "Pricing",
+	entities.SuggestionKindChangelog:  "Changelog",
+	entities.SuggestionKindTable:      "Table",
+	entities.SuggestionKindHero:       "Hero",
+	entities.SuggestionKindArticle:    "Article",
+	entities.SuggestionKindNavigation: "Navigation",
+	entities.SuggestionKindFooter:     "Footer",
+}
+
+// SuggestSections picks the regions of a page worth monitoring and ranks them
+// by how likely they are to change meaningfully: prices and changelogs first,
+// then data tables, the hero and articles, with navigation and footer last.
+// Regions nested inside a better-ranked region of the same kind are dropped.
+func SuggestSections(pageID uuid.UUID, candidates []SectionCandidate, viewportWidth int) []*entities.SectionSuggestion {
+	type ranked struct {
+		candidate *SectionCandidate
+		kind      string
+		score     float64
+	}
+	var all []ranked
+	for i := range candidates {
+		if kind, score := classifySection(&candidates[i], viewportWidth); kind != "" {
+			all = append(all, ranked{&candidates[i], kind, score})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].score != all[j].score {
+			return all[i].score > all[j].score
+		}
+		return all[i].candidate.Rect.Y < all[j].candidate.Rect.Y
+	})
+
+	var kept []ranked
+	kindCount := map[string]int{}
+	for _, r := range all {
+		if len(kept) == MaxSectionSuggestions {
+			break
+		}
+		nested := false
+		for _, k := range kept {
+			if k.kind == r.kind && (contains(k.candidate.Rect, r.candidate.Rect) || contains(r.candidate.Rect, k.candidate.Rect)) {
+				nested = true
+				break
+			}
+		}
+		if nested {
+			continue
+		}
+		kept = append(kept, r)
+		kindCount[r.kind]++
+	}
+
+	now := time.Now()
+	suggestions := make([]*entities.SectionSuggestion, 0, len(kept))
+	seen := map[string]int{}
+	for _, r := range kept {
+		c := r.candidate
+		seen[r.kind]++
+		name := kindNames[r.kind]
+		if kindCount[r.kind] > 1 {
+			name = fmt.Sprintf("%s %d", name, seen[r.kind])
+		}
+		rect := c.Rect
+		suggestions = append(suggestions, &entities.SectionSuggestion{
+			ID:            uuid.New(),
+			PageID:        pageID,
+			Kind:          r.kind,
+			Name:          name,
+			CSSSelector:   c.Selector,
+			XPathSelector: c.XPath,
+			Rect:          &rect,
+			ViewportWidth: viewportWidth,
+			Fingerprint: &entities.ElementFingerprint{
+				Tag:        c.Tag,
+				Text:       c.Text,
+				Attributes: c.Attributes,
+				Rect:       &rect,
+				Landmarks:  c.Landmarks,
+			},
+			Score:     r.score,
+			CreatedAt: now,
+		})
+	}
+	return suggestions
+}
+
+// classifySection returns the kind of region a candidate is and how likely it
+// is to change meaningfully, or an empty kind when it is not worth suggesting.
+func classifySection(c *SectionCandidate, viewportWidth int) (string, float64) {
+	text := strings.ToLower(c.Text)
+	hints := strings.ToLower(strings.Join(append(attributeValues(c.Attributes), c.Landmarks...), " "))
+
+	// Site chrome is judged by role alone; its links and nearby headings
+	// mention "pricing" and "changelog" without being either.
+	switch c.SemanticRole {
+	case "navigation", "header":
+		return entities.SuggestionKindNavigation, 0.3
+	case "footer":
+		return entities.SuggestionKindFooter, 0.2
+	}
+
+	switch {
+	case priceRe.MatchString(text) && (pricingRe.MatchString(hints) || pricingRe.MatchString(text)):
+		return entities.SuggestionKindPricing, 0.95
+	case pricingRe.MatchString(hints):
+		return entities.SuggestionKindPricing, 0.8
+	case changelogRe.MatchString(hints) || changelogRe.MatchString(text):
+		return entities.SuggestionKindChangelog, 0.85
+	case c.SemanticRole == "list" && versionRe.MatchString(text):
+		return entities.SuggestionKindChangelog, 0.75
+	case c.SemanticRole == "table":
+		return entities.SuggestionKindTable, 0.7
+	case heroRe.MatchString(hints):
+		return entities.SuggestionKindHero, 0.6
+	case isHeroRect(c, viewportWidth):
+		return entities.SuggestionKindHero, 0.55
+	case c.SemanticRole == "article":
+		return entities.SuggestionKindArticle, 0.5
+	default:
+		return "", 0
+	}
+}
+
+// isHeroRect reports whether a content region spans most of the first screen
+// without being a wrapper around the whole page.
+func isHeroRect(c *SectionCandidate, viewportWidth int) bool {
+	if c.SemanticRole == "form" || c.SemanticRole == "main" {
+		return false
+	}
+	return viewportWidth > 0 && c.Rect.Y < 700 && c.Rect.W*10 >= viewportWidth*6 &&
+		c.Rect.H >= 250 && c.Rect.H <= 1200
+}
+
+func attributeValues(attrs map[string]string) []string {
+	values := make([]string, 0, len(attrs))
+	for _, v := range attrs {
+		values = append(values, v)
+	}
+	return values
+}
+
+// contains reports whether rect b lies inside rect a.
+func contains(a, b entities.SectionRect) bool {
+	return a.X <= b.X && a.Y <= b.Y && a.X+a.W >= b.X+b.W && a.Y+a.H >= b.Y+b.H
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+func TestSuggestSections(t *testing.T) {
+	candidates := []SectionCandidate{
+		{Selector: "header", Tag: "header", SemanticRole: "header", Text: "Acme Pricing Docs Changelog", Rect: entities.SectionRect{X: 0, Y: 0, W: 1440, H: 80}},
+		{Selector: ".hero", Tag: "section", SemanticRole: "section", Text: "Ship faster with Acme", Rect: entities.SectionRect{X: 0, Y: 80, W: 1440, H: 520}},
+		{Selector: "#plans", Tag: "section", SemanticRole: "section", Text: "Starter $9/mo Pro $29/mo", Attributes: map[string]string{"id": "plans"}, Rect: entities.SectionRect{X: 0, Y: 700, W: 1440, H: 600}},
+		{Selector: "#plans > div:nth-of-type(1)", Tag: "div", SemanticRole: "generic", Text: "Starter $9/mo", Landmarks: []string{"h2:Pricing"}, Rect: entities.SectionRect{X: 100, Y: 760, W: 400, H: 500}},
+		{Selector: ".releases", Tag: "ul", SemanticRole: "list", Text: "v2.4.0 Faster sync v2.3.1 Bug fixes", Rect: entities.SectionRect{X: 0, Y: 1400, W: 900, H: 300}},
+		{Selector: ".testimonials", Tag: "div", SemanticRole: "generic", Text: "Loved by teams", Rect: entities.SectionRect{X: 0, Y: 1800, W: 1440, H: 300}},
+		{Selector: "footer", Tag: "footer", SemanticRole: "footer", Text: "© Acme Pricing", Rect: entities.SectionRect{X: 0, Y: 2200, W: 1440, H: 200}},
+	}
+
+	got := SuggestSections(uuid.New(), candidates, 1440)
+
+	want := []struct{ selector, kind string }{
+		{"#plans", entities.SuggestionKindPricing},
+		{".releases", entities.SuggestionKindChangelog},
+		{".hero", entities.SuggestionKindHero},
+		{"header", entities.SuggestionKindNavigation},
+		{"footer", entities.SuggestionKindFooter},
+	}
+	if len(got) != len(want) {
+		for _, s := range got {
+			t.Logf("%s %s %.2f", s.Kind, s.CSSSelector, s.Score)
+		}
+		t.Fatalf("got %d suggestions, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].CSSSelector != w.selector || got[i].Kind != w.kind {
+			t.Errorf("suggestion %d = %s (%s), want %s (%s)", i, got[i].CSSSelector, got[i].Kind, w.selector, w.kind)
+		}
+	}
+	if got[0].Name != "Pricing" || got[0].Fingerprint == nil || got[0].Fingerprint.Attributes["id"] != "plans" {
+		t.Errorf("pricing suggestion = %+v, want named with its fingerprint", got[0])
+	}
+}
+
+func TestSuggestSectionsCap(t *testing.T) {
+	var candidates []SectionCandidate
+	for i := 0; i < MaxSectionSuggestions+3; i++ {
+		candidates = append(candidates, SectionCandidate{
+			Selector:     "table",
+			Tag:          "table",
+			SemanticRole: "table",
+			Rect:         entities.SectionRect{X: 0, Y: i * 400, W: 800, H: 300},
+		})
+	}
+
+	got := SuggestSections(uuid.New(), candidates, 1440)
+	if len(got) != MaxSectionSuggestions {
+		t.Fatalf("got %d suggestions, want %d", len(got), MaxSectionSuggestions)
+	}
+	if got[0].Name != "Table 1" || got[1].Name != "Table 2" {
+		t.Errorf("names = %q, %q; want numbered tables", got[0].Name, got[1].Name)
+	}
+}
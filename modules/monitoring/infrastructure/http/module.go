@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -19,16 +20,28 @@ import (
 	createcheck "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/create_check"
 	createmonitoringconfig "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/create_monitoring_config"
 	createnotificationpreference "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/create_notification_preference"
+	exporttimelapse "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/export_timelapse"
+	getcheckdiff "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/get_check_diff"
 	getmonitoringconfig "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/get_monitoring_config"
 	listchecks "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/list_checks"
+	managecredentials "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/manage_credentials"
+	manageproxies "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/manage_proxies"
 	managesections "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/manage_sections"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/orchestrator"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/reaper"
+	reevaluatehistory "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/reevaluate_history"
+	replayarchive "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/replay_archive"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/retention"
+	snapshotkeys "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/snapshot_keys"
 	updatemonitoringconfig "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/update_monitoring_config"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/workers"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/scheduler"
 	snapshotapp "github.com/jcsoftdev/pulzifi-back/modules/snapshot/application"
 	snapshotextractor "github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	snapshotfilesystem "github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/filesystem"
+	snapshotlinkcheck "github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/linkcheck"
 	snapshotstorage "github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/storage"
 	sharedAI "github.com/jcsoftdev/pulzifi-back/shared/ai"
 	"github.com/jcsoftdev/pulzifi-back/shared/config"
@@ -37,6 +50,7 @@ import (
 	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
 	"github.com/jcsoftdev/pulzifi-back/shared/pubsub"
 	"github.com/jcsoftdev/pulzifi-back/shared/router"
+	"github.com/jcsoftdev/pulzifi-back/shared/secrets"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +61,19 @@ type Module struct {
 	scheduler   *scheduler.Scheduler
 	workerPool  *workers.WorkerPool
 	checkBroker *pubsub.CheckBroker
+	vault       *secrets.Vault
+	snapshots   getcheckdiff.SnapshotReader
+	reevaluator reevaluatehistory.Runner
+	timelapses  exporttimelapse.Runner
+	retention   *retention.Purger
+	reaper      *reaper.Reaper
+	keys        *snapshotkeys.Migrator
+	signer      *snapshotstorage.URLSigner
+	files       *snapshotfilesystem.Client // set when snapshots are stored on the local filesystem
+	replayer    *replayarchive.Replayer
+	extractor   *snapshotextractor.HTTPClient
+
+	extractorHealthInterval time.Duration
 }
 
 // NewModule creates a new instance of the Monitoring module
@@ -75,7 +102,26 @@ func NewModuleWithDB(db *sql.DB, eventBus *eventbus.EventBus, emailProvider emai
 		}
 	}
 
+	if objectStorage != nil {
+		m.snapshots = objectStorage
+		m.signer = snapshotstorage.NewURLSigner(objectStorage, m.db)
+		m.replayer = replayarchive.NewReplayer(objectStorage, cfg.ArchiveReplayURL, cfg.ArchiveReplaySignKey)
+	}
+	if files, ok := objectStorage.(*snapshotfilesystem.Client); ok {
+		m.files = files
+	}
+
 	extractorClient := snapshotextractor.NewHTTPClient(cfg.ExtractorURL)
+	extractorClient.SetCircuitBreaker(cfg.ExtractorBreakerThreshold, cfg.ExtractorBreakerCooldown)
+	m.extractor = extractorClient
+	m.extractorHealthInterval = cfg.ExtractorHealthInterval
+
+	// Page credentials and proxy passwords are encrypted with a per-tenant key derived from this vault.
+	m.vault, err = secrets.NewVault(cfg.CredentialsEncryptionKey)
+	if err != nil {
+		logger.Error("Invalid credentials encryption key — authenticated monitoring disabled", zap.Error(err))
+		m.vault, _ = secrets.NewVault("")
+	}
 
 	var insightHandler *generateinsights.GenerateInsightsHandler
 	if cfg.OpenRouterAPIKey != "" {
@@ -88,6 +134,17 @@ func NewModuleWithDB(db *sql.DB, eventBus *eventbus.EventBus, emailProvider emai
 
 	// Set pixel diff threshold from config
 	snapshotWorker.SetPixelDiffThreshold(cfg.PixelDiffThreshold)
+	snapshotWorker.SetDerivativeEncoding(cfg.SnapshotDerivativeFormat, cfg.SnapshotDerivativeQuality)
+
+	// Shared across all checks so link results are cached between pages
+	snapshotWorker.SetLinkChecker(snapshotlinkcheck.NewChecker(cfg.LinkCheckConcurrency, cfg.LinkCheckPerHost, cfg.LinkCheckTimeout, cfg.LinkCheckCacheTTL))
+
+	snapshotWorker.SetCredentialCipher(m.vault)
+
+	// History re-evaluations replay the worker's change detection in the background.
+	m.reevaluator = snapshotWorker
+	// Time-lapses are rendered from stored screenshots in the background too.
+	m.timelapses = snapshotWorker
 
 	// Initialize Vision AI analyzer if vision model is configured
 	if cfg.OpenRouterAPIKey != "" && cfg.OpenRouterVisionModel != "" {
@@ -117,26 +174,29 @@ func NewModuleWithDB(db *sql.DB, eventBus *eventbus.EventBus, emailProvider emai
 		}
 		check.Status = "error"
 		check.ErrorMessage = errMsg
-		if updateErr := repo.Update(ctx, check); updateErr != nil {
+		completed, updateErr := repo.Complete(ctx, check)
+		if updateErr != nil {
 			logger.Error("failCheck: failed to update check status", zap.Error(updateErr), zap.String("check_id", checkID.String()))
 			return
 		}
-		// Publish the failed check to SSE subscribers.
-		payload, _ := json.Marshal(listchecks.CheckResponse{
-			ID:              check.ID,
-			PageID:          check.PageID,
-			Status:          check.Status,
-			ScreenshotURL:   check.ScreenshotURL,
-			HTMLSnapshotURL: check.HTMLSnapshotURL,
-			ChangeDetected:  check.ChangeDetected,
-			ChangeType:      check.ChangeType,
-			ErrorMessage:    check.ErrorMessage,
-			CheckedAt:       check.CheckedAt,
-		})
-		m.checkBroker.Publish(check.PageID.String(), payload)
+		if completed {
+			m.publishCheck(check)
+		}
 	}
 	m.workerPool = workers.NewWorkerPool(snapshotWorker, 100, failCheck)
 
+	// Workers claim each check before running it, so duplicate dispatches of
+	// one check run once, and stamp a heartbeat on it so the reaper can tell
+	// checks whose worker died from checks still running.
+	m.workerPool.SetClaim(func(ctx context.Context, checkID uuid.UUID, schemaName string) (bool, error) {
+		return persistence.NewCheckPostgresRepository(m.db, schemaName).Claim(ctx, checkID)
+	})
+	m.workerPool.SetHeartbeat(func(ctx context.Context, checkID uuid.UUID, schemaName string) {
+		if err := persistence.NewCheckPostgresRepository(m.db, schemaName).Heartbeat(ctx, checkID); err != nil && ctx.Err() == nil {
+			logger.Warn("Failed to record check heartbeat", zap.Error(err), zap.String("check_id", checkID.String()))
+		}
+	}, workers.DefaultHeartbeatInterval)
+
 	// In API-only mode we still need immediate dispatch capability when user updates frequency.
 	// Start a lightweight in-process worker to consume TriggerPageCheck jobs.
 	if os.Getenv("ENABLE_WORKERS") == "false" {
@@ -153,12 +213,35 @@ func NewModuleWithDB(db *sql.DB, eventBus *eventbus.EventBus, emailProvider emai
 		m.checkBroker.Publish(pageID.String(), checkJSON)
 	})
 
+	// Re-dispatch or fail checks left pending by a crashed worker or a hung
+	// extractor, refunding their quota.
+	m.reaper = reaper.NewReaper(repoFactory, m.workerPool)
+	m.reaper.SetOnCheckFailed(m.publishCheck)
+
 	// Create Scheduler instance
 	m.scheduler = scheduler.NewScheduler(m.db, orch)
 
+	// Purge captures older than each tenant's plan storage period, and move
+	// rows stored before snapshots were private from public URLs to keys.
+	if objectStorage != nil {
+		m.retention = retention.NewPurger(repoFactory, objectStorage)
+		m.keys = snapshotkeys.NewMigrator(repoFactory, objectStorage)
+	}
+
 	return m
 }
 
+// publishCheck pushes a check's current state to SSE subscribers of its page,
+// in the same shape the worker publishes completed checks in.
+func (m *Module) publishCheck(check *entities.Check) {
+	payload, err := json.Marshal(listchecks.NewCheckResponse(check))
+	if err != nil {
+		logger.Error("Failed to serialize check for SSE notification", zap.Error(err))
+		return
+	}
+	m.checkBroker.Publish(check.PageID.String(), payload)
+}
+
 // StartBackgroundProcesses initializes and starts the Scheduler, Orchestrator, and Workers
 func (m *Module) StartBackgroundProcesses() {
 	if m.scheduler == nil || m.workerPool == nil {
@@ -172,6 +255,18 @@ func (m *Module) StartBackgroundProcesses() {
 	// Start Scheduler
 	m.scheduler.Start(context.Background())
 
+	m.reaper.Start(context.Background())
+
+	if m.retention != nil {
+		m.retention.Start(context.Background())
+	}
+	if m.keys != nil {
+		m.keys.Start(context.Background())
+	}
+	if m.extractor != nil {
+		m.extractor.StartHealthChecks(context.Background(), m.extractorHealthInterval)
+	}
+
 	logger.Info("Monitoring Scheduler and Orchestrator initialized and started")
 }
 
@@ -182,9 +277,16 @@ func (m *Module) ModuleName() string {
 
 // RegisterHTTPRoutes registers all HTTP routes for the Monitoring module
 func (m *Module) RegisterHTTPRoutes(router chi.Router) {
+	// Files and archive replays are authorized by their signed URL, so they
+	// load in <img> tags and iframes.
+	router.Get("/monitoring/files/*", m.handleGetFile)
+	router.Get("/monitoring/replay/*", m.handleReplayArchive)
+
 	router.Route("/monitoring", func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware.Authenticate)
 
+		r.With(middleware.AuthMiddleware.RequireRole("SUPER_ADMIN")).Get("/extractors", m.handleExtractorStatus)
+
 		// /checks sub-router: all routes require org+tenant authorization.
 		r.Route("/checks", func(cr chi.Router) {
 			cr.Use(middleware.OrgMiddleware.RequireOrganizationMembership)
@@ -193,8 +295,19 @@ func (m *Module) RegisterHTTPRoutes(router chi.Router) {
 			cr.Post("/", m.handleCreateCheck)
 			cr.Get("/", m.handleListChecks)
 			cr.Get("/{id}", m.handleGetCheck)
+			cr.Get("/{id}/diff", m.handleGetCheckDiff)
+			cr.Get("/{id}/archive", m.handleGetCheckArchive)
+			cr.Put("/{id}/pin", m.handlePinCheck)
+			cr.Delete("/{id}/pin", m.handleUnpinCheck)
 			cr.Get("/page/{pageId}", m.handleListChecksByPage)
 			cr.Post("/page/{pageId}/run", m.handleRunNow)
+			cr.Get("/page/{pageId}/reevaluations", m.handleListReevaluations)
+			cr.Post("/page/{pageId}/reevaluations", m.handleStartReevaluation)
+			cr.Get("/reevaluations/{id}", m.handleGetReevaluation)
+			cr.Post("/reevaluations/{id}/apply", m.handleApplyReevaluation)
+			cr.Get("/page/{pageId}/timelapses", m.handleListTimelapses)
+			cr.Post("/page/{pageId}/timelapses", m.handleStartTimelapse)
+			cr.Get("/timelapses/{id}", m.handleGetTimelapse)
 		})
 
 		r.Group(func(r chi.Router) {
@@ -214,6 +327,22 @@ func (m *Module) RegisterHTTPRoutes(router chi.Router) {
 				cr.Get("/", m.handleListSections)
 				cr.Post("/", m.handleSaveSections)
 				cr.Delete("/{sectionId}", m.handleDeleteSection)
+				cr.Post("/{sectionId}/accept-proposal", m.handleAcceptSectionProposal)
+				cr.Get("/suggestions", m.handleListSectionSuggestions)
+				cr.Post("/suggestions/accept", m.handleAcceptSectionSuggestions)
+			})
+			r.Route("/credentials/page/{pageId}", func(cr chi.Router) {
+				cr.Get("/", m.handleGetCredential)
+				cr.Put("/", m.handleSaveCredential)
+				cr.Delete("/", m.handleDeleteCredential)
+			})
+			r.Route("/proxies", func(cr chi.Router) {
+				cr.Get("/", m.handleListProxies)
+				cr.Post("/", m.handleCreateProxy)
+				cr.Put("/{id}", m.handleUpdateProxy)
+				cr.Delete("/{id}", m.handleDeleteProxy)
+				cr.Get("/workspace/{workspaceId}", m.handleGetWorkspaceProxyRoute)
+				cr.Put("/workspace/{workspaceId}", m.handleSetWorkspaceProxyRoute)
 			})
 		})
 	})
@@ -280,9 +409,14 @@ func (m *Module) handleListChecks(w http.ResponseWriter, r *http.Request) {
 
 	tenant := middleware.GetTenantFromContext(r.Context())
 	repo := persistence.NewCheckPostgresRepository(m.db, tenant)
-	handler := listchecks.NewListChecksHandler(repo)
+	handler := m.listChecksHandler(repo)
 
 	resp, err := handler.Handle(r.Context(), pageID)
+	if errors.Is(err, listchecks.ErrForbidden) {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "failed to list checks"})
@@ -310,8 +444,16 @@ func (m *Module) handleListChecksByPage(w http.ResponseWriter, r *http.Request)
 
 	tenant := middleware.GetTenantFromContext(r.Context())
 	repo := persistence.NewCheckPostgresRepository(m.db, tenant)
-	handler := listchecks.NewListChecksHandler(repo)
-	handler.HandleHTTP(w, r)
+	m.listChecksHandler(repo).HandleHTTP(w, r)
+}
+
+// listChecksHandler restricts check lists to members of the page's workspace
+// and signs their snapshot URLs when snapshots are stored.
+func (m *Module) listChecksHandler(repo *persistence.CheckPostgresRepository) *listchecks.ListChecksHandler {
+	if m.signer == nil {
+		return listchecks.NewListChecksHandler(repo)
+	}
+	return listchecks.NewListChecksHandlerWithSigner(repo, m.signer)
 }
 
 // handleRunNow triggers an immediate monitoring check for a page
@@ -360,6 +502,144 @@ func (m *Module) handleRunNow(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// handleGetFile serves a snapshot stored on the local filesystem
+// @Summary Get Snapshot File
+// @Description Serve a screenshot, HTML snapshot or document stored by the filesystem storage backend. The URL must carry the expires and signature parameters of a signed snapshot URL.
+// @Tags monitoring
+// @Param path path string true "Object key"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /monitoring/files/{path} [get]
+func (m *Module) handleGetFile(w http.ResponseWriter, r *http.Request) {
+	if m.files == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	key := chi.URLParam(r, "*")
+	if !m.files.Verify(key, r.URL.Query().Get("expires"), r.URL.Query().Get("signature")) {
+		http.Error(w, "invalid or expired signature", http.StatusForbidden)
+		return
+	}
+
+	p, err := m.files.Path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	http.ServeContent(w, r, path.Base(key), info.ModTime(), f)
+}
+
+// signCheckPayload replaces the snapshot keys of a check published to SSE
+// subscribers with URLs signed for the subscriber.
+func (m *Module) signCheckPayload(ctx context.Context, payload []byte) []byte {
+	if m.signer == nil {
+		return payload
+	}
+	var check listchecks.CheckResponse
+	if err := json.Unmarshal(payload, &check); err != nil {
+		return payload
+	}
+	check.SignURLs(ctx, m.signer)
+	signed, err := json.Marshal(&check)
+	if err != nil {
+		return payload
+	}
+	return signed
+}
+
+// handlePinCheck keeps a check's captures past the plan storage period
+// @Summary Pin Monitoring Check
+// @Description Pin a check as a baseline so retention purges keep its screenshot and snapshots
+// @Tags monitoring
+// @Security BearerAuth
+// @Param id path string true "Check ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /monitoring/checks/{id}/pin [put]
+func (m *Module) handlePinCheck(w http.ResponseWriter, r *http.Request) {
+	m.setCheckPinned(w, r, true)
+}
+
+// handleUnpinCheck lets retention purge a check's captures again
+// @Summary Unpin Monitoring Check
+// @Description Unpin a check so retention purges may delete its captures
+// @Tags monitoring
+// @Security BearerAuth
+// @Param id path string true "Check ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /monitoring/checks/{id}/pin [delete]
+func (m *Module) handleUnpinCheck(w http.ResponseWriter, r *http.Request) {
+	m.setCheckPinned(w, r, false)
+}
+
+func (m *Module) setCheckPinned(w http.ResponseWriter, r *http.Request, pinned bool) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid check id"})
+		return
+	}
+
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewCheckPostgresRepository(m.db, tenant)
+
+	check, err := repo.GetByID(r.Context(), id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "failed to get check"})
+		return
+	}
+	if check == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "check not found"})
+		return
+	}
+	if m.signer != nil {
+		member, err := m.signer.CanAccessPage(r.Context(), check.PageID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to get check"})
+			return
+		}
+		if !member {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": listchecks.ErrForbidden.Error()})
+			return
+		}
+	}
+
+	if err := repo.SetPinned(r.Context(), id, pinned); err != nil {
+		logger.Error("Failed to pin check", zap.Error(err), zap.String("check_id", id.String()))
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "failed to update check"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // handleGetCheck gets a monitoring check by ID
 // @Summary Get Monitoring Check
 // @Description Get a monitoring check by ID
@@ -394,6 +674,19 @@ func (m *Module) handleGetCheck(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]string{"error": "check not found"})
 		return
 	}
+	if m.signer != nil {
+		member, err := m.signer.CanAccessPage(r.Context(), check.PageID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "failed to get check"})
+			return
+		}
+		if !member {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": listchecks.ErrForbidden.Error()})
+			return
+		}
+	}
 
 	resp := listchecks.CheckResponse{
 		ID:              check.ID,
@@ -403,39 +696,303 @@ func (m *Module) handleGetCheck(w http.ResponseWriter, r *http.Request) {
 		Status:          check.Status,
 		ScreenshotURL:   check.ScreenshotURL,
 		HTMLSnapshotURL: check.HTMLSnapshotURL,
+		DocumentURL:     check.DocumentURL,
+		ThumbnailURL:    check.ThumbnailURL,
+		PreviewURL:      check.PreviewURL,
+		ArchiveURL:      check.ArchiveURL,
 		ChangeDetected:  check.ChangeDetected,
 		ChangeType:      check.ChangeType,
 		ErrorMessage:    check.ErrorMessage,
 		CheckedAt:       check.CheckedAt,
 	}
 
-	// If this is a parent check, include its section checks.
-	if check.SectionID == nil {
-		sectionChecks, err := repo.ListByParentCheckID(r.Context(), check.ID)
-		if err == nil && len(sectionChecks) > 0 {
-			resp.Sections = make([]*listchecks.CheckResponse, len(sectionChecks))
-			for i, sc := range sectionChecks {
-				resp.Sections[i] = &listchecks.CheckResponse{
+	resp.ProfileName = check.ProfileName
+	resp.ProxyID = check.ProxyID
+	resp.FetchEngine = check.FetchEngine
+	resp.SelectorFallback = check.SelectorFallback
+	resp.Recurrence = check.Recurrence
+	resp.Flapping = check.Flapping
+	resp.Pinned = check.Pinned
+	resp.PurgedAt = check.PurgedAt
+
+	// If this is a parent check, include its section and profile checks.
+	if check.SectionID == nil && check.ProfileName == "" {
+		childChecks, err := repo.ListByParentCheckID(r.Context(), check.ID)
+		if err == nil {
+			for _, sc := range childChecks {
+				child := &listchecks.CheckResponse{
 					ID:              sc.ID,
 					PageID:          sc.PageID,
 					SectionID:       sc.SectionID,
 					ParentCheckID:   sc.ParentCheckID,
+					ProfileName:     sc.ProfileName,
 					Status:          sc.Status,
 					ScreenshotURL:   sc.ScreenshotURL,
 					HTMLSnapshotURL: sc.HTMLSnapshotURL,
+					DocumentURL:     sc.DocumentURL,
+					ThumbnailURL:    sc.ThumbnailURL,
+					PreviewURL:      sc.PreviewURL,
+					ArchiveURL:      sc.ArchiveURL,
 					ChangeDetected:  sc.ChangeDetected,
 					ChangeType:      sc.ChangeType,
+					Recurrence:      sc.Recurrence,
+					Flapping:        sc.Flapping,
+					Pinned:          sc.Pinned,
+					PurgedAt:        sc.PurgedAt,
 					ErrorMessage:    sc.ErrorMessage,
 					CheckedAt:       sc.CheckedAt,
 				}
+				if sc.ProfileName != "" {
+					resp.Profiles = append(resp.Profiles, child)
+				} else {
+					resp.Sections = append(resp.Sections, child)
+				}
 			}
 		}
 	}
+	if m.signer != nil {
+		resp.SignURLs(r.Context(), m.signer)
+	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(resp)
 }
 
+// handleGetCheckDiff returns the content diff between a check and the one before it
+// @Summary Get Check Content Diff
+// @Description Compare a check's content blocks with the previous successful check in the same scope, with moved blocks and word-level edits
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json,plain,html
+// @Param id path string true "Check ID"
+// @Param format query string false "json (default), unified or html"
+// @Success 200 {object} getcheckdiff.CheckDiffResponse
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /monitoring/checks/{id}/diff [get]
+func (m *Module) handleGetCheckDiff(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewCheckPostgresRepository(m.db, tenant)
+	handler := getcheckdiff.NewGetCheckDiffHandler(repo, m.snapshots, m.signer)
+	handler.HandleHTTP(w, r)
+}
+
+// handleGetCheckArchive returns the links to a check's page archive
+// @Summary Get Check Page Archive
+// @Description Get a signed download URL for a check's MHTML page archive and a signed URL replaying it as a sandboxed page, for pages monitored in archive mode
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Check ID"
+// @Success 200 {object} replayarchive.CheckArchiveResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /monitoring/checks/{id}/archive [get]
+func (m *Module) handleGetCheckArchive(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.replayer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewCheckPostgresRepository(m.db, tenant)
+	replayarchive.NewGetCheckArchiveHandler(repo, m.signer, m.replayer).HandleHTTP(w, r)
+}
+
+// handleReplayArchive serves a part of a page archive to holders of a signed replay URL
+// @Summary Replay Page Archive
+// @Description Serve the page or a subresource of an MHTML archive with references rewritten to the archive. Responses are sandboxed: no scripts run and nothing loads from the live site.
+// @Tags monitoring
+// @Param path path string true "Signed replay path"
+// @Success 200
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /monitoring/replay/{path} [get]
+func (m *Module) handleReplayArchive(w http.ResponseWriter, r *http.Request) {
+	if m.replayer == nil {
+		http.NotFound(w, r)
+		return
+	}
+	m.replayer.HandleHTTP(w, r)
+}
+
+// handleExtractorStatus reports the extractor endpoints this process balances captures over
+// @Summary Extractor Pool Status
+// @Description Health, circuit breaker state and requests in flight of every configured extractor endpoint
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /monitoring/extractors [get]
+func (m *Module) handleExtractorStatus(w http.ResponseWriter, r *http.Request) {
+	if m.extractor == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"endpoints": m.extractor.Status()})
+}
+
+func (m *Module) reevaluateHistoryHandler(r *http.Request) *reevaluatehistory.ReevaluateHistoryHandler {
+	tenant := middleware.GetTenantFromContext(r.Context())
+	return reevaluatehistory.NewReevaluateHistoryHandler(
+		persistence.NewReevaluationPostgresRepository(m.db, tenant),
+		persistence.NewCheckPostgresRepository(m.db, tenant),
+		m.signer,
+		m.reevaluator,
+		tenant,
+	)
+}
+
+// handleStartReevaluation starts a dry-run replay of a page's history with its current rules
+// @Summary Re-evaluate Check History
+// @Description Replay change detection with the page's current ignore selectors, alert conditions and recurrence settings over its stored captures in a date range. The job runs in the background and reports which checks would have changed or alerted.
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Param request body reevaluatehistory.StartReevaluationRequest true "Date range and optional section"
+// @Success 202 {object} reevaluatehistory.ReevaluationJobResponse
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /monitoring/checks/page/{pageId}/reevaluations [post]
+func (m *Module) handleStartReevaluation(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.reevaluator == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.reevaluateHistoryHandler(r).HandleStartHTTP(w, r)
+}
+
+// handleListReevaluations lists a page's history re-evaluations
+// @Summary List History Re-evaluations
+// @Description List a page's history re-evaluation jobs, newest first, without their per-check results
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Success 200 {object} reevaluatehistory.ListReevaluationsResponse
+// @Failure 403 {string} string
+// @Router /monitoring/checks/page/{pageId}/reevaluations [get]
+func (m *Module) handleListReevaluations(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.reevaluateHistoryHandler(r).HandleListHTTP(w, r)
+}
+
+// handleGetReevaluation returns a history re-evaluation and its dry-run report
+// @Summary Get History Re-evaluation
+// @Description Get a history re-evaluation job with its per-check results once completed
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Re-evaluation job ID"
+// @Success 200 {object} reevaluatehistory.ReevaluationJobResponse
+// @Failure 404 {string} string
+// @Failure 403 {string} string
+// @Router /monitoring/checks/reevaluations/{id} [get]
+func (m *Module) handleGetReevaluation(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.reevaluateHistoryHandler(r).HandleGetHTTP(w, r)
+}
+
+// handleApplyReevaluation rewrites past checks with a re-evaluation's results
+// @Summary Apply History Re-evaluation
+// @Description Rewrite change_detected and change_type on the past checks a completed re-evaluation disagrees with. Alerts already sent are kept.
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Re-evaluation job ID"
+// @Success 200 {object} reevaluatehistory.ApplyReevaluationResponse
+// @Failure 404 {string} string
+// @Failure 409 {string} string
+// @Failure 403 {string} string
+// @Router /monitoring/checks/reevaluations/{id}/apply [post]
+func (m *Module) handleApplyReevaluation(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.reevaluateHistoryHandler(r).HandleApplyHTTP(w, r)
+}
+
+func (m *Module) exportTimelapseHandler(r *http.Request) *exporttimelapse.ExportTimelapseHandler {
+	tenant := middleware.GetTenantFromContext(r.Context())
+	return exporttimelapse.NewExportTimelapseHandler(
+		persistence.NewTimelapsePostgresRepository(m.db, tenant),
+		m.signer,
+		m.timelapses,
+		tenant,
+	)
+}
+
+// handleStartTimelapse starts rendering a time-lapse of a page's screenshots
+// @Summary Export Time-lapse
+// @Description Render the page's (or a section's) screenshots in a date range as an animated GIF, animated PNG or side-by-side JPEG strip. Frames share one width, can be stamped with their check date and can tint what changed since the previous frame. The job runs in the background; the result is stored with the page's snapshots.
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Param request body exporttimelapse.StartTimelapseRequest true "Date range, format and rendering options"
+// @Success 202 {object} exporttimelapse.TimelapseJobResponse
+// @Failure 400 {string} string
+// @Failure 403 {string} string
+// @Router /monitoring/checks/page/{pageId}/timelapses [post]
+func (m *Module) handleStartTimelapse(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.timelapses == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.exportTimelapseHandler(r).HandleStartHTTP(w, r)
+}
+
+// handleListTimelapses lists a page's time-lapse exports
+// @Summary List Time-lapses
+// @Description List a page's time-lapse jobs, newest first, with signed URLs of the rendered images
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Success 200 {object} exporttimelapse.ListTimelapsesResponse
+// @Failure 403 {string} string
+// @Router /monitoring/checks/page/{pageId}/timelapses [get]
+func (m *Module) handleListTimelapses(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.exportTimelapseHandler(r).HandleListHTTP(w, r)
+}
+
+// handleGetTimelapse returns a time-lapse job
+// @Summary Get Time-lapse
+// @Description Get a time-lapse job with a signed URL of the rendered image once completed
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Time-lapse job ID"
+// @Success 200 {object} exporttimelapse.TimelapseJobResponse
+// @Failure 403 {string} string
+// @Failure 404 {string} string
+// @Router /monitoring/checks/timelapses/{id} [get]
+func (m *Module) handleGetTimelapse(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.signer == nil {
+		http.Error(w, "Service not initialized", http.StatusInternalServerError)
+		return
+	}
+	m.exportTimelapseHandler(r).HandleGetHTTP(w, r)
+}
+
 // handleCreateMonitoringConfig creates a new monitoring config
 // @Summary Create Monitoring Config
 // @Description Create a new monitoring config
@@ -639,7 +1196,11 @@ func (m *Module) handleListSections(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.GetTenantFromContext(r.Context())
 	sectionRepo := persistence.NewMonitoredSectionPostgresRepository(m.db, tenant)
 	configRepo := persistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
-	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo)
+	suggestionRepo := persistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
+	if m.signer != nil {
+		handler.SetSnapshotSigner(m.signer)
+	}
 	handler.HandleListHTTP(w, r)
 }
 
@@ -662,7 +1223,8 @@ func (m *Module) handleSaveSections(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.GetTenantFromContext(r.Context())
 	sectionRepo := persistence.NewMonitoredSectionPostgresRepository(m.db, tenant)
 	configRepo := persistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
-	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo)
+	suggestionRepo := persistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
 	handler.HandleSaveHTTP(w, r)
 }
 
@@ -683,10 +1245,268 @@ func (m *Module) handleDeleteSection(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.GetTenantFromContext(r.Context())
 	sectionRepo := persistence.NewMonitoredSectionPostgresRepository(m.db, tenant)
 	configRepo := persistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
-	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo)
+	suggestionRepo := persistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
 	handler.HandleDeleteHTTP(w, r)
 }
 
+// handleAcceptSectionProposal switches a section to the selector proposed for it
+// @Summary Accept Section Selector Proposal
+// @Description Replace a section's selectors with the ones proposed after its fingerprint was matched
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Param sectionId path string true "Section ID"
+// @Success 200 {object} managesections.SectionResponse
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /monitoring/sections/page/{pageId}/{sectionId}/accept-proposal [post]
+func (m *Module) handleAcceptSectionProposal(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	sectionRepo := persistence.NewMonitoredSectionPostgresRepository(m.db, tenant)
+	configRepo := persistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
+	suggestionRepo := persistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
+	handler.HandleAcceptProposalHTTP(w, r)
+}
+
+// handleListSectionSuggestions returns the sections suggested for a page
+// @Summary List Section Suggestions
+// @Description List the regions suggested for monitoring when the page was added, best ranked first
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Success 200 {object} managesections.ListSuggestionsResponse
+// @Router /monitoring/sections/page/{pageId}/suggestions [get]
+func (m *Module) handleListSectionSuggestions(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	sectionRepo := persistence.NewMonitoredSectionPostgresRepository(m.db, tenant)
+	configRepo := persistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
+	suggestionRepo := persistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
+	handler.HandleListSuggestionsHTTP(w, r)
+}
+
+// handleAcceptSectionSuggestions turns suggestions into monitored sections
+// @Summary Accept Section Suggestions
+// @Description Add the chosen suggestions to the page's monitored sections and switch the page to sections mode
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Param request body managesections.AcceptSuggestionsRequest true "Accept Suggestions Request"
+// @Success 200 {object} managesections.ListSectionsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /monitoring/sections/page/{pageId}/suggestions/accept [post]
+func (m *Module) handleAcceptSectionSuggestions(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	sectionRepo := persistence.NewMonitoredSectionPostgresRepository(m.db, tenant)
+	configRepo := persistence.NewMonitoringConfigPostgresRepository(m.db, tenant)
+	suggestionRepo := persistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := managesections.NewManageSectionsHandler(sectionRepo, configRepo, suggestionRepo)
+	handler.HandleAcceptSuggestionsHTTP(w, r)
+}
+
+// handleGetCredential returns the credential a page is monitored with, without secret values
+// @Summary Get Page Credential
+// @Description Get the authentication settings of a page. Secret values are never returned.
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Success 200 {object} managecredentials.CredentialResponse
+// @Router /monitoring/credentials/page/{pageId} [get]
+func (m *Module) handleGetCredential(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewPageCredentialPostgresRepository(m.db, tenant)
+	handler := managecredentials.NewManageCredentialsHandler(repo, m.vault, tenant)
+	handler.HandleGetHTTP(w, r)
+}
+
+// handleSaveCredential stores the credential a page is monitored with
+// @Summary Save Page Credential
+// @Description Set custom headers, cookies, basic auth or a login script for a page. Secrets are encrypted at rest.
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pageId path string true "Page ID"
+// @Param request body managecredentials.SaveCredentialRequest true "Save Credential Request"
+// @Success 200 {object} managecredentials.CredentialResponse
+// @Router /monitoring/credentials/page/{pageId} [put]
+func (m *Module) handleSaveCredential(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewPageCredentialPostgresRepository(m.db, tenant)
+	handler := managecredentials.NewManageCredentialsHandler(repo, m.vault, tenant)
+	handler.HandleSaveHTTP(w, r)
+}
+
+// handleDeleteCredential removes a page's credential
+// @Summary Delete Page Credential
+// @Description Remove the authentication settings of a page
+// @Tags monitoring
+// @Security BearerAuth
+// @Param pageId path string true "Page ID"
+// @Success 204
+// @Router /monitoring/credentials/page/{pageId} [delete]
+func (m *Module) handleDeleteCredential(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewPageCredentialPostgresRepository(m.db, tenant)
+	handler := managecredentials.NewManageCredentialsHandler(repo, m.vault, tenant)
+	handler.HandleDeleteHTTP(w, r)
+}
+
+// handleListProxies lists the tenant's outbound proxies with their health
+// @Summary List Proxies
+// @Description List the outbound proxies checks can be routed through, with health and quarantine state. Passwords are never returned.
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} manageproxies.ListProxiesResponse
+// @Router /monitoring/proxies [get]
+func (m *Module) handleListProxies(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewProxyPostgresRepository(m.db, tenant)
+	handler := manageproxies.NewManageProxiesHandler(repo, m.vault, tenant)
+	handler.HandleListHTTP(w, r)
+}
+
+// handleCreateProxy registers an outbound proxy
+// @Summary Create Proxy
+// @Description Register an HTTP, HTTPS or SOCKS5 proxy with optional credentials, region and pool labels. Passwords are encrypted at rest.
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body manageproxies.SaveProxyRequest true "Save Proxy Request"
+// @Success 201 {object} manageproxies.ProxyResponse
+// @Router /monitoring/proxies [post]
+func (m *Module) handleCreateProxy(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewProxyPostgresRepository(m.db, tenant)
+	handler := manageproxies.NewManageProxiesHandler(repo, m.vault, tenant)
+	handler.HandleCreateHTTP(w, r)
+}
+
+// handleUpdateProxy replaces an outbound proxy's settings
+// @Summary Update Proxy
+// @Description Replace a proxy's settings. Saving a proxy lifts its quarantine.
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Proxy ID"
+// @Param request body manageproxies.SaveProxyRequest true "Save Proxy Request"
+// @Success 200 {object} manageproxies.ProxyResponse
+// @Router /monitoring/proxies/{id} [put]
+func (m *Module) handleUpdateProxy(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewProxyPostgresRepository(m.db, tenant)
+	handler := manageproxies.NewManageProxiesHandler(repo, m.vault, tenant)
+	handler.HandleUpdateHTTP(w, r)
+}
+
+// handleDeleteProxy removes an outbound proxy
+// @Summary Delete Proxy
+// @Description Remove a proxy from the registry
+// @Tags monitoring
+// @Security BearerAuth
+// @Param id path string true "Proxy ID"
+// @Success 204
+// @Router /monitoring/proxies/{id} [delete]
+func (m *Module) handleDeleteProxy(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewProxyPostgresRepository(m.db, tenant)
+	handler := manageproxies.NewManageProxiesHandler(repo, m.vault, tenant)
+	handler.HandleDeleteHTTP(w, r)
+}
+
+// handleGetWorkspaceProxyRoute returns a workspace's default proxy route
+// @Summary Get Workspace Proxy Route
+// @Description Get the proxy region and pool used by pages of a workspace that do not set their own
+// @Tags monitoring
+// @Security BearerAuth
+// @Produce json
+// @Param workspaceId path string true "Workspace ID"
+// @Success 200 {object} manageproxies.WorkspaceProxyRouteDTO
+// @Router /monitoring/proxies/workspace/{workspaceId} [get]
+func (m *Module) handleGetWorkspaceProxyRoute(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewProxyPostgresRepository(m.db, tenant)
+	handler := manageproxies.NewManageProxiesHandler(repo, m.vault, tenant)
+	handler.HandleGetWorkspaceRouteHTTP(w, r)
+}
+
+// handleSetWorkspaceProxyRoute sets a workspace's default proxy route
+// @Summary Set Workspace Proxy Route
+// @Description Set the proxy region and pool used by pages of a workspace that do not set their own. Empty values clear the route.
+// @Tags monitoring
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param workspaceId path string true "Workspace ID"
+// @Param request body manageproxies.WorkspaceProxyRouteDTO true "Workspace Proxy Route"
+// @Success 200 {object} manageproxies.WorkspaceProxyRouteDTO
+// @Router /monitoring/proxies/workspace/{workspaceId} [put]
+func (m *Module) handleSetWorkspaceProxyRoute(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil {
+		http.Error(w, "Database not initialized", http.StatusInternalServerError)
+		return
+	}
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewProxyPostgresRepository(m.db, tenant)
+	handler := manageproxies.NewManageProxiesHandler(repo, m.vault, tenant)
+	handler.HandleSetWorkspaceRouteHTTP(w, r)
+}
+
 // handleCheckSSE streams check-updated events to the client using SSE.
 // The client connects with /checks/page/{pageId}/stream and receives events
 // whenever a check for that page completes (success or error).
@@ -707,6 +1527,12 @@ func (m *Module) handleCheckSSE(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if m.signer != nil {
+		if member, err := m.signer.CanAccessPage(r.Context(), uuid.MustParse(pageIDStr)); err != nil || !member {
+			http.Error(w, listchecks.ErrForbidden.Error(), http.StatusForbidden)
+			return
+		}
+	}
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -736,7 +1562,7 @@ func (m *Module) handleCheckSSE(w http.ResponseWriter, r *http.Request) {
 			if !ok {
 				return
 			}
-			fmt.Fprintf(w, "event: check:updated\ndata: %s\n\n", payload)
+			fmt.Fprintf(w, "event: check:updated\ndata: %s\n\n", m.signCheckPayload(r.Context(), payload))
 			if err := rc.Flush(); err != nil {
 				return
 			}
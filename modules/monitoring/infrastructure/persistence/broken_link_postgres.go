@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+type BrokenLinkPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewBrokenLinkPostgresRepository(db *sql.DB, tenant string) *BrokenLinkPostgresRepository {
+	return &BrokenLinkPostgresRepository{db: db, tenant: tenant}
+}
+
+func (r *BrokenLinkPostgresRepository) ListOpenByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.BrokenLink, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	q := `SELECT id, page_id, url, COALESCE(anchor_text, ''), COALESCE(location, ''), COALESCE(status_code, 0), COALESCE(error_message, ''),
+	             first_check_id, last_check_id, first_detected_at, last_detected_at, resolved_at
+	      FROM broken_links WHERE page_id = $1 AND resolved_at IS NULL ORDER BY first_detected_at ASC`
+	rows, err := r.db.QueryContext(ctx, q, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*entities.BrokenLink
+	for rows.Next() {
+		var l entities.BrokenLink
+		if err := rows.Scan(
+			&l.ID, &l.PageID, &l.URL, &l.AnchorText, &l.Location, &l.StatusCode, &l.ErrorMessage,
+			&l.FirstCheckID, &l.LastCheckID, &l.FirstDetectedAt, &l.LastDetectedAt, &l.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		links = append(links, &l)
+	}
+	return links, rows.Err()
+}
+
+func (r *BrokenLinkPostgresRepository) Create(ctx context.Context, link *entities.BrokenLink) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `INSERT INTO broken_links (id, page_id, url, anchor_text, location, status_code, error_message, first_check_id, last_check_id, first_detected_at, last_detected_at)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := r.db.ExecContext(ctx, q,
+		link.ID, link.PageID, link.URL, link.AnchorText, link.Location, link.StatusCode, link.ErrorMessage,
+		link.FirstCheckID, link.LastCheckID, link.FirstDetectedAt, link.LastDetectedAt,
+	)
+	return err
+}
+
+func (r *BrokenLinkPostgresRepository) MarkSeen(ctx context.Context, id, checkID uuid.UUID, statusCode int, errMsg string) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `UPDATE broken_links SET last_check_id = $1, status_code = $2, error_message = $3, last_detected_at = NOW() WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, q, checkID, statusCode, errMsg, id)
+	return err
+}
+
+func (r *BrokenLinkPostgresRepository) Resolve(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	q := `UPDATE broken_links SET resolved_at = NOW() WHERE resolved_at IS NULL AND id IN (` + strings.Join(placeholders, ", ") + `)`
+	_, err := r.db.ExecContext(ctx, q, args...)
+	return err
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
 	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
 )
 
-const checkSelectColumns = `id, page_id, section_id, parent_check_id, status, COALESCE(screenshot_url, ''), COALESCE(html_snapshot_url, ''), COALESCE(content_hash, ''), COALESCE(change_detected, false), COALESCE(change_type, ''), COALESCE(error_message, ''), COALESCE(duration_ms, 0), COALESCE(screenshot_hash, ''), COALESCE(vision_change_summary, ''), checked_at`
+const checkSelectColumns = `id, page_id, section_id, parent_check_id, status, COALESCE(screenshot_url, ''), COALESCE(html_snapshot_url, ''), COALESCE(document_url, ''), COALESCE(thumbnail_url, ''), COALESCE(preview_url, ''), COALESCE(archive_url, ''), COALESCE(content_hash, ''), COALESCE(change_detected, false), COALESCE(change_type, ''), COALESCE(error_message, ''), COALESCE(duration_ms, 0), COALESCE(screenshot_hash, ''), COALESCE(vision_change_summary, ''), COALESCE(profile_name, ''), proxy_id, COALESCE(fetch_engine, ''), selector_fallback, recurrence, flapping, pinned, purged_at, checked_at`
 
 func scanCheck(row interface{ Scan(...interface{}) error }, check *entities.Check) error {
 	return row.Scan(
@@ -21,6 +22,10 @@ func scanCheck(row interface{ Scan(...interface{}) error }, check *entities.Chec
 		&check.Status,
 		&check.ScreenshotURL,
 		&check.HTMLSnapshotURL,
+		&check.DocumentURL,
+		&check.ThumbnailURL,
+		&check.PreviewURL,
+		&check.ArchiveURL,
 		&check.ContentHash,
 		&check.ChangeDetected,
 		&check.ChangeType,
@@ -28,6 +33,14 @@ func scanCheck(row interface{ Scan(...interface{}) error }, check *entities.Chec
 		&check.DurationMs,
 		&check.ScreenshotHash,
 		&check.VisionChangeSummary,
+		&check.ProfileName,
+		&check.ProxyID,
+		&check.FetchEngine,
+		&check.SelectorFallback,
+		&check.Recurrence,
+		&check.Flapping,
+		&check.Pinned,
+		&check.PurgedAt,
 		&check.CheckedAt,
 	)
 }
@@ -52,8 +65,8 @@ func (r *CheckPostgresRepository) Create(ctx context.Context, check *entities.Ch
 		return err
 	}
 
-	q := `INSERT INTO checks (id, page_id, section_id, parent_check_id, status, screenshot_url, html_snapshot_url, content_hash, change_detected, change_type, error_message, duration_ms, screenshot_hash, vision_change_summary, checked_at)
-	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+	q := `INSERT INTO checks (id, page_id, section_id, parent_check_id, status, screenshot_url, html_snapshot_url, document_url, content_hash, change_detected, change_type, error_message, duration_ms, screenshot_hash, vision_change_summary, profile_name, proxy_id, fetch_engine, selector_fallback, recurrence, flapping, checked_at, thumbnail_url, preview_url, archive_url)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''), $17, NULLIF($18, ''), $19, $20, $21, $22, NULLIF($23, ''), NULLIF($24, ''), NULLIF($25, ''))`
 
 	_, err := r.db.ExecContext(ctx, q,
 		check.ID,
@@ -63,6 +76,7 @@ func (r *CheckPostgresRepository) Create(ctx context.Context, check *entities.Ch
 		check.Status,
 		check.ScreenshotURL,
 		check.HTMLSnapshotURL,
+		check.DocumentURL,
 		check.ContentHash,
 		check.ChangeDetected,
 		check.ChangeType,
@@ -70,7 +84,16 @@ func (r *CheckPostgresRepository) Create(ctx context.Context, check *entities.Ch
 		check.DurationMs,
 		check.ScreenshotHash,
 		check.VisionChangeSummary,
+		check.ProfileName,
+		check.ProxyID,
+		check.FetchEngine,
+		check.SelectorFallback,
+		check.Recurrence,
+		check.Flapping,
 		check.CheckedAt,
+		check.ThumbnailURL,
+		check.PreviewURL,
+		check.ArchiveURL,
 	)
 	return err
 }
@@ -94,31 +117,81 @@ func (r *CheckPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*e
 	return &check, nil
 }
 
+// Claim moves a pending check to running for the worker about to execute it.
+// It returns false when the check already left pending: another worker
+// claimed a duplicate dispatch of it, or the reaper failed it.
+func (r *CheckPostgresRepository) Claim(ctx context.Context, id uuid.UUID) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return false, err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE checks SET status = 'running', heartbeat_at = NOW()
+		WHERE id = $1 AND status = 'pending'`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Heartbeat records that a worker is still running a check.
+func (r *CheckPostgresRepository) Heartbeat(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE checks SET heartbeat_at = NOW() WHERE id = $1 AND status = 'running'`, id)
+	return err
+}
+
 // Update updates an existing check
 func (r *CheckPostgresRepository) Update(ctx context.Context, check *entities.Check) error {
+	_, err := r.update(ctx, check, "")
+	return err
+}
+
+// Complete writes the outcome of a check that is still pending or running.
+// It returns false, writing nothing, when the check was already resolved,
+// e.g. failed by the reaper while its worker was still running it.
+func (r *CheckPostgresRepository) Complete(ctx context.Context, check *entities.Check) (bool, error) {
+	n, err := r.update(ctx, check, ` AND status IN ('pending', 'running')`)
+	return n > 0, err
+}
+
+// update writes check, restricted by the extra WHERE condition, and returns
+// the number of rows written.
+func (r *CheckPostgresRepository) update(ctx context.Context, check *entities.Check, condition string) (int64, error) {
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
-		return err
+		return 0, err
 	}
 
 	q := `UPDATE checks SET
 		status = $1,
 		screenshot_url = $2,
 		html_snapshot_url = $3,
-		content_hash = $4,
-		change_detected = $5,
-		change_type = $6,
-		error_message = $7,
-		duration_ms = $8,
-		screenshot_hash = $9,
-		vision_change_summary = $10,
-		section_id = $11,
-		parent_check_id = $12
-		WHERE id = $13`
-
-	_, err := r.db.ExecContext(ctx, q,
+		document_url = $4,
+		content_hash = $5,
+		change_detected = $6,
+		change_type = $7,
+		error_message = $8,
+		duration_ms = $9,
+		screenshot_hash = $10,
+		vision_change_summary = $11,
+		section_id = $12,
+		parent_check_id = $13,
+		proxy_id = $14,
+		fetch_engine = NULLIF($15, ''),
+		selector_fallback = $16,
+		recurrence = $17,
+		flapping = $18,
+		thumbnail_url = NULLIF($19, ''),
+		preview_url = NULLIF($20, ''),
+		archive_url = NULLIF($21, '')
+		WHERE id = $22` + condition
+
+	res, err := r.db.ExecContext(ctx, q,
 		check.Status,
 		check.ScreenshotURL,
 		check.HTMLSnapshotURL,
+		check.DocumentURL,
 		check.ContentHash,
 		check.ChangeDetected,
 		check.ChangeType,
@@ -128,18 +201,29 @@ func (r *CheckPostgresRepository) Update(ctx context.Context, check *entities.Ch
 		check.VisionChangeSummary,
 		check.SectionID,
 		check.ParentCheckID,
+		check.ProxyID,
+		check.FetchEngine,
+		check.SelectorFallback,
+		check.Recurrence,
+		check.Flapping,
+		check.ThumbnailURL,
+		check.PreviewURL,
+		check.ArchiveURL,
 		check.ID,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
-// ListByPage retrieves parent checks for a page (excludes section-level checks).
+// ListByPage retrieves parent checks for a page (excludes section and profile checks).
 func (r *CheckPostgresRepository) ListByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.Check, error) {
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return nil, err
 	}
 
-	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL ORDER BY checked_at DESC`
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND profile_name IS NULL ORDER BY checked_at DESC`
 
 	rows, err := r.db.QueryContext(ctx, q, pageID)
 	if err != nil {
@@ -167,7 +251,7 @@ func (r *CheckPostgresRepository) GetPreviousSuccessfulByPage(ctx context.Contex
 	}
 
 	var check entities.Check
-	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND id != $2 AND status = 'success' AND section_id IS NULL ORDER BY checked_at DESC LIMIT 1`
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND id != $2 AND status = 'success' AND section_id IS NULL AND profile_name IS NULL ORDER BY checked_at DESC LIMIT 1`
 
 	if err := scanCheck(r.db.QueryRowContext(ctx, q, pageID, excludeCheckID), &check); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -191,7 +275,7 @@ func (r *CheckPostgresRepository) ListByPageAndSection(ctx context.Context, page
 	var err error
 
 	if sectionID == nil {
-		q = `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL ORDER BY checked_at DESC`
+		q = `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND profile_name IS NULL ORDER BY checked_at DESC`
 		rows, err = r.db.QueryContext(ctx, q, pageID)
 	} else {
 		q = `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id = $2 ORDER BY checked_at DESC`
@@ -225,7 +309,7 @@ func (r *CheckPostgresRepository) GetPreviousSuccessfulBySection(ctx context.Con
 	var row *sql.Row
 
 	if sectionID == nil {
-		q = `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND id != $2 AND status = 'success' ORDER BY checked_at DESC LIMIT 1`
+		q = `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND profile_name IS NULL AND id != $2 AND status = 'success' ORDER BY checked_at DESC LIMIT 1`
 		row = r.db.QueryRowContext(ctx, q, pageID, excludeCheckID)
 	} else {
 		q = `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id = $2 AND id != $3 AND status = 'success' ORDER BY checked_at DESC LIMIT 1`
@@ -241,14 +325,14 @@ func (r *CheckPostgresRepository) GetPreviousSuccessfulBySection(ctx context.Con
 	return &check, nil
 }
 
-// GetLatestByPage retrieves the latest parent check for a page (excludes section-level checks).
+// GetLatestByPage retrieves the latest parent check for a page (excludes section and profile checks).
 func (r *CheckPostgresRepository) GetLatestByPage(ctx context.Context, pageID uuid.UUID) (*entities.Check, error) {
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return nil, err
 	}
 
 	var check entities.Check
-	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL ORDER BY checked_at DESC LIMIT 1`
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND profile_name IS NULL ORDER BY checked_at DESC LIMIT 1`
 
 	if err := scanCheck(r.db.QueryRowContext(ctx, q, pageID), &check); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -260,7 +344,7 @@ func (r *CheckPostgresRepository) GetLatestByPage(ctx context.Context, pageID uu
 	return &check, nil
 }
 
-// ListByParentCheckID retrieves all section checks that belong to a parent check.
+// ListByParentCheckID retrieves all section and profile checks that belong to a parent check.
 func (r *CheckPostgresRepository) ListByParentCheckID(ctx context.Context, parentCheckID uuid.UUID) ([]*entities.Check, error) {
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return nil, err
@@ -309,3 +393,139 @@ func (r *CheckPostgresRepository) ListSectionChecksByPage(ctx context.Context, p
 	}
 	return checks, nil
 }
+
+// GetPreviousSuccessfulByProfile retrieves the most recent successful check
+// captured with the given profile, excluding the given check ID.
+func (r *CheckPostgresRepository) GetPreviousSuccessfulByProfile(ctx context.Context, pageID uuid.UUID, profileName string, excludeCheckID uuid.UUID) (*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var check entities.Check
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND profile_name = $2 AND id != $3 AND status = 'success' ORDER BY checked_at DESC LIMIT 1`
+
+	if err := scanCheck(r.db.QueryRowContext(ctx, q, pageID, profileName, excludeCheckID), &check); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &check, nil
+}
+
+// ListProfileChecksByPage returns all profile checks for a page (profile_name IS NOT NULL).
+func (r *CheckPostgresRepository) ListProfileChecksByPage(ctx context.Context, pageID uuid.UUID) ([]*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND profile_name IS NOT NULL ORDER BY checked_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, q, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []*entities.Check
+	for rows.Next() {
+		var check entities.Check
+		if err := scanCheck(rows, &check); err != nil {
+			return nil, err
+		}
+		checks = append(checks, &check)
+	}
+	return checks, nil
+}
+
+// GetLastSelectorMatch retrieves the most recent successful parent check that
+// was captured with the page's element selector matching.
+func (r *CheckPostgresRepository) GetLastSelectorMatch(ctx context.Context, pageID uuid.UUID) (*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var check entities.Check
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND profile_name IS NULL AND status = 'success' AND NOT selector_fallback ORDER BY checked_at DESC LIMIT 1`
+
+	if err := scanCheck(r.db.QueryRowContext(ctx, q, pageID), &check); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &check, nil
+}
+
+// GetPreviousSuccessfulBefore retrieves the successful check that preceded the
+// given one in the same scope: the full page, one section or one profile.
+func (r *CheckPostgresRepository) GetPreviousSuccessfulBefore(ctx context.Context, check *entities.Check) (*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var scope string
+	args := []interface{}{check.PageID, check.ID, check.CheckedAt}
+	switch {
+	case check.SectionID != nil:
+		scope = `section_id = $4`
+		args = append(args, *check.SectionID)
+	case check.ProfileName != "":
+		scope = `profile_name = $4`
+		args = append(args, check.ProfileName)
+	default:
+		scope = `section_id IS NULL AND profile_name IS NULL`
+	}
+
+	var prev entities.Check
+	q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND id != $2 AND checked_at < $3 AND status = 'success' AND ` + scope + ` ORDER BY checked_at DESC LIMIT 1`
+	if err := scanCheck(r.db.QueryRowContext(ctx, q, args...), &prev); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &prev, nil
+}
+
+// ListSuccessfulInRange returns up to limit successful checks of the full page, or of one
+// section when sectionID is set, checked within [from, to], oldest first.
+func (r *CheckPostgresRepository) ListSuccessfulInRange(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, from, to time.Time, limit int) ([]*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	var err error
+	if sectionID == nil {
+		q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id IS NULL AND profile_name IS NULL AND status = 'success' AND checked_at BETWEEN $2 AND $3 ORDER BY checked_at ASC LIMIT $4`
+		rows, err = r.db.QueryContext(ctx, q, pageID, from, to, limit)
+	} else {
+		q := `SELECT ` + checkSelectColumns + ` FROM checks WHERE page_id = $1 AND section_id = $2 AND status = 'success' AND checked_at BETWEEN $3 AND $4 ORDER BY checked_at ASC LIMIT $5`
+		rows, err = r.db.QueryContext(ctx, q, pageID, *sectionID, from, to, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []*entities.Check
+	for rows.Next() {
+		var c entities.Check
+		if err := scanCheck(rows, &c); err != nil {
+			return nil, err
+		}
+		checks = append(checks, &c)
+	}
+	return checks, rows.Err()
+}
+
+// SetPinned pins or unpins a check as a baseline kept by retention purges.
+func (r *CheckPostgresRepository) SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE checks SET pinned = $1 WHERE id = $2`, pinned, id)
+	return err
+}
@@ -0,0 +1,137 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+// openCheckTestSchema creates a throwaway tenant schema with the checks
+// columns the worker and the reaper write.
+func openCheckTestSchema(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	db, schema := openTestSchema(t)
+	_, err := db.Exec(`CREATE TABLE checks (
+		id UUID PRIMARY KEY,
+		page_id UUID NOT NULL,
+		section_id UUID,
+		parent_check_id UUID,
+		proxy_id UUID,
+		status VARCHAR(50) NOT NULL,
+		screenshot_url TEXT,
+		html_snapshot_url TEXT,
+		document_url TEXT,
+		thumbnail_url TEXT,
+		preview_url TEXT,
+		archive_url TEXT,
+		content_hash VARCHAR(64),
+		change_detected BOOLEAN DEFAULT FALSE,
+		change_type VARCHAR(50),
+		error_message TEXT,
+		duration_ms INTEGER,
+		screenshot_hash VARCHAR(64),
+		vision_change_summary TEXT,
+		fetch_engine VARCHAR(20),
+		selector_fallback BOOLEAN NOT NULL DEFAULT FALSE,
+		recurrence BOOLEAN NOT NULL DEFAULT FALSE,
+		flapping BOOLEAN NOT NULL DEFAULT FALSE,
+		heartbeat_at TIMESTAMP,
+		dispatch_attempts INT NOT NULL DEFAULT 1,
+		checked_at TIMESTAMP NOT NULL DEFAULT NOW()
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, schema
+}
+
+func insertPendingCheck(t *testing.T, db *sql.DB) *entities.Check {
+	t.Helper()
+	check := entities.NewCheck(uuid.New(), "pending", false)
+	if _, err := db.Exec(`INSERT INTO checks (id, page_id, status) VALUES ($1, $2, $3)`, check.ID, check.PageID, check.Status); err != nil {
+		t.Fatal(err)
+	}
+	return check
+}
+
+func checkState(t *testing.T, db *sql.DB, id uuid.UUID) (status, errMsg string) {
+	t.Helper()
+	var msg sql.NullString
+	if err := db.QueryRow(`SELECT status, error_message FROM checks WHERE id = $1`, id).Scan(&status, &msg); err != nil {
+		t.Fatal(err)
+	}
+	return status, msg.String
+}
+
+func TestCheckRepository_ClaimOnce(t *testing.T) {
+	db, schema := openCheckTestSchema(t)
+	checks := NewCheckPostgresRepository(db, schema)
+	reaper := NewReaperPostgresRepository(db, schema)
+	ctx := context.Background()
+	check := insertPendingCheck(t, db)
+
+	if claimed, err := checks.Claim(ctx, check.ID); err != nil || !claimed {
+		t.Fatalf("first Claim = %v, %v; want the check claimed", claimed, err)
+	}
+	// A duplicate dispatch of the same check finds it already running.
+	if claimed, err := checks.Claim(ctx, check.ID); err != nil || claimed {
+		t.Fatalf("second Claim = %v, %v; want it refused", claimed, err)
+	}
+	if ok, err := reaper.Redispatch(ctx, check.ID, 1); err != nil || ok {
+		t.Errorf("Redispatch = %v, %v; want a running check left alone", ok, err)
+	}
+	if status, _ := checkState(t, db, check.ID); status != "running" {
+		t.Errorf("status = %q, want running", status)
+	}
+}
+
+func TestCheckRepository_CompleteAfterReaperFail(t *testing.T) {
+	db, schema := openCheckTestSchema(t)
+	checks := NewCheckPostgresRepository(db, schema)
+	reaper := NewReaperPostgresRepository(db, schema)
+	ctx := context.Background()
+	check := insertPendingCheck(t, db)
+
+	if claimed, err := checks.Claim(ctx, check.ID); err != nil || !claimed {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+	// The reaper fails the check past its run deadline while the worker is
+	// still running it.
+	if failed, err := reaper.Fail(ctx, check.ID, "check did not finish"); err != nil || !failed {
+		t.Fatalf("Fail = %v, %v; want the running check failed", failed, err)
+	}
+
+	check.Status = "success"
+	check.ScreenshotURL = "page/a.png"
+	if completed, err := checks.Complete(ctx, check); err != nil || completed {
+		t.Fatalf("Complete = %v, %v; want the worker's result refused", completed, err)
+	}
+	if status, msg := checkState(t, db, check.ID); status != "error" || msg != "check did not finish" {
+		t.Errorf("check = %q, %q; want the reaper's outcome kept", status, msg)
+	}
+}
+
+func TestCheckRepository_CompleteRunningCheck(t *testing.T) {
+	db, schema := openCheckTestSchema(t)
+	checks := NewCheckPostgresRepository(db, schema)
+	reaper := NewReaperPostgresRepository(db, schema)
+	ctx := context.Background()
+	check := insertPendingCheck(t, db)
+
+	if claimed, err := checks.Claim(ctx, check.ID); err != nil || !claimed {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+	check.Status = "success"
+	if completed, err := checks.Complete(ctx, check); err != nil || !completed {
+		t.Fatalf("Complete = %v, %v; want the result written", completed, err)
+	}
+	if failed, err := reaper.Fail(ctx, check.ID, "late"); err != nil || failed {
+		t.Errorf("Fail = %v, %v; want a finished check left alone", failed, err)
+	}
+	if status, _ := checkState(t, db, check.ID); status != "success" {
+		t.Errorf("status = %q, want success", status)
+	}
+}
@@ -0,0 +1,74 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+type ContentStatePostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewContentStatePostgresRepository(db *sql.DB, tenant string) *ContentStatePostgresRepository {
+	return &ContentStatePostgresRepository{db: db, tenant: tenant}
+}
+
+// ListRecent returns the latest states for a page, or one of its sections
+// when sectionID is set, newest first.
+func (r *ContentStatePostgresRepository) ListRecent(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, limit int) (entities.ContentHistory, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	var err error
+	if sectionID == nil {
+		q := `SELECT state_hash FROM content_states WHERE page_id = $1 AND section_id IS NULL ORDER BY recorded_at DESC LIMIT $2`
+		rows, err = r.db.QueryContext(ctx, q, pageID, limit)
+	} else {
+		q := `SELECT state_hash FROM content_states WHERE page_id = $1 AND section_id = $2 ORDER BY recorded_at DESC LIMIT $3`
+		rows, err = r.db.QueryContext(ctx, q, pageID, *sectionID, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history entities.ContentHistory
+	for rows.Next() {
+		var state string
+		if err := rows.Scan(&state); err != nil {
+			return nil, err
+		}
+		history = append(history, state)
+	}
+	return history, rows.Err()
+}
+
+// Record appends a check's state and trims the history to ContentHistorySize.
+func (r *ContentStatePostgresRepository) Record(ctx context.Context, pageID uuid.UUID, sectionID *uuid.UUID, checkID uuid.UUID, state string) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	// The trim runs on the snapshot before the insert, so it keeps one fewer
+	// of the existing states to leave room for the new one.
+	q := `WITH inserted AS (
+	          INSERT INTO content_states (id, page_id, section_id, check_id, state_hash, recorded_at)
+	          VALUES ($1, $2, $3, $4, $5, NOW())
+	      )
+	      DELETE FROM content_states
+	      WHERE page_id = $2 AND section_id IS NOT DISTINCT FROM $3
+	        AND id NOT IN (
+	          SELECT id FROM content_states
+	          WHERE page_id = $2 AND section_id IS NOT DISTINCT FROM $3
+	          ORDER BY recorded_at DESC LIMIT $6
+	        )`
+	_, err := r.db.ExecContext(ctx, q, uuid.New(), pageID, sectionID, checkID, state, entities.ContentHistorySize-1)
+	return err
+}
@@ -29,12 +29,32 @@ func marshalSectionRect(r *entities.SectionRect) []byte {
 	return b
 }
 
+func marshalFingerprint(f *entities.ElementFingerprint) []byte {
+	if f == nil {
+		return nil
+	}
+	b, _ := json.Marshal(f)
+	return b
+}
+
+func marshalSelectorProposal(p *entities.SelectorProposal) []byte {
+	if p == nil {
+		return nil
+	}
+	b, _ := json.Marshal(p)
+	return b
+}
+
 func scanSection(row interface{ Scan(...interface{}) error }, s *entities.MonitoredSection) error {
 	var offsetsRaw []byte
 	var rectRaw []byte
+	var fingerprintRaw []byte
+	var proposalRaw []byte
 	err := row.Scan(
 		&s.ID, &s.PageID, &s.Name, &s.CSSSelector, &s.XPathSelector,
-		&offsetsRaw, &rectRaw, &s.ViewportWidth, &s.SortOrder, &s.CreatedAt, &s.UpdatedAt,
+		&offsetsRaw, &rectRaw, &s.ViewportWidth, &s.SortOrder,
+		&s.SelectorHealth.MissCount, &s.SelectorHealth.BrokenAt,
+		&fingerprintRaw, &proposalRaw, &s.CreatedAt, &s.UpdatedAt,
 	)
 	if err != nil {
 		return err
@@ -51,6 +71,18 @@ func scanSection(row interface{ Scan(...interface{}) error }, s *entities.Monito
 			s.Rect = &rect
 		}
 	}
+	if len(fingerprintRaw) > 0 {
+		var fingerprint entities.ElementFingerprint
+		if json.Unmarshal(fingerprintRaw, &fingerprint) == nil {
+			s.Fingerprint = &fingerprint
+		}
+	}
+	if len(proposalRaw) > 0 {
+		var proposal entities.SelectorProposal
+		if json.Unmarshal(proposalRaw, &proposal) == nil {
+			s.SelectorProposal = &proposal
+		}
+	}
 	return nil
 }
 
@@ -60,11 +92,12 @@ func (r *MonitoredSectionPostgresRepository) Create(ctx context.Context, section
 	}
 	offsetsJSON := marshalSelectorOffsets(section.SelectorOffsets)
 	rectJSON := marshalSectionRect(section.Rect)
-	q := `INSERT INTO monitored_sections (id, page_id, name, css_selector, xpath_selector, selector_offsets, rect, viewport_width, sort_order, created_at, updated_at)
-	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	q := `INSERT INTO monitored_sections (id, page_id, name, css_selector, xpath_selector, selector_offsets, rect, viewport_width, sort_order, fingerprint, created_at, updated_at)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 	_, err := r.db.ExecContext(ctx, q,
 		section.ID, section.PageID, section.Name, section.CSSSelector, section.XPathSelector,
-		string(offsetsJSON), rectJSON, section.ViewportWidth, section.SortOrder, section.CreatedAt, section.UpdatedAt,
+		string(offsetsJSON), rectJSON, section.ViewportWidth, section.SortOrder, marshalFingerprint(section.Fingerprint),
+		section.CreatedAt, section.UpdatedAt,
 	)
 	return err
 }
@@ -77,7 +110,7 @@ func (r *MonitoredSectionPostgresRepository) GetByID(ctx context.Context, id uui
 	q := `SELECT id, page_id, name, css_selector, xpath_selector,
 	             COALESCE(selector_offsets, '{"top":0,"right":0,"bottom":0,"left":0}')::text,
 	             rect, COALESCE(viewport_width, 0),
-	             sort_order, created_at, updated_at
+	             sort_order, selector_miss_count, selector_broken_at, fingerprint, selector_proposal, created_at, updated_at
 	      FROM monitored_sections WHERE id = $1`
 	if err := scanSection(r.db.QueryRowContext(ctx, q, id), &s); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -95,7 +128,7 @@ func (r *MonitoredSectionPostgresRepository) ListByPageID(ctx context.Context, p
 	q := `SELECT id, page_id, name, css_selector, xpath_selector,
 	             COALESCE(selector_offsets, '{"top":0,"right":0,"bottom":0,"left":0}')::text,
 	             rect, COALESCE(viewport_width, 0),
-	             sort_order, created_at, updated_at
+	             sort_order, selector_miss_count, selector_broken_at, fingerprint, selector_proposal, created_at, updated_at
 	      FROM monitored_sections WHERE page_id = $1 ORDER BY sort_order ASC, created_at ASC`
 	rows, err := r.db.QueryContext(ctx, q, pageID)
 	if err != nil {
@@ -120,17 +153,43 @@ func (r *MonitoredSectionPostgresRepository) Update(ctx context.Context, section
 	section.UpdatedAt = time.Now()
 	offsetsJSON := marshalSelectorOffsets(section.SelectorOffsets)
 	rectJSON := marshalSectionRect(section.Rect)
+	// A changed selector starts with a clean health record.
 	q := `UPDATE monitored_sections
-	      SET name = $1, css_selector = $2, xpath_selector = $3, selector_offsets = $4,
-	          rect = $5, viewport_width = $6, sort_order = $7, updated_at = $8
-	      WHERE id = $9`
+	      SET selector_miss_count = CASE WHEN css_selector = $2 AND COALESCE(xpath_selector, '') = $3 THEN selector_miss_count ELSE 0 END,
+	          selector_broken_at = CASE WHEN css_selector = $2 AND COALESCE(xpath_selector, '') = $3 THEN selector_broken_at END,
+	          name = $1, css_selector = $2, xpath_selector = $3, selector_offsets = $4,
+	          rect = $5, viewport_width = $6, sort_order = $7, fingerprint = $8,
+	          selector_proposal = $9, updated_at = $10
+	      WHERE id = $11`
 	_, err := r.db.ExecContext(ctx, q,
 		section.Name, section.CSSSelector, section.XPathSelector, string(offsetsJSON),
-		rectJSON, section.ViewportWidth, section.SortOrder, section.UpdatedAt, section.ID,
+		rectJSON, section.ViewportWidth, section.SortOrder, marshalFingerprint(section.Fingerprint),
+		marshalSelectorProposal(section.SelectorProposal), section.UpdatedAt, section.ID,
 	)
 	return err
 }
 
+// UpdateSelectorHealth records the section selector's miss streak.
+func (r *MonitoredSectionPostgresRepository) UpdateSelectorHealth(ctx context.Context, id uuid.UUID, health entities.SelectorHealth) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `UPDATE monitored_sections SET selector_miss_count = $1, selector_broken_at = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, q, health.MissCount, health.BrokenAt, id)
+	return err
+}
+
+// UpdateSelectorProposal stores, or clears when nil, the replacement selector
+// proposed for a section.
+func (r *MonitoredSectionPostgresRepository) UpdateSelectorProposal(ctx context.Context, id uuid.UUID, proposal *entities.SelectorProposal) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `UPDATE monitored_sections SET selector_proposal = $1 WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, q, marshalSelectorProposal(proposal), id)
+	return err
+}
+
 func (r *MonitoredSectionPostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return err
@@ -157,14 +216,15 @@ func (r *MonitoredSectionPostgresRepository) ReplaceAll(ctx context.Context, pag
 	}
 
 	// Insert new sections
-	q := `INSERT INTO monitored_sections (id, page_id, name, css_selector, xpath_selector, selector_offsets, rect, viewport_width, sort_order, created_at, updated_at)
-	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	q := `INSERT INTO monitored_sections (id, page_id, name, css_selector, xpath_selector, selector_offsets, rect, viewport_width, sort_order, fingerprint, created_at, updated_at)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 	for _, s := range sections {
 		offsetsJSON := marshalSelectorOffsets(s.SelectorOffsets)
 		rectJSON := marshalSectionRect(s.Rect)
 		if _, err := tx.ExecContext(ctx, q,
 			s.ID, pageID, s.Name, s.CSSSelector, s.XPathSelector,
-			string(offsetsJSON), rectJSON, s.ViewportWidth, s.SortOrder, s.CreatedAt, s.UpdatedAt,
+			string(offsetsJSON), rectJSON, s.ViewportWidth, s.SortOrder, marshalFingerprint(s.Fingerprint),
+			s.CreatedAt, s.UpdatedAt,
 		); err != nil {
 			return err
 		}
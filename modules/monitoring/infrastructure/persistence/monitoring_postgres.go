@@ -39,6 +39,22 @@ func marshalSelectorOffsets(o *entities.SelectorOffsets) []byte {
 	return b
 }
 
+func marshalCaptureSteps(steps []entities.CaptureStep) []byte {
+	if steps == nil {
+		steps = []entities.CaptureStep{}
+	}
+	b, _ := json.Marshal(steps)
+	return b
+}
+
+func marshalCaptureProfiles(profiles []entities.CaptureProfile) []byte {
+	if profiles == nil {
+		profiles = []entities.CaptureProfile{}
+	}
+	b, _ := json.Marshal(profiles)
+	return b
+}
+
 func (r *MonitoringConfigPostgresRepository) Create(ctx context.Context, config *entities.MonitoringConfig) error {
 	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
 		return err
@@ -46,18 +62,21 @@ func (r *MonitoringConfigPostgresRepository) Create(ctx context.Context, config
 	insightTypesJSON := marshalStringSlice(config.EnabledInsightTypes)
 	alertConditionsJSON := marshalStringSlice(config.EnabledAlertConditions)
 	selectorOffsetsJSON := marshalSelectorOffsets(config.SelectorOffsets)
+	captureStepsJSON := marshalCaptureSteps(config.CaptureSteps)
+	captureProfilesJSON := marshalCaptureProfiles(config.CaptureProfiles)
 	q := `INSERT INTO monitoring_configs
 		(id, page_id, check_frequency, schedule_type, timezone, block_ads_cookies,
 		 enabled_insight_types, enabled_alert_conditions, custom_alert_condition,
-		 selector_type, css_selector, xpath_selector, selector_offsets,
-		 created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+		 selector_type, css_selector, xpath_selector, selector_offsets, selector_fallback,
+		 check_broken_links, capture_steps, capture_profiles, proxy_region, proxy_pool, fetch_engine, suppress_recurrences, legal_hold, archive, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NULLIF($18, ''), NULLIF($19, ''), $20, $21, $22, $23, $24, $25)`
 	_, err := r.db.ExecContext(ctx, q,
 		config.ID, config.PageID, config.CheckFrequency, config.ScheduleType,
 		config.Timezone, config.BlockAdsCookies,
 		string(insightTypesJSON), string(alertConditionsJSON), config.CustomAlertCondition,
-		config.SelectorType, config.CSSSelector, config.XPathSelector, string(selectorOffsetsJSON),
-		config.CreatedAt, config.UpdatedAt,
+		config.SelectorType, config.CSSSelector, config.XPathSelector, string(selectorOffsetsJSON), config.SelectorFallback,
+		config.CheckBrokenLinks, string(captureStepsJSON), string(captureProfilesJSON),
+		config.ProxyRoute.Region, config.ProxyRoute.Pool, config.FetchEngineOrDefault(), config.SuppressRecurrences, config.LegalHold, config.Archive, config.CreatedAt, config.UpdatedAt,
 	)
 	return err
 }
@@ -67,17 +86,31 @@ func (r *MonitoringConfigPostgresRepository) GetByPageID(ctx context.Context, pa
 		return nil, err
 	}
 	var c entities.MonitoringConfig
-	var insightTypesRaw, alertConditionsRaw, selectorOffsetsRaw []byte
+	var insightTypesRaw, alertConditionsRaw, selectorOffsetsRaw, captureStepsRaw, captureProfilesRaw []byte
 	q := `SELECT id, page_id, check_frequency, schedule_type, timezone, block_ads_cookies,
 		         enabled_insight_types, enabled_alert_conditions, custom_alert_condition,
 		         COALESCE(selector_type, 'full_page'), COALESCE(css_selector, ''), COALESCE(xpath_selector, ''),
 		         COALESCE(selector_offsets, '{"top":0,"right":0,"bottom":0,"left":0}')::text,
+		         selector_fallback, selector_miss_count, selector_broken_at,
+		         COALESCE(check_broken_links, false),
+		         COALESCE(capture_steps, '[]')::text,
+		         COALESCE(capture_profiles, '[]')::text,
+		         COALESCE(proxy_region, ''), COALESCE(proxy_pool, ''),
+		         fetch_engine, suppress_recurrences, legal_hold, archive,
+		         COALESCE(document_probe_url, ''), COALESCE(document_kind, ''), document_probed_at,
 		         created_at, updated_at
 		  FROM monitoring_configs WHERE page_id = $1 AND deleted_at IS NULL`
 	err := r.db.QueryRowContext(ctx, q, pageID).Scan(
 		&c.ID, &c.PageID, &c.CheckFrequency, &c.ScheduleType, &c.Timezone, &c.BlockAdsCookies,
 		&insightTypesRaw, &alertConditionsRaw, &c.CustomAlertCondition,
 		&c.SelectorType, &c.CSSSelector, &c.XPathSelector, &selectorOffsetsRaw,
+		&c.SelectorFallback, &c.SelectorHealth.MissCount, &c.SelectorHealth.BrokenAt,
+		&c.CheckBrokenLinks,
+		&captureStepsRaw,
+		&captureProfilesRaw,
+		&c.ProxyRoute.Region, &c.ProxyRoute.Pool,
+		&c.FetchEngine, &c.SuppressRecurrences, &c.LegalHold, &c.Archive,
+		&c.DocumentProbe.URL, &c.DocumentProbe.Kind, &c.DocumentProbe.ProbedAt,
 		&c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
@@ -98,6 +131,12 @@ func (r *MonitoringConfigPostgresRepository) GetByPageID(ctx context.Context, pa
 			c.SelectorOffsets = &offsets
 		}
 	}
+	if len(captureStepsRaw) > 0 {
+		_ = json.Unmarshal(captureStepsRaw, &c.CaptureSteps)
+	}
+	if len(captureProfilesRaw) > 0 {
+		_ = json.Unmarshal(captureProfilesRaw, &c.CaptureProfiles)
+	}
 	return &c, nil
 }
 
@@ -115,21 +154,52 @@ func (r *MonitoringConfigPostgresRepository) Update(ctx context.Context, config
 		return err
 	}
 	selectorOffsetsJSON := marshalSelectorOffsets(config.SelectorOffsets)
+	captureStepsJSON := marshalCaptureSteps(config.CaptureSteps)
+	captureProfilesJSON := marshalCaptureProfiles(config.CaptureProfiles)
+	// A changed selector starts with a clean health record.
 	q := `UPDATE monitoring_configs
 		  SET check_frequency = $1, schedule_type = $2, timezone = $3, block_ads_cookies = $4,
 		      enabled_insight_types = $5, enabled_alert_conditions = $6, custom_alert_condition = $7,
-		      selector_type = $8, css_selector = $9, xpath_selector = $10, selector_offsets = $11,
-		      updated_at = $12
-		  WHERE id = $13 AND deleted_at IS NULL`
+		      selector_miss_count = CASE WHEN COALESCE(selector_type, 'full_page') = $8 AND COALESCE(css_selector, '') = $9
+		                                      AND COALESCE(xpath_selector, '') = $10 THEN selector_miss_count ELSE 0 END,
+		      selector_broken_at = CASE WHEN COALESCE(selector_type, 'full_page') = $8 AND COALESCE(css_selector, '') = $9
+		                                     AND COALESCE(xpath_selector, '') = $10 THEN selector_broken_at END,
+		      selector_type = $8, css_selector = $9, xpath_selector = $10, selector_offsets = $11, selector_fallback = $12,
+		      check_broken_links = $13, capture_steps = $14, capture_profiles = $15,
+		      proxy_region = NULLIF($16, ''), proxy_pool = NULLIF($17, ''), fetch_engine = $18, suppress_recurrences = $19, legal_hold = $20, archive = $21, updated_at = $22
+		  WHERE id = $23 AND deleted_at IS NULL`
 	_, err = r.db.ExecContext(ctx, q,
 		config.CheckFrequency, config.ScheduleType, config.Timezone, config.BlockAdsCookies,
 		string(insightTypesJSON), string(alertConditionsJSON), config.CustomAlertCondition,
-		config.SelectorType, config.CSSSelector, config.XPathSelector, string(selectorOffsetsJSON),
-		config.UpdatedAt, config.ID,
+		config.SelectorType, config.CSSSelector, config.XPathSelector, string(selectorOffsetsJSON), config.SelectorFallback,
+		config.CheckBrokenLinks, string(captureStepsJSON), string(captureProfilesJSON),
+		config.ProxyRoute.Region, config.ProxyRoute.Pool, config.FetchEngineOrDefault(), config.SuppressRecurrences, config.LegalHold, config.Archive, config.UpdatedAt, config.ID,
 	)
 	return err
 }
 
+// UpdateSelectorHealth records the element selector's miss streak.
+func (r *MonitoringConfigPostgresRepository) UpdateSelectorHealth(ctx context.Context, pageID uuid.UUID, health entities.SelectorHealth) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `UPDATE monitoring_configs SET selector_miss_count = $1, selector_broken_at = $2
+		  WHERE page_id = $3 AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, q, health.MissCount, health.BrokenAt, pageID)
+	return err
+}
+
+// UpdateDocumentProbe caches what the page URL served when probed.
+func (r *MonitoringConfigPostgresRepository) UpdateDocumentProbe(ctx context.Context, pageID uuid.UUID, probe entities.DocumentProbe) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `UPDATE monitoring_configs SET document_probe_url = $1, document_kind = NULLIF($2, ''), document_probed_at = $3
+		  WHERE page_id = $4 AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, q, probe.URL, probe.Kind, probe.ProbedAt, pageID)
+	return err
+}
+
 func (r *MonitoringConfigPostgresRepository) BulkUpdateFrequency(ctx context.Context, pageIDs []uuid.UUID, frequency string) error {
 	if len(pageIDs) == 0 {
 		return nil
@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+type PageCredentialPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewPageCredentialPostgresRepository(db *sql.DB, tenant string) *PageCredentialPostgresRepository {
+	return &PageCredentialPostgresRepository{db: db, tenant: tenant}
+}
+
+func (r *PageCredentialPostgresRepository) GetByPageID(ctx context.Context, pageID uuid.UUID) (*entities.PageCredential, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var c entities.PageCredential
+	var scriptJSON []byte
+	var headerNamesJSON, cookieNamesJSON []byte
+	q := `SELECT id, page_id, auth_type, login_script, header_names, cookie_names, secret_ciphertext,
+	             COALESCE(last_login_status, ''), COALESCE(last_login_error, ''), last_login_at, created_at, updated_at
+	      FROM page_credentials WHERE page_id = $1`
+	err := r.db.QueryRowContext(ctx, q, pageID).Scan(
+		&c.ID, &c.PageID, &c.AuthType, &scriptJSON, &headerNamesJSON, &cookieNamesJSON, &c.SecretCiphertext,
+		&c.LastLoginStatus, &c.LastLoginError, &c.LastLoginAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(scriptJSON) > 0 && string(scriptJSON) != "null" {
+		var script entities.LoginScript
+		if err := json.Unmarshal(scriptJSON, &script); err != nil {
+			return nil, err
+		}
+		c.LoginScript = &script
+	}
+	if err := json.Unmarshal(headerNamesJSON, &c.HeaderNames); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(cookieNamesJSON, &c.CookieNames); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Upsert replaces the page's credential. Changing the credential clears the
+// recorded login status so the next check starts from a clean slate.
+func (r *PageCredentialPostgresRepository) Upsert(ctx context.Context, credential *entities.PageCredential) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	var scriptJSON []byte
+	if credential.LoginScript != nil {
+		b, err := json.Marshal(credential.LoginScript)
+		if err != nil {
+			return err
+		}
+		scriptJSON = b
+	}
+	credential.UpdatedAt = time.Now()
+
+	q := `INSERT INTO page_credentials
+	          (id, page_id, auth_type, login_script, header_names, cookie_names, secret_ciphertext, created_at, updated_at)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	      ON CONFLICT (page_id) DO UPDATE SET
+	          auth_type = EXCLUDED.auth_type,
+	          login_script = EXCLUDED.login_script,
+	          header_names = EXCLUDED.header_names,
+	          cookie_names = EXCLUDED.cookie_names,
+	          secret_ciphertext = EXCLUDED.secret_ciphertext,
+	          last_login_status = NULL,
+	          last_login_error = NULL,
+	          last_login_at = NULL,
+	          updated_at = EXCLUDED.updated_at
+	      RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, q,
+		credential.ID, credential.PageID, credential.AuthType, scriptJSON,
+		marshalStringSlice(credential.HeaderNames), marshalStringSlice(credential.CookieNames),
+		credential.SecretCiphertext, credential.CreatedAt, credential.UpdatedAt,
+	).Scan(&credential.ID, &credential.CreatedAt)
+}
+
+func (r *PageCredentialPostgresRepository) DeleteByPageID(ctx context.Context, pageID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM page_credentials WHERE page_id = $1`, pageID)
+	return err
+}
+
+func (r *PageCredentialPostgresRepository) UpdateLoginStatus(ctx context.Context, pageID uuid.UUID, status, errMsg string) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `UPDATE page_credentials SET last_login_status = $1, last_login_error = $2, last_login_at = NOW() WHERE page_id = $3`
+	_, err := r.db.ExecContext(ctx, q, status, errMsg, pageID)
+	return err
+}
@@ -0,0 +1,171 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+type ProxyPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewProxyPostgresRepository(db *sql.DB, tenant string) *ProxyPostgresRepository {
+	return &ProxyPostgresRepository{db: db, tenant: tenant}
+}
+
+const proxySelectColumns = `id, name, protocol, host, port, COALESCE(username, ''), COALESCE(password_ciphertext, ''),
+	COALESCE(region, ''), COALESCE(pool, ''), enabled, consecutive_failures, quarantined_until,
+	last_used_at, last_success_at, last_failure_at, COALESCE(last_error, ''), created_at, updated_at`
+
+func scanProxy(row interface{ Scan(...interface{}) error }, p *entities.Proxy) error {
+	return row.Scan(
+		&p.ID, &p.Name, &p.Protocol, &p.Host, &p.Port, &p.Username, &p.PasswordCiphertext,
+		&p.Region, &p.Pool, &p.Enabled, &p.ConsecutiveFailures, &p.QuarantinedUntil,
+		&p.LastUsedAt, &p.LastSuccessAt, &p.LastFailureAt, &p.LastError, &p.CreatedAt, &p.UpdatedAt,
+	)
+}
+
+func (r *ProxyPostgresRepository) List(ctx context.Context) ([]*entities.Proxy, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+proxySelectColumns+` FROM proxies ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proxies []*entities.Proxy
+	for rows.Next() {
+		var p entities.Proxy
+		if err := scanProxy(rows, &p); err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, &p)
+	}
+	return proxies, rows.Err()
+}
+
+func (r *ProxyPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Proxy, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var p entities.Proxy
+	if err := scanProxy(r.db.QueryRowContext(ctx, `SELECT `+proxySelectColumns+` FROM proxies WHERE id = $1`, id), &p); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProxyPostgresRepository) Create(ctx context.Context, p *entities.Proxy) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `INSERT INTO proxies (id, name, protocol, host, port, username, password_ciphertext, region, pool, enabled, created_at, updated_at)
+	      VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12)`
+	_, err := r.db.ExecContext(ctx, q,
+		p.ID, p.Name, p.Protocol, p.Host, p.Port, p.Username, p.PasswordCiphertext,
+		p.Region, p.Pool, p.Enabled, p.CreatedAt, p.UpdatedAt,
+	)
+	return err
+}
+
+func (r *ProxyPostgresRepository) Update(ctx context.Context, p *entities.Proxy) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	p.UpdatedAt = time.Now()
+	q := `UPDATE proxies SET name = $1, protocol = $2, host = $3, port = $4, username = NULLIF($5, ''),
+	          password_ciphertext = NULLIF($6, ''), region = NULLIF($7, ''), pool = NULLIF($8, ''), enabled = $9,
+	          consecutive_failures = $10, quarantined_until = $11, last_error = NULLIF($12, ''), updated_at = $13
+	      WHERE id = $14`
+	_, err := r.db.ExecContext(ctx, q,
+		p.Name, p.Protocol, p.Host, p.Port, p.Username,
+		p.PasswordCiphertext, p.Region, p.Pool, p.Enabled,
+		p.ConsecutiveFailures, p.QuarantinedUntil, p.LastError, p.UpdatedAt,
+		p.ID,
+	)
+	return err
+}
+
+func (r *ProxyPostgresRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `DELETE FROM proxies WHERE id = $1`, id)
+	return err
+}
+
+func (r *ProxyPostgresRepository) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `UPDATE proxies SET last_used_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+func (r *ProxyPostgresRepository) UpdateHealth(ctx context.Context, p *entities.Proxy) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `UPDATE proxies SET consecutive_failures = $1, quarantined_until = $2, last_success_at = $3,
+	          last_failure_at = $4, last_error = NULLIF($5, '')
+	      WHERE id = $6`
+	_, err := r.db.ExecContext(ctx, q,
+		p.ConsecutiveFailures, p.QuarantinedUntil, p.LastSuccessAt, p.LastFailureAt, p.LastError, p.ID,
+	)
+	return err
+}
+
+func (r *ProxyPostgresRepository) GetWorkspaceRoute(ctx context.Context, workspaceID uuid.UUID) (*entities.ProxyRoute, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var route entities.ProxyRoute
+	q := `SELECT COALESCE(proxy_region, ''), COALESCE(proxy_pool, '') FROM workspace_proxy_settings WHERE workspace_id = $1`
+	if err := r.db.QueryRowContext(ctx, q, workspaceID).Scan(&route.Region, &route.Pool); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &route, nil
+}
+
+func (r *ProxyPostgresRepository) SetWorkspaceRoute(ctx context.Context, workspaceID uuid.UUID, route entities.ProxyRoute) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	if route.IsZero() {
+		_, err := r.db.ExecContext(ctx, `DELETE FROM workspace_proxy_settings WHERE workspace_id = $1`, workspaceID)
+		return err
+	}
+
+	q := `INSERT INTO workspace_proxy_settings (workspace_id, proxy_region, proxy_pool, updated_at)
+	      VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NOW())
+	      ON CONFLICT (workspace_id) DO UPDATE SET
+	          proxy_region = EXCLUDED.proxy_region,
+	          proxy_pool = EXCLUDED.proxy_pool,
+	          updated_at = EXCLUDED.updated_at`
+	_, err := r.db.ExecContext(ctx, q, workspaceID, route.Region, route.Pool)
+	return err
+}
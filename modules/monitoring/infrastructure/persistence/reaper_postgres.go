@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/reaper"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+type ReaperPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewReaperPostgresRepository(db *sql.DB, tenant string) *ReaperPostgresRepository {
+	return &ReaperPostgresRepository{
+		db:     db,
+		tenant: tenant,
+	}
+}
+
+// ListStuck returns pending and running checks dispatched before cutoff,
+// oldest first.
+func (r *ReaperPostgresRepository) ListStuck(ctx context.Context, cutoff time.Time, limit int) ([]reaper.StuckCheck, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	q := `SELECT c.id, c.page_id, c.section_id, c.status, c.checked_at, p.url, c.heartbeat_at, c.dispatch_attempts
+		FROM checks c
+		JOIN pages p ON p.id = c.page_id
+		WHERE c.status IN ('pending', 'running') AND c.checked_at < $1
+		ORDER BY c.checked_at ASC
+		LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, q, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stuck []reaper.StuckCheck
+	for rows.Next() {
+		check := &entities.Check{}
+		s := reaper.StuckCheck{Check: check}
+		var heartbeat sql.NullTime
+		if err := rows.Scan(&check.ID, &check.PageID, &check.SectionID, &check.Status, &check.CheckedAt, &s.URL, &heartbeat, &s.Dispatches); err != nil {
+			return nil, err
+		}
+		if heartbeat.Valid {
+			s.HeartbeatAt = &heartbeat.Time
+		}
+		stuck = append(stuck, s)
+	}
+	return stuck, rows.Err()
+}
+
+// Redispatch restarts a pending check's deadlines, unless a worker claimed it
+// or another reaper already re-dispatched it.
+func (r *ReaperPostgresRepository) Redispatch(ctx context.Context, checkID uuid.UUID, dispatches int) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return false, err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE checks
+		SET dispatch_attempts = dispatch_attempts + 1, checked_at = NOW(), heartbeat_at = NULL
+		WHERE id = $1 AND status = 'pending' AND dispatch_attempts = $2`, checkID, dispatches)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Fail marks a still pending or running check as error.
+func (r *ReaperPostgresRepository) Fail(ctx context.Context, checkID uuid.UUID, reason string) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return false, err
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE checks SET status = 'error', error_message = $2
+		WHERE id = $1 AND status IN ('pending', 'running')`, checkID, reason)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *ReaperPostgresRepository) RefundUsage(ctx context.Context, checkID uuid.UUID) error {
+	return NewUsagePostgresRepository(r.db, r.tenant).RefundUsage(ctx, checkID)
+}
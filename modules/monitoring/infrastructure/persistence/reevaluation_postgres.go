@@ -0,0 +1,108 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+const reevaluationSummaryColumns = `id, page_id, section_id, range_from, range_to, status, total_checks, would_alert, flipped, COALESCE(error_message, ''), created_at, completed_at, applied_at`
+
+type ReevaluationPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewReevaluationPostgresRepository(db *sql.DB, tenant string) *ReevaluationPostgresRepository {
+	return &ReevaluationPostgresRepository{db: db, tenant: tenant}
+}
+
+func scanReevaluationSummary(row interface{ Scan(...interface{}) error }, job *entities.ReevaluationJob, extra ...interface{}) error {
+	dest := []interface{}{
+		&job.ID, &job.PageID, &job.SectionID, &job.From, &job.To, &job.Status,
+		&job.TotalChecks, &job.WouldAlert, &job.Flipped, &job.ErrorMessage,
+		&job.CreatedAt, &job.CompletedAt, &job.AppliedAt,
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
+func (r *ReevaluationPostgresRepository) Create(ctx context.Context, job *entities.ReevaluationJob) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `INSERT INTO reevaluation_jobs (id, page_id, section_id, range_from, range_to, status, created_at)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.ExecContext(ctx, q, job.ID, job.PageID, job.SectionID, job.From, job.To, job.Status, job.CreatedAt)
+	return err
+}
+
+func (r *ReevaluationPostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ReevaluationJob, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	var job entities.ReevaluationJob
+	var resultsRaw, unsupportedRaw []byte
+	q := `SELECT ` + reevaluationSummaryColumns + `, results, unsupported_selectors FROM reevaluation_jobs WHERE id = $1`
+	if err := scanReevaluationSummary(r.db.QueryRowContext(ctx, q, id), &job, &resultsRaw, &unsupportedRaw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(resultsRaw, &job.Results)
+	_ = json.Unmarshal(unsupportedRaw, &job.UnsupportedSelectors)
+	return &job, nil
+}
+
+// ListByPageID returns a page's jobs, newest first, without their results.
+func (r *ReevaluationPostgresRepository) ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.ReevaluationJob, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+	q := `SELECT ` + reevaluationSummaryColumns + ` FROM reevaluation_jobs WHERE page_id = $1 ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, q, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*entities.ReevaluationJob
+	for rows.Next() {
+		var job entities.ReevaluationJob
+		if err := scanReevaluationSummary(rows, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *ReevaluationPostgresRepository) Update(ctx context.Context, job *entities.ReevaluationJob) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	results := job.Results
+	if results == nil {
+		results = []entities.ReevaluationResult{}
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	q := `UPDATE reevaluation_jobs
+	      SET status = $1, total_checks = $2, would_alert = $3, flipped = $4, results = $5,
+	          unsupported_selectors = $6, error_message = NULLIF($7, ''), completed_at = $8, applied_at = $9
+	      WHERE id = $10`
+	_, err = r.db.ExecContext(ctx, q,
+		job.Status, job.TotalChecks, job.WouldAlert, job.Flipped, string(resultsJSON),
+		string(marshalStringSlice(job.UnsupportedSelectors)), job.ErrorMessage, job.CompletedAt, job.AppliedAt,
+		job.ID,
+	)
+	return err
+}
@@ -1,9 +1,13 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/orchestrator"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/reaper"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/retention"
+	snapshotkeys "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/snapshot_keys"
 )
 
 type PostgresRepositoryFactory struct {
@@ -25,3 +29,34 @@ func (f *PostgresRepositoryFactory) GetPageRepository(tenant string) orchestrato
 func (f *PostgresRepositoryFactory) GetUsageRepository(tenant string) orchestrator.UsageRepository {
 	return NewUsagePostgresRepository(f.db, tenant)
 }
+
+func (f *PostgresRepositoryFactory) GetRetentionRepository(tenant string) retention.Repository {
+	return NewRetentionPostgresRepository(f.db, tenant)
+}
+
+func (f *PostgresRepositoryFactory) GetSnapshotKeyRepository(tenant string) snapshotkeys.Repository {
+	return NewSnapshotKeyPostgresRepository(f.db, tenant)
+}
+
+func (f *PostgresRepositoryFactory) GetReaperRepository(tenant string) reaper.Repository {
+	return NewReaperPostgresRepository(f.db, tenant)
+}
+
+// ListTenants returns the schema names of all active organizations.
+func (f *PostgresRepositoryFactory) ListTenants(ctx context.Context) ([]string, error) {
+	rows, err := f.db.QueryContext(ctx, "SELECT schema_name FROM public.organizations WHERE deleted_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []string
+	for rows.Next() {
+		var schema string
+		if err := rows.Scan(&schema); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, schema)
+	}
+	return tenants, rows.Err()
+}
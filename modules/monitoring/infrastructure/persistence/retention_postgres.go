@@ -0,0 +1,218 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+	"github.com/lib/pq"
+)
+
+type RetentionPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewRetentionPostgresRepository(db *sql.DB, tenant string) *RetentionPostgresRepository {
+	return &RetentionPostgresRepository{
+		db:     db,
+		tenant: tenant,
+	}
+}
+
+// StoragePeriodDays returns the storage period of the tenant's active plan,
+// or 0 when the tenant has no active plan.
+func (r *RetentionPostgresRepository) StoragePeriodDays(ctx context.Context) (int, error) {
+	q := `
+		SELECT COALESCE(p.storage_period_days, 7)
+		FROM public.organizations o
+		JOIN public.organization_plans op ON op.organization_id = o.id
+			AND op.status = 'active' AND op.deleted_at IS NULL
+		JOIN public.plans p ON p.id = op.plan_id
+		WHERE o.schema_name = $1
+		ORDER BY op.started_at DESC
+		LIMIT 1
+	`
+	var days int
+	if err := r.db.QueryRowContext(ctx, q, r.tenant).Scan(&days); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return days, nil
+}
+
+func (r *RetentionPostgresRepository) ListExpired(ctx context.Context, cutoff time.Time, limit int) ([]*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	q := `SELECT ` + checkSelectColumns + ` FROM checks c
+		WHERE c.checked_at < $1
+		AND c.purged_at IS NULL
+		AND NOT c.pinned
+		AND (c.screenshot_url IS NOT NULL OR c.html_snapshot_url IS NOT NULL OR c.document_url IS NOT NULL
+			OR c.thumbnail_url IS NOT NULL OR c.preview_url IS NOT NULL OR c.archive_url IS NOT NULL)
+		AND NOT EXISTS (
+			SELECT 1 FROM monitoring_configs mc
+			WHERE mc.page_id = c.page_id AND mc.legal_hold AND mc.deleted_at IS NULL
+		)
+		AND NOT (c.status = 'success' AND NOT EXISTS (
+			SELECT 1 FROM checks later
+			WHERE later.page_id = c.page_id
+			AND later.section_id IS NOT DISTINCT FROM c.section_id
+			AND later.profile_name IS NOT DISTINCT FROM c.profile_name
+			AND later.status = 'success'
+			AND later.checked_at > c.checked_at
+		))
+		ORDER BY c.checked_at ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, q, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []*entities.Check
+	for rows.Next() {
+		var check entities.Check
+		if err := scanCheck(rows, &check); err != nil {
+			return nil, err
+		}
+		checks = append(checks, &check)
+	}
+	return checks, rows.Err()
+}
+
+// MarkPurged tombstones the checks and drops one reference per object key
+// they held. Keys stored before objects were reference counted get a row
+// without references, so the purge deletes them too.
+func (r *RetentionPostgresRepository) MarkPurged(ctx context.Context, checkIDs []uuid.UUID) error {
+	if len(checkIDs) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	placeholders := make([]string, len(checkIDs))
+	args := make([]interface{}, len(checkIDs))
+	for i, id := range checkIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	q := `WITH purged AS (
+			SELECT id, screenshot_url, html_snapshot_url, document_url, thumbnail_url, preview_url, archive_url FROM checks
+			WHERE id IN (` + strings.Join(placeholders, ", ") + `) AND purged_at IS NULL
+			FOR UPDATE
+		), released AS (
+			SELECT key, COUNT(*) AS refs FROM (
+				SELECT screenshot_url AS key FROM purged
+				UNION ALL SELECT html_snapshot_url FROM purged
+				UNION ALL SELECT document_url FROM purged
+				UNION ALL SELECT thumbnail_url FROM purged
+				UNION ALL SELECT preview_url FROM purged
+				UNION ALL SELECT archive_url FROM purged
+			) k
+			WHERE key IS NOT NULL AND key <> ''
+			GROUP BY key
+		), counted AS (
+			INSERT INTO snapshot_blobs (key, ref_count)
+			SELECT key, 0 FROM released
+			ON CONFLICT (key) DO UPDATE SET ref_count = GREATEST(snapshot_blobs.ref_count - (
+				SELECT refs FROM released WHERE released.key = EXCLUDED.key
+			), 0)
+		)
+		UPDATE checks SET screenshot_url = NULL, html_snapshot_url = NULL, document_url = NULL,
+			thumbnail_url = NULL, preview_url = NULL, archive_url = NULL, purged_at = NOW()
+		WHERE id IN (SELECT id FROM purged)`
+	_, err := r.db.ExecContext(ctx, q, args...)
+	return err
+}
+
+// DeleteUnreferenced deletes up to limit objects without references. Their
+// rows are first marked not uploaded, so a reference taken from then on
+// uploads the object again, and stay locked while del removes the objects:
+// an Acquire racing the purge waits and then starts a new row instead of
+// pointing at an object being deleted. Rows whose object could not be deleted
+// are kept for the next run.
+func (r *RetentionPostgresRepository) DeleteUnreferenced(ctx context.Context, limit int, del func(key string) error) (deleted, failed int, err error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return 0, 0, err
+	}
+
+	q := `UPDATE snapshot_blobs SET uploaded = FALSE
+		WHERE key IN (
+			SELECT key FROM snapshot_blobs WHERE ref_count = 0
+			LIMIT $1 FOR UPDATE SKIP LOCKED
+		) AND ref_count = 0
+		RETURNING key`
+	claimed, err := queryKeys(ctx, r.db, q, limit)
+	if err != nil || len(claimed) == 0 {
+		return 0, 0, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return 0, 0, err
+	}
+	// Keys referenced since they were claimed are being uploaded again.
+	keys, err := queryKeys(ctx, tx, `SELECT key FROM snapshot_blobs WHERE key = ANY($1) AND ref_count = 0 FOR UPDATE`, pq.Array(claimed))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var gone []string
+	for _, key := range keys {
+		if err := del(key); err != nil {
+			failed++
+			continue
+		}
+		gone = append(gone, key)
+	}
+	if len(gone) > 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM snapshot_blobs WHERE key = ANY($1)`, pq.Array(gone)); err != nil {
+			return 0, failed, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, failed, err
+	}
+	return len(gone), failed, nil
+}
+
+type keyQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func queryKeys(ctx context.Context, db keyQuerier, q string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
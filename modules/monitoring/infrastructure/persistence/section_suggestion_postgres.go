@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+type SectionSuggestionPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewSectionSuggestionPostgresRepository(db *sql.DB, tenant string) *SectionSuggestionPostgresRepository {
+	return &SectionSuggestionPostgresRepository{db: db, tenant: tenant}
+}
+
+// ReplaceForPage atomically replaces a page's suggestions.
+func (r *SectionSuggestionPostgresRepository) ReplaceForPage(ctx context.Context, pageID uuid.UUID, suggestions []*entities.SectionSuggestion) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM section_suggestions WHERE page_id = $1`, pageID); err != nil {
+		return err
+	}
+
+	q := `INSERT INTO section_suggestions (id, page_id, kind, name, css_selector, xpath_selector, rect, viewport_width, fingerprint, score, created_at)
+	      VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11)`
+	for _, s := range suggestions {
+		if _, err := tx.ExecContext(ctx, q,
+			s.ID, pageID, s.Kind, s.Name, s.CSSSelector, s.XPathSelector,
+			marshalSectionRect(s.Rect), s.ViewportWidth, marshalFingerprint(s.Fingerprint), s.Score, s.CreatedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListByPageID returns a page's suggestions, best ranked first.
+func (r *SectionSuggestionPostgresRepository) ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.SectionSuggestion, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+	q := `SELECT id, page_id, kind, name, css_selector, COALESCE(xpath_selector, ''), rect, viewport_width, fingerprint, score, created_at
+	      FROM section_suggestions WHERE page_id = $1 ORDER BY score DESC, created_at ASC`
+	rows, err := r.db.QueryContext(ctx, q, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []*entities.SectionSuggestion
+	for rows.Next() {
+		var s entities.SectionSuggestion
+		var rectRaw, fingerprintRaw []byte
+		if err := rows.Scan(
+			&s.ID, &s.PageID, &s.Kind, &s.Name, &s.CSSSelector, &s.XPathSelector,
+			&rectRaw, &s.ViewportWidth, &fingerprintRaw, &s.Score, &s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if len(rectRaw) > 0 {
+			var rect entities.SectionRect
+			if json.Unmarshal(rectRaw, &rect) == nil {
+				s.Rect = &rect
+			}
+		}
+		if len(fingerprintRaw) > 0 {
+			var fingerprint entities.ElementFingerprint
+			if json.Unmarshal(fingerprintRaw, &fingerprint) == nil {
+				s.Fingerprint = &fingerprint
+			}
+		}
+		suggestions = append(suggestions, &s)
+	}
+	return suggestions, rows.Err()
+}
+
+func (r *SectionSuggestionPostgresRepository) Delete(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	q := `DELETE FROM section_suggestions WHERE id IN (` + strings.Join(placeholders, ", ") + `)`
+	_, err := r.db.ExecContext(ctx, q, args...)
+	return err
+}
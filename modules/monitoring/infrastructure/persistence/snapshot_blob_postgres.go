@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+// SnapshotBlobPostgresRepository counts the checks that reference each stored
+// object. Objects are stored under their content hash, so identical captures
+// share one object.
+type SnapshotBlobPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewSnapshotBlobPostgresRepository(db *sql.DB, tenant string) *SnapshotBlobPostgresRepository {
+	return &SnapshotBlobPostgresRepository{
+		db:     db,
+		tenant: tenant,
+	}
+}
+
+// Acquire counts a new reference to the object and reports whether the
+// caller has to upload it: the object is new, or no upload of it has been
+// confirmed with MarkUploaded yet. Content-addressed keys make a repeated
+// upload harmless. While the retention purge is deleting the object, Acquire
+// waits for it and then starts a new row.
+func (r *SnapshotBlobPostgresRepository) Acquire(ctx context.Context, key string, size int64) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return false, err
+	}
+
+	q := `INSERT INTO snapshot_blobs (key, ref_count, size_bytes, uploaded) VALUES ($1, 1, $2, FALSE)
+		ON CONFLICT (key) DO UPDATE SET ref_count = snapshot_blobs.ref_count + 1
+		RETURNING uploaded`
+	var uploaded bool
+	if err := r.db.QueryRowContext(ctx, q, key, size).Scan(&uploaded); err != nil {
+		return false, err
+	}
+	return !uploaded, nil
+}
+
+// MarkUploaded records that the object is stored, so later references reuse
+// it.
+func (r *SnapshotBlobPostgresRepository) MarkUploaded(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `UPDATE snapshot_blobs SET uploaded = TRUE WHERE key = $1`, key)
+	return err
+}
+
+// Release drops a reference taken by Acquire. Objects without references are
+// deleted by the retention purge.
+func (r *SnapshotBlobPostgresRepository) Release(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `UPDATE snapshot_blobs SET ref_count = GREATEST(ref_count - 1, 0) WHERE key = $1`, key)
+	return err
+}
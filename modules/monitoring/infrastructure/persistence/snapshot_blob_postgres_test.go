@@ -0,0 +1,179 @@
+package persistence
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// openBlobTestSchema creates a throwaway tenant schema with the blob table.
+func openBlobTestSchema(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	db, schema := openTestSchema(t)
+	for _, name := range []string{"000054_add_snapshot_blobs.up.sql", "000060_add_snapshot_blob_uploads.up.sql"} {
+		migration, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "shared", "database", "migrations", "tenant", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	return db, schema
+}
+
+// openTestSchema creates a throwaway, empty tenant schema. The tests need a
+// database and are skipped unless TEST_DATABASE_URL is set.
+func openTestSchema(t *testing.T) (*sql.DB, string) {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("Skipping database test (set TEST_DATABASE_URL to run)")
+	}
+
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	schema := "persistence_test_" + hex.EncodeToString(suffix)
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	// Every pooled connection starts in the schema, so concurrent calls do
+	// not depend on which connection ran the repository's SET search_path.
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, schema
+}
+
+func blobRow(t *testing.T, db *sql.DB, key string) (refs int, uploaded, ok bool) {
+	t.Helper()
+	err := db.QueryRow(`SELECT ref_count, uploaded FROM snapshot_blobs WHERE key = $1`, key).Scan(&refs, &uploaded)
+	if err == sql.ErrNoRows {
+		return 0, false, false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return refs, uploaded, true
+}
+
+func TestSnapshotBlobRepository_AcquireUntilUploaded(t *testing.T) {
+	db, schema := openBlobTestSchema(t)
+	repo := NewSnapshotBlobPostgresRepository(db, schema)
+	ctx := context.Background()
+
+	upload, err := repo.Acquire(ctx, "page/a.png", 10)
+	if err != nil || !upload {
+		t.Fatalf("first Acquire = %v, %v; want an upload", upload, err)
+	}
+	// The first upload has not been confirmed, so the next reference uploads
+	// too instead of pointing at an object that may never arrive.
+	if upload, err := repo.Acquire(ctx, "page/a.png", 10); err != nil || !upload {
+		t.Fatalf("Acquire before MarkUploaded = %v, %v; want an upload", upload, err)
+	}
+	if err := repo.MarkUploaded(ctx, "page/a.png"); err != nil {
+		t.Fatal(err)
+	}
+	if upload, err := repo.Acquire(ctx, "page/a.png", 10); err != nil || upload {
+		t.Fatalf("Acquire after MarkUploaded = %v, %v; want the object reused", upload, err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := repo.Release(ctx, "page/a.png"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if refs, uploaded, ok := blobRow(t, db, "page/a.png"); !ok || refs != 0 || !uploaded {
+		t.Errorf("row = %d, %v, %v; want an uploaded object without references", refs, uploaded, ok)
+	}
+}
+
+func TestRetentionRepository_DeleteUnreferencedBlocksAcquire(t *testing.T) {
+	db, schema := openBlobTestSchema(t)
+	blobs := NewSnapshotBlobPostgresRepository(db, schema)
+	retention := NewRetentionPostgresRepository(db, schema)
+	ctx := context.Background()
+
+	for _, key := range []string{"page/old.png", "page/kept.png", "page/stuck.png"} {
+		if _, err := blobs.Acquire(ctx, key, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := blobs.MarkUploaded(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blobs.Release(ctx, "page/old.png")
+	blobs.Release(ctx, "page/stuck.png")
+
+	acquired := make(chan bool, 1)
+	deleted, failed, err := retention.DeleteUnreferenced(ctx, 10, func(key string) error {
+		if key == "page/stuck.png" {
+			return sql.ErrConnDone
+		}
+		// A check capturing the same content while the object is deleted.
+		go func() {
+			upload, err := blobs.Acquire(ctx, key, 1)
+			if err != nil {
+				t.Error(err)
+			}
+			acquired <- upload
+		}()
+		select {
+		case <-acquired:
+			t.Error("Acquire referenced an object being deleted")
+		case <-time.After(200 * time.Millisecond):
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 || failed != 1 {
+		t.Errorf("deleted = %d, failed = %d; want 1 and 1", deleted, failed)
+	}
+
+	select {
+	case upload := <-acquired:
+		if !upload {
+			t.Error("Acquire after the purge reused the deleted object")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Acquire still blocked after the purge")
+	}
+	if refs, uploaded, ok := blobRow(t, db, "page/old.png"); !ok || refs != 1 || uploaded {
+		t.Errorf("re-acquired row = %d, %v, %v; want a new row awaiting upload", refs, uploaded, ok)
+	}
+	if refs, _, ok := blobRow(t, db, "page/kept.png"); !ok || refs != 1 {
+		t.Errorf("referenced row = %d, %v; want it untouched", refs, ok)
+	}
+	// The object could not be deleted and may be gone in part, so the next
+	// reference uploads it again.
+	if refs, uploaded, ok := blobRow(t, db, "page/stuck.png"); !ok || refs != 0 || uploaded {
+		t.Errorf("undeleted row = %d, %v, %v; want it kept, unreferenced and not uploaded", refs, uploaded, ok)
+	}
+}
@@ -0,0 +1,104 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	snapshotkeys "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/snapshot_keys"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+// legacyRef matches snapshot columns that hold a public URL instead of an
+// object key.
+const legacyRef = `(%[1]s LIKE 'http://%%' OR %[1]s LIKE 'https://%%' OR %[1]s LIKE '/%%')`
+
+type SnapshotKeyPostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewSnapshotKeyPostgresRepository(db *sql.DB, tenant string) *SnapshotKeyPostgresRepository {
+	return &SnapshotKeyPostgresRepository{
+		db:     db,
+		tenant: tenant,
+	}
+}
+
+func (r *SnapshotKeyPostgresRepository) ListLegacyChecks(ctx context.Context, limit int) ([]*entities.Check, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	q := `SELECT ` + checkSelectColumns + ` FROM checks c
+		WHERE ` + legacyColumn("c.screenshot_url") + `
+		OR ` + legacyColumn("c.html_snapshot_url") + `
+		OR ` + legacyColumn("c.document_url") + `
+		ORDER BY c.checked_at ASC
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []*entities.Check
+	for rows.Next() {
+		var check entities.Check
+		if err := scanCheck(rows, &check); err != nil {
+			return nil, err
+		}
+		checks = append(checks, &check)
+	}
+	return checks, rows.Err()
+}
+
+func (r *SnapshotKeyPostgresRepository) UpdateCheckKeys(ctx context.Context, check *entities.Check) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `UPDATE checks SET screenshot_url = NULLIF($1, ''), html_snapshot_url = NULLIF($2, ''), document_url = NULLIF($3, '')
+		WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, q, check.ScreenshotURL, check.HTMLSnapshotURL, check.DocumentURL, check.ID)
+	return err
+}
+
+func (r *SnapshotKeyPostgresRepository) ListLegacyThumbnails(ctx context.Context, limit int) ([]snapshotkeys.Thumbnail, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+
+	q := `SELECT id, thumbnail_url FROM pages WHERE ` + legacyColumn("thumbnail_url") + ` LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, q, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var thumbnails []snapshotkeys.Thumbnail
+	for rows.Next() {
+		var t snapshotkeys.Thumbnail
+		if err := rows.Scan(&t.PageID, &t.URL); err != nil {
+			return nil, err
+		}
+		thumbnails = append(thumbnails, t)
+	}
+	return thumbnails, rows.Err()
+}
+
+func (r *SnapshotKeyPostgresRepository) UpdateThumbnailKey(ctx context.Context, pageID uuid.UUID, key string) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `UPDATE pages SET thumbnail_url = $1 WHERE id = $2`, key, pageID)
+	return err
+}
+
+func legacyColumn(column string) string {
+	return fmt.Sprintf(legacyRef, column)
+}
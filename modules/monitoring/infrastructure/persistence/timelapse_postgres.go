@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
+)
+
+const timelapseColumns = `id, page_id, section_id, range_from, range_to, format, width, date_stamp, highlight_diff, status, frames, COALESCE(result_url, ''), COALESCE(error_message, ''), created_at, completed_at`
+
+type TimelapsePostgresRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+func NewTimelapsePostgresRepository(db *sql.DB, tenant string) *TimelapsePostgresRepository {
+	return &TimelapsePostgresRepository{db: db, tenant: tenant}
+}
+
+func scanTimelapse(row interface{ Scan(...interface{}) error }, job *entities.TimelapseJob) error {
+	return row.Scan(
+		&job.ID, &job.PageID, &job.SectionID, &job.From, &job.To, &job.Format, &job.Width,
+		&job.DateStamp, &job.HighlightDiff, &job.Status, &job.Frames, &job.ResultURL,
+		&job.ErrorMessage, &job.CreatedAt, &job.CompletedAt,
+	)
+}
+
+func (r *TimelapsePostgresRepository) Create(ctx context.Context, job *entities.TimelapseJob) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `INSERT INTO timelapse_jobs (id, page_id, section_id, range_from, range_to, format, width, date_stamp, highlight_diff, status, created_at)
+	      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+	_, err := r.db.ExecContext(ctx, q,
+		job.ID, job.PageID, job.SectionID, job.From, job.To, job.Format, job.Width,
+		job.DateStamp, job.HighlightDiff, job.Status, job.CreatedAt,
+	)
+	return err
+}
+
+func (r *TimelapsePostgresRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.TimelapseJob, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+	var job entities.TimelapseJob
+	q := `SELECT ` + timelapseColumns + ` FROM timelapse_jobs WHERE id = $1`
+	if err := scanTimelapse(r.db.QueryRowContext(ctx, q, id), &job); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListByPageID returns a page's jobs, newest first.
+func (r *TimelapsePostgresRepository) ListByPageID(ctx context.Context, pageID uuid.UUID) ([]*entities.TimelapseJob, error) {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return nil, err
+	}
+	q := `SELECT ` + timelapseColumns + ` FROM timelapse_jobs WHERE page_id = $1 ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, q, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*entities.TimelapseJob
+	for rows.Next() {
+		var job entities.TimelapseJob
+		if err := scanTimelapse(rows, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *TimelapsePostgresRepository) Update(ctx context.Context, job *entities.TimelapseJob) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+	q := `UPDATE timelapse_jobs
+	      SET status = $1, frames = $2, result_url = NULLIF($3, ''), error_message = NULLIF($4, ''), completed_at = $5
+	      WHERE id = $6`
+	_, err := r.db.ExecContext(ctx, q, job.Status, job.Frames, job.ResultURL, job.ErrorMessage, job.CompletedAt, job.ID)
+	return err
+}
@@ -75,6 +75,132 @@ func (r *UsagePostgresRepository) LogUsage(ctx context.Context, pageID, checkID
 	return tx.Commit()
 }
 
+// RefundUsage gives back the checks a check consumed, for checks that never
+// ran to completion.
+func (r *UsagePostgresRepository) RefundUsage(ctx context.Context, checkID uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var consumed int
+	err = tx.QueryRowContext(ctx, `DELETE FROM usage_logs WHERE check_id = $1 RETURNING checks_consumed`, checkID).Scan(&consumed)
+	if err == sql.ErrNoRows {
+		return nil // nothing was logged, or it was refunded already
+	}
+	if err != nil {
+		return err
+	}
+
+	qUpdate := `UPDATE usage_tracking
+		SET checks_used = GREATEST(checks_used - $1, 0)
+		WHERE period_start <= $2 AND period_end >= $2`
+	if _, err := tx.ExecContext(ctx, qUpdate, consumed, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReserveLinkChecks takes up to want link verifications from what the tenant
+// may still run in the current billing period and returns how many it got.
+// Link checks are metered separately from page checks against the plan's
+// link_checks_allowed_monthly. The period row is locked while the budget is
+// taken, so concurrent passes can't spend the same verifications; callers
+// give back what they didn't use with ReleaseLinkChecks.
+func (r *UsagePostgresRepository) ReserveLinkChecks(ctx context.Context, want int) (int, error) {
+	if want <= 0 {
+		return 0, nil
+	}
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return 0, err
+	}
+
+	if err := r.ensureCurrentPeriod(ctx); err != nil {
+		return 0, fmt.Errorf("ensure billing period: %w", err)
+	}
+
+	var allowed int
+	planQuery := `
+		SELECT COALESCE(p.link_checks_allowed_monthly, 0)
+		FROM public.organizations o
+		JOIN public.organization_plans op ON op.organization_id = o.id
+			AND op.status = 'active' AND op.deleted_at IS NULL
+		JOIN public.plans p ON p.id = op.plan_id
+		WHERE o.schema_name = $1
+		ORDER BY op.started_at DESC
+		LIMIT 1
+	`
+	if err := r.db.QueryRowContext(ctx, planQuery, r.tenant).Scan(&allowed); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var used int
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(link_checks_used, 0) FROM usage_tracking
+		WHERE period_start <= $1::date AND period_end >= $1::date
+		ORDER BY period_end DESC LIMIT 1
+		FOR UPDATE`, now).Scan(&used)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	granted := allowed - used
+	if granted <= 0 {
+		return 0, nil
+	}
+	if granted > want {
+		granted = want
+	}
+	q := `UPDATE usage_tracking
+		SET link_checks_used = COALESCE(link_checks_used, 0) + $1
+		WHERE period_start <= $2::date AND period_end >= $2::date`
+	if _, err := tx.ExecContext(ctx, q, granted, now); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return granted, nil
+}
+
+// ReleaseLinkChecks gives back count reserved link verifications that were
+// not used.
+func (r *UsagePostgresRepository) ReleaseLinkChecks(ctx context.Context, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(r.tenant)); err != nil {
+		return err
+	}
+
+	q := `UPDATE usage_tracking
+		SET link_checks_used = GREATEST(COALESCE(link_checks_used, 0) - $1, 0)
+		WHERE period_start <= $2::date AND period_end >= $2::date`
+	_, err := r.db.ExecContext(ctx, q, count, time.Now())
+	return err
+}
+
 // ensureCurrentPeriod checks if a usage_tracking row exists for the current billing period.
 // If not, it creates one based on the org's plan and the plan's started_at anchor day.
 func (r *UsagePostgresRepository) ensureCurrentPeriod(ctx context.Context) error {
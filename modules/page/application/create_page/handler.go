@@ -14,14 +14,26 @@ import (
 	"go.uber.org/zap"
 )
 
+// SectionSuggester suggests sections for a page that was just added.
+type SectionSuggester interface {
+	SuggestInBackground(pageID uuid.UUID, url string)
+}
+
 type CreatePageHandler struct {
-	repo repositories.PageRepository
+	repo      repositories.PageRepository
+	suggester SectionSuggester
 }
 
 func NewCreatePageHandler(repo repositories.PageRepository) *CreatePageHandler {
 	return &CreatePageHandler{repo: repo}
 }
 
+// NewCreatePageHandlerWithSuggester creates a handler that also suggests
+// sections to monitor for every page it adds.
+func NewCreatePageHandlerWithSuggester(repo repositories.PageRepository, suggester SectionSuggester) *CreatePageHandler {
+	return &CreatePageHandler{repo: repo, suggester: suggester}
+}
+
 func (h *CreatePageHandler) Handle(ctx context.Context, req *CreatePageRequest, createdBy uuid.UUID) (*CreatePageResponse, error) {
 	// Create page entity
 	tags := req.Tags
@@ -48,6 +60,10 @@ func (h *CreatePageHandler) Handle(ctx context.Context, req *CreatePageRequest,
 		return nil, err
 	}
 
+	if h.suggester != nil {
+		go h.suggester.SuggestInBackground(page.ID, page.URL)
+	}
+
 	// Create default monitoring config
 	// Note: This should ideally be done in a transaction or a separate service
 	// For now we'll just return the page with default values
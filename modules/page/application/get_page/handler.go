@@ -12,14 +12,28 @@ import (
 	"go.uber.org/zap"
 )
 
+// ThumbnailSigner issues short-lived URLs for private page thumbnails to
+// members of the page's workspace.
+type ThumbnailSigner interface {
+	CanAccessWorkspace(ctx context.Context, workspaceID uuid.UUID) (bool, error)
+	Sign(ctx context.Context, key string) string
+}
+
 type GetPageHandler struct {
-	repo repositories.PageRepository
+	repo   repositories.PageRepository
+	signer ThumbnailSigner
 }
 
 func NewGetPageHandler(repo repositories.PageRepository) *GetPageHandler {
 	return &GetPageHandler{repo: repo}
 }
 
+// NewGetPageHandlerWithSigner returns thumbnails as signed URLs, or none when the
+// user is not a member of the workspace.
+func NewGetPageHandlerWithSigner(repo repositories.PageRepository, signer ThumbnailSigner) *GetPageHandler {
+	return &GetPageHandler{repo: repo, signer: signer}
+}
+
 func (h *GetPageHandler) Handle(ctx context.Context, id uuid.UUID) (*GetPageResponse, error) {
 	page, err := h.repo.GetByID(ctx, id)
 	if err != nil {
@@ -29,12 +43,24 @@ func (h *GetPageHandler) Handle(ctx context.Context, id uuid.UUID) (*GetPageResp
 		return nil, nil
 	}
 
+	thumbnailURL := page.ThumbnailURL
+	if h.signer != nil {
+		member, err := h.signer.CanAccessWorkspace(ctx, page.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		thumbnailURL = ""
+		if member {
+			thumbnailURL = h.signer.Sign(ctx, page.ThumbnailURL)
+		}
+	}
+
 	return &GetPageResponse{
 		ID:                   page.ID,
 		WorkspaceID:          page.WorkspaceID,
 		Name:                 page.Name,
 		URL:                  page.URL,
-		ThumbnailURL:         page.ThumbnailURL,
+		ThumbnailURL:         thumbnailURL,
 		LastCheckedAt:        page.LastCheckedAt,
 		LastChangeDetectedAt: page.LastChangeDetectedAt,
 		CheckCount:           page.CheckCount,
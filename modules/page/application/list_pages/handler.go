@@ -11,14 +11,28 @@ import (
 	"go.uber.org/zap"
 )
 
+// ThumbnailSigner issues short-lived URLs for private page thumbnails to
+// members of the page's workspace.
+type ThumbnailSigner interface {
+	CanAccessWorkspace(ctx context.Context, workspaceID uuid.UUID) (bool, error)
+	Sign(ctx context.Context, key string) string
+}
+
 type ListPagesHandler struct {
-	repo repositories.PageRepository
+	repo   repositories.PageRepository
+	signer ThumbnailSigner
 }
 
 func NewListPagesHandler(repo repositories.PageRepository) *ListPagesHandler {
 	return &ListPagesHandler{repo: repo}
 }
 
+// NewListPagesHandlerWithSigner returns thumbnails as signed URLs, or none when the
+// user is not a member of the workspace.
+func NewListPagesHandlerWithSigner(repo repositories.PageRepository, signer ThumbnailSigner) *ListPagesHandler {
+	return &ListPagesHandler{repo: repo, signer: signer}
+}
+
 func (h *ListPagesHandler) Handle(ctx context.Context, workspaceID uuid.UUID) (*ListPagesResponse, error) {
 	pages, err := h.repo.ListByWorkspace(ctx, workspaceID)
 	if err != nil {
@@ -30,6 +44,20 @@ func (h *ListPagesHandler) Handle(ctx context.Context, workspaceID uuid.UUID) (*
 		pageResponses = append(pageResponses, ToPageResponse(p))
 	}
 
+	if h.signer != nil {
+		member, err := h.signer.CanAccessWorkspace(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pageResponses {
+			if member {
+				pageResponses[i].ThumbnailURL = h.signer.Sign(ctx, pageResponses[i].ThumbnailURL)
+			} else {
+				pageResponses[i].ThumbnailURL = ""
+			}
+		}
+	}
+
 	return &ListPagesResponse{Pages: pageResponses}, nil
 }
 
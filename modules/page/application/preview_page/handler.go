@@ -33,6 +33,8 @@ func (h *PreviewPageHandler) Handle(ctx context.Context, req *PreviewPageRequest
 			Rect:         ElementRect{X: el.Rect.X, Y: el.Rect.Y, W: el.Rect.W, H: el.Rect.H},
 			TextPreview:  el.TextPreview,
 			SemanticRole: el.SemanticRole,
+			Attributes:   el.Attributes,
+			Landmarks:    el.Landmarks,
 		}
 	}
 
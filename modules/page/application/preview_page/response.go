@@ -12,13 +12,17 @@ type PreviewViewport struct {
 	Height int `json:"height"`
 }
 
+// PreviewElement is a section candidate. Attributes and Landmarks, with Tag,
+// TextPreview and Rect, make up the fingerprint saved with a section.
 type PreviewElement struct {
-	Selector     string      `json:"selector"`
-	XPath        string      `json:"xpath"`
-	Tag          string      `json:"tag"`
-	Rect         ElementRect `json:"rect"`
-	TextPreview  string      `json:"text_preview"`
-	SemanticRole string      `json:"semantic_role"`
+	Selector     string            `json:"selector"`
+	XPath        string            `json:"xpath"`
+	Tag          string            `json:"tag"`
+	Rect         ElementRect       `json:"rect"`
+	TextPreview  string            `json:"text_preview"`
+	SemanticRole string            `json:"semantic_role"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Landmarks    []string          `json:"landmarks,omitempty"`
 }
 
 type ElementRect struct {
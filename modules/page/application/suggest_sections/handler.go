@@ -0,0 +1,113 @@
+package suggestsections
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	monentities "github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monrepositories "github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/repositories"
+	monservices "github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/services"
+	"github.com/jcsoftdev/pulzifi-back/modules/page/domain/repositories"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// backgroundTimeout bounds a suggestion run started when a page is added.
+const backgroundTimeout = 3 * time.Minute
+
+// SuggestSectionsHandler previews a page and stores the regions worth
+// monitoring as section suggestions.
+type SuggestSectionsHandler struct {
+	extractorClient *extractor.HTTPClient
+	pageRepo        repositories.PageRepository
+	suggestionRepo  monrepositories.SectionSuggestionRepository
+}
+
+func NewSuggestSectionsHandler(extractorClient *extractor.HTTPClient, pageRepo repositories.PageRepository, suggestionRepo monrepositories.SectionSuggestionRepository) *SuggestSectionsHandler {
+	return &SuggestSectionsHandler{
+		extractorClient: extractorClient,
+		pageRepo:        pageRepo,
+		suggestionRepo:  suggestionRepo,
+	}
+}
+
+// Handle replaces the page's suggestions with ones ranked from a fresh preview.
+func (h *SuggestSectionsHandler) Handle(ctx context.Context, pageID uuid.UUID, url string) ([]*monentities.SectionSuggestion, error) {
+	preview, err := h.extractorClient.Preview(ctx, url, true)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]monservices.SectionCandidate, len(preview.Elements))
+	for i, el := range preview.Elements {
+		candidates[i] = monservices.SectionCandidate{
+			Selector:     el.Selector,
+			XPath:        el.XPath,
+			Tag:          el.Tag,
+			SemanticRole: el.SemanticRole,
+			Text:         el.TextPreview,
+			Rect:         monentities.SectionRect{X: el.Rect.X, Y: el.Rect.Y, W: el.Rect.W, H: el.Rect.H},
+			Attributes:   el.Attributes,
+			Landmarks:    el.Landmarks,
+		}
+	}
+	suggestions := monservices.SuggestSections(pageID, candidates, preview.Viewport.Width)
+
+	if err := h.suggestionRepo.ReplaceForPage(ctx, pageID, suggestions); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// SuggestInBackground runs Handle for a page that was just added. Failures
+// are only logged; the user can still pick sections by hand.
+func (h *SuggestSectionsHandler) SuggestInBackground(pageID uuid.UUID, url string) {
+	ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+	defer cancel()
+
+	suggestions, err := h.Handle(ctx, pageID, url)
+	if err != nil {
+		logger.Warn("Failed to suggest sections for new page", zap.String("page_id", pageID.String()), zap.Error(err))
+		return
+	}
+	logger.Info("Suggested sections for new page", zap.String("page_id", pageID.String()), zap.Int("count", len(suggestions)))
+}
+
+// HandleHTTP is the HTTP handler for POST /pages/{id}/suggest-sections
+func (h *SuggestSectionsHandler) HandleHTTP(w http.ResponseWriter, r *http.Request) {
+	pageID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid page id", http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.pageRepo.GetByID(r.Context(), pageID)
+	if err != nil {
+		logger.Error("Failed to load page", zap.Error(err))
+		http.Error(w, "failed to load page", http.StatusInternalServerError)
+		return
+	}
+	if page == nil {
+		http.Error(w, "page not found", http.StatusNotFound)
+		return
+	}
+
+	suggestions, err := h.Handle(r.Context(), pageID, page.URL)
+	if err != nil {
+		logger.Error("Failed to suggest sections", zap.Error(err))
+		http.Error(w, "failed to suggest sections", http.StatusInternalServerError)
+		return
+	}
+
+	resp := &SuggestSectionsResponse{Count: len(suggestions), Kinds: make([]string, len(suggestions))}
+	for i, s := range suggestions {
+		resp.Kinds[i] = s.Kind
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,8 @@
+package suggestsections
+
+// SuggestSectionsResponse reports how many sections were suggested. The
+// suggestions themselves are listed by the monitoring sections API.
+type SuggestSectionsResponse struct {
+	Count int      `json:"count"`
+	Kinds []string `json:"kinds"`
+}
@@ -8,14 +8,18 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
 	bulkdeletepages "github.com/jcsoftdev/pulzifi-back/modules/page/application/bulk_delete_pages"
 	createpage "github.com/jcsoftdev/pulzifi-back/modules/page/application/create_page"
 	deletepage "github.com/jcsoftdev/pulzifi-back/modules/page/application/delete_page"
 	getpage "github.com/jcsoftdev/pulzifi-back/modules/page/application/get_page"
 	listpages "github.com/jcsoftdev/pulzifi-back/modules/page/application/list_pages"
+	suggestsections "github.com/jcsoftdev/pulzifi-back/modules/page/application/suggest_sections"
 	updatepage "github.com/jcsoftdev/pulzifi-back/modules/page/application/update_page"
 	"github.com/jcsoftdev/pulzifi-back/modules/page/infrastructure/persistence"
 	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	snapshotstorage "github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/storage"
+	"github.com/jcsoftdev/pulzifi-back/shared/config"
 	"github.com/jcsoftdev/pulzifi-back/shared/logger"
 	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
 	"github.com/jcsoftdev/pulzifi-back/shared/router"
@@ -26,6 +30,7 @@ import (
 type Module struct {
 	db              *sql.DB
 	extractorClient *extractor.HTTPClient
+	signer          *snapshotstorage.URLSigner
 }
 
 // NewModule creates a new instance of the Page module
@@ -42,10 +47,16 @@ func NewModuleWithDB(db *sql.DB) router.ModuleRegisterer {
 
 // NewModuleWithExtractor creates a new instance with database and extractor client
 func NewModuleWithExtractor(db *sql.DB, extractorClient *extractor.HTTPClient) router.ModuleRegisterer {
-	return &Module{
+	m := &Module{
 		db:              db,
 		extractorClient: extractorClient,
 	}
+	if objectStorage, err := snapshotstorage.NewObjectStorage(config.Load()); err != nil {
+		logger.Warn("Object storage unavailable, page thumbnails will not be served", zap.Error(err))
+	} else {
+		m.signer = snapshotstorage.NewURLSigner(objectStorage, db)
+	}
+	return m
 }
 
 // ModuleName returns the name of the module
@@ -70,6 +81,7 @@ func (m *Module) RegisterHTTPRoutes(router chi.Router) {
 			r.Get("/{id}", m.handleGetPage)
 			r.Put("/{id}", m.handleUpdatePage)
 			r.Delete("/{id}", m.handleDeletePage)
+			r.Post("/{id}/suggest-sections", m.handleSuggestSections)
 		})
 	})
 }
@@ -208,6 +220,33 @@ func (m *Module) handleCreatePage(w http.ResponseWriter, r *http.Request) {
 
 	// Use real handler
 	handler := createpage.NewCreatePageHandler(repo)
+	if m.extractorClient != nil {
+		suggester := suggestsections.NewSuggestSectionsHandler(m.extractorClient, repo, monPersistence.NewSectionSuggestionPostgresRepository(m.db, tenant))
+		handler = createpage.NewCreatePageHandlerWithSuggester(repo, suggester)
+	}
+	handler.HandleHTTP(w, r)
+}
+
+// handleSuggestSections re-runs section suggestions for a page
+// @Summary Suggest Sections
+// @Description Preview the page and replace its section suggestions with freshly ranked ones
+// @Tags pages
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Page ID"
+// @Success 200 {object} suggestsections.SuggestSectionsResponse
+// @Failure 404 {object} map[string]string
+// @Router /pages/{id}/suggest-sections [post]
+func (m *Module) handleSuggestSections(w http.ResponseWriter, r *http.Request) {
+	if m.db == nil || m.extractorClient == nil {
+		http.Error(w, "Section suggestions not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	tenant := middleware.GetTenantFromContext(r.Context())
+	repo := persistence.NewPagePostgresRepository(m.db, tenant)
+	suggestionRepo := monPersistence.NewSectionSuggestionPostgresRepository(m.db, tenant)
+	handler := suggestsections.NewSuggestSectionsHandler(m.extractorClient, repo, suggestionRepo)
 	handler.HandleHTTP(w, r)
 }
 
@@ -240,6 +279,9 @@ func (m *Module) handleListPages(w http.ResponseWriter, r *http.Request) {
 
 	// Use real handler
 	handler := listpages.NewListPagesHandler(repo)
+	if m.signer != nil {
+		handler = listpages.NewListPagesHandlerWithSigner(repo, m.signer)
+	}
 	handler.HandleHTTP(w, r)
 }
 
@@ -261,6 +303,9 @@ func (m *Module) handleGetPage(w http.ResponseWriter, r *http.Request) {
 	tenant := middleware.GetTenantFromContext(r.Context())
 	repo := persistence.NewPagePostgresRepository(m.db, tenant)
 	handler := getpage.NewGetPageHandler(repo)
+	if m.signer != nil {
+		handler = getpage.NewGetPageHandlerWithSigner(repo, m.signer)
+	}
 	handler.HandleHTTP(w, r)
 }
 
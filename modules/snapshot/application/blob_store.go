@@ -0,0 +1,135 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	imagecompare "github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/services"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// blobKey is the object key of a capture: the page folder and the SHA-256 of
+// the content, so identical captures of a page map to the same object.
+func blobKey(pageID uuid.UUID, contentHash, ext string) string {
+	return fmt.Sprintf("%s/%s%s", pageID, contentHash, ext)
+}
+
+// hashBytes returns the hex SHA-256 of data.
+func hashBytes(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// blobRepository counts the checks referencing each stored capture.
+type blobRepository interface {
+	Acquire(ctx context.Context, key string, size int64) (bool, error)
+	MarkUploaded(ctx context.Context, key string) error
+	Release(ctx context.Context, key string) error
+}
+
+// blobRefs holds the references taken while capturing one check. They belong
+// to the check once its row is written with the keys; when that never
+// happens, release drops them so the objects can be purged.
+type blobRefs struct {
+	repo blobRepository
+	keys []string
+}
+
+func (s *SnapshotWorker) newBlobRefs(schemaName string) *blobRefs {
+	return &blobRefs{repo: monPersistence.NewSnapshotBlobPostgresRepository(s.db, schemaName)}
+}
+
+// release drops every reference taken so far.
+func (r *blobRefs) release(ctx context.Context) {
+	for _, key := range r.keys {
+		if err := r.repo.Release(ctx, key); err != nil {
+			logger.Warn("Failed to release blob reference", zap.String("key", key), zap.Error(err))
+		}
+	}
+	r.keys = nil
+}
+
+// clearCaptureKeys removes the object keys from a check whose references were
+// released.
+func clearCaptureKeys(check *entities.Check) {
+	check.ScreenshotURL, check.HTMLSnapshotURL, check.DocumentURL = "", "", ""
+	check.ThumbnailURL, check.PreviewURL, check.ArchiveURL = "", "", ""
+}
+
+// storeBlob stores a capture under its content hash and adds a reference to
+// it to refs. The object is uploaded unless an earlier upload of it was
+// confirmed, so a check never points at an object that is still being
+// uploaded or whose upload failed.
+func (s *SnapshotWorker) storeBlob(ctx context.Context, refs *blobRefs, pageID uuid.UUID, data []byte, contentHash, ext, contentType string) (string, error) {
+	key := blobKey(pageID, contentHash, ext)
+
+	upload, err := refs.repo.Acquire(ctx, key, int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to reference blob: %w", err)
+	}
+	if upload {
+		if _, err := s.objectStorage.Upload(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+			if relErr := refs.repo.Release(ctx, key); relErr != nil {
+				logger.Warn("Failed to release blob after upload error", zap.String("key", key), zap.Error(relErr))
+			}
+			return "", err
+		}
+		if err := refs.repo.MarkUploaded(ctx, key); err != nil {
+			// The object is stored; later references upload it once more.
+			logger.Warn("Failed to mark blob uploaded", zap.String("key", key), zap.Error(err))
+		}
+	}
+	refs.keys = append(refs.keys, key)
+	return key, nil
+}
+
+// storeDerivatives stores the thumbnail and preview of a screenshot on the
+// check. Failures are logged and leave the check without derivatives; the
+// original screenshot is still stored.
+func (s *SnapshotWorker) storeDerivatives(ctx context.Context, refs *blobRefs, check *entities.Check, imgBytes []byte) {
+	derivatives, err := imagecompare.EncodeDerivatives(imgBytes, s.derivativeFormat, s.derivativeQuality, imagecompare.ThumbnailSpec, imagecompare.PreviewSpec)
+	if err != nil {
+		logger.Warn("Failed to render screenshot derivatives", zap.String("check_id", check.ID.String()), zap.Error(err))
+		return
+	}
+	keys := make([]string, len(derivatives))
+	for i, data := range derivatives {
+		keys[i], err = s.storeBlob(ctx, refs, check.PageID, data, hashBytes(data), s.derivativeFormat.Extension(), s.derivativeFormat.ContentType())
+		if err != nil {
+			logger.Warn("Failed to upload screenshot derivative", zap.String("check_id", check.ID.String()), zap.Error(err))
+		}
+	}
+	check.ThumbnailURL, check.PreviewURL = keys[0], keys[1]
+}
+
+// storeArchive stores the MHTML archive the extractor recorded for the check.
+// An archive that fails to upload is logged; the check keeps its other
+// captures.
+func (s *SnapshotWorker) storeArchive(ctx context.Context, refs *blobRefs, check *entities.Check, mhtml string) {
+	if mhtml == "" {
+		return
+	}
+	data := []byte(mhtml)
+	key, err := s.storeBlob(ctx, refs, check.PageID, data, hashBytes(data), ".mhtml", "multipart/related")
+	if err != nil {
+		logger.Warn("Failed to upload page archive", zap.String("check_id", check.ID.String()), zap.Error(err))
+		return
+	}
+	check.ArchiveURL = key
+}
+
+// pageThumbnail is the object shown on the page's card: the check's thumbnail,
+// or its full screenshot when no thumbnail was stored.
+func pageThumbnail(check *entities.Check) string {
+	if check.ThumbnailURL != "" {
+		return check.ThumbnailURL
+	}
+	return check.ScreenshotURL
+}
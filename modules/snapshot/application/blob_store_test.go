@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+)
+
+type fakeBlob struct {
+	refs     int
+	uploaded bool
+}
+
+type fakeBlobRepo map[string]*fakeBlob
+
+func (r fakeBlobRepo) Acquire(_ context.Context, key string, _ int64) (bool, error) {
+	b, ok := r[key]
+	if !ok {
+		b = &fakeBlob{}
+		r[key] = b
+	}
+	b.refs++
+	return !b.uploaded, nil
+}
+
+func (r fakeBlobRepo) MarkUploaded(_ context.Context, key string) error {
+	r[key].uploaded = true
+	return nil
+}
+
+func (r fakeBlobRepo) Release(_ context.Context, key string) error {
+	if b := r[key]; b.refs > 0 {
+		b.refs--
+	}
+	return nil
+}
+
+type fakeObjectStorage struct {
+	uploads []string
+	err     error
+}
+
+func (s *fakeObjectStorage) Upload(_ context.Context, key string, r io.Reader, _ int64, _ string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	io.Copy(io.Discard, r)
+	s.uploads = append(s.uploads, key)
+	return key, nil
+}
+
+func (s *fakeObjectStorage) EnsureBucket(context.Context) error                  { return nil }
+func (s *fakeObjectStorage) Download(context.Context, string) ([]byte, error)    { return nil, nil }
+func (s *fakeObjectStorage) Delete(context.Context, string) error                { return nil }
+func (s *fakeObjectStorage) List(context.Context, string) ([]string, error)      { return nil, nil }
+func (s *fakeObjectStorage) Adopt(_ context.Context, url string) (string, error) { return url, nil }
+func (s *fakeObjectStorage) SignedURL(_ context.Context, key string, _ time.Duration) (string, error) {
+	return key, nil
+}
+
+func TestStoreBlob_SharesConfirmedUploads(t *testing.T) {
+	repo := fakeBlobRepo{}
+	storage := &fakeObjectStorage{}
+	s := &SnapshotWorker{objectStorage: storage}
+	pageID := uuid.New()
+	data := []byte("<html>same</html>")
+
+	first := &blobRefs{repo: repo}
+	key, err := s.storeBlob(context.Background(), first, pageID, data, hashBytes(data), ".html", "text/html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second := &blobRefs{repo: repo}
+	if again, err := s.storeBlob(context.Background(), second, pageID, data, hashBytes(data), ".html", "text/html"); err != nil || again != key {
+		t.Fatalf("second store = %q, %v; want %q", again, err, key)
+	}
+
+	if len(storage.uploads) != 1 {
+		t.Errorf("uploads = %v, want one", storage.uploads)
+	}
+	if b := repo[key]; b.refs != 2 || !b.uploaded {
+		t.Errorf("blob = %+v, want 2 references to an uploaded object", b)
+	}
+	if len(first.keys) != 1 || len(second.keys) != 1 {
+		t.Errorf("refs = %v / %v, want one key each", first.keys, second.keys)
+	}
+}
+
+func TestStoreBlob_UploadsUnconfirmedBlob(t *testing.T) {
+	pageID := uuid.New()
+	data := []byte("<html>racing</html>")
+	key := blobKey(pageID, hashBytes(data), ".html")
+	// Another check referenced the blob but has not finished uploading it,
+	// or its upload failed.
+	repo := fakeBlobRepo{key: {refs: 1}}
+	storage := &fakeObjectStorage{}
+	s := &SnapshotWorker{objectStorage: storage}
+
+	if _, err := s.storeBlob(context.Background(), &blobRefs{repo: repo}, pageID, data, hashBytes(data), ".html", "text/html"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(storage.uploads) != 1 || !repo[key].uploaded {
+		t.Errorf("uploads = %v, blob = %+v; want the object uploaded and confirmed", storage.uploads, repo[key])
+	}
+}
+
+func TestStoreBlob_UploadFailureDropsReference(t *testing.T) {
+	repo := fakeBlobRepo{}
+	s := &SnapshotWorker{objectStorage: &fakeObjectStorage{err: errors.New("bucket unavailable")}}
+	refs := &blobRefs{repo: repo}
+	data := []byte("png")
+
+	if _, err := s.storeBlob(context.Background(), refs, uuid.New(), data, hashBytes(data), ".png", "image/png"); err == nil {
+		t.Fatal("expected the upload error")
+	}
+	for key, b := range repo {
+		if b.refs != 0 || b.uploaded {
+			t.Errorf("%s = %+v, want no reference and no upload", key, b)
+		}
+	}
+	if len(refs.keys) != 0 {
+		t.Errorf("refs = %v, want none", refs.keys)
+	}
+}
+
+func TestBlobRefs_Release(t *testing.T) {
+	repo := fakeBlobRepo{}
+	s := &SnapshotWorker{objectStorage: &fakeObjectStorage{}}
+	refs := &blobRefs{repo: repo}
+	check := &entities.Check{PageID: uuid.New()}
+
+	img, page := []byte("png"), []byte("<html></html>")
+	check.ScreenshotURL, _ = s.storeBlob(context.Background(), refs, check.PageID, img, hashBytes(img), ".png", "image/png")
+	check.HTMLSnapshotURL, _ = s.storeBlob(context.Background(), refs, check.PageID, page, hashBytes(page), ".html", "text/html")
+
+	// The check row is never written, e.g. the capture failed afterwards.
+	refs.release(context.Background())
+	clearCaptureKeys(check)
+
+	for key, b := range repo {
+		if b.refs != 0 {
+			t.Errorf("%s has %d references, want 0", key, b.refs)
+		}
+	}
+	if len(refs.keys) != 0 || check.ScreenshotURL != "" || check.HTMLSnapshotURL != "" {
+		t.Errorf("refs = %v, check = %+v; want both cleared", refs.keys, check)
+	}
+	refs.release(context.Background())
+	if b := repo[blobKey(check.PageID, hashBytes(img), ".png")]; b.refs != 0 {
+		t.Errorf("second release dropped references again: %+v", b)
+	}
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	alertentities "github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+	alertPersistence "github.com/jcsoftdev/pulzifi-back/modules/alert/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/email/infrastructure/templates"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// linkCheckTimeout bounds the link requests of one broken-link pass.
+	linkCheckTimeout = 2 * time.Minute
+	// linkPassTimeout bounds a whole pass, including its database writes.
+	linkPassTimeout = linkCheckTimeout + time.Minute
+	// maxLinkPasses bounds how many pages have their links verified at once,
+	// so a burst of checks can't multiply the link checker's request budget.
+	maxLinkPasses = 4
+)
+
+// checkBrokenLinksAsync runs a broken-link pass in the background once one of
+// the maxLinkPasses slots frees up, so link verification never holds a check
+// worker after the check has been reported.
+func (s *SnapshotWorker) checkBrokenLinksAsync(schemaName string, checkRepo *monPersistence.CheckPostgresRepository, check *entities.Check, pageURL, pageHTML string) {
+	s.linkPasses <- struct{}{}
+	defer func() { <-s.linkPasses }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), linkPassTimeout)
+	defer cancel()
+	s.checkBrokenLinks(ctx, schemaName, checkRepo, check, pageURL, pageHTML)
+}
+
+// checkBrokenLinks verifies the links of a successful check's HTML, updates the
+// page's open broken links and raises a "broken_links" alert for links that
+// broke since the last pass. Verifications are metered against the tenant's
+// link check quota; cached results are free.
+func (s *SnapshotWorker) checkBrokenLinks(ctx context.Context, schemaName string, checkRepo *monPersistence.CheckPostgresRepository, check *entities.Check, pageURL, pageHTML string) {
+	if s.linkChecker == nil || pageHTML == "" {
+		return
+	}
+
+	links := sharedHTML.ExtractLinks(pageHTML, pageURL)
+	if len(links) == 0 {
+		return
+	}
+
+	urls := make([]string, len(links))
+	for i, l := range links {
+		urls[i] = l.URL
+	}
+
+	// The budget is taken from the quota before any request goes out, so
+	// passes running at once can't each spend the whole remainder; what a
+	// pass didn't need, thanks to cached results, goes back afterwards.
+	usageRepo := monPersistence.NewUsagePostgresRepository(s.db, schemaName)
+	budget, err := usageRepo.ReserveLinkChecks(ctx, len(urls))
+	if err != nil {
+		logger.Error("Failed to reserve link check quota", zap.Error(err), zap.String("page_id", check.PageID.String()))
+		return
+	}
+
+	linkCtx, cancel := context.WithTimeout(ctx, linkCheckTimeout)
+	results, fetched := s.linkChecker.CheckAll(linkCtx, urls, budget)
+	cancel()
+
+	if err := usageRepo.ReleaseLinkChecks(ctx, budget-fetched); err != nil {
+		logger.Error("Failed to release unused link check quota", zap.Error(err), zap.String("page_id", check.PageID.String()))
+	}
+	if len(results) < len(urls) {
+		logger.Warn("Link check incomplete (quota exhausted or timed out)",
+			zap.String("page_id", check.PageID.String()),
+			zap.Int("links", len(urls)),
+			zap.Int("verified", len(results)))
+	}
+
+	linkRepo := monPersistence.NewBrokenLinkPostgresRepository(s.db, schemaName)
+	open, err := linkRepo.ListOpenByPage(ctx, check.PageID)
+	if err != nil {
+		logger.Error("Failed to load open broken links", zap.Error(err), zap.String("page_id", check.PageID.String()))
+		return
+	}
+	openByURL := make(map[string]*entities.BrokenLink, len(open))
+	for _, l := range open {
+		openByURL[l.URL] = l
+	}
+
+	var newlyBroken []*entities.BrokenLink
+	var resolved []uuid.UUID
+	for _, link := range links {
+		result, ok := results[link.URL]
+		if !ok {
+			continue // not verified this pass; leave its state untouched
+		}
+		existing := openByURL[link.URL]
+
+		if !result.Broken {
+			if existing != nil {
+				resolved = append(resolved, existing.ID)
+			}
+			continue
+		}
+
+		if existing != nil {
+			if err := linkRepo.MarkSeen(ctx, existing.ID, check.ID, result.StatusCode, result.Error); err != nil {
+				logger.Error("Failed to update broken link", zap.Error(err), zap.String("url", link.URL))
+			}
+			continue
+		}
+
+		broken := entities.NewBrokenLink(check.PageID, check.ID, link.URL, link.AnchorText, link.Location, result.StatusCode, result.Error)
+		if err := linkRepo.Create(ctx, broken); err != nil {
+			logger.Error("Failed to record broken link", zap.Error(err), zap.String("url", link.URL))
+			continue
+		}
+		newlyBroken = append(newlyBroken, broken)
+	}
+
+	if err := linkRepo.Resolve(ctx, resolved); err != nil {
+		logger.Error("Failed to resolve fixed links", zap.Error(err), zap.String("page_id", check.PageID.String()))
+	}
+
+	logger.Info("Broken link check completed",
+		zap.String("page_id", check.PageID.String()),
+		zap.Int("links", len(links)),
+		zap.Int("requests", fetched),
+		zap.Int("newly_broken", len(newlyBroken)),
+		zap.Int("resolved", len(resolved)))
+
+	if len(newlyBroken) == 0 {
+		return
+	}
+
+	if !check.ChangeDetected {
+		check.ChangeDetected = true
+		check.ChangeType = "broken_links"
+		if err := checkRepo.Update(ctx, check); err != nil {
+			logger.Error("Failed to update check with broken links", zap.Error(err), zap.String("check_id", check.ID.String()))
+		}
+		s.notifyCheckDone(check)
+	}
+
+	s.createBrokenLinksAlert(ctx, schemaName, check, pageURL, newlyBroken)
+}
+
+// createBrokenLinksAlert records a "broken_links" alert carrying the anchor
+// text and location of each newly broken link, then notifies subscribers.
+func (s *SnapshotWorker) createBrokenLinksAlert(ctx context.Context, schemaName string, check *entities.Check, pageURL string, links []*entities.BrokenLink) {
+	workspaceID, ok := s.pageWorkspaceID(ctx, schemaName, check.PageID)
+	if !ok {
+		return
+	}
+
+	rows := make([]map[string]interface{}, 0, len(links))
+	for _, l := range links {
+		rows = append(rows, map[string]interface{}{
+			"url":         l.URL,
+			"anchor_text": l.AnchorText,
+			"location":    l.Location,
+			"status_code": l.StatusCode,
+			"error":       l.ErrorMessage,
+		})
+	}
+	summary := fmt.Sprintf("%d newly broken link(s) found", len(links))
+
+	alert := alertentities.NewAlert(workspaceID, check.PageID, check.ID, "broken_links", "Broken Links Detected", summary)
+	alert.ChangeSummary = summary
+	alert.Metadata = alertentities.Metadata{"broken_links": rows}
+
+	alertRepo := alertPersistence.NewAlertPostgresRepository(s.db, schemaName)
+	if err := alertRepo.Create(ctx, alert); err != nil {
+		logger.Error("Failed to create broken links alert", zap.Error(err))
+	}
+
+	go s.sendBrokenLinksEmails(schemaName, check, pageURL, links)
+	go s.dispatchWebhooks(schemaName, check, pageURL, summary)
+}
+
+func (s *SnapshotWorker) sendBrokenLinksEmails(schemaName string, check *entities.Check, pageURL string, links []*entities.BrokenLink) {
+	if s.emailProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows := make([]templates.BrokenLink, 0, len(links))
+	for _, l := range links {
+		status := l.ErrorMessage
+		if l.StatusCode > 0 {
+			status = "HTTP " + strconv.Itoa(l.StatusCode)
+		}
+		rows = append(rows, templates.BrokenLink{URL: l.URL, AnchorText: l.AnchorText, Location: l.Location, Status: status})
+	}
+	dashboardURL := fmt.Sprintf("%s/workspaces", s.frontendURL)
+	subject, html := templates.BrokenLinksNotification(pageURL, rows, dashboardURL)
+
+	s.sendEmailToSubscribers(ctx, schemaName, check.PageID, "broken_links", subject, html)
+}
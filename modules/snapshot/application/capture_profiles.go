@@ -0,0 +1,198 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	imagecompare "github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/services"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// profileProxy is the proxy route profile captures go through, starting from
+// the proxy the default capture ended up using. A profile that had to retry
+// through another proxy switches the remaining profiles to it.
+type profileProxy struct {
+	repo  *monPersistence.ProxyPostgresRepository
+	route entities.ProxyRoute
+	proxy *entities.Proxy
+}
+
+// runCaptureProfiles captures the page once per capture profile and stores
+// each result as a child check of parent, compared only against earlier
+// checks of the same profile. When a profile changed and the default capture
+// did not, the parent is marked changed and a single aggregated alert is
+// raised, the same way section changes are reported.
+func (s *SnapshotWorker) runCaptureProfiles(
+	ctx context.Context,
+	checkRepo *monPersistence.CheckPostgresRepository,
+	schemaName string,
+	parent *entities.Check,
+	targetURL string,
+	baseOpts extractor.ExtractOptions,
+	pp profileProxy,
+	profiles []entities.CaptureProfile,
+	enabledAlertConditions []string,
+) {
+	var changeSummaries []string
+	anyChanged := false
+
+	for _, profile := range profiles {
+		changed, summary := s.captureProfile(ctx, checkRepo, schemaName, parent, targetURL, &baseOpts, &pp, profile)
+		if !changed {
+			continue
+		}
+		anyChanged = true
+		if summary != "" {
+			changeSummaries = append(changeSummaries, profile.Name+": "+summary)
+		}
+	}
+
+	if !anyChanged || parent.ChangeDetected {
+		return
+	}
+
+	parent.ChangeDetected = true
+	parent.ChangeType = "content"
+	if err := checkRepo.Update(ctx, parent); err != nil {
+		logger.Error("Failed to update parent check after profile changes", zap.Error(err), zap.String("check_id", parent.ID.String()))
+		return
+	}
+	s.notifyCheckDone(parent)
+
+	if err := s.updatePageSnapshotMetadata(ctx, schemaName, parent.PageID, pageThumbnail(parent), true); err != nil {
+		logger.Error("Failed to update page snapshot metadata", zap.Error(err), zap.String("page_id", parent.PageID.String()))
+	}
+
+	if sliceContains(enabledAlertConditions, "any_changes") {
+		s.createAlert(ctx, schemaName, parent, targetURL, strings.Join(changeSummaries, "; "))
+	}
+}
+
+// captureProfile runs one profile extraction and records it as a child check.
+// Returns whether the profile's capture changed since its previous success.
+func (s *SnapshotWorker) captureProfile(
+	ctx context.Context,
+	checkRepo *monPersistence.CheckPostgresRepository,
+	schemaName string,
+	parent *entities.Check,
+	targetURL string,
+	baseOpts *extractor.ExtractOptions,
+	pp *profileProxy,
+	profile entities.CaptureProfile,
+) (bool, string) {
+	profileCheck := entities.NewCheck(parent.PageID, "success", false)
+	profileCheck.ParentCheckID = &parent.ID
+	profileCheck.ProfileName = profile.Name
+	profileCheck.ProxyID = parent.ProxyID
+	profileCheck.FetchEngine = entities.FetchEngineBrowser
+
+	refs := s.newBlobRefs(schemaName)
+	fail := func(msg string) (bool, string) {
+		refs.release(ctx)
+		clearCaptureKeys(profileCheck)
+		profileCheck.Status = "error"
+		profileCheck.ErrorMessage = msg
+		if err := checkRepo.Create(ctx, profileCheck); err != nil {
+			logger.Error("Failed to create profile check", zap.String("profile", profile.Name), zap.Error(err))
+			return false, ""
+		}
+		s.notifyCheckDone(profileCheck)
+		return false, ""
+	}
+
+	opts := *baseOpts
+	opts.Profile = toExtractorProfile(profile)
+	opts.Archive = false // the default capture's archive is the page's record
+
+	// Profiles pinned to a region are exactly the captures that must not
+	// leave the route, so they get the same health tracking and retry.
+	start := time.Now()
+	res, used, err := s.extractThroughProxy(ctx, pp.repo, schemaName, pp.route, pp.proxy, profileCheck, targetURL, &opts)
+	pp.proxy, baseOpts.Proxy = used, opts.Proxy
+	profileCheck.DurationMs = int(time.Since(start).Milliseconds())
+	if err != nil {
+		logger.Warn("Profile capture failed", zap.String("profile", profile.Name), zap.String("url", targetURL), zap.Error(err))
+		return fail(err.Error())
+	}
+
+	imgBytes := res.Screenshot
+	if len(imgBytes) == 0 {
+		return fail("extractor returned no screenshot")
+	}
+
+	screenshotHash := imagecompare.HashScreenshot(imgBytes)
+	imgKey, err := s.storeBlob(ctx, refs, parent.PageID, imgBytes, screenshotHash, ".png", "image/png")
+	if err != nil {
+		return fail(fmt.Sprintf("failed to upload screenshot: %v", err))
+	}
+	htmlKey, err := s.storeBlob(ctx, refs, parent.PageID, []byte(res.HTML), hashBytes([]byte(res.HTML)), ".html", "text/html")
+	if err != nil {
+		return fail(fmt.Sprintf("failed to upload html snapshot: %v", err))
+	}
+
+	contentHash := sha256.Sum256([]byte(sharedHTML.ExtractText(res.HTML)))
+	profileCheck.ScreenshotURL = imgKey
+	profileCheck.HTMLSnapshotURL = htmlKey
+	profileCheck.ContentHash = hex.EncodeToString(contentHash[:])
+	profileCheck.ContentBlockHash = sharedHTML.HashContentBlocks(sharedHTML.ExtractContentBlocks(res.HTML))
+	profileCheck.ScreenshotHash = screenshotHash
+	s.storeDerivatives(ctx, refs, profileCheck, imgBytes)
+
+	var changeSummary string
+	prev, err := checkRepo.GetPreviousSuccessfulByProfile(ctx, parent.PageID, profile.Name, uuid.Nil)
+	if err != nil {
+		logger.Warn("Failed to load previous profile check", zap.String("profile", profile.Name), zap.Error(err))
+	}
+	if prev != nil {
+		changeDetected, summary, contentDiff := s.detectChange(ctx, prev, profileCheck, imgBytes, targetURL, res.HTML, detectOptions{})
+		if changeDetected {
+			profileCheck.ChangeDetected = true
+			profileCheck.ChangeType = "content"
+			profileCheck.VisionChangeSummary = summary
+			if contentDiff != nil && contentDiff.HasChanges {
+				if diffJSON, err := json.Marshal(contentDiff); err == nil {
+					profileCheck.ContentDiffJSON = string(diffJSON)
+				}
+			}
+			changeSummary = summary
+		}
+	}
+
+	if err := checkRepo.Create(ctx, profileCheck); err != nil {
+		logger.Error("Failed to create profile check", zap.String("profile", profile.Name), zap.Error(err))
+		refs.release(ctx)
+		return false, ""
+	}
+	s.notifyCheckDone(profileCheck)
+
+	return profileCheck.ChangeDetected, changeSummary
+}
+
+func toExtractorProfile(p entities.CaptureProfile) *extractor.CaptureProfile {
+	out := &extractor.CaptureProfile{
+		Name:              p.Name,
+		ViewportWidth:     p.ViewportWidth,
+		ViewportHeight:    p.ViewportHeight,
+		DeviceScaleFactor: p.DeviceScaleFactor,
+		Mobile:            p.Mobile,
+		UserAgent:         p.UserAgent,
+		Locale:            p.Locale,
+		Timezone:          p.Timezone,
+	}
+	if p.Geolocation != nil {
+		geo := extractor.Geolocation(*p.Geolocation)
+		out.Geolocation = &geo
+	}
+	return out
+}
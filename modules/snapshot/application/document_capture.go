@@ -0,0 +1,202 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	imagecompare "github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/services"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/shared/document"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"github.com/jcsoftdev/pulzifi-back/shared/netguard"
+	"go.uber.org/zap"
+)
+
+const (
+	documentProbeTimeout   = 10 * time.Second
+	documentFetchTimeout   = 2 * time.Minute
+	documentThumbnailWidth = 800
+	documentUserAgent      = "Mozilla/5.0 (compatible; PulzifiMonitor/1.0)"
+)
+
+// errNotDocument is returned by captureDocument when a URL that looked like a
+// document (e.g. ends in .pdf) actually serves a web page, such as a login wall.
+var errNotDocument = errors.New("url did not return a document")
+
+// documentHTTPClient makes direct document and page requests. Page URLs are
+// tenant input, so it refuses to dial private addresses.
+var documentHTTPClient = &http.Client{
+	Timeout: documentFetchTimeout,
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         (&net.Dialer{Timeout: 10 * time.Second, Control: netguard.DenyPrivateAddresses}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConnsPerHost: 2,
+	},
+}
+
+// documentClient returns the client for document requests, routed through
+// proxy when the page has one so documents are fetched from the same place
+// as rendered pages.
+func documentClient(proxy *extractor.Proxy) *http.Client {
+	if proxy == nil {
+		return documentHTTPClient
+	}
+	proxyURL, err := url.Parse(proxy.Server)
+	if err != nil {
+		// Never fall back to a direct connection.
+		return &http.Client{Transport: &http.Transport{Proxy: func(*http.Request) (*url.URL, error) { return nil, err }}}
+	}
+	if proxy.Username != "" {
+		proxyURL.User = url.UserPassword(proxy.Username, proxy.Password)
+	}
+	return &http.Client{
+		Timeout:   documentFetchTimeout,
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL), DisableKeepAlives: true},
+	}
+}
+
+// detectDocument reports whether targetURL serves a PDF or DOCX file. URLs
+// with a document extension are trusted without a request; everything else is
+// probed and the answer cached on the page's config, so a page is probed once
+// per DocumentProbeTTL rather than on every check.
+func (s *SnapshotWorker) detectDocument(ctx context.Context, configRepo *monPersistence.MonitoringConfigPostgresRepository, pageConfig *entities.MonitoringConfig, targetURL string, proxy *extractor.Proxy, auth *extractor.Auth) document.Kind {
+	if kind := document.KindFromURL(targetURL); kind != "" {
+		return kind
+	}
+	if pageConfig != nil && pageConfig.DocumentProbe.Fresh(targetURL, time.Now()) {
+		return document.Kind(pageConfig.DocumentProbe.Kind)
+	}
+
+	kind, ok := s.probeDocument(ctx, targetURL, proxy, auth)
+	if ok && pageConfig != nil {
+		s.rememberDocumentKind(ctx, configRepo, pageConfig, targetURL, kind)
+	}
+	return kind
+}
+
+// rememberDocumentKind caches what targetURL served on the page's config.
+func (s *SnapshotWorker) rememberDocumentKind(ctx context.Context, configRepo *monPersistence.MonitoringConfigPostgresRepository, pageConfig *entities.MonitoringConfig, targetURL string, kind document.Kind) {
+	now := time.Now()
+	probe := entities.DocumentProbe{URL: targetURL, Kind: string(kind), ProbedAt: &now}
+	if err := configRepo.UpdateDocumentProbe(ctx, pageConfig.PageID, probe); err != nil {
+		logger.Warn("Failed to cache document probe", zap.String("page_id", pageConfig.PageID.String()), zap.Error(err))
+		return
+	}
+	pageConfig.DocumentProbe = probe
+}
+
+// probeDocument sends a HEAD request to find out whether targetURL serves a
+// document. ok is false when the probe got no usable answer, which is treated
+// as "not a document" so the browser path keeps handling it exactly as before.
+func (s *SnapshotWorker) probeDocument(ctx context.Context, targetURL string, proxy *extractor.Proxy, auth *extractor.Auth) (kind document.Kind, ok bool) {
+	probeCtx, cancel := context.WithTimeout(ctx, documentProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodHead, targetURL, nil)
+	if err != nil {
+		return "", false
+	}
+	req.Header.Set("User-Agent", documentUserAgent)
+	applyAuth(req, auth)
+
+	resp, err := documentClient(proxy).Do(req)
+	if err != nil {
+		return "", false
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", false
+	}
+	return document.DetectKind(resp.Header.Get("Content-Type"), resp.Request.URL.String()), true
+}
+
+// captureDocument downloads and parses a document without the browser,
+// stores the original file on the check and returns a synthesized extractor
+// result: one HTML element per paragraph (so content-block diffing works on
+// paragraphs) and, when the document embeds one, a page-1 thumbnail in place
+// of the screenshot.
+func (s *SnapshotWorker) captureDocument(ctx context.Context, refs *blobRefs, check *entities.Check, targetURL string, kind document.Kind, proxy *extractor.Proxy, auth *extractor.Auth) (*extractor.ExtractorResult, error) {
+	if s.objectStorage == nil {
+		return nil, errors.New("object storage client is not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", documentUserAgent)
+	req.Header.Set("Accept", kind.ContentType()+",*/*;q=0.8")
+	applyAuth(req, auth)
+
+	resp, err := documentClient(proxy).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch document: HTTP %d", resp.StatusCode)
+	}
+	served := document.DetectKind(resp.Header.Get("Content-Type"), resp.Request.URL.String())
+	if served == "" {
+		return nil, errNotDocument
+	}
+	kind = served
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, document.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if len(data) > document.MaxSize {
+		return nil, fmt.Errorf("document exceeds the %d MB size limit", document.MaxSize>>20)
+	}
+
+	doc, err := document.Parse(kind, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s document: %w", kind, err)
+	}
+
+	docKey, err := s.storeBlob(ctx, refs, check.PageID, data, hashBytes(data), kind.Extension(), kind.ContentType())
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload document: %w", err)
+	}
+	check.DocumentURL = docKey
+
+	var thumbnail []byte
+	if len(doc.Thumbnail) > 0 {
+		if thumb, err := imagecompare.ResizeToWidth(doc.Thumbnail, documentThumbnailWidth); err == nil {
+			thumbnail = thumb
+		} else {
+			logger.Warn("Failed to render document thumbnail", zap.String("page_id", check.PageID.String()), zap.Error(err))
+		}
+	}
+
+	if len(doc.Paragraphs) == 0 {
+		logger.Warn("Document has no extractable text (scanned or image-only?)",
+			zap.String("page_id", check.PageID.String()), zap.String("kind", string(kind)))
+	}
+	logger.Info("Captured document without browser",
+		zap.String("page_id", check.PageID.String()),
+		zap.String("kind", string(kind)),
+		zap.Int("bytes", len(data)),
+		zap.Int("pages", doc.PageCount),
+		zap.Int("paragraphs", len(doc.Paragraphs)),
+		zap.Bool("has_thumbnail", len(thumbnail) > 0))
+
+	return &extractor.ExtractorResult{
+		Title:           doc.Title,
+		HTML:            doc.HTML(),
+		Text:            doc.Text(),
+		Screenshot:      thumbnail,
+		SelectorMatched: true,
+	}, nil
+}
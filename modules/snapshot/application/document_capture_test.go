@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/shared/document"
+)
+
+// allowLoopback lets document and page requests reach httptest servers,
+// which listen on 127.0.0.1 and would otherwise be refused as private.
+func allowLoopback(t *testing.T) {
+	prev := documentHTTPClient
+	documentHTTPClient = &http.Client{Timeout: documentFetchTimeout}
+	t.Cleanup(func() { documentHTTPClient = prev })
+}
+
+// authenticatedPDFServer serves a PDF only to requests carrying every
+// credential; anything else gets the login page a real site would return.
+func authenticatedPDFServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		session, err := r.Cookie("session")
+		if !ok || user != "alice" || pass != "s3cret" || r.Header.Get("X-Api-Key") != "key-1" || err != nil || session.Value != "abc" {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("<html>Sign in</html>"))
+			return
+		}
+		if _, err := r.Cookie("other"); err == nil {
+			t.Error("cookie for another domain was sent")
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProbeDocument_AppliesPageAuth(t *testing.T) {
+	allowLoopback(t)
+	srv := authenticatedPDFServer(t)
+	s := &SnapshotWorker{}
+	auth := &extractor.Auth{
+		Headers: map[string]string{"X-Api-Key": "key-1"},
+		Basic:   &extractor.BasicAuth{Username: "alice", Password: "s3cret"},
+		Cookies: []extractor.AuthCookie{
+			{Name: "session", Value: "abc", Domain: "127.0.0.1"},
+			{Name: "other", Value: "x", Domain: "example.com"},
+		},
+	}
+
+	if kind, ok := s.probeDocument(context.Background(), srv.URL+"/report", nil, nil); ok || kind != "" {
+		t.Fatalf("anonymous probe = %q, %v; want the login page rejected", kind, ok)
+	}
+	kind, ok := s.probeDocument(context.Background(), srv.URL+"/report", nil, auth)
+	if !ok || kind != document.KindPDF {
+		t.Fatalf("authenticated probe = %q, %v; want a PDF", kind, ok)
+	}
+}
+
+func TestCookieMatches(t *testing.T) {
+	tests := []struct {
+		cookie extractor.AuthCookie
+		host   string
+		path   string
+		want   bool
+	}{
+		{extractor.AuthCookie{Name: "a"}, "example.com", "/", true},
+		{extractor.AuthCookie{Name: "a", Domain: ".example.com"}, "docs.example.com", "/", true},
+		{extractor.AuthCookie{Name: "a", Domain: "example.com"}, "badexample.com", "/", false},
+		{extractor.AuthCookie{Name: "a", Path: "/files"}, "example.com", "/files/q3.pdf", true},
+		{extractor.AuthCookie{Name: "a", Path: "/files"}, "example.com", "/filesystem", false},
+	}
+	for _, tt := range tests {
+		if got := cookieMatches(tt.cookie, tt.host, tt.path); got != tt.want {
+			t.Errorf("cookieMatches(%+v, %q, %q) = %v, want %v", tt.cookie, tt.host, tt.path, got, tt.want)
+		}
+	}
+}
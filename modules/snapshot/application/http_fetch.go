@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	httpFetchTimeout = 30 * time.Second
+	maxHTTPPageSize  = 10 << 20
+)
+
+// captureHTTP captures a page with the configured non-browser engine. It
+// returns a nil result and nil error when the check should go to the browser
+// instead: always for the "browser" engine, and in "auto" mode when the plain
+// fetch fails or the page renders its content with JavaScript.
+func (s *SnapshotWorker) captureHTTP(ctx context.Context, check *entities.Check, targetURL, engine string, proxy *extractor.Proxy) (*extractor.ExtractorResult, error) {
+	if engine == entities.FetchEngineBrowser {
+		return nil, nil
+	}
+
+	res, err := s.fetchHTTP(ctx, targetURL, proxy)
+	if engine == entities.FetchEngineHTTP {
+		if err == nil && sharedHTML.NeedsJavaScript(res.HTML) {
+			logger.Warn("Page looks client-rendered; the http engine may miss its content",
+				zap.String("page_id", check.PageID.String()))
+		}
+		return res, err
+	}
+
+	if err != nil {
+		logger.Info("HTTP fetch failed, escalating to browser",
+			zap.String("page_id", check.PageID.String()), zap.Error(err))
+		return nil, nil
+	}
+	if sharedHTML.NeedsJavaScript(res.HTML) {
+		logger.Info("Page needs JavaScript, escalating to browser", zap.String("page_id", check.PageID.String()))
+		return nil, nil
+	}
+	return res, nil
+}
+
+// fetchHTTP downloads targetURL with a plain GET and runs it through the same
+// HTML pipeline as browser captures. There is no screenshot, so change
+// detection relies on content blocks alone.
+func (s *SnapshotWorker) fetchHTTP(ctx context.Context, targetURL string, proxy *extractor.Proxy) (*extractor.ExtractorResult, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, httpFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", documentUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+
+	resp, err := documentClient(proxy).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("failed to fetch page: HTTP %d", resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("page is not HTML (content type %q)", mediaType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPPageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+	if len(body) > maxHTTPPageSize {
+		return nil, fmt.Errorf("page exceeds the %d MB size limit", maxHTTPPageSize>>20)
+	}
+
+	page := string(body)
+	var title string
+	if meta := sharedHTML.ExtractSEOMetadata(page); meta != nil {
+		title = meta.Title
+	}
+	return &extractor.ExtractorResult{
+		Title:           title,
+		HTML:            page,
+		Text:            sharedHTML.ExtractText(page),
+		SelectorMatched: true,
+	}, nil
+}
+
+// fetchEngineOf returns the engine that produced a check. Checks from before
+// engines were recorded came from the browser, or from a plain download when
+// they hold a document.
+func fetchEngineOf(check *entities.Check) string {
+	switch {
+	case check.FetchEngine != "":
+		return check.FetchEngine
+	case check.DocumentURL != "":
+		return entities.FetchEngineHTTP
+	default:
+		return entities.FetchEngineBrowser
+	}
+}
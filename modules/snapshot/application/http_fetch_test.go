@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	"github.com/jcsoftdev/pulzifi-back/shared/netguard"
+)
+
+var staticPage = "<html><head><title>Pricing</title></head><body><p>" +
+	strings.Repeat("Plans start at ten dollars a month. ", 20) + "</p></body></html>"
+
+// pageServer serves a static page at /static, a client-rendered shell at
+// /shell, an oversized page at /huge and a server error everywhere else.
+func pageServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/static":
+			w.Write([]byte(staticPage))
+		case "/shell":
+			w.Write([]byte(`<html><body><div id="root"></div><script src="/app.js"></script></body></html>`))
+		case "/huge":
+			w.Write([]byte("<html><body><p>"))
+			chunk := []byte(strings.Repeat("x", 1<<20))
+			for i := 0; i <= maxHTTPPageSize>>20; i++ {
+				w.Write(chunk)
+			}
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCaptureHTTP_Engines(t *testing.T) {
+	allowLoopback(t)
+	srv := pageServer(t)
+	s := &SnapshotWorker{}
+	check := &entities.Check{PageID: uuid.New()}
+
+	tests := []struct {
+		name       string
+		path       string
+		engine     string
+		wantResult bool
+		wantErr    bool
+	}{
+		{"browser engine never fetches", "/static", entities.FetchEngineBrowser, false, false},
+		{"auto keeps a static page", "/static", entities.FetchEngineAuto, true, false},
+		{"auto escalates a client-rendered page", "/shell", entities.FetchEngineAuto, false, false},
+		{"auto escalates a failed fetch", "/error", entities.FetchEngineAuto, false, false},
+		{"auto escalates an oversized page", "/huge", entities.FetchEngineAuto, false, false},
+		{"http keeps a client-rendered page", "/shell", entities.FetchEngineHTTP, true, false},
+		{"http reports a failed fetch", "/error", entities.FetchEngineHTTP, false, true},
+		{"http reports an oversized page", "/huge", entities.FetchEngineHTTP, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.captureHTTP(context.Background(), check, srv.URL+tt.path, tt.engine, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if (res != nil) != tt.wantResult {
+				t.Fatalf("result = %v, want result %v", res != nil, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestFetchHTTP_SizeLimit(t *testing.T) {
+	allowLoopback(t)
+	srv := pageServer(t)
+	s := &SnapshotWorker{}
+
+	_, err := s.fetchHTTP(context.Background(), srv.URL+"/huge", nil)
+	if err == nil || !strings.Contains(err.Error(), "size limit") {
+		t.Fatalf("err = %v, want the size limit", err)
+	}
+
+	res, err := s.fetchHTTP(context.Background(), srv.URL+"/static", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Title != "Pricing" || !strings.Contains(res.Text, "ten dollars") {
+		t.Errorf("result = %q / %q", res.Title, res.Text)
+	}
+}
+
+func TestFetchHTTP_RefusesPrivateAddresses(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+	s := &SnapshotWorker{}
+
+	_, err := s.fetchHTTP(context.Background(), srv.URL, nil)
+	if !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Errorf("err = %v, want a refused private address", err)
+	}
+	if hits != 0 {
+		t.Error("request reached the loopback server")
+	}
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	alertentities "github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+	alertrepositories "github.com/jcsoftdev/pulzifi-back/modules/alert/domain/repositories"
+	alertPersistence "github.com/jcsoftdev/pulzifi-back/modules/alert/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// contentDiffHash fingerprints a check's content block diff, so the same
+// change on different pages hashes alike. Returns "" when the check has no
+// diff, e.g. when only pixels or the vision model flagged the change.
+func contentDiffHash(check *entities.Check) string {
+	if check.ContentDiffJSON == "" {
+		return ""
+	}
+	var diff sharedHTML.ContentDiff
+	if err := json.Unmarshal([]byte(check.ContentDiffJSON), &diff); err != nil {
+		return ""
+	}
+	return diff.Signature()
+}
+
+// Kinds of incident recipients, prefixed to their IDs.
+const (
+	recipientUser        = "user"
+	recipientIntegration = "integration"
+)
+
+// groupIntoIncident attaches an alert to the incident for the same change on
+// other pages of the site within the incident window, opening one if needed.
+// Alerts without a content diff or site are left ungrouped.
+func (s *SnapshotWorker) groupIntoIncident(ctx context.Context, schemaName string, alert *alertentities.Alert, check *entities.Check, pageURL string) {
+	diffHash := contentDiffHash(check)
+	site := alertentities.IncidentSite(pageURL)
+	if diffHash == "" || site == "" {
+		return
+	}
+
+	incidentRepo := alertPersistence.NewIncidentPostgresRepository(s.db, schemaName)
+	incident, opened, err := incidentRepo.OpenOrJoin(ctx, alert.WorkspaceID, site, diffHash, alert.CreatedAt)
+	if err != nil {
+		logger.Error("Failed to group alert into incident", zap.Error(err), zap.String("check_id", check.ID.String()))
+		return
+	}
+	alert.IncidentID = &incident.ID
+	if !opened {
+		logger.Info("Alert joined open incident",
+			zap.String("incident_id", incident.ID.String()),
+			zap.String("site", site),
+			zap.Int("alert_count", incident.AlertCount))
+	}
+}
+
+// unnotifiedIncidentRecipients returns the IDs of kind the incident has not
+// notified yet and records them as notified. When that can't be recorded all
+// of them are returned: a duplicate notification beats a missed one.
+func (s *SnapshotWorker) unnotifiedIncidentRecipients(ctx context.Context, schemaName string, incidentID uuid.UUID, kind string, ids []uuid.UUID) []uuid.UUID {
+	incidentRepo := alertPersistence.NewIncidentPostgresRepository(s.db, schemaName)
+	pending, err := claimIncidentRecipients(ctx, incidentRepo, incidentID, kind, ids)
+	if err != nil {
+		logger.Error("Failed to record incident recipients", zap.Error(err), zap.String("incident_id", incidentID.String()))
+		return ids
+	}
+	return pending
+}
+
+func claimIncidentRecipients(ctx context.Context, repo alertrepositories.IncidentRepository, incidentID uuid.UUID, kind string, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	byRecipient := make(map[string]uuid.UUID, len(ids))
+	recipients := make([]string, len(ids))
+	for i, id := range ids {
+		recipients[i] = kind + ":" + id.String()
+		byRecipient[recipients[i]] = id
+	}
+	claimed, err := repo.ClaimRecipients(ctx, incidentID, recipients)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]uuid.UUID, 0, len(claimed))
+	for _, recipient := range claimed {
+		pending = append(pending, byRecipient[recipient])
+	}
+	return pending, nil
+}
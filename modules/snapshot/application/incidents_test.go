@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/alert/domain/repositories/mocks"
+)
+
+func TestClaimIncidentRecipients_PagesWithDifferentSubscribers(t *testing.T) {
+	repo := &mocks.MockIncidentRepository{}
+	incidentID := uuid.New()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	slack := uuid.New()
+	ctx := context.Background()
+
+	// Page A opens the incident; its subscribers and the integration hear of it.
+	pageA, err := claimIncidentRecipients(ctx, repo, incidentID, recipientUser, []uuid.UUID{alice, bob})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pageA) != 2 {
+		t.Errorf("page A notified %v, want alice and bob", pageA)
+	}
+	if got, _ := claimIncidentRecipients(ctx, repo, incidentID, recipientIntegration, []uuid.UUID{slack}); len(got) != 1 {
+		t.Errorf("page A webhooks = %v, want slack", got)
+	}
+
+	// Page B joins it: carol, subscribed only to page B, still hears of the
+	// change; bob and the integration were told already.
+	pageB, err := claimIncidentRecipients(ctx, repo, incidentID, recipientUser, []uuid.UUID{bob, carol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pageB) != 1 || pageB[0] != carol {
+		t.Errorf("page B notified %v, want only carol", pageB)
+	}
+	if got, _ := claimIncidentRecipients(ctx, repo, incidentID, recipientIntegration, []uuid.UUID{slack}); len(got) != 0 {
+		t.Errorf("page B webhooks = %v, want none", got)
+	}
+
+	// A user and an integration sharing an ID are different recipients.
+	if got, _ := claimIncidentRecipients(ctx, repo, uuid.New(), recipientUser, []uuid.UUID{slack}); len(got) != 1 {
+		t.Errorf("new incident notified %v, want its own recipients", got)
+	}
+}
+
+func TestClaimIncidentRecipients_Error(t *testing.T) {
+	repo := &mocks.MockIncidentRepository{ClaimRecipientsErr: errors.New("connection refused")}
+	if _, err := claimIncidentRecipients(context.Background(), repo, uuid.New(), recipientUser, []uuid.UUID{uuid.New()}); err == nil {
+		t.Error("expected the repository error")
+	}
+}
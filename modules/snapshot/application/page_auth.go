@@ -0,0 +1,167 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	alertentities "github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+	alertPersistence "github.com/jcsoftdev/pulzifi-back/modules/alert/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/email/infrastructure/templates"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// loadPageAuth decrypts the page's credential into extractor auth options.
+// Returns a nil credential when the page has none. A credential that exists but
+// cannot be decrypted is an error: capturing anonymously would record the login
+// wall as a content change.
+func (s *SnapshotWorker) loadPageAuth(ctx context.Context, repo *monPersistence.PageCredentialPostgresRepository, schemaName string, check *entities.Check) (*entities.PageCredential, *extractor.Auth, error) {
+	credential, err := repo.GetByPageID(ctx, check.PageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load page credentials: %w", err)
+	}
+	if credential == nil {
+		return nil, nil, nil
+	}
+	if s.credentialCipher == nil {
+		return nil, nil, errors.New("page has credentials but credential encryption is not configured")
+	}
+
+	secrets, err := credential.OpenSecrets(s.credentialCipher, schemaName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt page credentials: %w", err)
+	}
+
+	auth := &extractor.Auth{}
+	switch credential.AuthType {
+	case entities.AuthTypeHeaders:
+		auth.Headers = secrets.Headers
+	case entities.AuthTypeCookies:
+		for _, c := range secrets.Cookies {
+			auth.Cookies = append(auth.Cookies, extractor.AuthCookie(c))
+		}
+	case entities.AuthTypeBasic:
+		auth.Basic = &extractor.BasicAuth{Username: secrets.Username, Password: secrets.Password}
+	case entities.AuthTypeLoginScript:
+		if credential.LoginScript == nil {
+			return nil, nil, errors.New("page login script is missing")
+		}
+		auth.Login = &extractor.LoginAuth{
+			URL:              credential.LoginScript.URL,
+			UsernameSelector: credential.LoginScript.UsernameSelector,
+			PasswordSelector: credential.LoginScript.PasswordSelector,
+			SubmitSelector:   credential.LoginScript.SubmitSelector,
+			SuccessSelector:  credential.LoginScript.SuccessSelector,
+			Username:         secrets.Username,
+			Password:         secrets.Password,
+		}
+	}
+	return credential, auth, nil
+}
+
+// applyAuth adds the page's header, basic auth and cookie credentials to a
+// request made outside the browser, such as a document download. Login
+// scripts need the browser and are not applied; the login wall is then
+// detected as "not a document" and the browser path takes over.
+func applyAuth(req *http.Request, auth *extractor.Auth) {
+	if auth == nil {
+		return
+	}
+	for name, value := range auth.Headers {
+		req.Header.Set(name, value)
+	}
+	if auth.Basic != nil {
+		req.SetBasicAuth(auth.Basic.Username, auth.Basic.Password)
+	}
+	host := strings.ToLower(req.URL.Hostname())
+	for _, c := range auth.Cookies {
+		if !cookieMatches(c, host, req.URL.Path) {
+			continue
+		}
+		req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+	}
+}
+
+// cookieMatches reports whether the browser would send c to host and path.
+func cookieMatches(c extractor.AuthCookie, host, path string) bool {
+	if domain := strings.TrimPrefix(strings.ToLower(c.Domain), "."); domain != "" && host != domain && !strings.HasSuffix(host, "."+domain) {
+		return false
+	}
+	if c.Path == "" || c.Path == "/" {
+		return true
+	}
+	if path == "" {
+		path = "/"
+	}
+	return path == c.Path || strings.HasPrefix(path, strings.TrimSuffix(c.Path, "/")+"/")
+}
+
+// recordLoginResult tracks the outcome of a page's login script after an
+// extraction. A "login_failed" alert is raised only when the login goes from
+// working (or never tried) to failing, so a broken login alerts once rather
+// than on every check. Extraction errors unrelated to the login leave the
+// status untouched.
+func (s *SnapshotWorker) recordLoginResult(ctx context.Context, schemaName string, repo *monPersistence.PageCredentialPostgresRepository, credential *entities.PageCredential, check *entities.Check, pageURL string, extractErr error) {
+	if credential == nil || !credential.UsesLogin() {
+		return
+	}
+
+	var loginErr *extractor.LoginError
+	switch {
+	case extractErr == nil:
+		if credential.LastLoginStatus == entities.LoginStatusFailed {
+			logger.Info("Page login recovered", zap.String("page_id", check.PageID.String()))
+		}
+		if err := repo.UpdateLoginStatus(ctx, check.PageID, entities.LoginStatusOK, ""); err != nil {
+			logger.Error("Failed to record login status", zap.Error(err), zap.String("page_id", check.PageID.String()))
+		}
+	case errors.As(extractErr, &loginErr):
+		if err := repo.UpdateLoginStatus(ctx, check.PageID, entities.LoginStatusFailed, loginErr.Message); err != nil {
+			logger.Error("Failed to record login status", zap.Error(err), zap.String("page_id", check.PageID.String()))
+		}
+		if credential.LastLoginStatus != entities.LoginStatusFailed {
+			s.createLoginFailedAlert(ctx, schemaName, check, pageURL, loginErr.Message)
+		}
+	}
+}
+
+// createLoginFailedAlert records a "login_failed" alert and notifies subscribers.
+func (s *SnapshotWorker) createLoginFailedAlert(ctx context.Context, schemaName string, check *entities.Check, pageURL, reason string) {
+	workspaceID, ok := s.pageWorkspaceID(ctx, schemaName, check.PageID)
+	if !ok {
+		return
+	}
+
+	summary := "Login failed: " + reason
+	alert := alertentities.NewAlert(workspaceID, check.PageID, check.ID, "login_failed", "Page Login Failing", summary)
+	alert.ChangeSummary = summary
+
+	alertRepo := alertPersistence.NewAlertPostgresRepository(s.db, schemaName)
+	if err := alertRepo.Create(ctx, alert); err != nil {
+		logger.Error("Failed to create login failed alert", zap.Error(err))
+	}
+
+	go s.sendLoginFailedEmails(schemaName, check, pageURL, reason)
+	go s.dispatchWebhooks(schemaName, check, pageURL, summary)
+}
+
+func (s *SnapshotWorker) sendLoginFailedEmails(schemaName string, check *entities.Check, pageURL, reason string) {
+	if s.emailProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dashboardURL := fmt.Sprintf("%s/workspaces", s.frontendURL)
+	subject, html := templates.LoginFailedNotification(pageURL, reason, dashboardURL)
+
+	s.sendEmailToSubscribers(ctx, schemaName, check.PageID, "login_failed", subject, html)
+}
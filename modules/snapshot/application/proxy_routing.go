@@ -0,0 +1,124 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// resolveProxyRoute returns the page's proxy route, falling back to its
+// workspace's default. A zero route means the check connects directly.
+func (s *SnapshotWorker) resolveProxyRoute(ctx context.Context, repo *monPersistence.ProxyPostgresRepository, schemaName string, pageID uuid.UUID, pageConfig *entities.MonitoringConfig) (entities.ProxyRoute, error) {
+	var pageRoute entities.ProxyRoute
+	if pageConfig != nil {
+		pageRoute = pageConfig.ProxyRoute
+	}
+	if !pageRoute.IsZero() {
+		return pageRoute, nil
+	}
+
+	workspaceID, ok := s.pageWorkspaceID(ctx, schemaName, pageID)
+	if !ok {
+		return entities.ProxyRoute{}, errors.New("failed to resolve the page's workspace for proxy routing")
+	}
+	workspaceRoute, err := repo.GetWorkspaceRoute(ctx, workspaceID)
+	if err != nil {
+		return entities.ProxyRoute{}, fmt.Errorf("failed to load workspace proxy route: %w", err)
+	}
+	return entities.ResolveProxyRoute(pageRoute, workspaceRoute), nil
+}
+
+// pickProxy selects the least recently used healthy proxy on route and marks
+// it used. Having no proxy available is an error rather than a reason to
+// connect directly: a direct capture would come from the datacenter IP the
+// route exists to avoid, or show another country's page.
+func (s *SnapshotWorker) pickProxy(ctx context.Context, repo *monPersistence.ProxyPostgresRepository, schemaName string, route entities.ProxyRoute, exclude ...uuid.UUID) (*entities.Proxy, *extractor.Proxy, error) {
+	proxies, err := repo.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load proxies: %w", err)
+	}
+
+	now := time.Now()
+	proxy := entities.SelectProxy(proxies, route, now, exclude...)
+	if proxy == nil {
+		return nil, nil, fmt.Errorf("no healthy proxy available for %s", route)
+	}
+
+	var password string
+	if proxy.PasswordCiphertext != "" {
+		if s.credentialCipher == nil {
+			return nil, nil, errors.New("proxy has a password but credential encryption is not configured")
+		}
+		if password, err = proxy.OpenPassword(s.credentialCipher, schemaName); err != nil {
+			return nil, nil, fmt.Errorf("failed to decrypt proxy password: %w", err)
+		}
+	}
+
+	if err := repo.MarkUsed(ctx, proxy.ID, now); err != nil {
+		logger.Warn("Failed to record proxy use", zap.Error(err), zap.String("proxy_id", proxy.ID.String()))
+	}
+	return proxy, &extractor.Proxy{Server: proxy.Server(), Username: proxy.Username, Password: password}, nil
+}
+
+// extractThroughProxy runs the extraction and keeps proxy health up to date.
+// When the proxy itself fails, the capture is retried once through another
+// proxy on the same route; opts and check.ProxyID are switched to it and it is
+// returned, so later captures of the same check use the working proxy.
+func (s *SnapshotWorker) extractThroughProxy(ctx context.Context, repo *monPersistence.ProxyPostgresRepository, schemaName string, route entities.ProxyRoute, proxy *entities.Proxy, check *entities.Check, targetURL string, opts *extractor.ExtractOptions) (*extractor.ExtractorResult, *entities.Proxy, error) {
+	res, err := s.extractorClient.Extract(ctx, targetURL, *opts)
+	if proxy == nil || !s.recordProxyResult(ctx, repo, proxy, err) {
+		return res, proxy, err
+	}
+
+	next, nextOpts, pickErr := s.pickProxy(ctx, repo, schemaName, route, proxy.ID)
+	if pickErr != nil {
+		logger.Warn("No other proxy to retry through", zap.String("page_id", check.PageID.String()), zap.Error(pickErr))
+		return res, proxy, err
+	}
+	logger.Info("Retrying capture through another proxy",
+		zap.String("page_id", check.PageID.String()),
+		zap.String("failed_proxy", proxy.Name),
+		zap.String("proxy", next.Name))
+
+	opts.Proxy = nextOpts
+	check.ProxyID = &next.ID
+	res, err = s.extractorClient.Extract(ctx, targetURL, *opts)
+	s.recordProxyResult(ctx, repo, next, err)
+	return res, next, err
+}
+
+// recordProxyResult updates the proxy's health after a capture and reports
+// whether the proxy failed. Errors that are not the proxy's fault (the target
+// timing out, a failing login) leave its health unchanged.
+func (s *SnapshotWorker) recordProxyResult(ctx context.Context, repo *monPersistence.ProxyPostgresRepository, proxy *entities.Proxy, extractErr error) bool {
+	now := time.Now()
+	var proxyErr *extractor.ProxyError
+	failed := errors.As(extractErr, &proxyErr)
+	switch {
+	case extractErr == nil:
+		proxy.RecordSuccess(now)
+	case failed:
+		if proxy.RecordFailure(now, proxyErr.Message) {
+			logger.Warn("Proxy quarantined after repeated failures",
+				zap.String("proxy_id", proxy.ID.String()),
+				zap.String("proxy", proxy.Name),
+				zap.Int("consecutive_failures", proxy.ConsecutiveFailures),
+				zap.Duration("quarantine", entities.ProxyQuarantineDuration))
+		}
+	default:
+		return false
+	}
+
+	if err := repo.UpdateHealth(ctx, proxy); err != nil {
+		logger.Error("Failed to record proxy health", zap.Error(err), zap.String("proxy_id", proxy.ID.String()))
+	}
+	return failed
+}
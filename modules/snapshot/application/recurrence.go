@@ -0,0 +1,47 @@
+package application
+
+import (
+	"context"
+
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// contentHistory loads the recent content states of the check's page or
+// section. A failed lookup returns an empty history, so nothing is treated as
+// a recurrence.
+func (s *SnapshotWorker) contentHistory(ctx context.Context, stateRepo *monPersistence.ContentStatePostgresRepository, check *entities.Check) entities.ContentHistory {
+	history, err := stateRepo.ListRecent(ctx, check.PageID, check.SectionID, entities.ContentHistorySize)
+	if err != nil {
+		logger.Warn("Failed to load content history",
+			zap.String("page_id", check.PageID.String()), zap.Error(err))
+		return nil
+	}
+	return history
+}
+
+// classifyRecurrence marks a check whose change returned to a recently seen
+// content state as a recurrence, and as flapping when its page or section
+// keeps alternating between a few states. Returns whether the change should
+// still be alerted on.
+func classifyRecurrence(check *entities.Check, history entities.ContentHistory, suppressRecurrences bool) bool {
+	state := check.ContentState()
+	check.Flapping = history.IsFlapping(state)
+	check.Recurrence = check.ChangeDetected && history.IsRecurrence(state)
+	return !(check.Recurrence && suppressRecurrences)
+}
+
+// recordContentState appends the check's content state to its history. The
+// check must already be stored.
+func (s *SnapshotWorker) recordContentState(ctx context.Context, stateRepo *monPersistence.ContentStatePostgresRepository, check *entities.Check) {
+	state := check.ContentState()
+	if state == "" {
+		return
+	}
+	if err := stateRepo.Record(ctx, check.PageID, check.SectionID, check.ID, state); err != nil {
+		logger.Warn("Failed to record content state",
+			zap.String("check_id", check.ID.String()), zap.Error(err))
+	}
+}
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// ReevaluateHistory replays change detection with a page's current rules over
+// the stored captures in a re-evaluation job's date range and stores the
+// dry-run report on the job. Ignore selectors are re-applied to the stored
+// HTML, and alert conditions, recurrence suppression and the pixel diff
+// threshold are the current ones. Elements ignored when a page was captured
+// cannot be brought back, and the vision model is not consulted.
+func (s *SnapshotWorker) ReevaluateHistory(ctx context.Context, schemaName string, jobID uuid.UUID) error {
+	jobRepo := monPersistence.NewReevaluationPostgresRepository(s.db, schemaName)
+	job, err := jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("re-evaluation job not found: %s", jobID)
+	}
+
+	job.Status = entities.ReevaluationRunning
+	if err := jobRepo.Update(ctx, job); err != nil {
+		return err
+	}
+
+	results, unsupported, err := s.replayHistory(ctx, schemaName, job)
+	if err != nil {
+		job.Fail(err.Error())
+	} else {
+		job.Complete(results, unsupported)
+	}
+	if updateErr := jobRepo.Update(ctx, job); updateErr != nil {
+		return updateErr
+	}
+
+	logger.Info("History re-evaluation finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("page_id", job.PageID.String()),
+		zap.String("status", job.Status),
+		zap.Int("checks", job.TotalChecks),
+		zap.Int("flipped", job.Flipped))
+	return err
+}
+
+// replayHistory runs detectChange over consecutive stored captures, starting
+// from the last successful check before the range.
+func (s *SnapshotWorker) replayHistory(ctx context.Context, schemaName string, job *entities.ReevaluationJob) ([]entities.ReevaluationResult, []string, error) {
+	configRepo := monPersistence.NewMonitoringConfigPostgresRepository(s.db, schemaName)
+	pageConfig, err := configRepo.GetByPageID(ctx, job.PageID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load monitoring config: %w", err)
+	}
+
+	enabledAlertConditions := []string{"any_changes"}
+	suppressRecurrences := pageConfig == nil || pageConfig.SuppressRecurrences
+	var ignoreSelectors []string
+	if pageConfig != nil {
+		if len(pageConfig.EnabledAlertConditions) > 0 {
+			enabledAlertConditions = pageConfig.EnabledAlertConditions
+		}
+		ignoreSelectors = pageConfig.IgnoreSelectors
+	}
+	opts := detectOptions{ignoreSelectors: ignoreSelectors, noVision: true}
+
+	checkRepo := monPersistence.NewCheckPostgresRepository(s.db, schemaName)
+	checks, err := checkRepo.ListSuccessfulInRange(ctx, job.PageID, job.SectionID, job.From, job.To, entities.ReevaluationMaxChecks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(checks) == 0 {
+		return nil, nil, nil
+	}
+	baseline, err := checkRepo.GetPreviousSuccessfulBefore(ctx, checks[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unsupported []string
+	var prev *entities.Check
+	var prevHTML string
+	var history entities.ContentHistory
+	if baseline != nil {
+		prev, prevHTML, unsupported = s.replayCapture(baseline, ignoreSelectors)
+		history = history.Push(prev.ContentState())
+	}
+
+	results := make([]entities.ReevaluationResult, 0, len(checks))
+	for _, stored := range checks {
+		curr, currHTML, u := s.replayCapture(stored, ignoreSelectors)
+		if u != nil {
+			unsupported = u
+		}
+		result := entities.ReevaluationResult{CheckID: stored.ID, CheckedAt: stored.CheckedAt, WasChanged: stored.ChangeDetected}
+
+		if prev != nil && comparableCaptures(prev, curr) {
+			// The screenshot is only compared when the content is unchanged.
+			var currImg []byte
+			if curr.ScreenshotURL != "" && curr.ScreenshotHash != prev.ScreenshotHash {
+				currImg = s.downloadScreenshot(curr.ScreenshotURL)
+			}
+			changed, _, contentDiff := s.detectChange(ctx, prev, curr, currImg, "", currHTML, opts)
+			curr.ChangeDetected = changed
+			alertable := classifyRecurrence(curr, history, suppressRecurrences)
+			if changed {
+				result.WouldChange = true
+				result.ChangeType = "content"
+				result.Recurrence = curr.Recurrence
+				result.WouldAlert = alertable && sliceContains(enabledAlertConditions, "any_changes")
+				if contentDiff != nil {
+					result.Changes = contentDiff.TotalChanges
+				}
+			}
+			if sliceContains(enabledAlertConditions, "seo_changes") && prevHTML != "" && currHTML != "" {
+				if seoChanges := sharedHTML.DiffSEOMetadata(sharedHTML.ExtractSEOMetadata(prevHTML), sharedHTML.ExtractSEOMetadata(currHTML)); len(seoChanges) > 0 {
+					if !result.WouldChange {
+						result.WouldChange = true
+						result.ChangeType = "seo"
+					}
+					result.WouldAlert = true
+				}
+			}
+		}
+
+		results = append(results, result)
+		history = history.Push(curr.ContentState())
+		prev, prevHTML = curr, currHTML
+	}
+	return results, unsupported, nil
+}
+
+// replayCapture returns a copy of a stored check with its content hashes
+// recomputed from the stored HTML after removing the ignore selectors, the
+// filtered HTML and the selectors that could not be applied. The stored
+// hashes are kept when the snapshot cannot be downloaded.
+func (s *SnapshotWorker) replayCapture(check *entities.Check, ignoreSelectors []string) (*entities.Check, string, []string) {
+	c := *check
+	html := s.fetchHTMLFromURL(c.HTMLSnapshotURL)
+	if html == "" {
+		return &c, "", nil
+	}
+	var unsupported []string
+	if len(ignoreSelectors) > 0 {
+		html, unsupported = sharedHTML.RemoveElements(html, ignoreSelectors)
+	}
+	contentHash := sha256.Sum256([]byte(sharedHTML.ExtractText(html)))
+	c.ContentHash = hex.EncodeToString(contentHash[:])
+	c.ContentBlockHash = sharedHTML.HashContentBlocks(sharedHTML.ExtractContentBlocks(html))
+	return &c, html, unsupported
+}
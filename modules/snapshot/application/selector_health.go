@@ -0,0 +1,254 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	alertentities "github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+	alertPersistence "github.com/jcsoftdev/pulzifi-back/modules/alert/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/email/infrastructure/templates"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// brokenSelector is a selector the current check reported broken.
+type brokenSelector struct {
+	SectionID             *uuid.UUID // nil for the page's element selector
+	Name                  string
+	CSSSelector           string
+	XPathSelector         string
+	LastGoodScreenshotKey string
+	ProposedSelector      string // found by the section's fingerprint, if any
+}
+
+// emailLinkExpiry is how long snapshot links in emails stay valid, the
+// longest S3 presigned URLs allow.
+const emailLinkExpiry = 7 * 24 * time.Hour
+
+// recordElementSelector updates the health of the page's element selector
+// after a browser capture and raises a "selector_broken" alert once the
+// selector has missed entities.SelectorBrokenThreshold checks in a row.
+func (s *SnapshotWorker) recordElementSelector(
+	ctx context.Context,
+	schemaName string,
+	configRepo *monPersistence.MonitoringConfigPostgresRepository,
+	checkRepo *monPersistence.CheckPostgresRepository,
+	config *entities.MonitoringConfig,
+	check *entities.Check,
+	pageURL string,
+	matched bool,
+) {
+	health := config.SelectorHealth
+	if matched && health.MissCount == 0 && !health.Broken() {
+		return
+	}
+
+	broke := false
+	if matched {
+		if health.RecordMatch() {
+			logger.Info("Element selector matches again", zap.String("page_id", check.PageID.String()))
+		}
+	} else {
+		broke = health.RecordMiss(time.Now())
+		logger.Warn("Element selector did not match",
+			zap.String("page_id", check.PageID.String()),
+			zap.String("selector", config.CSSSelector),
+			zap.Int("consecutive_misses", health.MissCount))
+	}
+	if err := configRepo.UpdateSelectorHealth(ctx, check.PageID, health); err != nil {
+		logger.Error("Failed to record selector health", zap.Error(err), zap.String("page_id", check.PageID.String()))
+	}
+	config.SelectorHealth = health
+
+	if broke {
+		broken := brokenSelector{CSSSelector: config.CSSSelector, XPathSelector: config.XPathSelector}
+		if last, err := checkRepo.GetLastSelectorMatch(ctx, check.PageID); err == nil && last != nil {
+			broken.LastGoodScreenshotKey = last.ScreenshotURL
+		}
+		s.createSelectorBrokenAlert(ctx, schemaName, check, pageURL, []brokenSelector{broken})
+	}
+}
+
+// recordSectionSelector updates the health of a section's selector and
+// returns the section as broken when this miss crossed the threshold.
+func (s *SnapshotWorker) recordSectionSelector(
+	ctx context.Context,
+	sectionRepo *monPersistence.MonitoredSectionPostgresRepository,
+	checkRepo *monPersistence.CheckPostgresRepository,
+	section *entities.MonitoredSection,
+	matched bool,
+) *brokenSelector {
+	health := section.SelectorHealth
+	if matched && health.MissCount == 0 && !health.Broken() {
+		return nil
+	}
+
+	broke := false
+	if matched {
+		if health.RecordMatch() {
+			logger.Info("Section selector matches again",
+				zap.String("page_id", section.PageID.String()), zap.String("section", section.Name))
+		}
+	} else {
+		broke = health.RecordMiss(time.Now())
+		logger.Warn("Section selector did not match",
+			zap.String("page_id", section.PageID.String()),
+			zap.String("section", section.Name),
+			zap.Int("consecutive_misses", health.MissCount))
+	}
+	if err := sectionRepo.UpdateSelectorHealth(ctx, section.ID, health); err != nil {
+		logger.Error("Failed to record section selector health", zap.Error(err), zap.String("section_id", section.ID.String()))
+	}
+	section.SelectorHealth = health
+
+	if !broke {
+		return nil
+	}
+	broken := &brokenSelector{
+		SectionID:     &section.ID,
+		Name:          section.Name,
+		CSSSelector:   section.CSSSelector,
+		XPathSelector: section.XPathSelector,
+	}
+	if last, err := checkRepo.GetPreviousSuccessfulBySection(ctx, section.PageID, &section.ID, uuid.Nil); err == nil && last != nil {
+		broken.LastGoodScreenshotKey = last.ScreenshotURL
+	}
+	return broken
+}
+
+// createSelectorBrokenAlert records a "selector_broken" alert carrying each
+// broken selector and its last good screenshot, then notifies subscribers.
+func (s *SnapshotWorker) createSelectorBrokenAlert(ctx context.Context, schemaName string, check *entities.Check, pageURL string, broken []brokenSelector) {
+	workspaceID, ok := s.pageWorkspaceID(ctx, schemaName, check.PageID)
+	if !ok {
+		return
+	}
+
+	rows := make([]map[string]interface{}, 0, len(broken))
+	for _, b := range broken {
+		row := map[string]interface{}{
+			"css_selector":             b.CSSSelector,
+			"xpath_selector":           b.XPathSelector,
+			"last_good_screenshot_key": b.LastGoodScreenshotKey,
+		}
+		if b.SectionID != nil {
+			row["section_id"] = b.SectionID.String()
+			row["section_name"] = b.Name
+		}
+		if b.ProposedSelector != "" {
+			row["proposed_selector"] = b.ProposedSelector
+		}
+		rows = append(rows, row)
+	}
+	summary := fmt.Sprintf("Selector %q no longer matches the page", broken[0].CSSSelector)
+	if len(broken) > 1 {
+		summary = fmt.Sprintf("%d selectors no longer match the page", len(broken))
+	} else if broken[0].Name != "" {
+		summary = fmt.Sprintf("Section %q no longer matches the page", broken[0].Name)
+	}
+
+	alert := alertentities.NewAlert(workspaceID, check.PageID, check.ID, "selector_broken", "Selector Stopped Matching", summary)
+	alert.ChangeSummary = summary
+	alert.Metadata = alertentities.Metadata{"selectors": rows}
+
+	alertRepo := alertPersistence.NewAlertPostgresRepository(s.db, schemaName)
+	if err := alertRepo.Create(ctx, alert); err != nil {
+		logger.Error("Failed to create selector broken alert", zap.Error(err))
+	}
+
+	go s.sendSelectorBrokenEmails(schemaName, check, pageURL, broken)
+	go s.dispatchWebhooks(schemaName, check, pageURL, summary)
+}
+
+func (s *SnapshotWorker) sendSelectorBrokenEmails(schemaName string, check *entities.Check, pageURL string, broken []brokenSelector) {
+	if s.emailProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	selectors := make([]templates.BrokenSelector, len(broken))
+	for i, b := range broken {
+		selector := b.CSSSelector
+		if selector == "" {
+			selector = b.XPathSelector
+		}
+		selectors[i] = templates.BrokenSelector{
+			Name:             b.Name,
+			Selector:         selector,
+			ProposedSelector: b.ProposedSelector,
+		}
+		if b.LastGoodScreenshotKey != "" && s.objectStorage != nil {
+			if signed, err := s.objectStorage.SignedURL(ctx, b.LastGoodScreenshotKey, emailLinkExpiry); err == nil {
+				selectors[i].LastGoodScreenshotURL = signed
+			}
+		}
+	}
+	dashboardURL := fmt.Sprintf("%s/workspaces", s.frontendURL)
+	subject, html := templates.SelectorBrokenNotification(pageURL, selectors, dashboardURL)
+
+	s.sendEmailToSubscribers(ctx, schemaName, check.PageID, "selector_broken", subject, html)
+}
+
+// recordSelectorProposal stores the selector the extractor proposed after
+// finding a section by its fingerprint, and drops a stale proposal once the
+// section's own selector matches again.
+func (s *SnapshotWorker) recordSelectorProposal(
+	ctx context.Context,
+	sectionRepo *monPersistence.MonitoredSectionPostgresRepository,
+	section *entities.MonitoredSection,
+	result *extractor.SectionExtractResult,
+	screenshotURL string,
+) {
+	var proposal *entities.SelectorProposal
+	if result.Healed && result.ProposedSelector != "" {
+		proposal = &entities.SelectorProposal{
+			CSSSelector:   result.ProposedSelector,
+			XPathSelector: result.ProposedXPath,
+			Score:         result.MatchScore,
+			ScreenshotURL: screenshotURL,
+			ProposedAt:    time.Now(),
+		}
+		logger.Info("Section found by fingerprint, proposing new selector",
+			zap.String("section_id", section.ID.String()),
+			zap.String("proposed_selector", result.ProposedSelector),
+			zap.Float64("score", result.MatchScore))
+	} else if !result.SelectorMatched || section.SelectorProposal == nil {
+		return
+	}
+	if err := sectionRepo.UpdateSelectorProposal(ctx, section.ID, proposal); err != nil {
+		logger.Error("Failed to record selector proposal", zap.Error(err), zap.String("section_id", section.ID.String()))
+		return
+	}
+	section.SelectorProposal = proposal
+}
+
+// toExtractorFingerprint converts a section's fingerprint for the extractor.
+func toExtractorFingerprint(f *entities.ElementFingerprint) *extractor.ElementFingerprint {
+	if f == nil {
+		return nil
+	}
+	fingerprint := &extractor.ElementFingerprint{
+		Tag:        f.Tag,
+		Text:       f.Text,
+		Attributes: f.Attributes,
+		Landmarks:  f.Landmarks,
+	}
+	if f.Rect != nil {
+		fingerprint.Rect = &extractor.ElementRect{X: f.Rect.X, Y: f.Rect.Y, W: f.Rect.W, H: f.Rect.H}
+	}
+	return fingerprint
+}
+
+// comparableCaptures reports whether two checks captured the page the same
+// way. Raw and rendered HTML differ, as do an element and the full page it
+// falls back to, so such pairs start a new baseline instead of being diffed.
+func comparableCaptures(prev, curr *entities.Check) bool {
+	return fetchEngineOf(prev) == fetchEngineOf(curr) && prev.SelectorFallback == curr.SelectorFallback
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	alertentities "github.com/jcsoftdev/pulzifi-back/modules/alert/domain/entities"
+	alertPersistence "github.com/jcsoftdev/pulzifi-back/modules/alert/infrastructure/persistence"
+	"github.com/jcsoftdev/pulzifi-back/modules/email/infrastructure/templates"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// detectSEOChange compares the SEO metadata of the previous check's stored
+// HTML snapshot against the freshly extracted HTML. Returns the changed fields,
+// or nil when nothing changed or the previous snapshot is unavailable.
+func (s *SnapshotWorker) detectSEOChange(prevCheck *entities.Check, currHTML string) []sharedHTML.SEOFieldChange {
+	if prevCheck == nil || prevCheck.HTMLSnapshotURL == "" || currHTML == "" {
+		return nil
+	}
+
+	prevHTML := s.fetchHTMLFromURL(prevCheck.HTMLSnapshotURL)
+	if prevHTML == "" {
+		return nil
+	}
+
+	changes := sharedHTML.DiffSEOMetadata(sharedHTML.ExtractSEOMetadata(prevHTML), sharedHTML.ExtractSEOMetadata(currHTML))
+	if len(changes) > 0 {
+		logger.Info("SEO metadata change detected",
+			zap.String("page_id", prevCheck.PageID.String()),
+			zap.Int("changed_fields", len(changes)))
+	}
+	return changes
+}
+
+// createSEOAlert records a "seo_change" alert whose metadata carries the
+// field-level before/after values, then notifies email and webhook subscribers.
+func (s *SnapshotWorker) createSEOAlert(ctx context.Context, schemaName string, check *entities.Check, pageURL string, changes []sharedHTML.SEOFieldChange) {
+	workspaceID, ok := s.pageWorkspaceID(ctx, schemaName, check.PageID)
+	if !ok {
+		return
+	}
+
+	fields := make([]string, 0, len(changes))
+	rows := make([]map[string]interface{}, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+		rows = append(rows, map[string]interface{}{"field": c.Field, "before": c.Before, "after": c.After})
+	}
+	summary := fmt.Sprintf("SEO metadata changed: %s", strings.Join(fields, ", "))
+
+	alert := alertentities.NewAlert(workspaceID, check.PageID, check.ID, "seo_change", "SEO Metadata Changed", summary)
+	alert.ChangeSummary = summary
+	alert.Metadata = alertentities.Metadata{"seo_changes": rows}
+
+	alertRepo := alertPersistence.NewAlertPostgresRepository(s.db, schemaName)
+	if err := alertRepo.Create(ctx, alert); err != nil {
+		logger.Error("Failed to create SEO alert", zap.Error(err))
+	} else {
+		logger.Info("SEO alert created", zap.String("check_id", check.ID.String()))
+	}
+
+	go s.sendSEOAlertEmails(schemaName, check, pageURL, changes)
+	go s.dispatchWebhooks(schemaName, check, pageURL, summary)
+}
+
+// sendSEOAlertEmails emails the before/after table to users subscribed to
+// "seo_change" notifications for the page.
+func (s *SnapshotWorker) sendSEOAlertEmails(schemaName string, check *entities.Check, pageURL string, changes []sharedHTML.SEOFieldChange) {
+	if s.emailProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows := make([]templates.FieldChange, 0, len(changes))
+	for _, c := range changes {
+		rows = append(rows, templates.FieldChange{Field: c.Field, Before: c.Before, After: c.After})
+	}
+	dashboardURL := fmt.Sprintf("%s/workspaces", s.frontendURL)
+	subject, html := templates.SEOChangeNotification(pageURL, rows, dashboardURL)
+
+	s.sendEmailToSubscribers(ctx, schemaName, check.PageID, "seo_change", subject, html)
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
+	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
+	imagecompare "github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/services"
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// timelapseScanLimit caps how many checks are considered before frames
+	// are picked from them.
+	timelapseScanLimit = 5000
+	// timelapseFrameDelayMs is how long each frame of an animation is shown.
+	timelapseFrameDelayMs = 800
+)
+
+var errNoScreenshots = errors.New("no screenshots in the date range")
+
+// RenderTimelapse renders a time-lapse job's screenshots and uploads the
+// result. Frames show the top of the page, at most 1.5 times as tall as
+// wide, and only screenshots that differ from the previous frame are kept.
+func (s *SnapshotWorker) RenderTimelapse(ctx context.Context, schemaName string, jobID uuid.UUID) error {
+	jobRepo := monPersistence.NewTimelapsePostgresRepository(s.db, schemaName)
+	job, err := jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("time-lapse job not found: %s", jobID)
+	}
+
+	job.Status = entities.TimelapseRunning
+	if err := jobRepo.Update(ctx, job); err != nil {
+		return err
+	}
+
+	key, frames, err := s.renderTimelapse(ctx, schemaName, job)
+	if err != nil {
+		job.Fail(err.Error())
+	} else {
+		job.Complete(key, frames)
+	}
+	if updateErr := jobRepo.Update(ctx, job); updateErr != nil {
+		return updateErr
+	}
+
+	logger.Info("Time-lapse render finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("page_id", job.PageID.String()),
+		zap.String("status", job.Status),
+		zap.Int("frames", job.Frames))
+	return err
+}
+
+func (s *SnapshotWorker) renderTimelapse(ctx context.Context, schemaName string, job *entities.TimelapseJob) (string, int, error) {
+	if s.objectStorage == nil {
+		return "", 0, errors.New("object storage client is not configured")
+	}
+	checkRepo := monPersistence.NewCheckPostgresRepository(s.db, schemaName)
+	checks, err := checkRepo.ListSuccessfulInRange(ctx, job.PageID, job.SectionID, job.From, job.To, timelapseScanLimit)
+	if err != nil {
+		return "", 0, err
+	}
+	picked := pickTimelapseFrames(checks, entities.TimelapseMaxFrames)
+	if len(picked) == 0 {
+		return "", 0, errNoScreenshots
+	}
+
+	frames := make([]imagecompare.TimelapseFrame, 0, len(picked))
+	for _, check := range picked {
+		img := s.downloadScreenshot(timelapseSource(check, job.Width))
+		if img == nil {
+			continue
+		}
+		frames = append(frames, imagecompare.TimelapseFrame{Image: img, Label: check.CheckedAt.Format("2006-01-02")})
+	}
+
+	format := imagecompare.TimelapseFormat(job.Format)
+	data, err := imagecompare.RenderTimelapse(frames, format, imagecompare.TimelapseOptions{
+		Width:         job.Width,
+		MaxHeight:     job.Width * 3 / 2,
+		DelayMs:       timelapseFrameDelayMs,
+		Stamp:         job.DateStamp,
+		HighlightDiff: job.HighlightDiff,
+		Quality:       s.derivativeQuality,
+	})
+	if err != nil {
+		if errors.Is(err, imagecompare.ErrNoFrames) {
+			return "", 0, errNoScreenshots
+		}
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("%s/timelapses/%s%s", job.PageID, job.ID, format.Extension())
+	if _, err := s.objectStorage.Upload(ctx, key, bytes.NewReader(data), int64(len(data)), format.ContentType()); err != nil {
+		return "", 0, fmt.Errorf("failed to upload time-lapse: %w", err)
+	}
+	return key, len(frames), nil
+}
+
+// pickTimelapseFrames keeps the checks whose screenshot differs from the one
+// before and, when more than limit remain, samples them evenly, keeping the
+// first and last.
+func pickTimelapseFrames(checks []*entities.Check, limit int) []*entities.Check {
+	var distinct []*entities.Check
+	for _, c := range checks {
+		if c.ScreenshotURL == "" {
+			continue
+		}
+		if n := len(distinct); n > 0 && c.ScreenshotURL == distinct[n-1].ScreenshotURL {
+			continue
+		}
+		distinct = append(distinct, c)
+	}
+	if len(distinct) <= limit || limit < 2 {
+		return distinct
+	}
+	picked := make([]*entities.Check, limit)
+	for i := range picked {
+		picked[i] = distinct[i*(len(distinct)-1)/(limit-1)]
+	}
+	return picked
+}
+
+// timelapseSource is the stored image a frame is rendered from: the preview
+// when it is wide enough, which is far smaller than the full screenshot.
+func timelapseSource(check *entities.Check, width int) string {
+	if check.PreviewURL != "" && width <= imagecompare.PreviewSpec.Width {
+		return check.PreviewURL
+	}
+	return check.ScreenshotURL
+}
@@ -1,16 +1,14 @@
 package application
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"time"
 
@@ -21,13 +19,16 @@ import (
 	"github.com/jcsoftdev/pulzifi-back/modules/email/infrastructure/templates"
 	generateinsights "github.com/jcsoftdev/pulzifi-back/modules/insight/application/generate_insights"
 	insightservices "github.com/jcsoftdev/pulzifi-back/modules/insight/domain/services"
+	integrationentities "github.com/jcsoftdev/pulzifi-back/modules/integration/domain/entities"
 	integrationPersistence "github.com/jcsoftdev/pulzifi-back/modules/integration/infrastructure/persistence"
 	"github.com/jcsoftdev/pulzifi-back/modules/integration/infrastructure/webhook"
+	listchecks "github.com/jcsoftdev/pulzifi-back/modules/monitoring/application/list_checks"
 	"github.com/jcsoftdev/pulzifi-back/modules/monitoring/domain/entities"
 	monPersistence "github.com/jcsoftdev/pulzifi-back/modules/monitoring/infrastructure/persistence"
 	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/repositories"
 	imagecompare "github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/services"
 	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/extractor"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/linkcheck"
 	sharedHTML "github.com/jcsoftdev/pulzifi-back/shared/html"
 	"github.com/jcsoftdev/pulzifi-back/shared/logger"
 	"github.com/jcsoftdev/pulzifi-back/shared/middleware"
@@ -44,6 +45,11 @@ type SnapshotWorker struct {
 	visionAnalyzer     insightservices.VisionAnalyzer
 	pixelDiffThreshold float64
 	onCheckDone        func(pageID uuid.UUID, checkJSON []byte)
+	linkChecker        *linkcheck.Checker
+	linkPasses         chan struct{}
+	credentialCipher   entities.SecretCipher
+	derivativeFormat   imagecompare.DerivativeFormat
+	derivativeQuality  int
 }
 
 // SetOnCheckDone registers a callback invoked after every check completes
@@ -62,34 +68,32 @@ func (s *SnapshotWorker) SetPixelDiffThreshold(threshold float64) {
 	s.pixelDiffThreshold = threshold
 }
 
-// notifyCheckDone serializes a check into the same DTO format the frontend
-// expects and invokes the onCheckDone callback if set.
+// SetLinkChecker enables broken link detection for pages that opt into it.
+func (s *SnapshotWorker) SetLinkChecker(checker *linkcheck.Checker) {
+	s.linkChecker = checker
+	s.linkPasses = make(chan struct{}, maxLinkPasses)
+}
+
+// SetCredentialCipher enables authenticated checks by letting the worker
+// decrypt page credentials.
+func (s *SnapshotWorker) SetCredentialCipher(cipher entities.SecretCipher) {
+	s.credentialCipher = cipher
+}
+
+// SetDerivativeEncoding sets how screenshot thumbnails and previews are
+// encoded (default JPEG at quality 80).
+func (s *SnapshotWorker) SetDerivativeEncoding(format string, quality int) {
+	s.derivativeFormat = imagecompare.ParseDerivativeFormat(format)
+	s.derivativeQuality = quality
+}
+
+// notifyCheckDone serializes a check into the same DTO the API returns and
+// invokes the onCheckDone callback if set.
 func (s *SnapshotWorker) notifyCheckDone(check *entities.Check) {
 	if s.onCheckDone == nil {
 		return
 	}
-	type checkResponse struct {
-		ID              uuid.UUID `json:"id"`
-		PageID          uuid.UUID `json:"page_id"`
-		Status          string    `json:"status"`
-		ScreenshotURL   string    `json:"screenshot_url"`
-		HTMLSnapshotURL string    `json:"html_snapshot_url"`
-		ChangeDetected  bool      `json:"change_detected"`
-		ChangeType      string    `json:"change_type"`
-		ErrorMessage    string    `json:"error_message,omitempty"`
-		CheckedAt       string    `json:"checked_at"`
-	}
-	payload, err := json.Marshal(checkResponse{
-		ID:              check.ID,
-		PageID:          check.PageID,
-		Status:          check.Status,
-		ScreenshotURL:   check.ScreenshotURL,
-		HTMLSnapshotURL: check.HTMLSnapshotURL,
-		ChangeDetected:  check.ChangeDetected,
-		ChangeType:      check.ChangeType,
-		ErrorMessage:    check.ErrorMessage,
-		CheckedAt:       check.CheckedAt.Format("2006-01-02T15:04:05.999999Z"),
-	})
+	payload, err := json.Marshal(listchecks.NewCheckResponse(check))
 	if err != nil {
 		logger.Error("Failed to serialize check for SSE notification", zap.Error(err))
 		return
@@ -106,6 +110,8 @@ func NewSnapshotWorker(objectStorage repositories.ObjectStorage, extractorClient
 		emailProvider:      emailProvider,
 		frontendURL:        frontendURL,
 		pixelDiffThreshold: 0.001, // default
+		derivativeFormat:   imagecompare.DerivativeJPEG,
+		derivativeQuality:  80,
 	}
 }
 
@@ -121,19 +127,42 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 		return fmt.Errorf("check not found: %s", checkID)
 	}
 
+	// Blob references taken for this check are dropped again unless the
+	// check is written with their keys.
+	refs := s.newBlobRefs(schemaName)
+
 	// markError is a helper that ensures the check reaches "error" status.
 	markError := func(msg string, duration int) error {
+		refs.release(ctx)
+		clearCaptureKeys(check)
 		check.Status = "error"
 		check.ErrorMessage = msg
 		check.DurationMs = duration
-		if updateErr := checkRepo.Update(ctx, check); updateErr != nil {
+		completed, updateErr := checkRepo.Complete(ctx, check)
+		if updateErr != nil {
 			logger.Error("Failed to mark check as error", zap.Error(updateErr), zap.String("check_id", checkID.String()))
 			return updateErr
 		}
-		s.notifyCheckDone(check)
+		if completed {
+			s.notifyCheckDone(check)
+		}
 		return fmt.Errorf("%s", msg)
 	}
 
+	// complete writes the check's outcome. A check resolved meanwhile, e.g.
+	// failed by the reaper past its run deadline, keeps that outcome and the
+	// capture's blob references are dropped.
+	complete := func() (bool, error) {
+		completed, err := checkRepo.Complete(ctx, check)
+		if err != nil || !completed {
+			refs.release(ctx)
+		}
+		if err == nil && !completed {
+			logger.Warn("Check was resolved while running, discarding its result", zap.String("check_id", checkID.String()))
+		}
+		return completed, err
+	}
+
 	// Fetch monitoring config before extraction to get block_ads_cookies + selector settings
 	configRepo := monPersistence.NewMonitoringConfigPostgresRepository(s.db, schemaName)
 	pageConfig, configErr := configRepo.GetByPageID(ctx, check.PageID)
@@ -144,6 +173,7 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 
 	enabledInsightTypes := []string{"marketing", "market_analysis"}
 	enabledAlertConditions := []string{"any_changes"}
+	suppressRecurrences := pageConfig == nil || pageConfig.SuppressRecurrences
 	if pageConfig != nil {
 		if len(pageConfig.EnabledInsightTypes) > 0 {
 			enabledInsightTypes = pageConfig.EnabledInsightTypes
@@ -153,10 +183,40 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 		}
 	}
 
-	extractOpts := extractor.ExtractOptions{}
+	// Pages pinned to a proxy region or pool never connect directly.
+	proxyRepo := monPersistence.NewProxyPostgresRepository(s.db, schemaName)
+	proxyRoute, err := s.resolveProxyRoute(ctx, proxyRepo, schemaName, check.PageID, pageConfig)
+	if err != nil {
+		return markError(err.Error(), 0)
+	}
+	var proxy *entities.Proxy
+	var proxyOpts *extractor.Proxy
+	if !proxyRoute.IsZero() {
+		proxy, proxyOpts, err = s.pickProxy(ctx, proxyRepo, schemaName, proxyRoute)
+		if err != nil {
+			return markError(err.Error(), 0)
+		}
+		check.ProxyID = &proxy.ID
+	}
+
+	credentialRepo := monPersistence.NewPageCredentialPostgresRepository(s.db, schemaName)
+	credential, auth, err := s.loadPageAuth(ctx, credentialRepo, schemaName, check)
+	if err != nil {
+		return markError(err.Error(), 0)
+	}
+
+	// PDF/DOCX URLs are parsed directly instead of being rendered; selectors
+	// and sections do not apply to them.
+	docKind := s.detectDocument(ctx, configRepo, pageConfig, targetURL, proxyOpts, auth)
+
+	extractOpts := extractor.ExtractOptions{Auth: auth, Proxy: proxyOpts}
 	if pageConfig != nil {
 		extractOpts.BlockAdsCookies = pageConfig.BlockAdsCookies
+		for _, step := range pageConfig.CaptureSteps {
+			extractOpts.Steps = append(extractOpts.Steps, extractor.CaptureStep(step))
+		}
 		extractOpts.IgnoreSelectors = pageConfig.IgnoreSelectors
+		extractOpts.Archive = pageConfig.Archive
 
 		switch pageConfig.SelectorType {
 		case "element":
@@ -173,6 +233,9 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 				}
 			}
 		case "sections":
+			if docKind != "" {
+				break
+			}
 			// Query sections and pass them to the extractor for a single page load.
 			sectionRepo := monPersistence.NewMonitoredSectionPostgresRepository(s.db, schemaName)
 			pageSections, err := sectionRepo.ListByPageID(ctx, check.PageID)
@@ -190,6 +253,11 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 			logger.Info("Processing sections mode",
 				zap.String("page_id", check.PageID.String()),
 				zap.Int("section_count", len(pageSections)))
+			if len(pageConfig.CaptureProfiles) > 0 {
+				// Rejected by config validation; only configs saved before it get here.
+				logger.Warn("Capture profiles are not captured in sections mode",
+					zap.String("page_id", check.PageID.String()))
+			}
 
 			sectionsByID := make(map[uuid.UUID]*entities.MonitoredSection, len(pageSections))
 			for _, sec := range pageSections {
@@ -198,12 +266,14 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 				if sec.SelectorOffsets != nil {
 					opt.Offsets = &extractor.SelectorOffsets{Top: sec.SelectorOffsets.Top, Right: sec.SelectorOffsets.Right, Bottom: sec.SelectorOffsets.Bottom, Left: sec.SelectorOffsets.Left}
 				}
+				opt.Fingerprint = toExtractorFingerprint(sec.Fingerprint)
 				extractOpts.Sections = append(extractOpts.Sections, opt)
 			}
 
 			startTime := time.Now()
-			res, err := s.extractorClient.Extract(ctx, targetURL, extractOpts)
+			res, _, err := s.extractThroughProxy(ctx, proxyRepo, schemaName, proxyRoute, proxy, check, targetURL, &extractOpts)
 			duration := int(time.Since(startTime).Milliseconds())
+			s.recordLoginResult(ctx, schemaName, credentialRepo, credential, check, targetURL, err)
 			if err != nil {
 				return markError(err.Error(), duration)
 			}
@@ -211,28 +281,27 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 			logger.Info("Extractor returned sections result",
 				zap.String("page_id", check.PageID.String()),
 				zap.Int("section_results", len(res.Sections)),
-				zap.Bool("has_full_page_screenshot", res.ScreenshotBase64 != ""))
+				zap.Bool("has_full_page_screenshot", len(res.Screenshot) > 0))
 
 			// Store full-page screenshot on the parent check if available.
-			if res.ScreenshotBase64 != "" {
-				if imgBytes, decErr := base64.StdEncoding.DecodeString(res.ScreenshotBase64); decErr == nil && len(imgBytes) > 0 {
-					ts := time.Now().Unix()
-					imgName := fmt.Sprintf("%s/%d.png", check.PageID, ts)
-					if imgURL, upErr := s.objectStorage.Upload(ctx, imgName, bytes.NewReader(imgBytes), int64(len(imgBytes)), "image/png"); upErr == nil {
-						check.ScreenshotURL = imgURL
-					}
+			if imgBytes := res.Screenshot; len(imgBytes) > 0 {
+				if imgKey, upErr := s.storeBlob(ctx, refs, check.PageID, imgBytes, imagecompare.HashScreenshot(imgBytes), ".png", "image/png"); upErr == nil {
+					check.ScreenshotURL = imgKey
+					s.storeDerivatives(ctx, refs, check, imgBytes)
 				}
 			}
+			s.storeArchive(ctx, refs, check, res.MHTML)
 
 			// Mark parent check as complete.
 			check.Status = "success"
+			check.FetchEngine = entities.FetchEngineBrowser
 			check.DurationMs = duration
-			if err := checkRepo.Update(ctx, check); err != nil {
+			if completed, err := complete(); err != nil || !completed {
 				return err
 			}
 			s.notifyCheckDone(check)
 
-			anyChanged := s.processSectionsFromExtractor(ctx, checkRepo, schemaName, check.ID, check.PageID, sectionsByID, res.Sections, targetURL, enabledAlertConditions)
+			anyChanged := s.processSectionsFromExtractor(ctx, checkRepo, sectionRepo, schemaName, check.ID, check.PageID, sectionsByID, res.Sections, targetURL, enabledAlertConditions, suppressRecurrences)
 			if anyChanged {
 				check.ChangeDetected = true
 				check.ChangeType = "content"
@@ -247,33 +316,70 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 		}
 	}
 
+	// Logins and DOM-level settings always need the browser, whatever the
+	// page's engine setting.
+	engine := entities.FetchEngineBrowser
+	if pageConfig != nil && auth == nil && !pageConfig.NeedsBrowser() {
+		engine = pageConfig.FetchEngineOrDefault()
+	}
+
 	startTime := time.Now()
-	res, err := s.extractorClient.Extract(ctx, targetURL, extractOpts)
+	var res *extractor.ExtractorResult
+	check.FetchEngine = entities.FetchEngineHTTP
+	if docKind != "" {
+		res, err = s.captureDocument(ctx, refs, check, targetURL, docKind, proxyOpts, auth)
+	} else {
+		res, err = s.captureHTTP(ctx, check, targetURL, engine, proxyOpts)
+	}
+	if errors.Is(err, errNotDocument) && pageConfig != nil && pageConfig.DocumentProbe.Fresh(targetURL, time.Now()) {
+		// The cached kind is stale: the URL serves a web page now.
+		s.rememberDocumentKind(ctx, configRepo, pageConfig, targetURL, "")
+	}
+	if (res == nil && err == nil) || errors.Is(err, errNotDocument) {
+		check.FetchEngine = entities.FetchEngineBrowser
+		res, proxy, err = s.extractThroughProxy(ctx, proxyRepo, schemaName, proxyRoute, proxy, check, targetURL, &extractOpts)
+		s.recordLoginResult(ctx, schemaName, credentialRepo, credential, check, targetURL, err)
+	}
 	duration := int(time.Since(startTime).Milliseconds())
 
 	if err != nil {
 		return markError(err.Error(), duration)
 	}
 
-	// Process Results
-	imgBytes, err := base64.StdEncoding.DecodeString(res.ScreenshotBase64)
-	if err != nil {
-		return markError(fmt.Sprintf("failed to decode screenshot: %v", err), duration)
+	// The extractor captures the full page when the element selector misses.
+	// That capture is only used when the page opted into the fallback.
+	if extractOpts.Selector != "" && check.FetchEngine == entities.FetchEngineBrowser {
+		s.recordElementSelector(ctx, schemaName, configRepo, checkRepo, pageConfig, check, targetURL, res.SelectorMatched)
+		if !res.SelectorMatched {
+			if !pageConfig.SelectorFallback {
+				return markError(fmt.Sprintf("selector %q no longer matches the page", pageConfig.CSSSelector), duration)
+			}
+			check.SelectorFallback = true
+		}
 	}
 
-	ts := time.Now().Unix()
-	imgName := fmt.Sprintf("%s/%d.png", check.PageID, ts)
-	htmlName := fmt.Sprintf("%s/%d.html", check.PageID, ts)
+	// Process Results
+	imgBytes := res.Screenshot
 
-	// Upload
+	// Screenshot hash (pixel-based)
+	var screenshotHash string
+	if len(imgBytes) > 0 {
+		screenshotHash = imagecompare.HashScreenshot(imgBytes)
+	}
+
+	// Upload. Captures identical to an earlier one reuse its object.
 	if s.objectStorage == nil {
 		return markError("object storage client is not configured", duration)
 	}
-	imgURL, err := s.objectStorage.Upload(ctx, imgName, bytes.NewReader(imgBytes), int64(len(imgBytes)), "image/png")
-	if err != nil {
-		return markError(fmt.Sprintf("failed to upload screenshot: %v", err), duration)
+	// Documents without an embedded page image have no screenshot.
+	var imgKey string
+	if len(imgBytes) > 0 {
+		imgKey, err = s.storeBlob(ctx, refs, check.PageID, imgBytes, screenshotHash, ".png", "image/png")
+		if err != nil {
+			return markError(fmt.Sprintf("failed to upload screenshot: %v", err), duration)
+		}
 	}
-	htmlURL, err := s.objectStorage.Upload(ctx, htmlName, strings.NewReader(res.HTML), int64(len(res.HTML)), "text/html")
+	htmlKey, err := s.storeBlob(ctx, refs, check.PageID, []byte(res.HTML), hashBytes([]byte(res.HTML)), ".html", "text/html")
 	if err != nil {
 		return markError(fmt.Sprintf("failed to upload html snapshot: %v", err), duration)
 	}
@@ -287,28 +393,41 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 	contentBlocks := sharedHTML.ExtractContentBlocks(res.HTML)
 	contentBlockHash := sharedHTML.HashContentBlocks(contentBlocks)
 
-	// Screenshot hash (pixel-based)
-	screenshotHash := imagecompare.HashScreenshot(imgBytes)
-
 	// Update Check
 	check.Status = "success"
 	check.DurationMs = duration
-	check.ScreenshotURL = imgURL
-	check.HTMLSnapshotURL = htmlURL
+	check.ScreenshotURL = imgKey
+	check.HTMLSnapshotURL = htmlKey
 	check.ContentHash = contentHashStr
 	check.ContentBlockHash = contentBlockHash
 	check.ScreenshotHash = screenshotHash
 	check.ChangeDetected = false
 	check.ChangeType = ""
+	if len(imgBytes) > 0 {
+		s.storeDerivatives(ctx, refs, check, imgBytes)
+	}
+	s.storeArchive(ctx, refs, check, res.MHTML)
 
 	// Fetch previous successful check for comparison
 	prevCheck := s.getPreviousSuccessfulCheck(ctx, checkRepo, check.PageID, check.ID)
 
+	if prevCheck != nil && !comparableCaptures(prevCheck, check) {
+		logger.Info("Capture method changed, starting a new baseline",
+			zap.String("page_id", check.PageID.String()),
+			zap.String("previous_engine", fetchEngineOf(prevCheck)),
+			zap.String("engine", check.FetchEngine),
+			zap.Bool("selector_fallback", check.SelectorFallback))
+		prevCheck = nil
+	}
+
+	stateRepo := monPersistence.NewContentStatePostgresRepository(s.db, schemaName)
+
 	if prevCheck != nil {
-		changeDetected, changeSummary, contentDiff := s.detectChange(ctx, prevCheck, check, imgBytes, res.ScreenshotBase64, targetURL, res.HTML)
+		changeDetected, changeSummary, contentDiff := s.detectChange(ctx, prevCheck, check, imgBytes, targetURL, res.HTML, detectOptions{})
+		check.ChangeDetected = changeDetected
+		alertable := classifyRecurrence(check, s.contentHistory(ctx, stateRepo, check), suppressRecurrences)
 
 		if changeDetected {
-			check.ChangeDetected = true
 			check.ChangeType = "content"
 			check.VisionChangeSummary = changeSummary
 
@@ -319,13 +438,17 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 				}
 			}
 
-			// Only alert if "any_changes" is an enabled alert condition
-			if sliceContains(enabledAlertConditions, "any_changes") {
+			// Only alert if "any_changes" is an enabled alert condition. Changes
+			// back to a recently seen state are A/B or rotation noise.
+			if !alertable {
+				logger.Info("Suppressing alert for recurring content state",
+					zap.String("check_id", check.ID.String()), zap.Bool("flapping", check.Flapping))
+			} else if sliceContains(enabledAlertConditions, "any_changes") {
 				s.createAlert(ctx, schemaName, check, targetURL, changeSummary)
 			}
 
 			// Generate insights for enabled types
-			if s.insightHandler != nil && len(enabledInsightTypes) > 0 {
+			if alertable && s.insightHandler != nil && len(enabledInsightTypes) > 0 {
 				var diffText string
 				if contentDiff != nil && contentDiff.HasChanges {
 					diffText = sharedHTML.FormatDiffForAI(contentDiff)
@@ -334,21 +457,54 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 				go s.generateInsightsAsync(check, targetURL, prevText, res.Text, schemaName, enabledInsightTypes, diffText)
 			}
 		}
+
+		// SEO metadata lives mostly in <head>, which content blocks ignore, so it
+		// is compared separately and alerted on with its own type.
+		if sliceContains(enabledAlertConditions, "seo_changes") {
+			if seoChanges := s.detectSEOChange(prevCheck, res.HTML); len(seoChanges) > 0 {
+				if !check.ChangeDetected {
+					check.ChangeDetected = true
+					check.ChangeType = "seo"
+				}
+				s.createSEOAlert(ctx, schemaName, check, targetURL, seoChanges)
+			}
+		}
 	}
 
-	if err := checkRepo.Update(ctx, check); err != nil {
+	if completed, err := complete(); err != nil || !completed {
 		return err
 	}
+	s.recordContentState(ctx, stateRepo, check)
 
 	s.notifyCheckDone(check)
 
-	if err := s.updatePageSnapshotMetadata(ctx, schemaName, check.PageID, imgURL, check.ChangeDetected); err != nil {
+	if err := s.updatePageSnapshotMetadata(ctx, schemaName, check.PageID, pageThumbnail(check), check.ChangeDetected); err != nil {
 		logger.Error("Failed to update page snapshot metadata", zap.Error(err), zap.String("page_id", check.PageID.String()))
 	}
 
+	// Capture profiles are extra device/locale variants of the same page, each
+	// recorded as a child check. Documents render the same everywhere.
+	if pageConfig != nil && len(pageConfig.CaptureProfiles) > 0 && docKind == "" {
+		pp := profileProxy{repo: proxyRepo, route: proxyRoute, proxy: proxy}
+		s.runCaptureProfiles(ctx, checkRepo, schemaName, check, targetURL, extractOpts, pp, pageConfig.CaptureProfiles, enabledAlertConditions)
+	}
+
+	// Link verification runs in the background after the check is reported so
+	// it never delays the result or holds the worker; newly broken links
+	// re-notify with the change type.
+	if pageConfig != nil && pageConfig.CheckBrokenLinks && s.linkChecker != nil {
+		go s.checkBrokenLinksAsync(schemaName, checkRepo, check, targetURL, res.HTML)
+	}
+
 	return nil
 }
 
+// detectOptions adjusts detectChange for replays over stored captures.
+type detectOptions struct {
+	ignoreSelectors []string // removed from the previous HTML snapshot before diffing
+	noVision        bool     // decide visual changes on the pixel diff alone
+}
+
 // detectChange runs a multi-stage content-first change detection pipeline:
 //
 //	Stage 1: Content block hash (fast structural identity)
@@ -358,7 +514,7 @@ func (s *SnapshotWorker) ExecuteCheck(ctx context.Context, checkID uuid.UUID, ta
 //	Stage 5: Normalized text hash fallback (legacy compatibility)
 //
 // Returns (changeDetected, changeSummary, contentDiff)
-func (s *SnapshotWorker) detectChange(ctx context.Context, prevCheck, currCheck *entities.Check, currImgBytes []byte, currBase64 string, pageURL string, currHTML string) (bool, string, *sharedHTML.ContentDiff) {
+func (s *SnapshotWorker) detectChange(ctx context.Context, prevCheck, currCheck *entities.Check, currImgBytes []byte, pageURL string, currHTML string, opts detectOptions) (bool, string, *sharedHTML.ContentDiff) {
 	pageID := currCheck.PageID.String()
 
 	// ── Stage 1: Content block hash comparison ───────────────────────────
@@ -376,9 +532,10 @@ func (s *SnapshotWorker) detectChange(ctx context.Context, prevCheck, currCheck
 						result, err := imagecompare.CompareScreenshots(prevImgBytes, currImgBytes, s.pixelDiffThreshold)
 						if err == nil && !result.Identical && result.DiffRatio >= s.pixelDiffThreshold {
 							// ── Stage 4: Vision AI (optional) ────────────
-							if s.visionAnalyzer != nil {
+							if s.visionAnalyzer != nil && !opts.noVision {
 								prevB64 := base64.StdEncoding.EncodeToString(prevImgBytes)
-								visionResult, vErr := s.visionAnalyzer.AnalyzeChange(ctx, prevB64, currBase64, pageURL)
+								currB64 := base64.StdEncoding.EncodeToString(currImgBytes)
+						visionResult, vErr := s.visionAnalyzer.AnalyzeChange(ctx, prevB64, currB64, pageURL)
 								if vErr != nil {
 									logger.Error("Vision AI failed, reporting visual change",
 										zap.Error(vErr), zap.String("page_id", pageID))
@@ -408,6 +565,9 @@ func (s *SnapshotWorker) detectChange(ctx context.Context, prevCheck, currCheck
 			zap.String("page_id", pageID))
 
 		prevHTML := s.fetchHTMLFromURL(prevCheck.HTMLSnapshotURL)
+		if len(opts.ignoreSelectors) > 0 {
+			prevHTML, _ = sharedHTML.RemoveElements(prevHTML, opts.ignoreSelectors)
+		}
 		var contentDiff *sharedHTML.ContentDiff
 		if prevHTML != "" {
 			prevBlocks := sharedHTML.ExtractContentBlocks(prevHTML)
@@ -448,9 +608,10 @@ func (s *SnapshotWorker) detectChange(ctx context.Context, prevCheck, currCheck
 					}
 
 					// ── Stage 4: Vision AI analysis (optional) ───────────
-					if s.visionAnalyzer != nil {
+					if s.visionAnalyzer != nil && !opts.noVision {
 						prevB64 := base64.StdEncoding.EncodeToString(prevImgBytes)
-						visionResult, vErr := s.visionAnalyzer.AnalyzeChange(ctx, prevB64, currBase64, pageURL)
+						currB64 := base64.StdEncoding.EncodeToString(currImgBytes)
+						visionResult, vErr := s.visionAnalyzer.AnalyzeChange(ctx, prevB64, currB64, pageURL)
 						if vErr != nil {
 							logger.Error("Vision AI failed, reporting change based on pixel diff",
 								zap.Error(vErr), zap.String("page_id", pageID))
@@ -484,22 +645,22 @@ func (s *SnapshotWorker) detectChange(ctx context.Context, prevCheck, currCheck
 }
 
 // downloadScreenshot fetches a screenshot using the object storage client.
-func (s *SnapshotWorker) downloadScreenshot(url string) []byte {
-	if url == "" {
+func (s *SnapshotWorker) downloadScreenshot(key string) []byte {
+	if key == "" {
 		return nil
 	}
 
 	if s.objectStorage == nil {
-		logger.Error("Object storage not configured, cannot download screenshot", zap.String("url", url))
+		logger.Error("Object storage not configured, cannot download screenshot", zap.String("key", key))
 		return nil
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	data, err := s.objectStorage.Download(ctx, url)
+	data, err := s.objectStorage.Download(ctx, key)
 	if err != nil {
-		logger.Error("Failed to download screenshot via object storage client", zap.String("url", url), zap.Error(err))
+		logger.Error("Failed to download screenshot via object storage client", zap.String("key", key), zap.Error(err))
 		return nil
 	}
 
@@ -515,15 +676,25 @@ func (s *SnapshotWorker) getPreviousSuccessfulCheck(ctx context.Context, repo *m
 }
 
 
-func (s *SnapshotWorker) createAlert(ctx context.Context, schemaName string, check *entities.Check, pageURL string, changeSummary string) {
+// pageWorkspaceID resolves the workspace that owns a page so alerts can be
+// scoped to it. Returns false (after logging) when the lookup fails.
+func (s *SnapshotWorker) pageWorkspaceID(ctx context.Context, schemaName string, pageID uuid.UUID) (uuid.UUID, bool) {
 	if _, err := s.db.ExecContext(ctx, middleware.GetSetSearchPathSQL(schemaName)); err != nil {
 		logger.Error("Failed to set search path for alert", zap.Error(err))
-		return
+		return uuid.Nil, false
 	}
 
 	var workspaceID uuid.UUID
-	if err := s.db.QueryRowContext(ctx, `SELECT workspace_id FROM pages WHERE id = $1`, check.PageID).Scan(&workspaceID); err != nil {
-		logger.Error("Failed to get workspace_id for alert", zap.Error(err), zap.String("page_id", check.PageID.String()))
+	if err := s.db.QueryRowContext(ctx, `SELECT workspace_id FROM pages WHERE id = $1`, pageID).Scan(&workspaceID); err != nil {
+		logger.Error("Failed to get workspace_id for alert", zap.Error(err), zap.String("page_id", pageID.String()))
+		return uuid.Nil, false
+	}
+	return workspaceID, true
+}
+
+func (s *SnapshotWorker) createAlert(ctx context.Context, schemaName string, check *entities.Check, pageURL string, changeSummary string) {
+	workspaceID, ok := s.pageWorkspaceID(ctx, schemaName, check.PageID)
+	if !ok {
 		return
 	}
 
@@ -537,6 +708,12 @@ func (s *SnapshotWorker) createAlert(ctx context.Context, schemaName string, che
 
 	alert := alertentities.NewAlert(workspaceID, check.PageID, check.ID, "content_change", alertTitle, alertDescription)
 	alert.ChangeSummary = changeSummary
+	// Recurrences only reach here with suppression off; flag them so they
+	// can be ranked below genuinely new content.
+	if check.Recurrence {
+		alert.Metadata = alertentities.Metadata{"recurrence": true, "flapping": check.Flapping}
+	}
+	s.groupIntoIncident(ctx, schemaName, alert, check, pageURL)
 
 	alertRepo := alertPersistence.NewAlertPostgresRepository(s.db, schemaName)
 	if err := alertRepo.Create(ctx, alert); err != nil {
@@ -545,15 +722,20 @@ func (s *SnapshotWorker) createAlert(ctx context.Context, schemaName string, che
 		logger.Info("Alert created", zap.String("check_id", check.ID.String()))
 	}
 
+	// A site-wide change reaches each subscriber and integration once: alerts
+	// joining an incident skip the recipients earlier alerts notified.
+
 	// Send email notifications asynchronously
-	go s.sendAlertEmails(schemaName, check, pageURL, changeSummary)
+	go s.sendAlertEmails(schemaName, check, pageURL, changeSummary, alert.IncidentID)
 
 	// Dispatch webhooks (Slack, Discord, Teams) asynchronously
-	go s.dispatchWebhooks(schemaName, check, pageURL, changeSummary)
+	go s.dispatchAlertWebhooks(schemaName, check, pageURL, changeSummary, alert.IncidentID)
 }
 
-// sendAlertEmails queries notification preferences for the page and sends email alerts.
-func (s *SnapshotWorker) sendAlertEmails(schemaName string, check *entities.Check, pageURL string, changeSummary string) {
+// sendAlertEmails queries notification preferences for the page and sends email
+// alerts. For an alert in an incident, only subscribers the incident has not
+// notified yet are emailed.
+func (s *SnapshotWorker) sendAlertEmails(schemaName string, check *entities.Check, pageURL string, changeSummary string, incidentID *uuid.UUID) {
 	if s.emailProvider == nil {
 		return
 	}
@@ -561,44 +743,66 @@ func (s *SnapshotWorker) sendAlertEmails(schemaName string, check *entities.Chec
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	notifRepo := monPersistence.NewNotificationPreferencePostgresRepository(s.db, schemaName)
-	prefs, err := notifRepo.GetEmailEnabledByPage(ctx, check.PageID)
+	dashboardURL := fmt.Sprintf("%s/workspaces", s.frontendURL)
+	changeType := check.ChangeType
+	if changeType == "" {
+		changeType = "content"
+	}
+	// Use change summary as the change type description if available
+	if changeSummary != "" {
+		changeType = changeSummary
+	}
+	subject, html := templates.AlertNotification(pageURL, changeType, dashboardURL)
+
+	userIDs, err := s.emailSubscribers(ctx, schemaName, check.PageID, "page_change")
 	if err != nil {
 		logger.Error("Failed to get email-enabled preferences", zap.Error(err))
 		return
 	}
-
-	if len(prefs) == 0 {
-		return
+	if incidentID != nil {
+		userIDs = s.unnotifiedIncidentRecipients(ctx, schemaName, *incidentID, recipientUser, userIDs)
 	}
+	s.sendEmails(ctx, userIDs, subject, html)
+}
 
-	// Filter preferences by change_types: empty means all types, otherwise must include "page_change"
-	var filteredPrefs []*entities.NotificationPreference
-	for _, pref := range prefs {
-		if len(pref.ChangeTypes) == 0 || sliceContains(pref.ChangeTypes, "page_change") {
-			filteredPrefs = append(filteredPrefs, pref)
-		}
-	}
-	if len(filteredPrefs) == 0 {
+// sendEmailToSubscribers delivers an email to every user with email
+// notifications enabled for the page whose change_types include changeType
+// (an empty change_types list subscribes to all types).
+func (s *SnapshotWorker) sendEmailToSubscribers(ctx context.Context, schemaName string, pageID uuid.UUID, changeType, subject, html string) {
+	userIDs, err := s.emailSubscribers(ctx, schemaName, pageID, changeType)
+	if err != nil {
+		logger.Error("Failed to get email-enabled preferences", zap.Error(err))
 		return
 	}
+	s.sendEmails(ctx, userIDs, subject, html)
+}
 
-	dashboardURL := fmt.Sprintf("%s/workspaces", s.frontendURL)
-	changeType := check.ChangeType
-	if changeType == "" {
-		changeType = "content"
+// emailSubscribers returns the users with email notifications enabled for the
+// page whose change_types include changeType.
+func (s *SnapshotWorker) emailSubscribers(ctx context.Context, schemaName string, pageID uuid.UUID, changeType string) ([]uuid.UUID, error) {
+	notifRepo := monPersistence.NewNotificationPreferencePostgresRepository(s.db, schemaName)
+	prefs, err := notifRepo.GetEmailEnabledByPage(ctx, pageID)
+	if err != nil {
+		return nil, err
 	}
-	// Use change summary as the change type description if available
-	if changeSummary != "" {
-		changeType = changeSummary
+
+	var userIDs []uuid.UUID
+	for _, pref := range prefs {
+		if len(pref.ChangeTypes) > 0 && !sliceContains(pref.ChangeTypes, changeType) {
+			continue
+		}
+		userIDs = append(userIDs, pref.UserID)
 	}
-	subject, html := templates.AlertNotification(pageURL, changeType, dashboardURL)
+	return userIDs, nil
+}
 
-	for _, pref := range filteredPrefs {
+// sendEmails delivers an email to each user.
+func (s *SnapshotWorker) sendEmails(ctx context.Context, userIDs []uuid.UUID, subject, html string) {
+	for _, userID := range userIDs {
 		// Look up user email
 		var email string
-		if err := s.db.QueryRowContext(ctx, `SELECT email FROM public.users WHERE id = $1`, pref.UserID).Scan(&email); err != nil {
-			logger.Error("Failed to get user email for alert notification", zap.Error(err), zap.String("user_id", pref.UserID.String()))
+		if err := s.db.QueryRowContext(ctx, `SELECT email FROM public.users WHERE id = $1`, userID).Scan(&email); err != nil {
+			logger.Error("Failed to get user email for alert notification", zap.Error(err), zap.String("user_id", userID.String()))
 			continue
 		}
 		if err := s.emailProvider.Send(ctx, email, subject, html); err != nil {
@@ -609,6 +813,13 @@ func (s *SnapshotWorker) sendAlertEmails(schemaName string, check *entities.Chec
 
 // dispatchWebhooks sends webhook notifications to enabled Slack/Discord/Teams integrations.
 func (s *SnapshotWorker) dispatchWebhooks(schemaName string, check *entities.Check, pageURL string, changeSummary string) {
+	s.dispatchAlertWebhooks(schemaName, check, pageURL, changeSummary, nil)
+}
+
+// dispatchAlertWebhooks sends webhook notifications to enabled
+// Slack/Discord/Teams integrations. For an alert in an incident, only
+// integrations the incident has not notified yet are sent to.
+func (s *SnapshotWorker) dispatchAlertWebhooks(schemaName string, check *entities.Check, pageURL string, changeSummary string, incidentID *uuid.UUID) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -623,6 +834,7 @@ func (s *SnapshotWorker) dispatchWebhooks(schemaName string, check *entities.Che
 		changeType = changeSummary
 	}
 
+	var enabled []*integrationentities.Integration
 	for _, serviceType := range []string{"slack", "discord", "teams"} {
 		integrations, err := integrationRepo.ListByServiceType(ctx, serviceType)
 		if err != nil {
@@ -630,16 +842,36 @@ func (s *SnapshotWorker) dispatchWebhooks(schemaName string, check *entities.Che
 			continue
 		}
 		for _, integration := range integrations {
-			if !integration.Enabled {
-				continue
+			if integration.Enabled {
+				enabled = append(enabled, integration)
 			}
-			if err := sender.Dispatch(ctx, integration, pageURL, changeType); err != nil {
-				logger.Error("Failed to dispatch webhook",
-					zap.Error(err),
-					zap.String("service_type", serviceType),
-					zap.String("integration_id", integration.ID.String()))
+		}
+	}
+	if incidentID != nil {
+		ids := make([]uuid.UUID, len(enabled))
+		for i, integration := range enabled {
+			ids[i] = integration.ID
+		}
+		pending := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range s.unnotifiedIncidentRecipients(ctx, schemaName, *incidentID, recipientIntegration, ids) {
+			pending[id] = true
+		}
+		kept := enabled[:0]
+		for _, integration := range enabled {
+			if pending[integration.ID] {
+				kept = append(kept, integration)
 			}
 		}
+		enabled = kept
+	}
+
+	for _, integration := range enabled {
+		if err := sender.Dispatch(ctx, integration, pageURL, changeType); err != nil {
+			logger.Error("Failed to dispatch webhook",
+				zap.Error(err),
+				zap.String("service_type", integration.ServiceType),
+				zap.String("integration_id", integration.ID.String()))
+		}
 	}
 }
 
@@ -675,77 +907,19 @@ func sliceContains(s []string, target string) bool {
 	return false
 }
 
-// fetchTextFromURL downloads HTML from the given URL and extracts plain text.
-// It retries up to 3 times with exponential backoff (1s, 2s, 4s) and uses a
-// 30-second timeout per attempt.
-func (s *SnapshotWorker) fetchTextFromURL(rawURL string) string {
-	if rawURL == "" {
+// fetchTextFromURL downloads an HTML snapshot and extracts plain text.
+func (s *SnapshotWorker) fetchTextFromURL(key string) string {
+	html := s.fetchHTMLFromURL(key)
+	if html == "" {
 		return ""
 	}
-
-	const maxRetries = 3
-	backoff := 1 * time.Second
-
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
-		if err != nil {
-			cancel()
-			logger.Error("Failed to create request for text extraction",
-				zap.String("url", rawURL), zap.Error(err))
-			return ""
-		}
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			cancel()
-			lastErr = err
-			logger.Warn("Fetch attempt failed, will retry",
-				zap.String("url", rawURL),
-				zap.Int("attempt", attempt),
-				zap.Int("max_retries", maxRetries),
-				zap.Duration("backoff", backoff),
-				zap.Error(err))
-			if attempt < maxRetries {
-				time.Sleep(backoff)
-				backoff *= 2
-			}
-			continue
-		}
-
-		content, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		cancel()
-		if err != nil {
-			lastErr = err
-			logger.Warn("Failed to read HTML body, will retry",
-				zap.String("url", rawURL),
-				zap.Int("attempt", attempt),
-				zap.Int("max_retries", maxRetries),
-				zap.Duration("backoff", backoff),
-				zap.Error(err))
-			if attempt < maxRetries {
-				time.Sleep(backoff)
-				backoff *= 2
-			}
-			continue
-		}
-
-		return sharedHTML.ExtractText(string(content))
-	}
-
-	logger.Error("All fetch attempts exhausted for text extraction",
-		zap.String("url", rawURL),
-		zap.Int("attempts", maxRetries),
-		zap.Error(lastErr))
-	return ""
+	return sharedHTML.ExtractText(html)
 }
 
-// fetchHTMLFromURL downloads raw HTML from the given URL. Uses the object storage
-// client. Returns empty string on failure.
-func (s *SnapshotWorker) fetchHTMLFromURL(rawURL string) string {
-	if rawURL == "" {
+// fetchHTMLFromURL downloads a raw HTML snapshot by its object key. Returns
+// empty string on failure.
+func (s *SnapshotWorker) fetchHTMLFromURL(key string) string {
+	if key == "" {
 		return ""
 	}
 
@@ -756,9 +930,9 @@ func (s *SnapshotWorker) fetchHTMLFromURL(rawURL string) string {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	data, err := s.objectStorage.Download(ctx, rawURL)
+	data, err := s.objectStorage.Download(ctx, key)
 	if err != nil {
-		logger.Error("Failed to download HTML snapshot", zap.String("url", rawURL), zap.Error(err))
+		logger.Error("Failed to download HTML snapshot", zap.String("key", key), zap.Error(err))
 		return ""
 	}
 
@@ -772,6 +946,7 @@ func (s *SnapshotWorker) fetchHTMLFromURL(rawURL string) string {
 func (s *SnapshotWorker) processSectionsFromExtractor(
 	ctx context.Context,
 	checkRepo *monPersistence.CheckPostgresRepository,
+	sectionRepo *monPersistence.MonitoredSectionPostgresRepository,
 	schemaName string,
 	parentCheckID uuid.UUID,
 	pageID uuid.UUID,
@@ -779,20 +954,17 @@ func (s *SnapshotWorker) processSectionsFromExtractor(
 	sectionResults []extractor.SectionExtractResult,
 	targetURL string,
 	enabledAlertConditions []string,
+	suppressRecurrences bool,
 ) bool {
 	anyChanged := false
+	alertChanged := false
 	firstScreenshotURL := ""
 	var changeSummaries []string
+	var brokenSelectors []brokenSelector
+	stateRepo := monPersistence.NewContentStatePostgresRepository(s.db, schemaName)
 
 	for i := range sectionResults {
 		sec := &sectionResults[i]
-		if sec.ScreenshotBase64 == "" {
-			logger.Warn("Section has no screenshot (selector may not have matched)",
-				zap.String("section_id", sec.ID),
-				zap.Bool("selector_matched", sec.SelectorMatched))
-			continue
-		}
-
 		sectionID, err := uuid.Parse(sec.ID)
 		if err != nil {
 			logger.Warn("Invalid section ID in extractor result", zap.String("id", sec.ID))
@@ -803,24 +975,29 @@ func (s *SnapshotWorker) processSectionsFromExtractor(
 			continue
 		}
 
-		imgBytes, err := base64.StdEncoding.DecodeString(sec.ScreenshotBase64)
-		if err != nil || len(imgBytes) == 0 {
-			logger.Warn("Failed to decode section screenshot", zap.String("section_id", sec.ID), zap.Error(err))
+		if broken := s.recordSectionSelector(ctx, sectionRepo, checkRepo, section, sec.SelectorMatched); broken != nil {
+			broken.ProposedSelector = sec.ProposedSelector
+			brokenSelectors = append(brokenSelectors, *broken)
+		}
+		imgBytes := sec.Screenshot
+		if len(imgBytes) == 0 {
+			logger.Warn("Section has no screenshot",
+				zap.String("section_id", sec.ID),
+				zap.Bool("selector_matched", sec.SelectorMatched))
 			continue
 		}
 
-		ts := time.Now().Unix()
-		imgName := fmt.Sprintf("%s/sections/%s/%d.png", pageID, sectionID, ts)
-		imgURL, err := s.objectStorage.Upload(ctx, imgName, bytes.NewReader(imgBytes), int64(len(imgBytes)), "image/png")
+		refs := s.newBlobRefs(schemaName)
+		screenshotHash := imagecompare.HashScreenshot(imgBytes)
+		imgKey, err := s.storeBlob(ctx, refs, pageID, imgBytes, screenshotHash, ".png", "image/png")
 		if err != nil {
 			logger.Error("Failed to upload section screenshot", zap.String("section_id", sec.ID), zap.Error(err))
 			continue
 		}
 
-		htmlURL := ""
+		htmlKey := ""
 		if sec.HTML != "" {
-			htmlName := fmt.Sprintf("%s/sections/%s/%d.html", pageID, sectionID, ts)
-			htmlURL, _ = s.objectStorage.Upload(ctx, htmlName, strings.NewReader(sec.HTML), int64(len(sec.HTML)), "text/html")
+			htmlKey, _ = s.storeBlob(ctx, refs, pageID, []byte(sec.HTML), hashBytes([]byte(sec.HTML)), ".html", "text/html")
 		}
 
 		contentHash := sha256.Sum256([]byte(sharedHTML.ExtractText(sec.HTML)))
@@ -832,17 +1009,19 @@ func (s *SnapshotWorker) processSectionsFromExtractor(
 		sectionCheck := entities.NewCheck(pageID, "success", false)
 		sectionCheck.SectionID = &section.ID
 		sectionCheck.ParentCheckID = &parentCheckID
-		sectionCheck.ScreenshotURL = imgURL
-		sectionCheck.HTMLSnapshotURL = htmlURL
+		sectionCheck.ScreenshotURL = imgKey
+		sectionCheck.HTMLSnapshotURL = htmlKey
 		sectionCheck.ContentHash = contentHashStr
 		sectionCheck.ContentBlockHash = sectionContentBlockHash
-		sectionCheck.ScreenshotHash = imagecompare.HashScreenshot(imgBytes)
+		sectionCheck.ScreenshotHash = screenshotHash
+		s.storeDerivatives(ctx, refs, sectionCheck, imgBytes)
 
 		prevSectionCheck, _ := checkRepo.GetPreviousSuccessfulBySection(ctx, pageID, &section.ID, uuid.Nil)
 		if prevSectionCheck != nil {
-			changeDetected, changeSummary, contentDiff := s.detectChange(ctx, prevSectionCheck, sectionCheck, imgBytes, sec.ScreenshotBase64, targetURL, sec.HTML)
+			changeDetected, changeSummary, contentDiff := s.detectChange(ctx, prevSectionCheck, sectionCheck, imgBytes, targetURL, sec.HTML, detectOptions{})
+			sectionCheck.ChangeDetected = changeDetected
+			alertable := classifyRecurrence(sectionCheck, s.contentHistory(ctx, stateRepo, sectionCheck), suppressRecurrences)
 			if changeDetected {
-				sectionCheck.ChangeDetected = true
 				sectionCheck.ChangeType = "content"
 				sectionCheck.VisionChangeSummary = changeSummary
 				if contentDiff != nil && contentDiff.HasChanges {
@@ -851,34 +1030,45 @@ func (s *SnapshotWorker) processSectionsFromExtractor(
 					}
 				}
 				anyChanged = true
-				if changeSummary != "" {
-					changeSummaries = append(changeSummaries, changeSummary)
+				if alertable {
+					alertChanged = true
+					if changeSummary != "" {
+						changeSummaries = append(changeSummaries, changeSummary)
+					}
 				}
 			}
 		}
 
 		if err := checkRepo.Create(ctx, sectionCheck); err != nil {
 			logger.Error("Failed to create section check", zap.String("section_id", sec.ID), zap.Error(err))
+			refs.release(ctx)
 			continue
 		}
+		s.recordContentState(ctx, stateRepo, sectionCheck)
 		s.notifyCheckDone(sectionCheck)
+		s.recordSelectorProposal(ctx, sectionRepo, section, sec, imgKey)
 
 		if firstScreenshotURL == "" {
-			firstScreenshotURL = imgURL
+			firstScreenshotURL = pageThumbnail(sectionCheck)
 		}
 	}
 
-	// Create a single aggregated alert for all section changes
-	if anyChanged && sliceContains(enabledAlertConditions, "any_changes") {
-		aggregatedSummary := strings.Join(changeSummaries, "; ")
-		// Use the parent check for the alert to avoid FK issues with section checks
+	// Create a single aggregated alert for all section changes and another for
+	// sections whose selectors broke. Alerts use the parent check to avoid FK
+	// issues with section checks.
+	if (alertChanged && sliceContains(enabledAlertConditions, "any_changes")) || len(brokenSelectors) > 0 {
 		parentCheck, err := checkRepo.GetByID(ctx, parentCheckID)
 		if err != nil {
 			logger.Error("Failed to retrieve parent check for aggregated alert",
 				zap.Error(err), zap.String("parent_check_id", parentCheckID.String()))
 		}
 		if parentCheck != nil {
-			s.createAlert(ctx, schemaName, parentCheck, targetURL, aggregatedSummary)
+			if alertChanged && sliceContains(enabledAlertConditions, "any_changes") {
+				s.createAlert(ctx, schemaName, parentCheck, targetURL, strings.Join(changeSummaries, "; "))
+			}
+			if len(brokenSelectors) > 0 {
+				s.createSelectorBrokenAlert(ctx, schemaName, parentCheck, targetURL, brokenSelectors)
+			}
 		}
 	}
 
@@ -3,9 +3,29 @@ package repositories
 import (
 	"context"
 	"io"
+	"time"
 )
 
+// SignedURLExpiry is how long the snapshot URLs the API hands out stay valid.
+const SignedURLExpiry = 15 * time.Minute
+
+// ObjectStorage keeps snapshots private. Objects are referred to by the key
+// Upload returns and are only readable through signed URLs.
 type ObjectStorage interface {
+	// Upload stores an object and returns its key.
 	Upload(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error)
 	EnsureBucket(ctx context.Context) error
+	// Download returns the content of an object.
+	Download(ctx context.Context, key string) ([]byte, error)
+	// Delete removes an object. Deleting an object that no longer exists is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the keys of the objects whose names start with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// SignedURL returns a URL that grants read access to an object until
+	// expiry has passed.
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// Adopt takes over an object uploaded under a public URL before snapshots
+	// were private: it stops serving the object publicly and returns its key.
+	Adopt(ctx context.Context, objectURL string) (string, error)
 }
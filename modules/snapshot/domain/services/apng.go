@@ -0,0 +1,115 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// encodeAPNG writes the frames, which share one size, as an animated PNG. Each
+// frame is encoded with image/png and its image data moved into the frame
+// chunks of the APNG extension; viewers without APNG support show the first
+// frame.
+func encodeAPNG(w io.Writer, images []*image.NRGBA, delayMs int) error {
+	var out bytes.Buffer
+	out.Write(pngSignature)
+	seq := uint32(0)
+	bounds := images[0].Rect
+	var ihdr []byte
+
+	for i, img := range images {
+		var enc bytes.Buffer
+		if err := png.Encode(&enc, img); err != nil {
+			return err
+		}
+		chunks, err := readPNGChunks(enc.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// Frames share the first frame's header, so they must encode alike.
+		if i > 0 && !bytes.Equal(chunks[0].data, ihdr) {
+			return errors.New("APNG frames differ in size or color type")
+		}
+		if i == 0 {
+			// IHDR, then the animation control chunk.
+			ihdr = chunks[0].data
+			writePNGChunk(&out, "IHDR", ihdr)
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(images)))
+			binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+			writePNGChunk(&out, "acTL", actl)
+		}
+
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(bounds.Dy()))
+		binary.BigEndian.PutUint16(fctl[20:], uint16(min(max(delayMs, 0), 65535)))
+		binary.BigEndian.PutUint16(fctl[22:], 1000) // delay in milliseconds
+		writePNGChunk(&out, "fcTL", fctl)
+		seq++
+
+		for _, c := range chunks {
+			if c.typ != "IDAT" {
+				continue
+			}
+			if i == 0 {
+				writePNGChunk(&out, "IDAT", c.data)
+				continue
+			}
+			fdat := make([]byte, 4+len(c.data))
+			binary.BigEndian.PutUint32(fdat, seq)
+			copy(fdat[4:], c.data)
+			writePNGChunk(&out, "fdAT", fdat)
+			seq++
+		}
+	}
+	writePNGChunk(&out, "IEND", nil)
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+func readPNGChunks(b []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(b, pngSignature) {
+		return nil, errors.New("not a PNG")
+	}
+	b = b[len(pngSignature):]
+	var chunks []pngChunk
+	for len(b) >= 12 {
+		n := int(binary.BigEndian.Uint32(b))
+		if len(b) < 12+n {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{typ: string(b[4:8]), data: b[8 : 8+n]})
+		b = b[12+n:]
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" {
+		return nil, errors.New("PNG without IHDR")
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(w *bytes.Buffer, typ string, data []byte) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(data)))
+	w.Write(n[:])
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	w.WriteString(typ)
+	w.Write(data)
+	binary.BigEndian.PutUint32(n[:], crc.Sum32())
+	w.Write(n[:])
+}
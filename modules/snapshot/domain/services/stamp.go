@@ -0,0 +1,64 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// glyphs is a 3x5 pixel font for date stamps. Each row is three bits, the
+// leftmost pixel in the high bit. Characters without a glyph are drawn as
+// spaces.
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'-': {0, 0, 7, 0, 0},
+	':': {0, 2, 0, 2, 0},
+	'/': {1, 1, 2, 4, 4},
+	'.': {0, 0, 0, 0, 2},
+}
+
+// stampScale is the size of a glyph pixel; stampPadding surrounds the text.
+const (
+	stampScale   = 3
+	stampPadding = 2 * stampScale
+)
+
+var stampBackground = color.NRGBA{A: 200}
+
+// drawLabel stamps text in white on a dark box in img's bottom-left corner.
+func drawLabel(img *image.NRGBA, text string) {
+	if text == "" {
+		return
+	}
+	runes := []rune(text)
+	w := len(runes)*4*stampScale - stampScale + 2*stampPadding
+	h := 5*stampScale + 2*stampPadding
+	b := img.Rect
+	box := image.Rect(b.Min.X, b.Max.Y-h, b.Min.X+w, b.Max.Y).Intersect(b)
+	draw.Draw(img, box, image.NewUniform(stampBackground), image.Point{}, draw.Over)
+
+	white := image.NewUniform(color.White)
+	for i, r := range runes {
+		glyph := glyphs[r]
+		x0 := box.Min.X + stampPadding + i*4*stampScale
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+				x := x0 + col*stampScale
+				y := box.Min.Y + stampPadding + row*stampScale
+				draw.Draw(img, image.Rect(x, y, x+stampScale, y+stampScale).Intersect(b), white, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+)
+
+// DerivativeFormat is the encoding of screenshot derivatives.
+type DerivativeFormat string
+
+const (
+	DerivativeJPEG DerivativeFormat = "jpeg"
+	DerivativePNG  DerivativeFormat = "png"
+)
+
+// ParseDerivativeFormat maps a configured format name to a supported
+// encoding. Unknown names, including webp for which no encoder is bundled,
+// fall back to JPEG.
+func ParseDerivativeFormat(name string) DerivativeFormat {
+	if strings.EqualFold(strings.TrimSpace(name), string(DerivativePNG)) {
+		return DerivativePNG
+	}
+	return DerivativeJPEG
+}
+
+func (f DerivativeFormat) Extension() string {
+	if f == DerivativePNG {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+func (f DerivativeFormat) ContentType() string {
+	if f == DerivativePNG {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// DerivativeSpec sizes a derivative: at most Width pixels wide and, when
+// MaxHeight is set, cropped to the top MaxHeight pixels.
+type DerivativeSpec struct {
+	Width     int
+	MaxHeight int
+}
+
+var (
+	// ThumbnailSpec is the above-the-fold card image of page lists.
+	ThumbnailSpec = DerivativeSpec{Width: 320, MaxHeight: 240}
+	// PreviewSpec is the medium image shown before the full screenshot loads.
+	PreviewSpec = DerivativeSpec{Width: 1024, MaxHeight: 3072}
+)
+
+// ResizeToWidth decodes a PNG or JPEG image, downscales it to at most maxWidth
+// pixels wide (preserving aspect ratio, area-averaged) and re-encodes it as
+// PNG. Images already narrower than maxWidth are only re-encoded.
+func ResizeToWidth(imgBytes []byte, maxWidth int) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(imgBytes))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	src := toNRGBA(img)
+	if maxWidth > 0 && src.Bounds().Dx() > maxWidth {
+		src = downscale(src, maxWidth)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		return nil, fmt.Errorf("encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale shrinks src to width w by averaging the source pixels covered by
+// each destination pixel (box filter), which avoids the aliasing of nearest
+// neighbour sampling on text-heavy document pages.
+func downscale(src *image.NRGBA, w int) *image.NRGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	h := max(1, sh*w/sw)
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for dy := 0; dy < h; dy++ {
+		y0, y1 := dy*sh/h, max((dy+1)*sh/h, dy*sh/h+1)
+		for dx := 0; dx < w; dx++ {
+			x0, x1 := dx*sw/w, max((dx+1)*sw/w, dx*sw/w+1)
+
+			var r, g, b, a, n uint32
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := pixelAt(src, sb.Min.X+x, sb.Min.Y+y)
+					r += uint32(pr)
+					g += uint32(pg)
+					b += uint32(pb)
+					a += uint32(pa)
+					n++
+				}
+			}
+
+			offset := dy*dst.Stride + dx*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// EncodeDerivatives decodes a screenshot once and returns one downscaled copy
+// per spec, encoded in format. quality applies to JPEG (1-100).
+func EncodeDerivatives(imgBytes []byte, format DerivativeFormat, quality int, specs ...DerivativeSpec) ([][]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(imgBytes))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	src := toNRGBA(img)
+
+	out := make([][]byte, len(specs))
+	for i, spec := range specs {
+		var buf bytes.Buffer
+		dst := derive(src, spec)
+		if format == DerivativePNG {
+			err = png.Encode(&buf, dst)
+		} else {
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: min(max(quality, 1), 100)})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("encode image: %w", err)
+		}
+		out[i] = buf.Bytes()
+	}
+	return out, nil
+}
+
+// derive crops src to the part that ends up within spec after scaling, then
+// downscales it.
+func derive(src *image.NRGBA, spec DerivativeSpec) *image.NRGBA {
+	sb := src.Bounds()
+	scaled := spec.Width > 0 && sb.Dx() > spec.Width
+	if spec.MaxHeight > 0 {
+		maxSrcHeight := spec.MaxHeight
+		if scaled {
+			maxSrcHeight = spec.MaxHeight * sb.Dx() / spec.Width
+		}
+		if sb.Dy() > maxSrcHeight {
+			src = src.SubImage(image.Rect(sb.Min.X, sb.Min.Y, sb.Max.X, sb.Min.Y+maxSrcHeight)).(*image.NRGBA)
+		}
+	}
+	if scaled {
+		return downscale(src, spec.Width)
+	}
+	return src
+}
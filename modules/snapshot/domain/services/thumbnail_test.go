@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestResizeToWidth(t *testing.T) {
+	// Left half black, right half white.
+	src := makeImageFromFunc(400, 200, func(x, y int) color.NRGBA {
+		if x < 200 {
+			return color.NRGBA{0, 0, 0, 255}
+		}
+		return color.NRGBA{255, 255, 255, 255}
+	})
+
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, src, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		input        []byte
+		maxWidth     int
+		wantW, wantH int
+	}{
+		{"png downscaled", encodePNG(src), 100, 100, 50},
+		{"jpeg downscaled", jpg.Bytes(), 100, 100, 50},
+		{"narrower than max", encodePNG(src), 800, 400, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ResizeToWidth(tt.input, tt.maxWidth)
+			if err != nil {
+				t.Fatalf("ResizeToWidth: %v", err)
+			}
+			img, err := png.Decode(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("output is not PNG: %v", err)
+			}
+			if got := img.Bounds(); got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+				t.Fatalf("size = %dx%d, want %dx%d", got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+			}
+			left := color.NRGBAModel.Convert(img.At(10, 10)).(color.NRGBA)
+			right := color.NRGBAModel.Convert(img.At(img.Bounds().Dx()-10, 10)).(color.NRGBA)
+			if left.R > 40 || right.R < 215 {
+				t.Errorf("halves not preserved: left=%v right=%v", left, right)
+			}
+		})
+	}
+}
+
+func TestResizeToWidthInvalidInput(t *testing.T) {
+	if _, err := ResizeToWidth([]byte("not an image"), 100); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
+func TestEncodeDerivatives(t *testing.T) {
+	// A tall page: black above the fold, white below.
+	src := makeImageFromFunc(1280, 6000, func(x, y int) color.NRGBA {
+		if y < 960 {
+			return color.NRGBA{0, 0, 0, 255}
+		}
+		return color.NRGBA{255, 255, 255, 255}
+	})
+
+	out, err := EncodeDerivatives(encodePNG(src), DerivativeJPEG, 80, ThumbnailSpec, PreviewSpec)
+	if err != nil {
+		t.Fatalf("EncodeDerivatives: %v", err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("got %d derivatives, want 2", len(out))
+	}
+
+	thumb, err := jpeg.Decode(bytes.NewReader(out[0]))
+	if err != nil {
+		t.Fatalf("thumbnail is not JPEG: %v", err)
+	}
+	if got := thumb.Bounds(); got.Dx() != 320 || got.Dy() != 240 {
+		t.Fatalf("thumbnail size = %dx%d, want 320x240", got.Dx(), got.Dy())
+	}
+	if c := color.NRGBAModel.Convert(thumb.At(160, 120)).(color.NRGBA); c.R > 40 {
+		t.Errorf("thumbnail should show the top of the page, got %v", c)
+	}
+
+	preview, err := jpeg.Decode(bytes.NewReader(out[1]))
+	if err != nil {
+		t.Fatalf("preview is not JPEG: %v", err)
+	}
+	if got := preview.Bounds(); got.Dx() != 1024 || got.Dy() != 3072 {
+		t.Fatalf("preview size = %dx%d, want 1024x3072", got.Dx(), got.Dy())
+	}
+	if len(out[1]) >= len(encodePNG(src)) {
+		t.Errorf("preview (%d bytes) is not smaller than the original", len(out[1]))
+	}
+}
+
+func TestParseDerivativeFormat(t *testing.T) {
+	tests := map[string]DerivativeFormat{
+		"":     DerivativeJPEG,
+		"jpeg": DerivativeJPEG,
+		"PNG":  DerivativePNG,
+		"webp": DerivativeJPEG,
+	}
+	for name, want := range tests {
+		if got := ParseDerivativeFormat(name); got != want {
+			t.Errorf("ParseDerivativeFormat(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
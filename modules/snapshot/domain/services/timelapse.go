@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+)
+
+// TimelapseFormat is the encoding of a page's time-lapse.
+type TimelapseFormat string
+
+const (
+	TimelapseGIF   TimelapseFormat = "gif"   // animated GIF, 256 colors per frame
+	TimelapseAPNG  TimelapseFormat = "apng"  // animated PNG, lossless
+	TimelapseStrip TimelapseFormat = "strip" // frames side by side in one JPEG
+)
+
+func (f TimelapseFormat) Extension() string {
+	switch f {
+	case TimelapseAPNG:
+		return ".png"
+	case TimelapseStrip:
+		return ".jpg"
+	default:
+		return ".gif"
+	}
+}
+
+func (f TimelapseFormat) ContentType() string {
+	switch f {
+	case TimelapseAPNG:
+		return "image/apng"
+	case TimelapseStrip:
+		return "image/jpeg"
+	default:
+		return "image/gif"
+	}
+}
+
+// ErrNoFrames is returned when none of a time-lapse's screenshots decode.
+var ErrNoFrames = errors.New("no frames to render")
+
+// TimelapseFrame is one screenshot of a time-lapse and the label stamped on
+// it.
+type TimelapseFrame struct {
+	Image []byte
+	Label string
+}
+
+// TimelapseOptions shapes a time-lapse. Frames are scaled to Width and cropped
+// to MaxHeight, then padded to the tallest frame so they line up.
+type TimelapseOptions struct {
+	Width         int
+	MaxHeight     int
+	DelayMs       int  // time each frame is shown in animations
+	Stamp         bool // draw each frame's label in its bottom-left corner
+	HighlightDiff bool // tint the pixels that changed since the previous frame
+	Quality       int  // JPEG quality of strips, 1-100
+}
+
+// stripGutter is the space between frames of a strip.
+const stripGutter = 8
+
+var highlightColor = color.NRGBA{R: 255, G: 48, B: 48, A: 255}
+
+// RenderTimelapse renders screenshots, oldest first, as an animation or a
+// strip. Screenshots that do not decode are skipped.
+func RenderTimelapse(frames []TimelapseFrame, format TimelapseFormat, opts TimelapseOptions) ([]byte, error) {
+	images, labels := normalizeFrames(frames, opts)
+	if len(images) == 0 {
+		return nil, ErrNoFrames
+	}
+
+	if opts.HighlightDiff {
+		// Compare against the untinted previous frame.
+		prev := image.NewNRGBA(images[0].Rect)
+		copy(prev.Pix, images[0].Pix)
+		for i := 1; i < len(images); i++ {
+			curr := image.NewNRGBA(images[i].Rect)
+			copy(curr.Pix, images[i].Pix)
+			highlightChanges(images[i], prev)
+			prev = curr
+		}
+	}
+	if opts.Stamp {
+		for i, img := range images {
+			drawLabel(img, labels[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case TimelapseAPNG:
+		err = encodeAPNG(&buf, images, opts.DelayMs)
+	case TimelapseStrip:
+		err = jpeg.Encode(&buf, strip(images), &jpeg.Options{Quality: min(max(opts.Quality, 1), 100)})
+	default:
+		err = encodeGIF(&buf, images, opts.DelayMs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode time-lapse: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeFrames decodes and sizes the frames and pads them on white to a
+// common height.
+func normalizeFrames(frames []TimelapseFrame, opts TimelapseOptions) ([]*image.NRGBA, []string) {
+	spec := DerivativeSpec{Width: opts.Width, MaxHeight: opts.MaxHeight}
+	var sized []*image.NRGBA
+	var labels []string
+	width, height := 0, 0
+	for _, f := range frames {
+		img, _, err := image.Decode(bytes.NewReader(f.Image))
+		if err != nil {
+			continue
+		}
+		dst := derive(toNRGBA(img), spec)
+		sized = append(sized, dst)
+		labels = append(labels, f.Label)
+		width = max(width, dst.Rect.Dx())
+		height = max(height, dst.Rect.Dy())
+	}
+
+	out := make([]*image.NRGBA, len(sized))
+	for i, src := range sized {
+		dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(dst, dst.Rect, image.White, image.Point{}, draw.Src)
+		draw.Draw(dst, src.Rect.Sub(src.Rect.Min), src, src.Rect.Min, draw.Over)
+		out[i] = dst
+	}
+	return out, labels
+}
+
+// highlightChanges blends the highlight color into the pixels of img that
+// differ perceptibly from prev. Both images have the same bounds.
+func highlightChanges(img, prev *image.NRGBA) {
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		p, q := img.Pix[i:i+4:i+4], prev.Pix[i:i+4:i+4]
+		if p[0] == q[0] && p[1] == q[1] && p[2] == q[2] && p[3] == q[3] {
+			continue
+		}
+		if colorDelta(q[0], q[1], q[2], q[3], p[0], p[1], p[2], p[3], false) <= 0.1 {
+			continue
+		}
+		p[0] = uint8((uint16(p[0]) + uint16(highlightColor.R)) / 2)
+		p[1] = uint8((uint16(p[1]) + uint16(highlightColor.G)) / 2)
+		p[2] = uint8((uint16(p[2]) + uint16(highlightColor.B)) / 2)
+		p[3] = 255
+	}
+}
+
+func encodeGIF(buf *bytes.Buffer, images []*image.NRGBA, delayMs int) error {
+	anim := &gif.GIF{LoopCount: 0}
+	for _, img := range images {
+		frame := image.NewPaletted(img.Rect, palette.Plan9)
+		draw.FloydSteinberg.Draw(frame, img.Rect, img, image.Point{})
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, max(delayMs/10, 1)) // hundredths of a second
+	}
+	return gif.EncodeAll(buf, anim)
+}
+
+// strip lays the frames out left to right.
+func strip(images []*image.NRGBA) *image.NRGBA {
+	w, h := images[0].Rect.Dx(), images[0].Rect.Dy()
+	out := image.NewNRGBA(image.Rect(0, 0, len(images)*w+(len(images)-1)*stripGutter, h))
+	draw.Draw(out, out.Rect, image.White, image.Point{}, draw.Src)
+	for i, img := range images {
+		x := i * (w + stripGutter)
+		draw.Draw(out, image.Rect(x, 0, x+w, h), img, image.Point{}, draw.Src)
+	}
+	return out
+}
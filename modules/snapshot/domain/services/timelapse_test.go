@@ -0,0 +1,115 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func timelapseFrames() []TimelapseFrame {
+	white := color.NRGBA{255, 255, 255, 255}
+	// The second frame gains a black block; the third is shorter.
+	changed := makeImageFromFunc(400, 300, func(x, y int) color.NRGBA {
+		if x >= 200 && y < 100 {
+			return color.NRGBA{0, 0, 0, 255}
+		}
+		return white
+	})
+	return []TimelapseFrame{
+		{Image: encodePNG(makeImage(400, 300, white)), Label: "2026-01-01"},
+		{Image: encodePNG(changed), Label: "2026-02-01"},
+		{Image: []byte("not an image"), Label: "2026-02-15"},
+		{Image: encodePNG(makeImage(400, 100, white)), Label: "2026-03-01"},
+	}
+}
+
+func TestRenderTimelapse_GIF(t *testing.T) {
+	out, err := RenderTimelapse(timelapseFrames(), TimelapseGIF, TimelapseOptions{Width: 200, MaxHeight: 1000, DelayMs: 500})
+	if err != nil {
+		t.Fatalf("RenderTimelapse: %v", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("not a GIF: %v", err)
+	}
+	if len(anim.Image) != 3 {
+		t.Fatalf("got %d frames, want 3 (undecodable screenshot skipped)", len(anim.Image))
+	}
+	for i, frame := range anim.Image {
+		if b := frame.Bounds(); b.Dx() != 200 || b.Dy() != 150 {
+			t.Errorf("frame %d size = %dx%d, want 200x150", i, b.Dx(), b.Dy())
+		}
+	}
+	if anim.Delay[0] != 50 {
+		t.Errorf("delay = %d, want 50", anim.Delay[0])
+	}
+}
+
+func TestRenderTimelapse_APNG(t *testing.T) {
+	out, err := RenderTimelapse(timelapseFrames(), TimelapseAPNG, TimelapseOptions{Width: 200, DelayMs: 1000})
+	if err != nil {
+		t.Fatalf("RenderTimelapse: %v", err)
+	}
+	chunks, err := readPNGChunks(out)
+	if err != nil {
+		t.Fatalf("readPNGChunks: %v", err)
+	}
+	counts := map[string]int{}
+	for _, c := range chunks {
+		counts[c.typ]++
+	}
+	if chunks[1].typ != "acTL" || counts["fcTL"] != 3 || counts["fdAT"] < 2 || counts["IEND"] != 1 {
+		t.Errorf("chunks = %v", counts)
+	}
+	// The first frame stays readable by plain PNG decoders.
+	first, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("first frame: %v", err)
+	}
+	if b := first.Bounds(); b.Dx() != 200 || b.Dy() != 150 {
+		t.Errorf("first frame size = %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestRenderTimelapse_StripWithHighlightAndStamp(t *testing.T) {
+	opts := TimelapseOptions{Width: 200, Stamp: true, HighlightDiff: true, Quality: 90}
+	out, err := RenderTimelapse(timelapseFrames(), TimelapseStrip, opts)
+	if err != nil {
+		t.Fatalf("RenderTimelapse: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("not a JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 3*200+2*stripGutter || b.Dy() != 150 {
+		t.Fatalf("strip size = %dx%d", b.Dx(), b.Dy())
+	}
+
+	at := func(x, y int) color.NRGBA { return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA) }
+	// Second frame: the new black block is tinted red.
+	if c := at(200+stripGutter+150, 20); c.R < 100 || c.G > 60 {
+		t.Errorf("changed pixel = %v, want a red tint", c)
+	}
+	// Third frame: the removed block is tinted too.
+	if c := at(2*(200+stripGutter)+150, 20); c.R < 200 || c.G > 200 {
+		t.Errorf("reverted pixel = %v, want a red tint", c)
+	}
+	if c := at(100, 20); c.R < 240 || c.G < 240 {
+		t.Errorf("unchanged pixel = %v, want white", c)
+	}
+	// Stamp in the bottom-left corner.
+	if c := at(2, 148); c.R > 100 {
+		t.Errorf("stamp background = %v, want dark", c)
+	}
+}
+
+func TestRenderTimelapse_NoFrames(t *testing.T) {
+	_, err := RenderTimelapse([]TimelapseFrame{{Image: []byte("x")}}, TimelapseGIF, TimelapseOptions{Width: 200})
+	if !errors.Is(err, ErrNoFrames) {
+		t.Fatalf("err = %v, want ErrNoFrames", err)
+	}
+}
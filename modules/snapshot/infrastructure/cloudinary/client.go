@@ -10,6 +10,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"path"
 	"sort"
 	"strconv"
@@ -19,6 +20,10 @@ import (
 	"github.com/jcsoftdev/pulzifi-back/shared/config"
 )
 
+// deliveryType keeps uploads off public delivery URLs; they are read through
+// signed download URLs.
+const deliveryType = "authenticated"
+
 type Client struct {
 	cloudName string
 	apiKey    string
@@ -28,9 +33,7 @@ type Client struct {
 }
 
 type uploadResponse struct {
-	SecureURL string `json:"secure_url"`
-	URL       string `json:"url"`
-	Error     *struct {
+	Error *struct {
 		Message string `json:"message"`
 	} `json:"error"`
 }
@@ -62,6 +65,7 @@ func (c *Client) Upload(ctx context.Context, objectName string, reader io.Reader
 	params := map[string]string{
 		"public_id": publicID,
 		"timestamp": strconv.FormatInt(timestamp, 10),
+		"type":      deliveryType,
 	}
 	if c.folder != "" {
 		params["folder"] = c.folder
@@ -96,6 +100,9 @@ func (c *Client) Upload(ctx context.Context, objectName string, reader io.Reader
 	if err := writer.WriteField("public_id", publicID); err != nil {
 		return "", err
 	}
+	if err := writer.WriteField("type", deliveryType); err != nil {
+		return "", err
+	}
 	if c.folder != "" {
 		if err := writer.WriteField("folder", c.folder); err != nil {
 			return "", err
@@ -130,14 +137,246 @@ func (c *Client) Upload(ctx context.Context, objectName string, reader io.Reader
 		return "", fmt.Errorf("cloudinary upload failed with status %d", resp.StatusCode)
 	}
 
-	if uploadRes.SecureURL != "" {
-		return uploadRes.SecureURL, nil
+	return strings.TrimPrefix(objectName, "/"), nil
+}
+
+func (c *Client) Download(ctx context.Context, key string) ([]byte, error) {
+	downloadURL, err := c.SignedURL(ctx, key, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("cloudinary download failed with status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	resourceType, publicID, _ := c.resource(key)
+	var destroyRes struct {
+		Result string `json:"result"`
+	}
+	status, err := c.post(ctx, resourceType, "destroy", map[string]string{
+		"public_id": publicID,
+		"type":      deliveryType,
+	}, &destroyRes)
+	if err != nil {
+		return err
+	}
+	if destroyRes.Result != "ok" && destroyRes.Result != "not found" {
+		return fmt.Errorf("cloudinary delete failed with status %d: %s", status, destroyRes.Result)
+	}
+	return nil
+}
+
+// List pages through the Admin API for the image and raw resources whose
+// keys start with prefix.
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	if c.folder != "" {
+		prefix = c.folder + "/" + strings.TrimPrefix(prefix, "/")
+	}
+
+	var keys []string
+	for _, resourceType := range []string{"image", "raw"} {
+		cursor := ""
+		for {
+			query := url.Values{"prefix": {prefix}, "max_results": {"500"}}
+			if cursor != "" {
+				query.Set("next_cursor", cursor)
+			}
+			listURL := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/resources/%s/%s?%s", c.cloudName, resourceType, deliveryType, query.Encode())
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.SetBasicAuth(c.apiKey, c.apiSecret)
+
+			resp, err := c.http.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			var listRes struct {
+				Resources []struct {
+					PublicID string `json:"public_id"`
+					Format   string `json:"format"`
+				} `json:"resources"`
+				NextCursor string `json:"next_cursor"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&listRes)
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				return nil, fmt.Errorf("cloudinary list failed with status %d", resp.StatusCode)
+			}
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range listRes.Resources {
+				key := r.PublicID
+				if resourceType == "image" && r.Format != "" {
+					key += "." + r.Format
+				}
+				keys = append(keys, c.keyFromPublicID(key))
+			}
+			if listRes.NextCursor == "" {
+				break
+			}
+			cursor = listRes.NextCursor
+		}
+	}
+	return keys, nil
+}
+
+// SignedURL returns a private download URL, which Cloudinary serves until
+// expires_at.
+func (c *Client) SignedURL(_ context.Context, key string, expiry time.Duration) (string, error) {
+	resourceType, publicID, format := c.resource(key)
+	params := map[string]string{
+		"public_id":  publicID,
+		"format":     format,
+		"type":       deliveryType,
+		"expires_at": strconv.FormatInt(time.Now().Add(expiry).Unix(), 10),
+		"timestamp":  strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	query.Set("api_key", c.apiKey)
+	query.Set("signature", c.sign(params))
+	return fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/%s/download?%s", c.cloudName, resourceType, query.Encode()), nil
+}
+
+// Adopt renames a public upload to the authenticated delivery type, which
+// takes it off its public URL, and returns its key.
+func (c *Client) Adopt(ctx context.Context, objectURL string) (string, error) {
+	resourceType, publicID, err := parseDeliveryURL(objectURL)
+	if err != nil {
+		return "", err
+	}
+	var renameRes struct {
+		PublicID string `json:"public_id"`
+	}
+	if _, err := c.post(ctx, resourceType, "rename", map[string]string{
+		"from_public_id": publicID,
+		"to_public_id":   publicID,
+		"to_type":        deliveryType,
+	}, &renameRes); err != nil {
+		return "", err
+	}
+
+	key := publicID
+	if resourceType != "raw" {
+		if u, err := url.Parse(objectURL); err == nil {
+			key += path.Ext(u.Path)
+		}
 	}
-	if uploadRes.URL != "" {
-		return uploadRes.URL, nil
+	return c.keyFromPublicID(key), nil
+}
+
+// resource maps a key to its resource type, public ID and, for images, the
+// format it is delivered in. Upload names public IDs after the key without
+// its extension.
+func (c *Client) resource(key string) (string, string, string) {
+	ext := path.Ext(key)
+	publicID := strings.TrimSuffix(strings.TrimPrefix(key, "/"), ext)
+	if c.folder != "" {
+		publicID = c.folder + "/" + publicID
 	}
+	switch strings.ToLower(ext) {
+	case ".png", ".jpg", ".jpeg", ".webp", ".gif":
+		return "image", publicID, strings.TrimPrefix(strings.ToLower(ext), ".")
+	}
+	return "raw", publicID, ""
+}
 
-	return "", fmt.Errorf("cloudinary upload did not return a URL")
+func (c *Client) keyFromPublicID(publicID string) string {
+	if c.folder != "" {
+		return strings.TrimPrefix(publicID, c.folder+"/")
+	}
+	return publicID
+}
+
+// post calls a signed Upload API method and decodes its response into out.
+func (c *Client) post(ctx context.Context, resourceType, method string, params map[string]string, out interface{}) (int, error) {
+	params["timestamp"] = strconv.FormatInt(time.Now().Unix(), 10)
+	form := url.Values{}
+	for key, value := range params {
+		form.Set(key, value)
+	}
+	form.Set("api_key", c.apiKey)
+	form.Set("signature", c.sign(params))
+
+	apiURL := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/%s/%s", c.cloudName, resourceType, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	var errRes struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if resp.StatusCode >= 300 {
+		if json.Unmarshal(body, &errRes) == nil && errRes.Error != nil && errRes.Error.Message != "" {
+			return resp.StatusCode, fmt.Errorf("cloudinary %s failed: %s", method, errRes.Error.Message)
+		}
+		return resp.StatusCode, fmt.Errorf("cloudinary %s failed with status %d", method, resp.StatusCode)
+	}
+	return resp.StatusCode, json.Unmarshal(body, out)
+}
+
+// parseDeliveryURL splits a public delivery URL such as
+// https://res.cloudinary.com/<cloud>/image/upload/v123/<folder>/<id>.png into
+// its resource type and public ID. Image public IDs exclude the format
+// extension; raw ones include it.
+func parseDeliveryURL(objectURL string) (string, string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	for i := 1; i+1 < len(parts); i++ {
+		if parts[i] != "upload" {
+			continue
+		}
+		resourceType, rest := parts[i-1], parts[i+1:]
+		if len(rest) > 1 && len(rest[0]) > 1 && rest[0][0] == 'v' {
+			if _, err := strconv.Atoi(rest[0][1:]); err == nil {
+				rest = rest[1:]
+			}
+		}
+		publicID := strings.Join(rest, "/")
+		if resourceType != "raw" {
+			publicID = strings.TrimSuffix(publicID, path.Ext(publicID))
+		}
+		if publicID != "" {
+			return resourceType, publicID, nil
+		}
+	}
+	return "", "", fmt.Errorf("not a cloudinary delivery URL: %q", objectURL)
 }
 
 func (c *Client) sign(params map[string]string) string {
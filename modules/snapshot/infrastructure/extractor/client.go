@@ -4,34 +4,75 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
 )
 
+// extractAttempts is how many times an extraction is tried, on different
+// endpoints when there are several.
+const extractAttempts = 3
+
+// DefaultAttemptTimeout bounds one extraction attempt, so an endpoint that
+// accepts requests but never answers fails in time for a retry elsewhere.
+const DefaultAttemptTimeout = 150 * time.Second
+
+// errAttemptTimeout reports that an attempt ran out of its own time, as
+// opposed to the caller's; unlike a context error it counts against the
+// endpoint.
+var errAttemptTimeout = errors.New("extractor attempt timed out")
+
 type SectionExtractOption struct {
-	ID            string           `json:"id"`
-	Selector      string           `json:"selector,omitempty"`
-	SelectorXPath string           `json:"selectorXpath,omitempty"`
-	Offsets       *SelectorOffsets `json:"selectorOffsets,omitempty"`
+	ID            string              `json:"id"`
+	Selector      string              `json:"selector,omitempty"`
+	SelectorXPath string              `json:"selectorXpath,omitempty"`
+	Offsets       *SelectorOffsets    `json:"selectorOffsets,omitempty"`
+	Fingerprint   *ElementFingerprint `json:"fingerprint,omitempty"`
 }
 
+// ElementFingerprint lets the extractor find a section's element by
+// resemblance when neither of its selectors matches.
+type ElementFingerprint struct {
+	Tag        string            `json:"tag"`
+	Text       string            `json:"text,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Rect       *ElementRect      `json:"rect,omitempty"`
+	Landmarks  []string          `json:"landmarks,omitempty"`
+}
+
+// SectionExtractResult is one section's capture. When the selectors missed
+// but the fingerprint matched, SelectorMatched is false, Healed is true and
+// the capture is of the matched element, whose selectors are proposed.
 type SectionExtractResult struct {
-	ID               string `json:"id"`
-	ScreenshotBase64 string `json:"screenshot_base64"`
-	HTML             string `json:"html"`
-	Text             string `json:"text"`
-	SelectorMatched  bool   `json:"selector_matched"`
+	ID               string  `json:"id"`
+	Screenshot       []byte  `json:"screenshot_base64,omitempty"`
+	HTML             string  `json:"html"`
+	Text             string  `json:"text"`
+	SelectorMatched  bool    `json:"selector_matched"`
+	Healed           bool    `json:"healed,omitempty"`
+	ProposedSelector string  `json:"proposed_selector,omitempty"`
+	ProposedXPath    string  `json:"proposed_xpath,omitempty"`
+	MatchScore       float64 `json:"match_score,omitempty"`
 }
 
+// ExtractorResult is a page's capture. Screenshots are raw image bytes:
+// extractors answering with JSON send them base64-encoded, which
+// encoding/json decodes into the byte slices, and multipart responses carry
+// them as binary parts.
 type ExtractorResult struct {
-	Title            string                 `json:"title"`
-	HTML             string                 `json:"html"`
-	Text             string                 `json:"text"`
-	ScreenshotBase64 string                 `json:"screenshot_base64"`
-	SelectorMatched  bool                   `json:"selector_matched"`
-	Sections         []SectionExtractResult `json:"sections,omitempty"`
+	Title           string                 `json:"title"`
+	HTML            string                 `json:"html"`
+	Text            string                 `json:"text"`
+	Screenshot      []byte                 `json:"screenshot_base64,omitempty"`
+	SelectorMatched bool                   `json:"selector_matched"`
+	Sections        []SectionExtractResult `json:"sections,omitempty"`
+	MHTML           string                 `json:"mhtml,omitempty"` // page archive, when requested
 }
 
 type SelectorOffsets struct {
@@ -41,21 +82,147 @@ type SelectorOffsets struct {
 	Left   int `json:"left"`
 }
 
+// CaptureStep is a browser action the extractor runs after navigation and
+// before capture (click, type, select, scroll_to, wait_for_selector,
+// wait_for_network_idle, wait, press).
+type CaptureStep struct {
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+	Value    string `json:"value,omitempty"`
+	WaitMs   int    `json:"wait_ms,omitempty"`
+}
+
+// StepError reports which capture step failed. Step is 1-based.
+type StepError struct {
+	Step    int
+	Action  string
+	Message string
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("capture step %d (%s) failed: %s", e.Step, e.Action, e.Message)
+}
+
+// AuthCookie is a cookie set in the browser context before navigation.
+type AuthCookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// BasicAuth is answered to HTTP basic auth challenges.
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginAuth is a form login the extractor performs before opening the page.
+type LoginAuth struct {
+	URL              string `json:"url"`
+	UsernameSelector string `json:"username_selector"`
+	PasswordSelector string `json:"password_selector"`
+	SubmitSelector   string `json:"submit_selector"`
+	SuccessSelector  string `json:"success_selector,omitempty"`
+	Username         string `json:"username"`
+	Password         string `json:"password"`
+}
+
+// Auth carries the decrypted credentials a page is captured with. It is only
+// sent to the extractor and must never be logged.
+type Auth struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Cookies []AuthCookie      `json:"cookies,omitempty"`
+	Basic   *BasicAuth        `json:"basic,omitempty"`
+	Login   *LoginAuth        `json:"login,omitempty"`
+}
+
+// Geolocation is the position the browser reports to the page.
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"`
+}
+
+// CaptureProfile overrides the browser's device, locale and geo emulation for
+// one extraction. Zero fields keep the extractor's desktop defaults.
+type CaptureProfile struct {
+	Name              string       `json:"name"`
+	ViewportWidth     int          `json:"viewport_width,omitempty"`
+	ViewportHeight    int          `json:"viewport_height,omitempty"`
+	DeviceScaleFactor float64      `json:"device_scale_factor,omitempty"`
+	Mobile            bool         `json:"mobile,omitempty"`
+	UserAgent         string       `json:"user_agent,omitempty"`
+	Locale            string       `json:"locale,omitempty"`
+	Timezone          string       `json:"timezone,omitempty"`
+	Geolocation       *Geolocation `json:"geolocation,omitempty"`
+}
+
+// Proxy is the outbound proxy the extractor routes a capture through. Server
+// includes the scheme, e.g. "socks5://10.0.0.6:1080". The password is a
+// secret and must never be logged.
+type Proxy struct {
+	Server   string `json:"server"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// ProxyError reports that the proxy itself, not the target page, failed.
+type ProxyError struct {
+	Message string
+}
+
+func (e *ProxyError) Error() string {
+	return "proxy failed: " + e.Message
+}
+
+// PageError reports that the extractor could not load the page: navigation
+// failed or timed out. It says nothing about the extractor itself, so it is
+// neither retried nor counted against the endpoint.
+type PageError struct {
+	Code    string
+	Message string
+}
+
+func (e *PageError) Error() string {
+	if e.Code == "TIMEOUT_ERROR" {
+		return "page load timed out: " + e.Message
+	}
+	return "page failed to load: " + e.Message
+}
+
+// LoginError reports that the extractor could not log in with the page's credentials.
+type LoginError struct {
+	Message string
+}
+
+func (e *LoginError) Error() string {
+	return "login failed: " + e.Message
+}
+
 type ExtractOptions struct {
 	BlockAdsCookies bool
 	Selector        string
 	SelectorXPath   string
 	SelectorOffsets *SelectorOffsets
+	IgnoreSelectors []string // STUB
 	Sections        []SectionExtractOption
+	Steps           []CaptureStep
+	Auth            *Auth
+	Profile         *CaptureProfile
+	Proxy           *Proxy
+	Archive         bool // also return an MHTML archive of the page
 }
 
 type PreviewElement struct {
-	Selector     string      `json:"selector"`
-	XPath        string      `json:"xpath"`
-	Tag          string      `json:"tag"`
-	Rect         ElementRect `json:"rect"`
-	TextPreview  string      `json:"text_preview"`
-	SemanticRole string      `json:"semantic_role"`
+	Selector     string            `json:"selector"`
+	XPath        string            `json:"xpath"`
+	Tag          string            `json:"tag"`
+	Rect         ElementRect       `json:"rect"`
+	TextPreview  string            `json:"text_preview"`
+	SemanticRole string            `json:"semantic_role"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Landmarks    []string          `json:"landmarks,omitempty"`
 }
 
 type ElementRect struct {
@@ -77,15 +244,21 @@ type PreviewViewport struct {
 	Height int `json:"height"`
 }
 
+// HTTPClient calls the extractor service. It balances requests over one or
+// more endpoints with per-endpoint circuit breakers, see pool.
 type HTTPClient struct {
-	baseURL         string
+	endpoints       *pool
+	attemptTimeout  time.Duration
 	httpClient      *http.Client
 	streamingClient *http.Client
 }
 
-func NewHTTPClient(baseURL string) *HTTPClient {
+// NewHTTPClient builds a client for the extractor endpoints in baseURLs,
+// separated by commas.
+func NewHTTPClient(baseURLs string) *HTTPClient {
 	return &HTTPClient{
-		baseURL: baseURL,
+		endpoints:      newPool(baseURLs),
+		attemptTimeout: DefaultAttemptTimeout,
 		httpClient: &http.Client{
 			Timeout: 120 * time.Second,
 		},
@@ -98,9 +271,47 @@ func NewHTTPClient(baseURL string) *HTTPClient {
 	}
 }
 
-// Extract sends an extraction request to the scraper service.
-// Uses the streaming client with a 5-minute context deadline so that large
-// pages (which may take minutes to scroll/screenshot) are not killed prematurely.
+// SetCircuitBreaker sets how many consecutive failed requests open an
+// endpoint's circuit and how long it stays open.
+func (c *HTTPClient) SetCircuitBreaker(threshold int, cooldown time.Duration) {
+	c.endpoints.mu.Lock()
+	defer c.endpoints.mu.Unlock()
+	if threshold > 0 {
+		c.endpoints.threshold = threshold
+	}
+	if cooldown > 0 {
+		c.endpoints.cooldown = cooldown
+	}
+}
+
+// StartHealthChecks probes every endpoint's /health at interval until ctx is
+// done. Endpoints failing the probe get no requests while others are available.
+func (c *HTTPClient) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.endpoints.probe(ctx, c.httpClient)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("Extractor health checks started",
+		zap.Int("endpoints", len(c.endpoints.endpoints)),
+		zap.Duration("interval", interval))
+}
+
+// Status reports the state of every endpoint.
+func (c *HTTPClient) Status() []EndpointStatus {
+	return c.endpoints.status()
+}
+
 func (c *HTTPClient) Extract(ctx context.Context, url string, opts ExtractOptions) (*ExtractorResult, error) {
 	payload := map[string]interface{}{
 		"url":               url,
@@ -118,6 +329,21 @@ func (c *HTTPClient) Extract(ctx context.Context, url string, opts ExtractOption
 	if len(opts.Sections) > 0 {
 		payload["sections"] = opts.Sections
 	}
+	if len(opts.Steps) > 0 {
+		payload["steps"] = opts.Steps
+	}
+	if opts.Auth != nil {
+		payload["auth"] = opts.Auth
+	}
+	if opts.Profile != nil {
+		payload["profile"] = opts.Profile
+	}
+	if opts.Proxy != nil {
+		payload["proxy"] = opts.Proxy
+	}
+	if opts.Archive {
+		payload["archive"] = true
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -127,57 +353,197 @@ func (c *HTTPClient) Extract(ctx context.Context, url string, opts ExtractOption
 	extractCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(extractCtx, "POST", c.baseURL+"/extract", bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
+	// Each attempt goes to an endpoint not tried yet while there is one, and
+	// backs off before reusing one.
+	tried := map[*endpoint]bool{}
 	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
-		if attempt > 0 {
+	for attempt := 0; attempt < extractAttempts; attempt++ {
+		if attempt > 0 && len(tried) >= c.endpoints.size() {
 			select {
 			case <-extractCtx.Done():
 				return nil, extractCtx.Err()
 			case <-time.After(time.Duration(attempt) * 2 * time.Second):
 			}
-
-			req, err = http.NewRequestWithContext(extractCtx, "POST", c.baseURL+"/extract", bytes.NewBuffer(body))
-			if err != nil {
-				return nil, err
-			}
-			req.Header.Set("Content-Type", "application/json")
 		}
 
-		var resp *http.Response
-		resp, err = c.streamingClient.Do(req)
+		e, trial, err := c.endpoints.acquire(tried)
 		if err != nil {
-			lastErr = err
-			continue
+			return nil, err
+		}
+		tried[e] = true
+
+		result, retry, err := c.extractFrom(extractCtx, e, trial, body)
+		if err == nil {
+			return result, nil
+		}
+		if !retry || extractCtx.Err() != nil {
+			return nil, err
 		}
+		logger.Warn("Extractor request failed, retrying",
+			zap.String("endpoint", e.url), zap.Int("attempt", attempt+1), zap.Error(err))
+		lastErr = err
+	}
 
-		if resp.StatusCode >= 500 {
-			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-			resp.Body.Close()
-			lastErr = fmt.Errorf("extractor service returned status: %d, body: %s", resp.StatusCode, string(respBody))
-			continue
+	return nil, fmt.Errorf("extractor failed after %d attempts: %w", extractAttempts, lastErr)
+}
+
+// extractFrom runs one extraction on e within the attempt timeout. retry
+// reports whether the failure was the endpoint's, so another attempt may
+// succeed.
+func (c *HTTPClient) extractFrom(ctx context.Context, e *endpoint, trial bool, body []byte) (result *ExtractorResult, retry bool, err error) {
+	var endpointErr error
+	defer func() { c.endpoints.release(e, trial, endpointErr) }()
+
+	attemptCtx, cancel := context.WithTimeout(ctx, c.attemptTimeout)
+	defer cancel()
+	// timedOut turns a failure caused by the attempt's own deadline into an
+	// endpoint failure; the caller's cancellation stays a context error.
+	timedOut := func(err error) error {
+		if errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return fmt.Errorf("%w after %s: %v", errAttemptTimeout, c.attemptTimeout, err)
 		}
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(attemptCtx, "POST", e.url+"/extract", bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	setExtractHeaders(req)
+
+	resp, err := c.streamingClient.Do(req)
+	if err != nil {
+		err = timedOut(err)
+		endpointErr = err
+		return nil, true, err
+	}
+	defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
-			resp.Body.Close()
-			return nil, fmt.Errorf("extractor service returned status: %d, body: %s", resp.StatusCode, string(respBody))
+	if resp.StatusCode >= 500 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		// Retrying through a dead proxy is pointless; the caller picks another.
+		if proxyErr := parseProxyError(resp.StatusCode, respBody); proxyErr != nil {
+			return nil, false, proxyErr
 		}
+		if pageErr := parsePageError(respBody); pageErr != nil {
+			return nil, false, pageErr
+		}
+		err := fmt.Errorf("extractor service returned status: %d, body: %s", resp.StatusCode, string(respBody))
+		if !endpointFault(resp.StatusCode, respBody) {
+			return nil, false, err
+		}
+		endpointErr = err
+		return nil, true, endpointErr
+	}
 
-		defer resp.Body.Close()
-		var result ExtractorResult
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, err
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if stepErr := parseStepError(resp.StatusCode, respBody); stepErr != nil {
+			return nil, false, stepErr
+		}
+		if loginErr := parseLoginError(resp.StatusCode, respBody); loginErr != nil {
+			return nil, false, loginErr
 		}
-		return &result, nil
+		return nil, false, fmt.Errorf("extractor service returned status: %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	// A body cut short means the extractor went away mid-response.
+	result, err = decodeResult(resp)
+	if err != nil {
+		err = timedOut(err)
+		endpointErr = err
+		return nil, true, err
+	}
+	return result, false, nil
+}
+
+// setExtractHeaders asks for the multipart protocol; extractors that predate
+// it ignore the header and answer with JSON.
+func setExtractHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "multipart/mixed, application/json")
+	req.Header.Set(ProtocolHeader, ProtocolMultipart)
+}
+
+// parseStepError decodes the extractor's capture step failure response
+// (HTTP 422, code CAPTURE_STEP_FAILED). Returns nil for any other error.
+func parseStepError(status int, body []byte) *StepError {
+	if status != http.StatusUnprocessableEntity {
+		return nil
+	}
+	var payload struct {
+		Error  string `json:"error"`
+		Code   string `json:"code"`
+		Step   int    `json:"step"`
+		Action string `json:"action"`
 	}
+	if json.Unmarshal(body, &payload) != nil || payload.Code != "CAPTURE_STEP_FAILED" {
+		return nil
+	}
+	return &StepError{Step: payload.Step, Action: payload.Action, Message: payload.Error}
+}
 
-	return nil, fmt.Errorf("extractor failed after 3 attempts: %w", lastErr)
+// parseLoginError decodes the extractor's login failure response
+// (HTTP 422, code LOGIN_FAILED). Returns nil for any other error.
+func parseLoginError(status int, body []byte) *LoginError {
+	if status != http.StatusUnprocessableEntity {
+		return nil
+	}
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) != nil || payload.Code != "LOGIN_FAILED" {
+		return nil
+	}
+	return &LoginError{Message: payload.Error}
+}
+
+// parseProxyError decodes the extractor's proxy failure response
+// (HTTP 502, code PROXY_FAILED). Returns nil for any other error.
+func parseProxyError(status int, body []byte) *ProxyError {
+	if status != http.StatusBadGateway {
+		return nil
+	}
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) != nil || payload.Code != "PROXY_FAILED" {
+		return nil
+	}
+	return &ProxyError{Message: payload.Error}
+}
+
+// parsePageError decodes the extractor's navigation failure (HTTP 502, code
+// NAVIGATION_ERROR) and timeout (HTTP 504, code TIMEOUT_ERROR) responses.
+// Returns nil for any other error.
+func parsePageError(body []byte) *PageError {
+	var payload struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return nil
+	}
+	switch payload.Code {
+	case "NAVIGATION_ERROR", "TIMEOUT_ERROR":
+		return &PageError{Code: payload.Code, Message: payload.Error}
+	}
+	return nil
+}
+
+// endpointFault reports whether a 5xx response is the extractor's own failure
+// and counts against its circuit: a 500 or 503, or a browser failure (code
+// BROWSER_ERROR). Page failures and gateway errors in front of it don't.
+func endpointFault(status int, body []byte) bool {
+	var payload struct {
+		Code string `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Code == "BROWSER_ERROR" {
+		return true
+	}
+	return status == http.StatusInternalServerError || status == http.StatusServiceUnavailable
 }
 
 func (c *HTTPClient) Preview(ctx context.Context, url string, blockAdsCookies bool) (*PreviewResult, error) {
@@ -190,7 +556,14 @@ func (c *HTTPClient) Preview(ctx context.Context, url string, blockAdsCookies bo
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/preview", bytes.NewBuffer(body))
+	e, trial, err := c.endpoints.acquire(nil)
+	if err != nil {
+		return nil, err
+	}
+	var endpointErr error
+	defer func() { c.endpoints.release(e, trial, endpointErr) }()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.url+"/preview", bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -198,12 +571,21 @@ func (c *HTTPClient) Preview(ctx context.Context, url string, blockAdsCookies bo
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		endpointErr = err
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("extractor preview returned status: %d", resp.StatusCode)
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if pageErr := parsePageError(respBody); pageErr != nil {
+			return nil, pageErr
+		}
+		err := fmt.Errorf("extractor preview returned status: %d", resp.StatusCode)
+		if resp.StatusCode >= 500 && endpointFault(resp.StatusCode, respBody) {
+			endpointErr = err
+		}
+		return nil, err
 	}
 
 	var result PreviewResult
@@ -230,11 +612,16 @@ func (c *HTTPClient) PreviewStream(ctx context.Context, url string, blockAdsCook
 		return nil, err
 	}
 
+	e, trial, err := c.endpoints.acquire(nil)
+	if err != nil {
+		return nil, err
+	}
 	streamCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 
-	req, err := http.NewRequestWithContext(streamCtx, "POST", c.baseURL+"/preview", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(streamCtx, "POST", e.url+"/preview", bytes.NewBuffer(body))
 	if err != nil {
 		cancel()
+		c.endpoints.release(e, trial, nil)
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
@@ -242,28 +629,45 @@ func (c *HTTPClient) PreviewStream(ctx context.Context, url string, blockAdsCook
 	resp, err := c.streamingClient.Do(req)
 	if err != nil {
 		cancel()
+		c.endpoints.release(e, trial, err)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
 		resp.Body.Close()
 		cancel()
-		return nil, fmt.Errorf("extractor preview returned status: %d", resp.StatusCode)
+		if pageErr := parsePageError(respBody); pageErr != nil {
+			c.endpoints.release(e, trial, nil)
+			return nil, pageErr
+		}
+		err := fmt.Errorf("extractor preview returned status: %d", resp.StatusCode)
+		if resp.StatusCode >= 500 && endpointFault(resp.StatusCode, respBody) {
+			c.endpoints.release(e, trial, err)
+		} else {
+			c.endpoints.release(e, trial, nil)
+		}
+		return nil, err
 	}
 
-	// Wrap the body so that the context cancel is called when the body is closed.
-	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	// Wrap the body so that the context cancel is called, and the endpoint
+	// released, when the body is closed.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: func() {
+		cancel()
+		c.endpoints.release(e, trial, nil)
+	}}
 	return resp, nil
 }
 
-// cancelOnClose wraps an io.ReadCloser and calls a cancel function on Close.
+// cancelOnClose wraps an io.ReadCloser and calls a cancel function once on Close.
 type cancelOnClose struct {
 	io.ReadCloser
 	cancel context.CancelFunc
+	once   sync.Once
 }
 
 func (c *cancelOnClose) Close() error {
 	err := c.ReadCloser.Close()
-	c.cancel()
+	c.once.Do(c.cancel)
 	return err
 }
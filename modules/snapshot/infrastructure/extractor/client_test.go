@@ -0,0 +1,108 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+)
+
+func TestHTTPClient_Extract_JSONProtocol(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title":"Home","screenshot_base64":"iVBORw==","selector_matched":true,
+			"sections":[{"id":"s1","screenshot_base64":"AQID","selector_matched":true}]}`))
+	}))
+	defer srv.Close()
+
+	res, err := NewHTTPClient(srv.URL).Extract(context.Background(), "https://example.com", ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if res.Title != "Home" || !bytes.Equal(res.Screenshot, []byte{0x89, 'P', 'N', 'G'}) {
+		t.Errorf("result = %+v", res)
+	}
+	if len(res.Sections) != 1 || !bytes.Equal(res.Sections[0].Screenshot, []byte{1, 2, 3}) {
+		t.Errorf("sections = %+v", res.Sections)
+	}
+}
+
+func TestHTTPClient_Extract_MultipartProtocol(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ProtocolHeader) != ProtocolMultipart {
+			t.Errorf("%s = %q", ProtocolHeader, r.Header.Get(ProtocolHeader))
+		}
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		writePart := func(name, contentType string, data []byte) {
+			h := textproto.MIMEHeader{}
+			h.Set("Content-Disposition", `form-data; name="`+name+`"`)
+			h.Set("Content-Type", contentType)
+			p, _ := mw.CreatePart(h)
+			p.Write(data)
+		}
+		meta, _ := json.Marshal(map[string]any{
+			"title":            "Home",
+			"selector_matched": true,
+			"sections": []map[string]any{
+				{"id": "s1", "selector_matched": true},
+				{"id": "s2", "selector_matched": false},
+			},
+		})
+		writePart("result", "application/json", meta)
+		writePart("screenshot", "image/png", []byte("full"))
+		writePart("section:s1", "image/png", []byte("first"))
+		writePart("thumbnail", "image/png", []byte("ignored"))
+		writePart("mhtml", "multipart/related", []byte("MIME-Version: 1.0"))
+		mw.Close()
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+		w.Write(body.Bytes())
+	}))
+	defer srv.Close()
+
+	res, err := NewHTTPClient(srv.URL).Extract(context.Background(), "https://example.com", ExtractOptions{Archive: true})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if res.Title != "Home" || string(res.Screenshot) != "full" || res.MHTML != "MIME-Version: 1.0" {
+		t.Errorf("result = %+v", res)
+	}
+	if string(res.Sections[0].Screenshot) != "first" || res.Sections[1].Screenshot != nil {
+		t.Errorf("sections = %+v", res.Sections)
+	}
+}
+
+func TestDecodeMultipartResult_ArchiveInResultPart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	p, _ := mw.CreateFormField("result")
+	p.Write([]byte(`{"title":"Home","selector_matched":true,"mhtml":"MIME-Version: 1.0"}`))
+	p, _ = mw.CreateFormField("screenshot")
+	p.Write([]byte("full"))
+	mw.Close()
+
+	res, err := decodeMultipartResult(&body, mw.Boundary())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if res.MHTML != "MIME-Version: 1.0" {
+		t.Errorf("MHTML = %q, want the archive from the result part kept", res.MHTML)
+	}
+}
+
+func TestDecodeMultipartResult_MissingResult(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	p, _ := mw.CreateFormField("screenshot")
+	p.Write([]byte("full"))
+	mw.Close()
+
+	if _, err := decodeMultipartResult(&body, mw.Boundary()); err == nil {
+		t.Fatal("expected an error without a result part")
+	}
+}
@@ -0,0 +1,98 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Extraction results travel in one of two protocols. Version 1 is a JSON
+// body with base64 screenshots. Version 2 is a multipart/mixed body: a JSON
+// "result" part without the screenshots and archive, followed by raw
+// "screenshot", "section:<id>" and "mhtml" parts. The worker asks for
+// version 2 through ProtocolHeader and decodes whichever the extractor
+// answers with, so either side can be upgraded first.
+const (
+	ProtocolHeader    = "X-Extractor-Protocol"
+	ProtocolMultipart = "2"
+
+	sectionPartPrefix = "section:"
+)
+
+// decodeResult reads an extraction response in either protocol.
+func decodeResult(resp *http.Response) (*ExtractorResult, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		var result ExtractorResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	}
+	return decodeMultipartResult(resp.Body, params["boundary"])
+}
+
+// decodeMultipartResult reads a version 2 response. Parts are read one at a
+// time straight into the result, so each image is held once, undecoded.
+func decodeMultipartResult(body io.Reader, boundary string) (*ExtractorResult, error) {
+	if boundary == "" {
+		return nil, errors.New("multipart extractor response without boundary")
+	}
+	var result *ExtractorResult
+	sections := map[string][]byte{}
+	var screenshot []byte
+	var archive *string
+
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read extractor response: %w", err)
+		}
+		name := part.FormName()
+		switch {
+		case name == "result":
+			result = &ExtractorResult{}
+			err = json.NewDecoder(part).Decode(result)
+		case name == "screenshot":
+			screenshot, err = io.ReadAll(part)
+		case name == "mhtml":
+			var b []byte
+			b, err = io.ReadAll(part)
+			mhtml := string(b)
+			archive = &mhtml
+		case strings.HasPrefix(name, sectionPartPrefix):
+			sections[strings.TrimPrefix(name, sectionPartPrefix)], err = io.ReadAll(part)
+		default:
+			// Unknown parts are skipped so the extractor can add new ones.
+		}
+		part.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read extractor part %q: %w", name, err)
+		}
+	}
+	if result == nil {
+		return nil, errors.New("multipart extractor response without result part")
+	}
+
+	result.Screenshot = screenshot
+	// An archive inside the JSON part, from an extractor that predates the
+	// mhtml part, is kept.
+	if archive != nil {
+		result.MHTML = *archive
+	}
+	for i := range result.Sections {
+		if img, ok := sections[result.Sections[i].ID]; ok {
+			result.Sections[i].Screenshot = img
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,228 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/logger"
+	"go.uber.org/zap"
+)
+
+// Defaults of the per-endpoint circuit breaker and health probes.
+const (
+	DefaultBreakerThreshold = 3
+	DefaultBreakerCooldown  = 30 * time.Second
+	DefaultHealthInterval   = 10 * time.Second
+
+	healthTimeout = 5 * time.Second
+)
+
+var ErrNoEndpoints = errors.New("no extractor endpoints configured")
+
+// EndpointStatus is a snapshot of one extractor endpoint's state.
+type EndpointStatus struct {
+	URL         string     `json:"url"`
+	Healthy     bool       `json:"healthy"`
+	CircuitOpen bool       `json:"circuit_open"`
+	HalfOpen    bool       `json:"half_open"`
+	OpenUntil   *time.Time `json:"open_until,omitempty"`
+	InFlight    int        `json:"in_flight"`
+	Failures    int        `json:"consecutive_failures"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastProbeAt *time.Time `json:"last_probe_at,omitempty"`
+}
+
+type endpoint struct {
+	url       string
+	healthy   bool
+	inFlight  int
+	failures  int
+	openUntil time.Time
+	trial     bool // a half-open circuit's trial request is in flight
+	lastError string
+	lastProbe time.Time
+}
+
+// pool balances requests over extractor endpoints. Each request goes to the
+// available endpoint with the fewest outstanding requests. An endpoint is
+// unavailable while its last health probe failed or its circuit is open: the
+// circuit opens after threshold consecutive failed requests and, once the
+// cooldown has passed, goes half-open: a single trial request goes through and
+// the endpoint stays unavailable to others until it ends.
+type pool struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+	next      int
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// newPool parses a comma-separated list of endpoint base URLs.
+func newPool(baseURLs string) *pool {
+	p := &pool{threshold: DefaultBreakerThreshold, cooldown: DefaultBreakerCooldown, now: time.Now}
+	for _, u := range strings.Split(baseURLs, ",") {
+		if u = strings.TrimRight(strings.TrimSpace(u), "/"); u != "" {
+			p.endpoints = append(p.endpoints, &endpoint{url: u, healthy: true})
+		}
+	}
+	return p
+}
+
+func (p *pool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.endpoints)
+}
+
+// acquire picks an endpoint for a request, preferring ones not in tried, and
+// counts the request as outstanding until release. trial reports whether the
+// request is its endpoint's half-open trial, to be passed back to release.
+// When every endpoint is unavailable the least loaded one is tried anyway, so
+// a lone extractor is retried while it restarts.
+func (p *pool) acquire(tried map[*endpoint]bool) (*endpoint, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.endpoints) == 0 {
+		return nil, false, ErrNoEndpoints
+	}
+	now := p.now()
+	available := func(e *endpoint) bool { return e.healthy && !now.Before(e.openUntil) && !e.trial }
+
+	var best *endpoint
+	bestRank := 0
+	n := len(p.endpoints)
+	for i := 0; i < n; i++ {
+		// Start from a rotating offset so ties are spread across endpoints.
+		e := p.endpoints[(p.next+i)%n]
+		rank := 0
+		if tried[e] {
+			rank += 2
+		}
+		if !available(e) {
+			rank++
+		}
+		if best == nil || rank < bestRank || (rank == bestRank && e.inFlight < best.inFlight) {
+			best, bestRank = e, rank
+		}
+	}
+	p.next = (p.next + 1) % n
+	trial := bestRank%2 == 0 && best.failures >= p.threshold
+	if trial {
+		best.trial = true
+	}
+	best.inFlight++
+	return best, trial, nil
+}
+
+// release ends a request; trial is what acquire returned for it. A failed
+// request counts towards opening the endpoint's circuit; a successful one
+// closes it. Requests the caller gave up on count as neither, but a trial
+// still ends so the next request can try again.
+func (p *pool) release(e *endpoint, trial bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e.inFlight--
+	if trial {
+		e.trial = false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if err == nil {
+		e.failures = 0
+		e.openUntil = time.Time{}
+		return
+	}
+	e.failures++
+	e.lastError = err.Error()
+	if e.failures >= p.threshold {
+		e.openUntil = p.now().Add(p.cooldown)
+		logger.Warn("Extractor circuit opened",
+			zap.String("endpoint", e.url),
+			zap.Int("failures", e.failures),
+			zap.Duration("cooldown", p.cooldown),
+			zap.Error(err))
+	}
+}
+
+func (p *pool) status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	out := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		s := EndpointStatus{
+			URL:       e.url,
+			Healthy:   e.healthy,
+			InFlight:  e.inFlight,
+			Failures:  e.failures,
+			LastError: e.lastError,
+		}
+		if now.Before(e.openUntil) {
+			openUntil := e.openUntil
+			s.CircuitOpen, s.OpenUntil = true, &openUntil
+		} else if e.failures >= p.threshold {
+			s.HalfOpen = true
+		}
+		if !e.lastProbe.IsZero() {
+			lastProbe := e.lastProbe
+			s.LastProbeAt = &lastProbe
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// probe checks every endpoint's /health once.
+func (p *pool) probe(ctx context.Context, client *http.Client) {
+	p.mu.Lock()
+	endpoints := append([]*endpoint(nil), p.endpoints...)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			err := probeHealth(ctx, client, e.url)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if e.healthy != (err == nil) {
+				if err != nil {
+					logger.Warn("Extractor endpoint unhealthy", zap.String("endpoint", e.url), zap.Error(err))
+				} else {
+					logger.Info("Extractor endpoint healthy again", zap.String("endpoint", e.url))
+				}
+			}
+			e.healthy = err == nil
+			e.lastProbe = p.now()
+			if err != nil {
+				e.lastError = err.Error()
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+func probeHealth(ctx context.Context, client *http.Client, baseURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("health check returned " + resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool_AcquireLeastOutstanding(t *testing.T) {
+	p := newPool("http://a, http://b/ ,")
+	if len(p.endpoints) != 2 || p.endpoints[1].url != "http://b" {
+		t.Fatalf("endpoints = %+v", p.endpoints)
+	}
+
+	first, _, _ := p.acquire(nil)
+	second, _, _ := p.acquire(nil)
+	if first == second {
+		t.Fatal("second request went to the busy endpoint")
+	}
+	p.release(first, false, nil)
+	if next, _, _ := p.acquire(nil); next != first {
+		t.Errorf("got %s, want the idle %s", next.url, first.url)
+	}
+}
+
+func TestPool_CircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := newPool("http://a,http://b")
+	p.now = func() time.Time { return now }
+	a, b := p.endpoints[0], p.endpoints[1]
+
+	for i := 0; i < p.threshold; i++ {
+		e, _, _ := p.acquire(map[*endpoint]bool{b: true})
+		p.release(e, false, errors.New("connection refused"))
+	}
+	if s := p.status()[0]; !s.CircuitOpen || s.Failures != p.threshold {
+		t.Fatalf("status = %+v, want open circuit", s)
+	}
+	for i := 0; i < 3; i++ {
+		if e, _, _ := p.acquire(nil); e != b {
+			t.Fatalf("request went to %s with its circuit open", e.url)
+		} else {
+			p.release(e, false, nil)
+		}
+	}
+
+	// After the cooldown a trial request goes through and closes the circuit.
+	now = now.Add(p.cooldown)
+	e, trial, _ := p.acquire(map[*endpoint]bool{b: true})
+	if e != a || !trial {
+		t.Fatalf("trial request went to %s (trial %v)", e.url, trial)
+	}
+	p.release(e, trial, nil)
+	if s := p.status()[0]; s.CircuitOpen || s.Failures != 0 {
+		t.Errorf("status = %+v, want closed circuit", s)
+	}
+}
+
+func TestPool_HalfOpenAllowsOneTrial(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := newPool("http://a,http://b")
+	p.now = func() time.Time { return now }
+	a, b := p.endpoints[0], p.endpoints[1]
+
+	for i := 0; i < p.threshold; i++ {
+		e, _, _ := p.acquire(map[*endpoint]bool{b: true})
+		p.release(e, false, errors.New("connection refused"))
+	}
+	now = now.Add(p.cooldown)
+
+	trial, isTrial, _ := p.acquire(map[*endpoint]bool{b: true})
+	if trial != a || !isTrial {
+		t.Fatalf("trial request went to %s (trial %v)", trial.url, isTrial)
+	}
+	if s := p.status()[0]; !s.HalfOpen || s.CircuitOpen {
+		t.Fatalf("status = %+v, want half-open", s)
+	}
+	// While the trial is in flight, everything else goes to b.
+	for i := 0; i < 3; i++ {
+		if e, _, _ := p.acquire(nil); e != b {
+			t.Fatalf("request %d went to %s during the trial", i, e.url)
+		}
+	}
+
+	// A failed trial re-opens the circuit for another cooldown.
+	p.release(trial, true, errors.New("connection refused"))
+	if s := p.status()[0]; !s.CircuitOpen || !s.OpenUntil.Equal(now.Add(p.cooldown)) {
+		t.Errorf("status = %+v, want the circuit re-opened", s)
+	}
+}
+
+func TestPool_CanceledTrialAllowsAnother(t *testing.T) {
+	p := newPool("http://a,http://b")
+	a, b := p.endpoints[0], p.endpoints[1]
+	a.failures = p.threshold
+
+	trial, isTrial, _ := p.acquire(map[*endpoint]bool{b: true})
+	if trial != a || !isTrial {
+		t.Fatalf("trial request went to %s (trial %v)", trial.url, isTrial)
+	}
+	p.release(trial, true, context.Canceled)
+	if e, isTrial, _ := p.acquire(map[*endpoint]bool{b: true}); e != a || !isTrial {
+		t.Errorf("got %s (trial %v), want a new trial on %s", e.url, isTrial, a.url)
+	}
+}
+
+func TestPool_OlderRequestDoesNotEndTrial(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := newPool("http://a,http://b")
+	p.now = func() time.Time { return now }
+	a, b := p.endpoints[0], p.endpoints[1]
+
+	// A slow request starts on a before its circuit opens.
+	slow, slowTrial, _ := p.acquire(map[*endpoint]bool{b: true})
+	if slow != a || slowTrial {
+		t.Fatalf("slow request went to %s (trial %v)", slow.url, slowTrial)
+	}
+	for i := 0; i < p.threshold; i++ {
+		e, _, _ := p.acquire(map[*endpoint]bool{b: true})
+		p.release(e, false, errors.New("connection refused"))
+	}
+	now = now.Add(p.cooldown)
+	trial, isTrial, _ := p.acquire(map[*endpoint]bool{b: true})
+	if trial != a || !isTrial {
+		t.Fatalf("trial request went to %s (trial %v)", trial.url, isTrial)
+	}
+
+	// The slow request finishing must not let a second request through
+	// while the trial is in flight.
+	p.release(slow, slowTrial, context.DeadlineExceeded)
+	for i := 0; i < 3; i++ {
+		if e, isTrial, _ := p.acquire(nil); e != b || isTrial {
+			t.Fatalf("request %d went to %s (trial %v) during the trial", i, e.url, isTrial)
+		}
+	}
+}
+
+func TestPool_CanceledRequestsDontCount(t *testing.T) {
+	p := newPool("http://a")
+	e, _, _ := p.acquire(nil)
+	p.release(e, false, context.Canceled)
+	if s := p.status()[0]; s.Failures != 0 || s.InFlight != 0 {
+		t.Errorf("status = %+v", s)
+	}
+}
+
+func TestHTTPClient_Extract_RetriesOnAnotherEndpoint(t *testing.T) {
+	var downHits atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downHits.Add(1)
+		http.Error(w, "restarting", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title":"Home","selector_matched":true}`))
+	}))
+	defer up.Close()
+
+	client := NewHTTPClient(down.URL + "," + up.URL)
+	for i := 0; i < 2; i++ {
+		res, err := client.Extract(context.Background(), "https://example.com", ExtractOptions{})
+		if err != nil {
+			t.Fatalf("Extract: %v", err)
+		}
+		if res.Title != "Home" {
+			t.Errorf("result = %+v", res)
+		}
+	}
+	if downHits.Load() > 2 {
+		t.Errorf("failing endpoint got %d requests", downHits.Load())
+	}
+}
+
+func TestHTTPClient_Extract_RetriesHungEndpoint(t *testing.T) {
+	// The handler leaves the POST body unread, so the server never sees the
+	// client give up; it holds on until the test ends.
+	done := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer hung.Close()
+	defer close(done)
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title":"Home","selector_matched":true}`))
+	}))
+	defer up.Close()
+
+	client := NewHTTPClient(hung.URL + "," + up.URL)
+	client.attemptTimeout = 50 * time.Millisecond
+	res, err := client.Extract(context.Background(), "https://example.com", ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if res.Title != "Home" {
+		t.Errorf("result = %+v", res)
+	}
+	if s := client.Status()[0]; s.Failures != 1 || s.InFlight != 0 {
+		t.Errorf("hung endpoint status = %+v, want one failure", s)
+	}
+}
+
+func TestHTTPClient_Extract_CanceledAttemptDoesntCount(t *testing.T) {
+	// The handler leaves the POST body unread, so the server never sees the
+	// client give up; it holds on until the test ends.
+	done := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer hung.Close()
+	defer close(done)
+
+	client := NewHTTPClient(hung.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	e, trial, _ := client.endpoints.acquire(nil)
+	if _, retry, err := client.extractFrom(ctx, e, trial, []byte(`{"url":"https://example.com"}`)); err == nil || errors.Is(err, errAttemptTimeout) {
+		t.Fatalf("err = %v, retry = %v; want the caller's deadline", err, retry)
+	}
+	if s := client.Status()[0]; s.Failures != 0 {
+		t.Errorf("failures = %d, want the caller's deadline not counted", s.Failures)
+	}
+}
+
+func TestHTTPClient_Extract_PageFailuresDontCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantRetry bool
+		wantPage  bool
+	}{
+		{"navigation", http.StatusBadGateway, `{"error":"net::ERR_NAME_NOT_RESOLVED","code":"NAVIGATION_ERROR"}`, false, true},
+		{"timeout", http.StatusGatewayTimeout, `{"error":"Navigation timeout of 30000 ms exceeded","code":"TIMEOUT_ERROR"}`, false, true},
+		{"gateway", http.StatusBadGateway, `bad gateway`, false, false},
+		{"browser", http.StatusServiceUnavailable, `{"error":"Browser closed","code":"BROWSER_ERROR"}`, true, false},
+		{"internal", http.StatusInternalServerError, `{"error":"boom","code":"EXTRACTION_ERROR"}`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := NewHTTPClient(srv.URL)
+			e, trial, _ := client.endpoints.acquire(nil)
+			_, retry, err := client.extractFrom(context.Background(), e, trial, []byte(`{"url":"https://example.com"}`))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var pageErr *PageError
+			if errors.As(err, &pageErr) != tt.wantPage {
+				t.Errorf("err = %v, want page error %v", err, tt.wantPage)
+			}
+			if retry != tt.wantRetry {
+				t.Errorf("retry = %v, want %v", retry, tt.wantRetry)
+			}
+			if counted := client.Status()[0].Failures > 0; counted != tt.wantRetry {
+				t.Errorf("failures = %d, want counted %v", client.Status()[0].Failures, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestPool_Probe(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unhealthy.Close()
+
+	p := newPool(healthy.URL + "," + unhealthy.URL)
+	p.probe(context.Background(), http.DefaultClient)
+	status := p.status()
+	if !status[0].Healthy || status[1].Healthy || status[1].LastProbeAt == nil {
+		t.Fatalf("status = %+v", status)
+	}
+	for i := 0; i < 3; i++ {
+		e, _, _ := p.acquire(nil)
+		if e.url != healthy.URL {
+			t.Fatalf("request went to unhealthy %s", e.url)
+		}
+		p.release(e, false, nil)
+	}
+}
@@ -0,0 +1,193 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/config"
+)
+
+// tempPrefix marks files still being written; they are never listed or served.
+const tempPrefix = ".upload-"
+
+// Client stores objects on the local filesystem. Each object lives under two
+// levels of directories named after its hashed name, so no directory grows
+// with the number of pages, and is written to a temporary file that is
+// renamed into place so readers never see a partial object. The API serves
+// objects to holders of URLs signed with an HMAC key.
+type Client struct {
+	root      string
+	publicURL string
+	signKey   []byte
+}
+
+func NewClient(cfg *config.Config) (*Client, error) {
+	if cfg.LocalStorageDir == "" {
+		return nil, fmt.Errorf("local storage directory is required")
+	}
+	if cfg.LocalStorageSignKey == "" {
+		return nil, fmt.Errorf("local storage signing key is required")
+	}
+	root, err := filepath.Abs(cfg.LocalStorageDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		root:      root,
+		publicURL: strings.TrimSuffix(cfg.LocalStoragePublicURL, "/"),
+		signKey:   []byte(cfg.LocalStorageSignKey),
+	}, nil
+}
+
+func (c *Client) EnsureBucket(ctx context.Context) error {
+	return os.MkdirAll(c.root, 0o755)
+}
+
+func (c *Client) Upload(ctx context.Context, objectName string, reader io.Reader, _ int64, _ string) (string, error) {
+	p, err := c.Path(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), tempPrefix+"*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := io.Copy(tmp, reader); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", err
+	}
+	return cleanName(objectName), nil
+}
+
+func (c *Client) Download(ctx context.Context, key string) ([]byte, error) {
+	p, err := c.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	p, err := c.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List walks the whole store, as objects are sharded by hash.
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(c.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+		rel, err := filepath.Rel(c.root, p)
+		if err != nil {
+			return err
+		}
+		// Strip the two shard directories.
+		parts := strings.SplitN(filepath.ToSlash(rel), "/", 3)
+		if len(parts) < 3 {
+			return nil
+		}
+		if name := parts[2]; strings.HasPrefix(name, prefix) {
+			keys = append(keys, name)
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (c *Client) SignedURL(_ context.Context, key string, expiry time.Duration) (string, error) {
+	name := cleanName(key)
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {c.signature(name, expires)}}
+	return c.publicURL + "/" + name + "?" + query.Encode(), nil
+}
+
+// Verify reports whether a signed URL's expires and signature parameters
+// grant access to key now.
+func (c *Client) Verify(key, expires, signature string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(c.signature(cleanName(key), expires)))
+}
+
+// Adopt returns the key of an object uploaded under an unsigned URL, which
+// the API no longer serves.
+func (c *Client) Adopt(_ context.Context, objectURL string) (string, error) {
+	name, ok := strings.CutPrefix(objectURL, c.publicURL+"/")
+	if !ok {
+		return "", fmt.Errorf("object URL %q is not served from %s", objectURL, c.publicURL)
+	}
+	return name, nil
+}
+
+// Path returns where an object is stored. Names are cleaned as rooted paths,
+// so they cannot escape the storage root.
+func (c *Client) Path(objectName string) (string, error) {
+	name := cleanName(objectName)
+	if name == "" || strings.HasPrefix(path.Base(name), tempPrefix) {
+		return "", fmt.Errorf("invalid object name %q", objectName)
+	}
+	sum := sha256.Sum256([]byte(name))
+	shard := hex.EncodeToString(sum[:2])
+	return filepath.Join(c.root, shard[:2], shard[2:], filepath.FromSlash(name)), nil
+}
+
+func (c *Client) signature(name, expires string) string {
+	mac := hmac.New(sha256.New, c.signKey)
+	mac.Write([]byte(name + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func cleanName(objectName string) string {
+	return strings.TrimPrefix(path.Clean("/"+objectName), "/")
+}
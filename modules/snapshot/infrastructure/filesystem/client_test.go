@@ -0,0 +1,133 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/config"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := NewClient(&config.Config{LocalStorageDir: t.TempDir(), LocalStoragePublicURL: "/api/v1/monitoring/files/", LocalStorageSignKey: "k"})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := c.EnsureBucket(context.Background()); err != nil {
+		t.Fatalf("EnsureBucket: %v", err)
+	}
+	return c
+}
+
+func TestClient_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	key, err := c.Upload(ctx, "/page/1.html", strings.NewReader("<p>hi</p>"), 9, "text/html")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if key != "page/1.html" {
+		t.Errorf("key = %q", key)
+	}
+
+	p, _ := c.Path("page/1.html")
+	rel, _ := filepath.Rel(c.root, p)
+	if parts := strings.Split(filepath.ToSlash(rel), "/"); len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		t.Errorf("object stored at %q, want two shard directories", rel)
+	}
+
+	got, err := c.Download(ctx, key)
+	if err != nil || string(got) != "<p>hi</p>" {
+		t.Fatalf("Download = %q, %v", got, err)
+	}
+
+	if err := c.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := c.Download(ctx, key); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Download after delete err = %v", err)
+	}
+	if err := c.Delete(ctx, key); err != nil {
+		t.Errorf("deleting a missing object: %v", err)
+	}
+}
+
+func TestClient_List(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+	for _, name := range []string{"a/1.png", "a/sections/s/1.png", "b/1.png"} {
+		if _, err := c.Upload(ctx, name, strings.NewReader("x"), 1, "image/png"); err != nil {
+			t.Fatalf("Upload %s: %v", name, err)
+		}
+	}
+	// A write in progress is not listed.
+	p, _ := c.Path("a/2.png")
+	os.MkdirAll(filepath.Dir(p), 0o755)
+	os.WriteFile(filepath.Join(filepath.Dir(p), tempPrefix+"123"), []byte("x"), 0o644)
+
+	keys, err := c.List(ctx, "a/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	sort.Strings(keys)
+	want := []string{"a/1.png", "a/sections/s/1.png"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Errorf("List = %v, want %v", keys, want)
+	}
+}
+
+func TestClient_SignedURL(t *testing.T) {
+	c := newTestClient(t)
+	signed, err := c.SignedURL(context.Background(), "a/1.png", time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil || u.Path != "/api/v1/monitoring/files/a/1.png" {
+		t.Fatalf("signed URL = %q", signed)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+
+	if !c.Verify("a/1.png", expires, signature) {
+		t.Error("valid signature rejected")
+	}
+	if c.Verify("a/2.png", expires, signature) {
+		t.Error("signature accepted for another object")
+	}
+	if c.Verify("a/1.png", "1", c.signature("a/1.png", "1")) {
+		t.Error("expired signature accepted")
+	}
+
+	key, err := c.Adopt(context.Background(), "/api/v1/monitoring/files/a/1.png")
+	if err != nil || key != "a/1.png" {
+		t.Errorf("Adopt = %q, %v", key, err)
+	}
+}
+
+func TestClient_PathStaysInRoot(t *testing.T) {
+	c := newTestClient(t)
+	for _, name := range []string{"../../etc/passwd", "/abs/x", "a/../../x"} {
+		p, err := c.Path(name)
+		if err != nil {
+			continue
+		}
+		if !strings.HasPrefix(p, c.root+string(filepath.Separator)) {
+			t.Errorf("Path(%q) = %q escapes the root", name, p)
+		}
+	}
+	if _, err := c.Path(""); err == nil {
+		t.Error("empty name accepted")
+	}
+	if _, err := c.Adopt(context.Background(), "https://elsewhere/x"); err == nil {
+		t.Error("foreign URL adopted")
+	}
+}
@@ -0,0 +1,243 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/netguard"
+)
+
+// Result is the outcome of verifying a single URL.
+type Result struct {
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Broken     bool      `json:"broken"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Checker verifies links with bounded global and per-host concurrency. Results
+// are cached for a TTL so the same URL linked from many pages (nav, footer) is
+// requested once per window.
+type Checker struct {
+	client     *http.Client
+	maxWorkers int
+	perHost    int
+	cacheTTL   time.Duration
+
+	mu        sync.Mutex
+	cache     map[string]Result
+	hostSlots map[string]chan struct{}
+}
+
+const (
+	userAgent        = "Mozilla/5.0 (compatible; PulzifiLinkChecker/1.0)"
+	maxCacheEntries  = 50000
+	maxRedirects     = 10
+	bodyDiscardLimit = 64 << 10
+)
+
+// NewChecker creates a Checker. maxWorkers bounds the total number of in-flight
+// requests; perHost bounds in-flight requests against any single host.
+func NewChecker(maxWorkers, perHost int, timeout, cacheTTL time.Duration) *Checker {
+	if maxWorkers <= 0 {
+		maxWorkers = 10
+	}
+	if perHost <= 0 {
+		perHost = 2
+	}
+
+	// Links on monitored pages must not be usable to probe hosts inside our
+	// own network.
+	dialer := &net.Dialer{Timeout: timeout, Control: netguard.DenyPrivateAddresses}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConnsPerHost:   perHost,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+	}
+
+	return &Checker{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				return nil
+			},
+		},
+		maxWorkers: maxWorkers,
+		perHost:    perHost,
+		cacheTTL:   cacheTTL,
+		cache:      make(map[string]Result),
+		hostSlots:  make(map[string]chan struct{}),
+	}
+}
+
+// CheckAll verifies urls, serving cached results first and issuing at most
+// budget fresh requests (budget < 0 means unlimited). URLs left unchecked
+// because the budget ran out are absent from the returned map. The second
+// return value is the number of fresh requests made.
+func (c *Checker) CheckAll(ctx context.Context, urls []string, budget int) (map[string]Result, int) {
+	results := make(map[string]Result, len(urls))
+	var pending []string
+	for _, u := range urls {
+		if r, ok := c.cached(u); ok {
+			results[u] = r
+			continue
+		}
+		if budget >= 0 && len(pending) >= budget {
+			continue
+		}
+		pending = append(pending, u)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		workers = make(chan struct{}, c.maxWorkers)
+	)
+	for _, u := range pending {
+		wg.Add(1)
+		go func(rawURL string) {
+			defer wg.Done()
+
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-workers }()
+
+			r := c.check(ctx, rawURL)
+			if ctx.Err() != nil {
+				// Cancelled mid-flight: the result says nothing about the link.
+				return
+			}
+			c.store(r)
+
+			mu.Lock()
+			results[rawURL] = r
+			mu.Unlock()
+		}(u)
+	}
+	wg.Wait()
+
+	return results, len(pending)
+}
+
+// check issues a HEAD request and falls back to GET for servers that reject
+// or mishandle HEAD. The per-host slot is held for the whole exchange.
+func (c *Checker) check(ctx context.Context, rawURL string) Result {
+	result := Result{URL: rawURL, CheckedAt: time.Now()}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		result.Broken = true
+		result.Error = err.Error()
+		return result
+	}
+
+	slot := c.hostSlot(strings.ToLower(u.Host))
+	select {
+	case slot <- struct{}{}:
+	case <-ctx.Done():
+		result.Error = ctx.Err().Error()
+		return result
+	}
+	defer func() { <-slot }()
+
+	status, err := c.do(ctx, http.MethodHead, rawURL)
+	if err != nil || status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden || status == http.StatusNotFound {
+		status, err = c.do(ctx, http.MethodGet, rawURL)
+	}
+
+	result.StatusCode = status
+	if err != nil {
+		result.Error = err.Error()
+		result.Broken = true
+		return result
+	}
+	result.Broken = isBrokenStatus(status)
+	return result
+}
+
+func (c *Checker) do(ctx context.Context, method, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, bodyDiscardLimit)) //nolint:errcheck
+
+	return resp.StatusCode, nil
+}
+
+// isBrokenStatus treats 4xx/5xx as broken, except rate limiting and the
+// non-standard codes some sites return to bots, which say nothing about
+// whether the target exists.
+func isBrokenStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, 999:
+		return false
+	}
+	return status >= 400
+}
+
+func (c *Checker) hostSlot(host string) chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	slot, ok := c.hostSlots[host]
+	if !ok {
+		slot = make(chan struct{}, c.perHost)
+		c.hostSlots[host] = slot
+	}
+	return slot
+}
+
+func (c *Checker) cached(rawURL string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.cache[rawURL]
+	if !ok {
+		return Result{}, false
+	}
+	if time.Since(r.CheckedAt) > c.cacheTTL {
+		delete(c.cache, rawURL)
+		return Result{}, false
+	}
+	return r, true
+}
+
+func (c *Checker) store(r Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.cache) >= maxCacheEntries {
+		for k, v := range c.cache {
+			if time.Since(v.CheckedAt) > c.cacheTTL {
+				delete(c.cache, k)
+			}
+		}
+		if len(c.cache) >= maxCacheEntries {
+			c.cache = make(map[string]Result)
+		}
+	}
+	c.cache[r.URL] = r
+}
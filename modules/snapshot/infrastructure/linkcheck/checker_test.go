@@ -0,0 +1,222 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jcsoftdev/pulzifi-back/shared/netguard"
+)
+
+// allowLoopback lets a checker reach httptest servers, which listen on
+// 127.0.0.1 and would otherwise be refused as private addresses.
+func allowLoopback(c *Checker) *Checker {
+	c.client.Transport = &http.Transport{}
+	return c
+}
+
+// concurrencyServer records the most requests it served at once.
+type concurrencyServer struct {
+	*httptest.Server
+	inFlight atomic.Int32
+	max      atomic.Int32
+	hits     atomic.Int32
+}
+
+func newConcurrencyServer(t *testing.T, delay time.Duration, handler http.HandlerFunc) *concurrencyServer {
+	s := &concurrencyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.hits.Add(1)
+		n := s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		for {
+			m := s.max.Load()
+			if n <= m || s.max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		handler(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func ok(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+
+func TestChecker_CheckAll_Statuses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/head-rejected":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/rate-limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	c := allowLoopback(NewChecker(4, 2, 5*time.Second, time.Minute))
+	tests := map[string]bool{
+		srv.URL + "/ok":            false,
+		srv.URL + "/missing":       true,
+		srv.URL + "/head-rejected": false,
+		srv.URL + "/rate-limited":  false,
+	}
+	urls := make([]string, 0, len(tests))
+	for u := range tests {
+		urls = append(urls, u)
+	}
+
+	results, fetched := c.CheckAll(context.Background(), urls, -1)
+	if fetched != len(urls) {
+		t.Errorf("fetched = %d, want %d", fetched, len(urls))
+	}
+	for u, wantBroken := range tests {
+		r, ok := results[u]
+		if !ok {
+			t.Errorf("%s: no result", u)
+			continue
+		}
+		if r.Broken != wantBroken {
+			t.Errorf("%s: broken = %v (status %d, error %q), want %v", u, r.Broken, r.StatusCode, r.Error, wantBroken)
+		}
+	}
+}
+
+func TestChecker_CheckAll_ConcurrencyLimits(t *testing.T) {
+	servers := make([]*concurrencyServer, 3)
+	var urls []string
+	for i := range servers {
+		servers[i] = newConcurrencyServer(t, 30*time.Millisecond, ok)
+		for j := 0; j < 4; j++ {
+			urls = append(urls, servers[i].URL+"/"+strings.Repeat("p", j+1))
+		}
+	}
+
+	var total atomic.Int32
+	var totalMax atomic.Int32
+	c := allowLoopback(NewChecker(2, 1, 5*time.Second, time.Minute))
+	c.client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		n := total.Add(1)
+		defer total.Add(-1)
+		for {
+			m := totalMax.Load()
+			if n <= m || totalMax.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	results, _ := c.CheckAll(context.Background(), urls, -1)
+	if len(results) != len(urls) {
+		t.Fatalf("got %d results, want %d", len(results), len(urls))
+	}
+	if got := totalMax.Load(); got > 2 {
+		t.Errorf("%d requests in flight, want at most 2", got)
+	}
+	for i, s := range servers {
+		if got := s.max.Load(); got > 1 {
+			t.Errorf("server %d had %d requests in flight, want at most 1 per host", i, got)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestChecker_CheckAll_CacheAndBudget(t *testing.T) {
+	srv := newConcurrencyServer(t, 0, ok)
+	c := allowLoopback(NewChecker(4, 2, 5*time.Second, time.Hour))
+	urls := []string{srv.URL + "/a", srv.URL + "/b", srv.URL + "/c"}
+
+	results, fetched := c.CheckAll(context.Background(), urls, 2)
+	if fetched != 2 || len(results) != 2 {
+		t.Fatalf("fetched = %d, results = %d; want the budget of 2", fetched, len(results))
+	}
+	if _, ok := results[urls[2]]; ok {
+		t.Error("URL beyond the budget was checked")
+	}
+
+	// Cached URLs are free, so the budget only covers the remaining one.
+	hits := srv.hits.Load()
+	results, fetched = c.CheckAll(context.Background(), urls, 1)
+	if fetched != 1 || len(results) != 3 {
+		t.Fatalf("fetched = %d, results = %d; want 1 fresh request and 3 results", fetched, len(results))
+	}
+	if got := srv.hits.Load() - hits; got != 1 {
+		t.Errorf("server got %d requests, want 1", got)
+	}
+}
+
+func TestChecker_CacheExpires(t *testing.T) {
+	srv := newConcurrencyServer(t, 0, ok)
+	c := allowLoopback(NewChecker(4, 2, 5*time.Second, time.Minute))
+	u := srv.URL + "/a"
+
+	c.CheckAll(context.Background(), []string{u}, -1)
+	c.mu.Lock()
+	r := c.cache[u]
+	r.CheckedAt = r.CheckedAt.Add(-2 * time.Minute)
+	c.cache[u] = r
+	c.mu.Unlock()
+
+	if _, fetched := c.CheckAll(context.Background(), []string{u}, -1); fetched != 1 {
+		t.Errorf("fetched = %d, want the expired entry re-checked", fetched)
+	}
+	if got := srv.hits.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2", got)
+	}
+}
+
+func TestChecker_CancelledResultsAreNotCached(t *testing.T) {
+	var once sync.Once
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(release) })
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c := allowLoopback(NewChecker(4, 2, 5*time.Second, time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-release
+		cancel()
+	}()
+	results, _ := c.CheckAll(ctx, []string{srv.URL}, -1)
+	if len(results) != 0 {
+		t.Errorf("results = %+v, want none for a cancelled pass", results)
+	}
+	if _, ok := c.cached(srv.URL); ok {
+		t.Error("cancelled result was cached")
+	}
+}
+
+func TestChecker_RefusesPrivateAddresses(t *testing.T) {
+	srv := newConcurrencyServer(t, 0, ok)
+	c := NewChecker(4, 2, 5*time.Second, time.Minute)
+
+	results, _ := c.CheckAll(context.Background(), []string{srv.URL}, -1)
+	r := results[srv.URL]
+	if !r.Broken || !strings.Contains(r.Error, netguard.ErrPrivateAddress.Error()) {
+		t.Errorf("result = %+v, want a refused private address", r)
+	}
+	if srv.hits.Load() != 0 {
+		t.Error("request reached the loopback server")
+	}
+}
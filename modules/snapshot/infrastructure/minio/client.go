@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/jcsoftdev/pulzifi-back/shared/config"
 	"github.com/minio/minio-go/v7"
@@ -12,24 +14,42 @@ import (
 )
 
 type Client struct {
-	minioClient *minio.Client
-	bucketName  string
-	publicURL   string
+	minioClient   *minio.Client
+	presignClient *minio.Client // signs URLs for the public endpoint
+	bucketName    string
+	publicURL     string
 }
 
 func NewClient(cfg *config.Config) (*Client, error) {
+	creds := credentials.NewStaticV4(cfg.MinIOAccessKey, cfg.MinIOSecretKey, "")
 	client, err := minio.New(cfg.MinIOEndpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(cfg.MinIOAccessKey, cfg.MinIOSecretKey, ""),
+		Creds:  creds,
 		Secure: cfg.MinIOUseSSL,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Presigned URLs embed the host they were signed for, so they are signed
+	// with a client for the public endpoint. The region is fixed to keep
+	// signing offline.
+	presignClient := client
+	if public, err := url.Parse(cfg.MinIOPublicURL); err == nil && public.Host != "" {
+		presignClient, err = minio.New(public.Host, &minio.Options{
+			Creds:  creds,
+			Secure: public.Scheme == "https",
+			Region: cfg.MinIORegion,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Client{
-		minioClient: client,
-		bucketName:  cfg.MinIOBucket,
-		publicURL:   cfg.MinIOPublicURL,
+		minioClient:   client,
+		presignClient: presignClient,
+		bucketName:    cfg.MinIOBucket,
+		publicURL:     cfg.MinIOPublicURL,
 	}, nil
 }
 
@@ -44,9 +64,8 @@ func (c *Client) EnsureBucket(ctx context.Context) error {
 			return err
 		}
 	}
-	// Set policy to public read
-	policy := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::%s/*"]}]}`, c.bucketName)
-	return c.minioClient.SetBucketPolicy(ctx, c.bucketName, policy)
+	// Snapshots are private: drop the public-read policy older versions set.
+	return c.minioClient.SetBucketPolicy(ctx, c.bucketName, "")
 }
 
 func (c *Client) Upload(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error) {
@@ -56,12 +75,59 @@ func (c *Client) Upload(ctx context.Context, objectName string, reader io.Reader
 	if err != nil {
 		return "", err
 	}
-	
-	// Construct public URL
-	// If public URL doesn't end with slash, add it
-	baseURL := c.publicURL
-	if !strings.HasSuffix(baseURL, "/") {
-		baseURL += "/"
+	return objectName, nil
+}
+
+func (c *Client) Download(ctx context.Context, key string) ([]byte, error) {
+	obj, err := c.minioClient.GetObject(ctx, c.bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return io.ReadAll(obj)
+}
+
+func (c *Client) Delete(ctx context.Context, key string) error {
+	// S3 reports success for keys that do not exist.
+	return c.minioClient.RemoveObject(ctx, c.bucketName, key, minio.RemoveObjectOptions{})
+}
+
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for obj := range c.minioClient.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		keys = append(keys, obj.Key)
+	}
+	return keys, nil
+}
+
+func (c *Client) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	u, err := c.presignClient.PresignedGetObject(ctx, c.bucketName, key, expiry, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// Adopt returns the key of an object uploaded under a public URL. The bucket
+// policy that made it public is removed by EnsureBucket.
+func (c *Client) Adopt(ctx context.Context, objectURL string) (string, error) {
+	return c.objectName(objectURL)
+}
+
+// objectName recovers the object key from a public URL: the path after the
+// bucket segment.
+func (c *Client) objectName(objectURL string) (string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", err
+	}
+	prefix := "/" + c.bucketName + "/"
+	i := strings.Index(u.Path, prefix)
+	if i < 0 || i+len(prefix) == len(u.Path) {
+		return "", fmt.Errorf("object URL %q is not in bucket %s", objectURL, c.bucketName)
 	}
-	return fmt.Sprintf("%s%s/%s", baseURL, c.bucketName, objectName), nil
+	return u.Path[i+len(prefix):], nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/domain/repositories"
 	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/cloudinary"
+	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/filesystem"
 	"github.com/jcsoftdev/pulzifi-back/modules/snapshot/infrastructure/minio"
 	"github.com/jcsoftdev/pulzifi-back/shared/config"
 )
@@ -18,6 +19,8 @@ func NewObjectStorage(cfg *config.Config) (repositories.ObjectStorage, error) {
 		return minio.NewClient(cfg)
 	case "cloudinary":
 		return cloudinary.NewClient(cfg)
+	case "filesystem", "local":
+		return filesystem.NewClient(cfg)
 	default:
 		return nil, fmt.Errorf("unsupported object storage provider: %s", cfg.ObjectStorageProvider)
 	}
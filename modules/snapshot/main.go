@@ -54,6 +54,8 @@ func main() {
 
 	// Init Extractor Client
 	extractorClient := extractor.NewHTTPClient(cfg.ExtractorURL)
+	extractorClient.SetCircuitBreaker(cfg.ExtractorBreakerThreshold, cfg.ExtractorBreakerCooldown)
+	extractorClient.StartHealthChecks(context.Background(), cfg.ExtractorHealthInterval)
 
 	// Init Insight Handler (optional — requires OPENROUTER_API_KEY)
 	var insightHandler *generateinsights.GenerateInsightsHandler
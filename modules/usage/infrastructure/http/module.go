@@ -87,6 +87,8 @@ type quotaPeriod struct {
 	checksUsed       int
 	nextRefillAt     sql.NullTime
 	storagePeriodDays int
+	linkChecksAllowed int
+	linkChecksUsed    int
 }
 
 // billingPeriodForDate returns the period_start and period_end for the given date,
@@ -151,7 +153,7 @@ func (m *Module) ensureCurrentPeriod(ctx context.Context, tenant string) (*quota
 
 	// Try to find an existing period covering today
 	q := `
-		SELECT period_start, period_end, checks_allowed, checks_used, next_refill_at
+		SELECT period_start, period_end, checks_allowed, checks_used, next_refill_at, COALESCE(link_checks_used, 0)
 		FROM usage_tracking
 		WHERE period_start <= $1::date AND period_end >= $1::date
 		ORDER BY period_end DESC
@@ -159,11 +161,12 @@ func (m *Module) ensureCurrentPeriod(ctx context.Context, tenant string) (*quota
 	`
 	var qp quotaPeriod
 	err := m.db.QueryRowContext(ctx, q, now).Scan(
-		&qp.periodStart, &qp.periodEnd, &qp.checksAllowed, &qp.checksUsed, &qp.nextRefillAt,
+		&qp.periodStart, &qp.periodEnd, &qp.checksAllowed, &qp.checksUsed, &qp.nextRefillAt, &qp.linkChecksUsed,
 	)
 	if err == nil {
-		// Fetch storage_period_days
+		// Fetch storage_period_days and link check allowance from the plan
 		qp.storagePeriodDays = m.fetchStoragePeriodDays(ctx, tenant)
+		qp.linkChecksAllowed = m.fetchLinkChecksAllowed(ctx, tenant)
 		return &qp, nil
 	}
 	if err != sql.ErrNoRows {
@@ -172,7 +175,7 @@ func (m *Module) ensureCurrentPeriod(ctx context.Context, tenant string) (*quota
 
 	// No active period — look up the org's plan to create one
 	planQuery := `
-		SELECT p.checks_allowed_monthly, COALESCE(p.storage_period_days, 7), op.started_at
+		SELECT p.checks_allowed_monthly, COALESCE(p.storage_period_days, 7), COALESCE(p.link_checks_allowed_monthly, 0), op.started_at
 		FROM public.organizations o
 		JOIN public.organization_plans op ON op.organization_id = o.id
 			AND op.status = 'active' AND op.deleted_at IS NULL
@@ -181,9 +184,9 @@ func (m *Module) ensureCurrentPeriod(ctx context.Context, tenant string) (*quota
 		ORDER BY op.started_at DESC
 		LIMIT 1
 	`
-	var checksAllowed, storageDays int
+	var checksAllowed, storageDays, linkChecksAllowed int
 	var startedAt time.Time
-	if err := m.db.QueryRowContext(ctx, planQuery, tenant).Scan(&checksAllowed, &storageDays, &startedAt); err != nil {
+	if err := m.db.QueryRowContext(ctx, planQuery, tenant).Scan(&checksAllowed, &storageDays, &linkChecksAllowed, &startedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("no active plan found for tenant %s", tenant)
 		}
@@ -210,6 +213,7 @@ func (m *Module) ensureCurrentPeriod(ctx context.Context, tenant string) (*quota
 		checksUsed:        0,
 		nextRefillAt:      sql.NullTime{Time: nextRefill, Valid: true},
 		storagePeriodDays: storageDays,
+		linkChecksAllowed: linkChecksAllowed,
 	}, nil
 }
 
@@ -231,6 +235,26 @@ func (m *Module) fetchStoragePeriodDays(ctx context.Context, tenant string) int
 	return storagePeriodDays
 }
 
+// fetchLinkChecksAllowed returns the monthly broken-link verification allowance
+// of the tenant's active plan. Link checks are metered separately from checks.
+func (m *Module) fetchLinkChecksAllowed(ctx context.Context, tenant string) int {
+	linkChecksAllowed := 0
+	q := `
+		SELECT COALESCE(p.link_checks_allowed_monthly, 0)
+		FROM public.organizations o
+		JOIN public.organization_plans op ON op.organization_id = o.id
+			AND op.status = 'active' AND op.deleted_at IS NULL
+		JOIN public.plans p ON p.id = op.plan_id
+		WHERE o.schema_name = $1
+		ORDER BY op.started_at DESC
+		LIMIT 1
+	`
+	if err := m.db.QueryRowContext(ctx, q, tenant).Scan(&linkChecksAllowed); err != nil && err != sql.ErrNoRows {
+		logger.Warn("Failed to fetch link_checks_allowed_monthly, using default", zap.Error(err), zap.String("tenant", tenant))
+	}
+	return linkChecksAllowed
+}
+
 func (m *Module) handleListPlans(w http.ResponseWriter, r *http.Request) {
 	if !isSuperAdmin(r) {
 		forbidden(w)
@@ -243,7 +267,7 @@ func (m *Module) handleListPlans(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rows, err := m.db.QueryContext(r.Context(), `
-		SELECT id, code, name, description, checks_allowed_monthly, is_active, storage_period_days
+		SELECT id, code, name, description, checks_allowed_monthly, is_active, storage_period_days, COALESCE(link_checks_allowed_monthly, 0)
 		FROM public.plans
 		WHERE is_active = TRUE
 		ORDER BY checks_allowed_monthly ASC
@@ -263,21 +287,23 @@ func (m *Module) handleListPlans(w http.ResponseWriter, r *http.Request) {
 		var checksAllowed int
 		var isActive bool
 		var storagePeriodDays int
+		var linkChecksAllowed int
 
-		if err := rows.Scan(&id, &code, &name, &description, &checksAllowed, &isActive, &storagePeriodDays); err != nil {
+		if err := rows.Scan(&id, &code, &name, &description, &checksAllowed, &isActive, &storagePeriodDays, &linkChecksAllowed); err != nil {
 			logger.Error("Failed to scan plan row", zap.Error(err))
 			http.Error(w, "failed to list plans", http.StatusInternalServerError)
 			return
 		}
 
 		plans = append(plans, map[string]interface{}{
-			"id":                     id,
-			"code":                   code,
-			"name":                   name,
-			"description":            description.String,
-			"checks_allowed_monthly": checksAllowed,
-			"is_active":              isActive,
-			"storage_period_days":    storagePeriodDays,
+			"id":                          id,
+			"code":                        code,
+			"name":                        name,
+			"description":                 description.String,
+			"checks_allowed_monthly":      checksAllowed,
+			"is_active":                   isActive,
+			"storage_period_days":         storagePeriodDays,
+			"link_checks_allowed_monthly": linkChecksAllowed,
 		})
 	}
 
@@ -707,6 +733,8 @@ func (m *Module) handleGetQuotas(w http.ResponseWriter, r *http.Request) {
 			"checks_allowed":     qp.checksAllowed,
 			"next_refill_at":     refill,
 			"storage_period_days": qp.storagePeriodDays,
+			"link_checks_used":    qp.linkChecksUsed,
+			"link_checks_allowed": qp.linkChecksAllowed,
 		},
 		"message": "get usage quotas",
 	})
@@ -54,6 +54,7 @@ type Config struct {
 	MinIOBucket    string
 	MinIOUseSSL    bool
 	MinIOPublicURL string
+	MinIORegion    string
 
 	// Snapshot object storage provider
 	ObjectStorageProvider string
@@ -61,9 +62,23 @@ type Config struct {
 	CloudinaryAPIKey      string
 	CloudinaryAPISecret   string
 	CloudinaryFolder      string
+	LocalStorageDir       string
+	LocalStoragePublicURL string
+	LocalStorageSignKey   string
 
-	// Extractor
-	ExtractorURL string
+	// Screenshot thumbnail and preview derivatives
+	SnapshotDerivativeFormat  string
+	SnapshotDerivativeQuality int
+
+	// Page archive replay
+	ArchiveReplayURL     string
+	ArchiveReplaySignKey string
+
+	// Extractor; ExtractorURL may list several endpoints separated by commas
+	ExtractorURL              string
+	ExtractorHealthInterval   time.Duration
+	ExtractorBreakerThreshold int
+	ExtractorBreakerCooldown  time.Duration
 
 	// AI / OpenRouter
 	OpenRouterAPIKey     string
@@ -71,6 +86,15 @@ type Config struct {
 	OpenRouterVisionModel string
 	PixelDiffThreshold    float64
 
+	// Broken link detection
+	LinkCheckConcurrency int
+	LinkCheckPerHost     int
+	LinkCheckTimeout     time.Duration
+	LinkCheckCacheTTL    time.Duration
+
+	// Page credentials (authenticated monitoring)
+	CredentialsEncryptionKey string
+
 	// Email (Resend)
 	ResendAPIKey     string
 	EmailFromAddress string
@@ -133,16 +157,32 @@ func Load() *Config {
 		MinIOBucket:           getEnv("MINIO_BUCKET", ""),
 		MinIOUseSSL:           getEnvBool("MINIO_USE_SSL", false),
 		MinIOPublicURL:        getEnv("MINIO_PUBLIC_URL", ""),
+		MinIORegion:           getEnv("MINIO_REGION", "us-east-1"),
 		ObjectStorageProvider: getEnv("OBJECT_STORAGE_PROVIDER", "minio"),
 		CloudinaryCloudName:   getEnv("CLOUDINARY_CLOUD_NAME", ""),
 		CloudinaryAPIKey:      getEnv("CLOUDINARY_API_KEY", ""),
 		CloudinaryAPISecret:   getEnv("CLOUDINARY_API_SECRET", ""),
 		CloudinaryFolder:      getEnv("CLOUDINARY_FOLDER", ""),
+		LocalStorageDir:       getEnv("LOCAL_STORAGE_DIR", "./volume/snapshots"),
+		LocalStoragePublicURL: getEnv("LOCAL_STORAGE_PUBLIC_URL", "/api/v1/monitoring/files"),
+		LocalStorageSignKey:   getEnv("LOCAL_STORAGE_SIGNING_KEY", jwtSecret),
+		SnapshotDerivativeFormat:  getEnv("SNAPSHOT_DERIVATIVE_FORMAT", "jpeg"),
+		SnapshotDerivativeQuality: getEnvInt("SNAPSHOT_DERIVATIVE_QUALITY", 80),
+		ArchiveReplayURL:          getEnv("ARCHIVE_REPLAY_URL", "/api/v1/monitoring/replay"),
+		ArchiveReplaySignKey:      getEnv("ARCHIVE_REPLAY_SIGNING_KEY", jwtSecret),
 		ExtractorURL:          mustGetEnv("EXTRACTOR_URL"),
+		ExtractorHealthInterval:   getEnvDuration("EXTRACTOR_HEALTH_INTERVAL", 10*time.Second),
+		ExtractorBreakerThreshold: getEnvInt("EXTRACTOR_BREAKER_THRESHOLD", 3),
+		ExtractorBreakerCooldown:  getEnvDuration("EXTRACTOR_BREAKER_COOLDOWN", 30*time.Second),
 		OpenRouterAPIKey:       getEnv("OPENROUTER_API_KEY", ""),
 		OpenRouterModel:        getEnv("OPENROUTER_MODEL", "mistralai/mistral-7b-instruct:free"),
 		OpenRouterVisionModel:  getEnv("OPENROUTER_VISION_MODEL", ""),
 		PixelDiffThreshold:     getEnvFloat("PIXEL_DIFF_THRESHOLD", 0.001),
+		LinkCheckConcurrency:  getEnvInt("LINK_CHECK_CONCURRENCY", 10),
+		LinkCheckPerHost:      getEnvInt("LINK_CHECK_PER_HOST", 2),
+		LinkCheckTimeout:      getEnvDuration("LINK_CHECK_TIMEOUT", 10*time.Second),
+		LinkCheckCacheTTL:     getEnvDuration("LINK_CHECK_CACHE_TTL", time.Hour),
+		CredentialsEncryptionKey: getEnv("CREDENTIALS_ENCRYPTION_KEY", ""),
 		ResendAPIKey:          getEnv("RESEND_API_KEY", ""),
 		EmailFromAddress:      getEnv("EMAIL_FROM_ADDRESS", ""),
 		EmailFromName:         getEnv("EMAIL_FROM_NAME", ""),
@@ -0,0 +1,4 @@
+-- Rollback: add_link_checks_quota
+-- Scope: public
+
+ALTER TABLE plans DROP COLUMN IF EXISTS link_checks_allowed_monthly;
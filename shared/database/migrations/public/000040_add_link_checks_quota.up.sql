@@ -0,0 +1,6 @@
+-- Migration: add_link_checks_quota
+-- Scope: public
+-- Created: 2026-10-18T09:12:41Z
+
+ALTER TABLE plans
+    ADD COLUMN IF NOT EXISTS link_checks_allowed_monthly INTEGER NOT NULL DEFAULT 1000;
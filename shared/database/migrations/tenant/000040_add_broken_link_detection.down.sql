@@ -0,0 +1,8 @@
+-- Rollback: add_broken_link_detection
+-- Scope: tenant
+
+ALTER TABLE usage_tracking DROP COLUMN IF EXISTS link_checks_used;
+
+DROP TABLE IF EXISTS broken_links;
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS check_broken_links;
@@ -0,0 +1,27 @@
+-- Migration: add_broken_link_detection
+-- Scope: tenant
+-- Created: 2026-10-18T09:12:41Z
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS check_broken_links BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS broken_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    anchor_text TEXT,
+    location VARCHAR(255),
+    status_code INTEGER,
+    error_message TEXT,
+    first_check_id UUID NOT NULL,
+    last_check_id UUID NOT NULL,
+    first_detected_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_detected_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_broken_links_page_open
+    ON broken_links(page_id) WHERE resolved_at IS NULL;
+
+ALTER TABLE usage_tracking
+    ADD COLUMN IF NOT EXISTS link_checks_used INTEGER NOT NULL DEFAULT 0;
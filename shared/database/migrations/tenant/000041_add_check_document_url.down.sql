@@ -0,0 +1,4 @@
+-- Rollback: add_check_document_url
+-- Scope: tenant
+
+ALTER TABLE checks DROP COLUMN IF EXISTS document_url;
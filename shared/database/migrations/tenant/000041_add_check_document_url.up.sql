@@ -0,0 +1,7 @@
+-- Migration: add_check_document_url
+-- Scope: tenant
+-- Created: 2026-10-18T11:05:17Z
+
+-- Original PDF/DOCX file for checks of pages that serve documents instead of HTML.
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS document_url TEXT;
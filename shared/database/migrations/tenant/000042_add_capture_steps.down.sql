@@ -0,0 +1,4 @@
+-- Rollback: add_capture_steps
+-- Scope: tenant
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS capture_steps;
@@ -0,0 +1,7 @@
+-- Migration: add_capture_steps
+-- Scope: tenant
+-- Created: 2026-10-18T12:20:44Z
+
+-- Ordered browser actions (click, type, wait, ...) run before each capture.
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS capture_steps JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,4 @@
+-- Rollback: add_page_credentials
+-- Scope: tenant
+
+DROP TABLE IF EXISTS page_credentials;
@@ -0,0 +1,18 @@
+-- Migration: add_page_credentials
+-- Scope: tenant
+-- Created: 2026-10-18T13:05:22Z
+
+CREATE TABLE IF NOT EXISTS page_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    page_id UUID NOT NULL UNIQUE REFERENCES pages(id) ON DELETE CASCADE,
+    auth_type VARCHAR(20) NOT NULL,
+    login_script JSONB,
+    header_names JSONB NOT NULL DEFAULT '[]',
+    cookie_names JSONB NOT NULL DEFAULT '[]',
+    secret_ciphertext TEXT NOT NULL,
+    last_login_status VARCHAR(20),
+    last_login_error TEXT,
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
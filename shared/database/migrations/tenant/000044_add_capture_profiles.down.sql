@@ -0,0 +1,8 @@
+-- Rollback: add_capture_profiles
+-- Scope: tenant
+
+DROP INDEX IF EXISTS idx_checks_page_profile;
+
+ALTER TABLE checks DROP COLUMN IF EXISTS profile_name;
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS capture_profiles;
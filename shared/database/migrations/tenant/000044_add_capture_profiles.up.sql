@@ -0,0 +1,12 @@
+-- Migration: add_capture_profiles
+-- Scope: tenant
+-- Created: 2026-10-18T14:21:07Z
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS capture_profiles JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS profile_name VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_checks_page_profile
+    ON checks(page_id, profile_name, checked_at DESC) WHERE profile_name IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Rollback: add_proxies
+-- Scope: tenant
+
+ALTER TABLE checks DROP COLUMN IF EXISTS proxy_id;
+
+ALTER TABLE monitoring_configs
+    DROP COLUMN IF EXISTS proxy_pool,
+    DROP COLUMN IF EXISTS proxy_region;
+
+DROP TABLE IF EXISTS workspace_proxy_settings;
+DROP TABLE IF EXISTS proxies;
@@ -0,0 +1,40 @@
+-- Migration: add_proxies
+-- Scope: tenant
+-- Created: 2026-10-18T15:02:47Z
+
+CREATE TABLE IF NOT EXISTS proxies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    protocol VARCHAR(10) NOT NULL,
+    host VARCHAR(255) NOT NULL,
+    port INTEGER NOT NULL,
+    username VARCHAR(255),
+    password_ciphertext TEXT,
+    region VARCHAR(32),
+    pool VARCHAR(32),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    quarantined_until TIMESTAMP,
+    last_used_at TIMESTAMP,
+    last_success_at TIMESTAMP,
+    last_failure_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_proxies_region_pool ON proxies(region, pool) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS workspace_proxy_settings (
+    workspace_id UUID PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    proxy_region VARCHAR(32),
+    proxy_pool VARCHAR(32),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS proxy_region VARCHAR(32),
+    ADD COLUMN IF NOT EXISTS proxy_pool VARCHAR(32);
+
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS proxy_id UUID REFERENCES proxies(id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+-- Rollback: add_fetch_engine
+-- Scope: tenant
+
+ALTER TABLE checks DROP COLUMN IF EXISTS fetch_engine;
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS fetch_engine;
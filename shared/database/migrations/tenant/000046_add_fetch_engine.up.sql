@@ -0,0 +1,11 @@
+-- Migration: add_fetch_engine
+-- Scope: tenant
+-- Created: 2026-10-18T16:10:05Z
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS fetch_engine VARCHAR(10) NOT NULL DEFAULT 'browser'
+        CHECK (fetch_engine IN ('browser', 'http', 'auto'));
+
+-- Engine that produced the capture; NULL for checks made before engines existed.
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS fetch_engine VARCHAR(10);
@@ -0,0 +1,13 @@
+-- Rollback: add_selector_health
+-- Scope: tenant
+
+ALTER TABLE checks DROP COLUMN IF EXISTS selector_fallback;
+
+ALTER TABLE monitored_sections
+    DROP COLUMN IF EXISTS selector_broken_at,
+    DROP COLUMN IF EXISTS selector_miss_count;
+
+ALTER TABLE monitoring_configs
+    DROP COLUMN IF EXISTS selector_broken_at,
+    DROP COLUMN IF EXISTS selector_miss_count,
+    DROP COLUMN IF EXISTS selector_fallback;
@@ -0,0 +1,15 @@
+-- Migration: add_selector_health
+-- Scope: tenant
+-- Created: 2026-10-18T16:48:22Z
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS selector_fallback BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS selector_miss_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS selector_broken_at TIMESTAMP;
+
+ALTER TABLE monitored_sections
+    ADD COLUMN IF NOT EXISTS selector_miss_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS selector_broken_at TIMESTAMP;
+
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS selector_fallback BOOLEAN NOT NULL DEFAULT FALSE;
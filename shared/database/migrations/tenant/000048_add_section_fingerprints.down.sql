@@ -0,0 +1,6 @@
+-- Rollback: add_section_fingerprints
+-- Scope: tenant
+
+ALTER TABLE monitored_sections
+    DROP COLUMN IF EXISTS selector_proposal,
+    DROP COLUMN IF EXISTS fingerprint;
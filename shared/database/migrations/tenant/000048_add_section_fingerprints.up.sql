@@ -0,0 +1,10 @@
+-- Migration: add_section_fingerprints
+-- Scope: tenant
+-- Created: 2026-10-18T17:32:05Z
+
+-- fingerprint describes the element a section was drawn on, so it can be found
+-- again when both of its selectors stop matching. selector_proposal holds the
+-- replacement selector found that way until the user accepts it.
+ALTER TABLE monitored_sections
+    ADD COLUMN IF NOT EXISTS fingerprint JSONB,
+    ADD COLUMN IF NOT EXISTS selector_proposal JSONB;
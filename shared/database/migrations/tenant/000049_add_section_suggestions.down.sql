@@ -0,0 +1,4 @@
+-- Rollback: add_section_suggestions
+-- Scope: tenant
+
+DROP TABLE IF EXISTS section_suggestions;
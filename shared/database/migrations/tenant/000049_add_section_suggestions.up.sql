@@ -0,0 +1,21 @@
+-- Migration: add_section_suggestions
+-- Scope: tenant
+-- Created: 2026-10-18T18:05:41Z
+
+-- Sections suggested from the page's structure when it is added, ranked by
+-- how likely each region is to change meaningfully.
+CREATE TABLE IF NOT EXISTS section_suggestions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    css_selector TEXT NOT NULL,
+    xpath_selector TEXT,
+    rect JSONB,
+    viewport_width INTEGER NOT NULL DEFAULT 0,
+    fingerprint JSONB,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_section_suggestions_page ON section_suggestions(page_id, score DESC);
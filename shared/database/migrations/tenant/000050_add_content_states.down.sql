@@ -0,0 +1,10 @@
+-- Rollback: add_content_states
+-- Scope: tenant
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS suppress_recurrences;
+
+ALTER TABLE checks
+    DROP COLUMN IF EXISTS flapping,
+    DROP COLUMN IF EXISTS recurrence;
+
+DROP TABLE IF EXISTS content_states;
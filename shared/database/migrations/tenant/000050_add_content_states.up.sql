@@ -0,0 +1,24 @@
+-- Migration: add_content_states
+-- Scope: tenant
+-- Created: 2026-10-18T19:12:07Z
+
+-- Recent content states per page and section, newest last. A change back to a
+-- state seen here is a recurrence (A/B test, rotating content) rather than a
+-- new change.
+CREATE TABLE IF NOT EXISTS content_states (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES monitored_sections(id) ON DELETE CASCADE,
+    check_id UUID NOT NULL REFERENCES checks(id) ON DELETE CASCADE,
+    state_hash VARCHAR(64) NOT NULL,
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_states_page_section ON content_states(page_id, section_id, recorded_at DESC);
+
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS recurrence BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS flapping BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS suppress_recurrences BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,6 @@
+-- Rollback: add_alert_incidents
+-- Scope: tenant
+
+DROP INDEX IF EXISTS idx_alerts_incident;
+ALTER TABLE alerts DROP COLUMN IF EXISTS incident_id;
+DROP TABLE IF EXISTS alert_incidents;
@@ -0,0 +1,22 @@
+-- Migration: add_alert_incidents
+-- Scope: tenant
+-- Created: 2026-10-18T19:48:22Z
+
+-- Groups alerts for the same content change across pages of one site, so a
+-- site-wide change (a footer link, a cookie banner) notifies once.
+CREATE TABLE IF NOT EXISTS alert_incidents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL,
+    site VARCHAR(255) NOT NULL,
+    diff_hash VARCHAR(64) NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    alert_count INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (workspace_id, site, diff_hash, window_start)
+);
+
+ALTER TABLE alerts
+    ADD COLUMN IF NOT EXISTS incident_id UUID REFERENCES alert_incidents(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_incident ON alerts(incident_id) WHERE incident_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Rollback: add_reevaluation_jobs
+-- Scope: tenant
+
+DROP TABLE IF EXISTS reevaluation_jobs;
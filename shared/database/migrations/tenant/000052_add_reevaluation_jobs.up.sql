@@ -0,0 +1,25 @@
+-- Migration: add_reevaluation_jobs
+-- Scope: tenant
+-- Created: 2026-10-18T20:41:53Z
+
+-- Dry-run replays of change detection over a page's stored captures with its
+-- current rules. results holds one entry per replayed check.
+CREATE TABLE IF NOT EXISTS reevaluation_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES monitored_sections(id) ON DELETE CASCADE,
+    range_from TIMESTAMP NOT NULL,
+    range_to TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_checks INTEGER NOT NULL DEFAULT 0,
+    would_alert INTEGER NOT NULL DEFAULT 0,
+    flipped INTEGER NOT NULL DEFAULT 0,
+    results JSONB NOT NULL DEFAULT '[]',
+    unsupported_selectors JSONB NOT NULL DEFAULT '[]',
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP,
+    applied_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reevaluation_jobs_page ON reevaluation_jobs(page_id, created_at DESC);
@@ -0,0 +1,10 @@
+-- Rollback: add_snapshot_retention
+-- Scope: tenant
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS legal_hold;
+
+DROP INDEX IF EXISTS idx_checks_retention;
+
+ALTER TABLE checks
+    DROP COLUMN IF EXISTS purged_at,
+    DROP COLUMN IF EXISTS pinned;
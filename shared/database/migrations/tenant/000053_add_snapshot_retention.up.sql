@@ -0,0 +1,15 @@
+-- Migration: add_snapshot_retention
+-- Scope: tenant
+-- Created: 2026-10-18T21:26:40Z
+
+-- Retention purges delete a check's stored captures once it is older than the
+-- plan's storage period and tombstone the row with purged_at. Pinned checks
+-- and pages under legal hold are kept.
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_checks_retention ON checks(checked_at) WHERE purged_at IS NULL AND NOT pinned;
+
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
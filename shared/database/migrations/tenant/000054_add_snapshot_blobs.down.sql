@@ -0,0 +1,4 @@
+-- Rollback: add_snapshot_blobs
+-- Scope: tenant
+
+DROP TABLE IF EXISTS snapshot_blobs;
@@ -0,0 +1,15 @@
+-- Migration: add_snapshot_blobs
+-- Scope: tenant
+-- Created: 2026-10-18T23:02:15Z
+
+-- Captures are stored under their content hash, so consecutive identical
+-- captures share one object. ref_count is the number of checks pointing at
+-- the object; retention deletes objects whose count has dropped to zero.
+CREATE TABLE IF NOT EXISTS snapshot_blobs (
+    key TEXT PRIMARY KEY,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_snapshot_blobs_unreferenced ON snapshot_blobs(key) WHERE ref_count = 0;
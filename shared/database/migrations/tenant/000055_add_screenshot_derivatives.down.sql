@@ -0,0 +1,6 @@
+-- Rollback: add_screenshot_derivatives
+-- Scope: tenant
+
+ALTER TABLE checks
+    DROP COLUMN IF EXISTS preview_url,
+    DROP COLUMN IF EXISTS thumbnail_url;
@@ -0,0 +1,9 @@
+-- Migration: add_screenshot_derivatives
+-- Scope: tenant
+-- Created: 2026-10-18T23:41:09Z
+
+-- Compressed, downscaled copies of each screenshot. The original stays
+-- lossless for pixel comparison; lists and previews load the derivatives.
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS thumbnail_url TEXT,
+    ADD COLUMN IF NOT EXISTS preview_url TEXT;
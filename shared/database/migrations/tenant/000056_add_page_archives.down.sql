@@ -0,0 +1,6 @@
+-- Rollback: add_page_archives
+-- Scope: tenant
+
+ALTER TABLE checks DROP COLUMN IF EXISTS archive_url;
+
+ALTER TABLE monitoring_configs DROP COLUMN IF EXISTS archive;
@@ -0,0 +1,11 @@
+-- Migration: add_page_archives
+-- Scope: tenant
+-- Created: 2026-10-19T00:52:17Z
+
+-- Pages in archive mode store an MHTML file of the page and its
+-- subresources with every check, replayable as it rendered on that date.
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS archive BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS archive_url TEXT;
@@ -0,0 +1,4 @@
+-- Rollback: add_timelapse_jobs
+-- Scope: tenant
+
+DROP TABLE IF EXISTS timelapse_jobs;
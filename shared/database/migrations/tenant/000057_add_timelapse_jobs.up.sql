@@ -0,0 +1,26 @@
+-- Migration: add_timelapse_jobs
+-- Scope: tenant
+-- Created: 2026-10-19T01:37:44Z
+
+-- Background renders of a page's or section's screenshots over a date range
+-- as an animated GIF/APNG or a strip. result_url holds the rendered object's
+-- key.
+CREATE TABLE IF NOT EXISTS timelapse_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES monitored_sections(id) ON DELETE CASCADE,
+    range_from TIMESTAMP NOT NULL,
+    range_to TIMESTAMP NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    width INTEGER NOT NULL,
+    date_stamp BOOLEAN NOT NULL DEFAULT FALSE,
+    highlight_diff BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    frames INTEGER NOT NULL DEFAULT 0,
+    result_url TEXT,
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_timelapse_jobs_page ON timelapse_jobs(page_id, created_at DESC);
@@ -0,0 +1,8 @@
+-- Rollback: add_check_heartbeats
+-- Scope: tenant
+
+DROP INDEX IF EXISTS idx_checks_pending;
+
+ALTER TABLE checks
+    DROP COLUMN IF EXISTS dispatch_attempts,
+    DROP COLUMN IF EXISTS heartbeat_at;
@@ -0,0 +1,11 @@
+-- Migration: add_check_heartbeats
+-- Scope: tenant
+-- Created: 2026-10-19T04:12:08Z
+
+-- Workers stamp heartbeat_at while running a check; the reaper uses it and
+-- dispatch_attempts to resolve checks left pending by a crashed worker.
+ALTER TABLE checks
+    ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS dispatch_attempts INT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_checks_pending ON checks (checked_at) WHERE status = 'pending';
@@ -0,0 +1,7 @@
+-- Rollback: add_document_probes
+-- Scope: tenant
+
+ALTER TABLE monitoring_configs
+    DROP COLUMN IF EXISTS document_probed_at,
+    DROP COLUMN IF EXISTS document_kind,
+    DROP COLUMN IF EXISTS document_probe_url;
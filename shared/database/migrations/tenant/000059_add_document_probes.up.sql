@@ -0,0 +1,10 @@
+-- Migration: add_document_probes
+-- Scope: tenant
+-- Created: 2026-10-19T09:41:26Z
+
+-- Caches whether a page URL without a document extension serves a PDF, a
+-- DOCX or a web page, so checks don't send a HEAD probe every time.
+ALTER TABLE monitoring_configs
+    ADD COLUMN IF NOT EXISTS document_probe_url TEXT,
+    ADD COLUMN IF NOT EXISTS document_kind VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS document_probed_at TIMESTAMP;
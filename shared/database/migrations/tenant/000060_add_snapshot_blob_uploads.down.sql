@@ -0,0 +1,4 @@
+-- Rollback: add_snapshot_blob_uploads
+-- Scope: tenant
+
+ALTER TABLE snapshot_blobs DROP COLUMN IF EXISTS uploaded;
@@ -0,0 +1,10 @@
+-- Migration: add_snapshot_blob_uploads
+-- Scope: tenant
+-- Created: 2026-10-19T14:12:48Z
+
+-- A blob row is written before its object is uploaded. Until uploaded is set,
+-- every check that references the blob uploads the object itself instead of
+-- pointing at one that may never arrive. The retention purge also clears it
+-- before deleting an object, so a reference taken meanwhile re-uploads.
+-- Existing rows were uploaded.
+ALTER TABLE snapshot_blobs ADD COLUMN IF NOT EXISTS uploaded BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,8 @@
+-- Rollback: add_check_running_status
+-- Scope: tenant
+
+DROP INDEX IF EXISTS idx_checks_unfinished;
+
+UPDATE checks SET status = 'pending' WHERE status = 'running';
+
+CREATE INDEX IF NOT EXISTS idx_checks_pending ON checks (checked_at) WHERE status = 'pending';
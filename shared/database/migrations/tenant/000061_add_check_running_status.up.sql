@@ -0,0 +1,9 @@
+-- Migration: add_check_running_status
+-- Scope: tenant
+-- Created: 2026-10-19T16:05:31Z
+
+-- Workers move a check from pending to running when they pick it up, so a
+-- check dispatched twice runs once; the reaper watches both states.
+DROP INDEX IF EXISTS idx_checks_pending;
+
+CREATE INDEX IF NOT EXISTS idx_checks_unfinished ON checks (checked_at) WHERE status IN ('pending', 'running');
@@ -0,0 +1,16 @@
+-- Rollback: add_incident_sliding_window
+-- Scope: tenant
+
+DROP TABLE IF EXISTS alert_incident_recipients;
+DROP INDEX IF EXISTS idx_alert_incidents_change;
+ALTER TABLE alert_incidents DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE alert_incidents RENAME COLUMN opened_at TO window_start;
+
+-- Back to clock-hour buckets, keeping one incident per bucket.
+UPDATE alert_incidents SET window_start = date_trunc('hour', window_start);
+DELETE FROM alert_incidents a USING alert_incidents b
+    WHERE a.workspace_id = b.workspace_id AND a.site = b.site AND a.diff_hash = b.diff_hash
+      AND a.window_start = b.window_start AND a.id > b.id;
+ALTER TABLE alert_incidents
+    ADD CONSTRAINT alert_incidents_workspace_id_site_diff_hash_window_start_key
+    UNIQUE (workspace_id, site, diff_hash, window_start);
@@ -0,0 +1,23 @@
+-- Migration: add_incident_sliding_window
+-- Scope: tenant
+-- Created: 2026-10-19T17:22:04Z
+
+-- Incidents collect alerts until an hour after the last one instead of per
+-- clock hour, so the same change seen at 10:59 and 11:01 is one incident.
+ALTER TABLE alert_incidents RENAME COLUMN window_start TO opened_at;
+ALTER TABLE alert_incidents DROP CONSTRAINT IF EXISTS alert_incidents_workspace_id_site_diff_hash_window_start_key;
+ALTER TABLE alert_incidents ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP;
+UPDATE alert_incidents SET last_seen_at = updated_at WHERE last_seen_at IS NULL;
+ALTER TABLE alert_incidents ALTER COLUMN last_seen_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alert_incidents_change
+    ON alert_incidents (workspace_id, site, diff_hash, last_seen_at DESC);
+
+-- Recipients (users by email, webhook integrations) an incident's alerts
+-- already notified, so later alerts only reach the ones it did not.
+CREATE TABLE IF NOT EXISTS alert_incident_recipients (
+    incident_id UUID NOT NULL REFERENCES alert_incidents(id) ON DELETE CASCADE,
+    recipient VARCHAR(100) NOT NULL,
+    notified_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (incident_id, recipient)
+);
@@ -0,0 +1,144 @@
+// Package document extracts monitorable text from non-HTML documents (PDF and
+// DOCX) so they can go through the same content-block diffing as web pages.
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies a supported document format.
+type Kind string
+
+const (
+	KindPDF  Kind = "pdf"
+	KindDOCX Kind = "docx"
+)
+
+const (
+	pdfContentType  = "application/pdf"
+	docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// MaxSize is the largest document that will be downloaded and parsed.
+const MaxSize = 50 << 20
+
+// Paragraph is one block of document text.
+type Paragraph struct {
+	Text  string
+	Level int // heading level 1-6; 0 for body text
+}
+
+// Document is the text content of a parsed document.
+type Document struct {
+	Kind          Kind
+	Title         string
+	Paragraphs    []Paragraph
+	PageCount     int
+	Thumbnail     []byte // page-1 image (PDF) or embedded preview (DOCX); may be nil
+	ThumbnailType string
+}
+
+// DetectKind returns the document kind for a response content type, falling
+// back to the URL's extension when the server sends a generic type. It returns
+// "" for anything that should be rendered by the browser.
+func DetectKind(contentType, rawURL string) Kind {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case pdfContentType, "application/x-pdf":
+		return KindPDF
+	case docxContentType:
+		return KindDOCX
+	case "", "application/octet-stream", "binary/octet-stream", "application/download":
+		return KindFromURL(rawURL)
+	}
+	return ""
+}
+
+// KindFromURL guesses the document kind from the URL path extension.
+func KindFromURL(rawURL string) Kind {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".pdf":
+		return KindPDF
+	case ".docx":
+		return KindDOCX
+	}
+	return ""
+}
+
+// Sniff checks data against the kind's magic bytes.
+func (k Kind) Sniff(data []byte) bool {
+	switch k {
+	case KindPDF:
+		return bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-"))
+	case KindDOCX:
+		return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+	}
+	return false
+}
+
+// Extension returns the file extension used when storing the original.
+func (k Kind) Extension() string {
+	return "." + string(k)
+}
+
+// ContentType returns the canonical MIME type of the kind.
+func (k Kind) ContentType() string {
+	if k == KindDOCX {
+		return docxContentType
+	}
+	return pdfContentType
+}
+
+// Parse extracts the text of a document.
+func Parse(kind Kind, data []byte) (*Document, error) {
+	if !kind.Sniff(data) {
+		return nil, fmt.Errorf("content is not a valid %s file", kind)
+	}
+	switch kind {
+	case KindPDF:
+		return parsePDF(data)
+	case KindDOCX:
+		return parseDOCX(data)
+	}
+	return nil, fmt.Errorf("unsupported document kind %q", kind)
+}
+
+// HTML renders the document as minimal HTML (one element per paragraph) so the
+// existing HTML content-block extraction and diffing can be reused unchanged.
+func (d *Document) HTML() string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>")
+	sb.WriteString(html.EscapeString(d.Title))
+	sb.WriteString("</title></head><body>")
+	for _, p := range d.Paragraphs {
+		tag := "p"
+		if p.Level > 0 {
+			tag = "h" + strconv.Itoa(p.Level)
+		}
+		sb.WriteString("<" + tag + ">")
+		sb.WriteString(html.EscapeString(p.Text))
+		sb.WriteString("</" + tag + ">\n")
+	}
+	sb.WriteString("</body></html>")
+	return sb.String()
+}
+
+// Text returns the paragraphs separated by blank lines.
+func (d *Document) Text() string {
+	parts := make([]string, len(d.Paragraphs))
+	for i, p := range d.Paragraphs {
+		parts[i] = p.Text
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,177 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a minimal single-page PDF around a content stream.
+func buildPDF(t *testing.T, content string, compress bool) []byte {
+	t.Helper()
+
+	stream := []byte(content)
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(stream)
+		w.Close()
+		stream = buf.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d%s >>\nstream\n%s\nendstream", len(stream), filter, stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Title (Quarterly Report) >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	for i, obj := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R /Info 6 0 R /Size 7 >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func TestParsePDF(t *testing.T) {
+	content := `BT /F1 18 Tf 72 720 Td (Pricing) Tj ET
+BT /F1 11 Tf 72 690 Td (Plans start at ) Tj [($10) -300 (per month.)] TJ
+0 -13 Td (Annual billing is discounted.) Tj
+0 -40 Td (Contact sales for enterprise.) Tj ET`
+
+	for _, compress := range []bool{false, true} {
+		doc, err := Parse(KindPDF, buildPDF(t, content, compress))
+		if err != nil {
+			t.Fatalf("Parse(compress=%v): %v", compress, err)
+		}
+		if doc.Title != "Quarterly Report" {
+			t.Errorf("Title = %q", doc.Title)
+		}
+		want := []string{
+			"Pricing",
+			"Plans start at $10 per month. Annual billing is discounted.",
+			"Contact sales for enterprise.",
+		}
+		if len(doc.Paragraphs) != len(want) {
+			t.Fatalf("got %d paragraphs %+v, want %d", len(doc.Paragraphs), doc.Paragraphs, len(want))
+		}
+		for i, p := range doc.Paragraphs {
+			if p.Text != want[i] {
+				t.Errorf("paragraph %d = %q, want %q", i, p.Text, want[i])
+			}
+		}
+	}
+}
+
+func TestParsePDFRejectsNonPDF(t *testing.T) {
+	if _, err := Parse(KindPDF, []byte("<html></html>")); err == nil {
+		t.Fatal("expected an error for non-PDF content")
+	}
+}
+
+// zlibZeros compresses n zero bytes, a stream with a compression ratio of
+// about 1000:1.
+func zlibZeros(n int) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	chunk := make([]byte, 1<<20)
+	for ; n > 0; n -= len(chunk) {
+		w.Write(chunk[:min(n, len(chunk))])
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestInflateLimits(t *testing.T) {
+	bomb := zlibZeros(64 << 20)
+	if len(bomb) > 256<<10 {
+		t.Fatalf("bomb is %d bytes, want a high-ratio stream", len(bomb))
+	}
+	if _, err := inflate(bomb, 1<<20); err != errPDFStreamTooLarge {
+		t.Fatalf("inflate err = %v, want %v", err, errPDFStreamTooLarge)
+	}
+
+	// A stream within the per-stream limit still fails once the file's
+	// total budget runs out.
+	f := &pdfFile{inflated: maxPDFInflatedTotal - 1<<20}
+	if _, err := f.inflate(zlibZeros(2 << 20)); err != errPDFTooLarge || !f.tooLarge {
+		t.Fatalf("inflate err = %v, tooLarge = %v; want the total limit", err, f.tooLarge)
+	}
+}
+
+func TestParsePDFCompressionBomb(t *testing.T) {
+	pdf := buildPDF(t, strings.Repeat("\x00", maxPDFStreamSize+1<<20), true)
+	if len(pdf) > 256<<10 {
+		t.Fatalf("PDF is %d bytes, want a high-ratio stream", len(pdf))
+	}
+	doc, err := Parse(KindPDF, pdf)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(doc.Paragraphs) != 0 {
+		t.Errorf("got paragraphs %+v from an oversized stream", doc.Paragraphs)
+	}
+}
+
+func TestParseDOCX(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Terms of Service</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Effective </w:t></w:r><w:r><w:t>January 1 &amp; onward.</w:t></w:r></w:p>
+<w:p></w:p>
+</w:body></w:document>`))
+	w, _ = zw.Create("docProps/core.xml")
+	w.Write([]byte(`<cp:coreProperties xmlns:cp="x" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>ToS</dc:title></cp:coreProperties>`))
+	zw.Close()
+
+	doc, err := Parse(KindDOCX, buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if doc.Title != "ToS" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	want := []Paragraph{{Text: "Terms of Service", Level: 1}, {Text: "Effective January 1 & onward."}}
+	if len(doc.Paragraphs) != len(want) {
+		t.Fatalf("got %+v, want %+v", doc.Paragraphs, want)
+	}
+	for i := range want {
+		if doc.Paragraphs[i] != want[i] {
+			t.Errorf("paragraph %d = %+v, want %+v", i, doc.Paragraphs[i], want[i])
+		}
+	}
+	if html := doc.HTML(); !strings.Contains(html, "<h1>Terms of Service</h1>") || !strings.Contains(html, "<p>Effective January 1 &amp; onward.</p>") {
+		t.Errorf("HTML() = %s", html)
+	}
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		contentType, url string
+		want             Kind
+	}{
+		{"application/pdf", "https://example.com/download?id=1", KindPDF},
+		{"application/pdf; charset=binary", "https://example.com/x", KindPDF},
+		{docxContentType, "https://example.com/x", KindDOCX},
+		{"application/octet-stream", "https://example.com/files/report.PDF", KindPDF},
+		{"", "https://example.com/terms.docx", KindDOCX},
+		{"text/html; charset=utf-8", "https://example.com/report.pdf", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectKind(tt.contentType, tt.url); got != tt.want {
+			t.Errorf("DetectKind(%q, %q) = %q, want %q", tt.contentType, tt.url, got, tt.want)
+		}
+	}
+}
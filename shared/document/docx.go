@@ -0,0 +1,194 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxDOCXPartSize bounds how much of a single archive member is inflated, so a
+// crafted document cannot exhaust memory.
+const maxDOCXPartSize = 64 << 20
+
+const wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// parseDOCX reads the body paragraphs of word/document.xml, keeping heading
+// levels from the paragraph styles, plus the title and embedded thumbnail
+// from docProps when present.
+func parseDOCX(data []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	body, ok := files["word/document.xml"]
+	if !ok {
+		return nil, errors.New("DOCX has no word/document.xml")
+	}
+	xmlData, err := readZipFile(body)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Kind: KindDOCX}
+	doc.Paragraphs, err = docxParagraphs(xmlData)
+	if err != nil {
+		return nil, err
+	}
+
+	if core, ok := files["docProps/core.xml"]; ok {
+		if coreData, err := readZipFile(core); err == nil {
+			doc.Title = docxTitle(coreData)
+		}
+	}
+
+	for name, contentType := range map[string]string{
+		"docProps/thumbnail.jpeg": "image/jpeg",
+		"docProps/thumbnail.jpg":  "image/jpeg",
+		"docProps/thumbnail.png":  "image/png",
+	} {
+		if f, ok := files[name]; ok {
+			if thumb, err := readZipFile(f); err == nil && len(thumb) > 0 {
+				doc.Thumbnail, doc.ThumbnailType = thumb, contentType
+				break
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, maxDOCXPartSize))
+}
+
+func docxParagraphs(data []byte) ([]Paragraph, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var (
+		paragraphs []Paragraph
+		text       strings.Builder
+		level      int
+		inPara     int // nesting depth: text boxes embed paragraphs in paragraphs
+		inText     bool
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				if inPara == 0 {
+					text.Reset()
+					level = 0
+				}
+				inPara++
+			case "pStyle":
+				level = headingLevel(xmlAttr(t, "val"))
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteByte('\t')
+			case "br", "cr":
+				text.WriteByte(' ')
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				inPara--
+				if inPara == 0 {
+					if s := strings.Join(strings.Fields(text.String()), " "); s != "" {
+						paragraphs = append(paragraphs, Paragraph{Text: s, Level: level})
+					}
+				} else {
+					text.WriteByte(' ')
+				}
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return paragraphs, nil
+}
+
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// headingLevel maps a paragraph style ID such as "Heading2" or "Title" to a
+// heading level, returning 0 for body styles.
+func headingLevel(style string) int {
+	s := strings.ToLower(style)
+	if s == "title" {
+		return 1
+	}
+	if !strings.HasPrefix(s, "heading") {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "heading"))
+	if err != nil || n < 1 {
+		return 0
+	}
+	return min(n, 6)
+}
+
+func docxTitle(data []byte) string {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	inTitle := false
+	var sb strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inTitle = t.Name.Local == "title"
+		case xml.EndElement:
+			if inTitle {
+				return strings.TrimSpace(sb.String())
+			}
+		case xml.CharData:
+			if inTitle {
+				sb.Write(t)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,643 @@
+package document
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// This file implements the subset of the PDF object model needed for text
+// extraction: the COS tokenizer, indirect object lookup (including object
+// streams) and the common stream filters.
+
+type pdfName string
+
+type pdfRef struct {
+	num int
+	gen int
+}
+
+type pdfDict map[pdfName]interface{}
+
+type pdfArray []interface{}
+
+type pdfString []byte
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+type pdfKeyword string
+
+var errPDFSyntax = errors.New("pdf syntax error")
+
+// maxPDFStreamSize bounds how much a single stream is inflated, and
+// maxPDFInflatedTotal how much all of a file's streams are inflated together,
+// so a small file of compression bombs can't exhaust memory.
+const (
+	maxPDFStreamSize    = 16 << 20
+	maxPDFInflatedTotal = 128 << 20
+)
+
+var (
+	errPDFStreamTooLarge = errors.New("PDF stream exceeds the decompressed size limit")
+	errPDFTooLarge       = errors.New("PDF exceeds the decompressed size limit")
+)
+
+// ── Lexer ────────────────────────────────────────────────────────────────────
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// next returns the next token: a primitive value, a pdfKeyword (including
+// structural tokens "[", "]", "<<", ">>") or io.EOF.
+func (l *pdfLexer) next() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.readName(), nil
+	case c == '(':
+		return l.readLiteralString(), nil
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<"), nil
+		}
+		return l.readHexString(), nil
+	case c == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfKeyword(">>"), nil
+		}
+		l.pos++
+		return nil, errPDFSyntax
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return pdfKeyword(string(c)), nil
+	case c == ')':
+		l.pos++
+		return nil, errPDFSyntax
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if n, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	}
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return pdfKeyword(word), nil
+}
+
+func (l *pdfLexer) readName() pdfName {
+	l.pos++ // '/'
+	var buf []byte
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if b, err := hex.DecodeString(string(l.data[l.pos+1 : l.pos+3])); err == nil {
+				buf = append(buf, b[0])
+				l.pos += 3
+				continue
+			}
+		}
+		buf = append(buf, c)
+		l.pos++
+	}
+	return pdfName(buf)
+}
+
+func (l *pdfLexer) readLiteralString() pdfString {
+	l.pos++ // '('
+	var buf []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			buf = append(buf, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return buf
+			}
+			buf = append(buf, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return buf
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case '\r':
+				// Line continuation; swallow an optional LF.
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					buf = append(buf, byte(v))
+				} else {
+					buf = append(buf, e)
+				}
+			}
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
+
+func (l *pdfLexer) readHexString() pdfString {
+	l.pos++ // '<'
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out, _ := hex.DecodeString(string(digits))
+	return out
+}
+
+// readObject parses one complete object, resolving "N G R" references and
+// composite arrays/dictionaries.
+func (l *pdfLexer) readObject() (interface{}, error) {
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+	return l.finishObject(tok)
+}
+
+func (l *pdfLexer) finishObject(tok interface{}) (interface{}, error) {
+	switch t := tok.(type) {
+	case pdfKeyword:
+		switch t {
+		case "[":
+			var arr pdfArray
+			for {
+				item, err := l.next()
+				if err != nil {
+					return nil, err
+				}
+				if k, ok := item.(pdfKeyword); ok && k == "]" {
+					return arr, nil
+				}
+				obj, err := l.finishObject(item)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, obj)
+			}
+		case "<<":
+			dict := pdfDict{}
+			for {
+				key, err := l.next()
+				if err != nil {
+					return nil, err
+				}
+				if k, ok := key.(pdfKeyword); ok && k == ">>" {
+					return dict, nil
+				}
+				name, ok := key.(pdfName)
+				if !ok {
+					return nil, errPDFSyntax
+				}
+				val, err := l.readObject()
+				if err != nil {
+					return nil, err
+				}
+				dict[name] = val
+			}
+		}
+		return t, nil
+	case int64:
+		// Look ahead for "gen R".
+		save := l.pos
+		if gen, err := l.next(); err == nil {
+			if g, ok := gen.(int64); ok {
+				if r, err := l.next(); err == nil {
+					if k, ok := r.(pdfKeyword); ok && k == "R" {
+						return pdfRef{num: int(t), gen: int(g)}, nil
+					}
+				}
+			}
+		}
+		l.pos = save
+		return t, nil
+	}
+	return tok, nil
+}
+
+// ── Document ─────────────────────────────────────────────────────────────────
+
+type pdfFile struct {
+	data    []byte
+	offsets map[int]int // object number -> byte offset just after "obj"
+	objects map[int]interface{}
+	trailer pdfDict
+
+	inflated int  // bytes inflated so far, across all streams
+	tooLarge bool // maxPDFInflatedTotal was reached
+}
+
+var pdfObjHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+func openPDF(data []byte) (*pdfFile, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, errors.New("not a PDF file")
+	}
+
+	f := &pdfFile{data: data, offsets: map[int]int{}, objects: map[int]interface{}{}}
+
+	// Scan for object headers instead of trusting the xref table: it is
+	// frequently broken in real-world files and later definitions (incremental
+	// updates) must win anyway.
+	for _, m := range pdfObjHeader.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		f.offsets[num] = m[1]
+	}
+	if len(f.offsets) == 0 {
+		return nil, errors.New("no objects found in PDF")
+	}
+
+	f.trailer = f.findTrailer()
+	if _, encrypted := f.trailer["Encrypt"]; encrypted {
+		return nil, errors.New("encrypted PDFs are not supported")
+	}
+	f.loadObjectStreams()
+	return f, nil
+}
+
+// findTrailer returns the last trailer dictionary, or the last cross-reference
+// stream dictionary for files that use xref streams.
+func (f *pdfFile) findTrailer() pdfDict {
+	if idx := bytes.LastIndex(f.data, []byte("trailer")); idx >= 0 {
+		l := &pdfLexer{data: f.data, pos: idx + len("trailer")}
+		if obj, err := l.readObject(); err == nil {
+			if d, ok := obj.(pdfDict); ok {
+				return d
+			}
+		}
+	}
+	var trailer pdfDict
+	lastOffset := -1
+	for num, off := range f.offsets {
+		if s, ok := f.object(num).(*pdfStream); ok && s.dict["Type"] == pdfName("XRef") && off > lastOffset {
+			trailer = s.dict
+			lastOffset = off
+		}
+	}
+	if trailer == nil {
+		trailer = pdfDict{}
+	}
+	return trailer
+}
+
+// loadObjectStreams registers the objects packed inside /Type /ObjStm streams.
+// Directly defined objects take precedence.
+func (f *pdfFile) loadObjectStreams() {
+	for num := range f.offsets {
+		s, ok := f.object(num).(*pdfStream)
+		if !ok || s.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := f.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		n, _ := f.resolve(s.dict["N"]).(int64)
+		first, _ := f.resolve(s.dict["First"]).(int64)
+		if first <= 0 || int(first) > len(data) {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:first]}
+		for i := int64(0); i < n; i++ {
+			numTok, err1 := header.next()
+			offTok, err2 := header.next()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			objNum, ok1 := numTok.(int64)
+			off, ok2 := offTok.(int64)
+			if !ok1 || !ok2 {
+				break
+			}
+			if _, direct := f.offsets[int(objNum)]; direct {
+				continue
+			}
+			body := &pdfLexer{data: data, pos: int(first + off)}
+			if obj, err := body.readObject(); err == nil {
+				f.objects[int(objNum)] = obj
+			}
+		}
+	}
+}
+
+func (f *pdfFile) object(num int) interface{} {
+	if obj, ok := f.objects[num]; ok {
+		return obj
+	}
+	off, ok := f.offsets[num]
+	if !ok {
+		return nil
+	}
+	// Guard against reference cycles while parsing (e.g. /Length refs).
+	f.objects[num] = nil
+
+	l := &pdfLexer{data: f.data, pos: off}
+	obj, err := l.readObject()
+	if err != nil {
+		return nil
+	}
+
+	if dict, ok := obj.(pdfDict); ok {
+		save := l.pos
+		if tok, err := l.next(); err == nil && tok == pdfKeyword("stream") {
+			obj = f.readStreamBody(dict, l.pos)
+		} else {
+			l.pos = save
+		}
+	}
+
+	f.objects[num] = obj
+	return obj
+}
+
+func (f *pdfFile) readStreamBody(dict pdfDict, pos int) *pdfStream {
+	// The keyword is followed by CRLF or LF.
+	if pos < len(f.data) && f.data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(f.data) && f.data[pos] == '\n' {
+		pos++
+	}
+
+	end := -1
+	if length, ok := f.resolve(dict["Length"]).(int64); ok && length >= 0 && pos+int(length) <= len(f.data) {
+		end = pos + int(length)
+		// Sanity-check: "endstream" should follow shortly after.
+		tail := f.data[end:min(len(f.data), end+32)]
+		if !bytes.Contains(tail, []byte("endstream")) {
+			end = -1
+		}
+	}
+	if end < 0 {
+		idx := bytes.Index(f.data[pos:], []byte("endstream"))
+		if idx < 0 {
+			return &pdfStream{dict: dict}
+		}
+		end = pos + idx
+		for end > pos && (f.data[end-1] == '\n' || f.data[end-1] == '\r') {
+			end--
+		}
+	}
+	return &pdfStream{dict: dict, raw: f.data[pos:end]}
+}
+
+func (f *pdfFile) resolve(obj interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = f.object(ref.num)
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(obj interface{}) pdfDict {
+	switch v := f.resolve(obj).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// decodeStream applies the stream's filters. Image filters (DCT, JPX, CCITT,
+// JBIG2) are left encoded and reported as an error.
+func (f *pdfFile) decodeStream(s *pdfStream) ([]byte, error) {
+	data := s.raw
+	var filters []interface{}
+	switch v := f.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{v}
+	case pdfArray:
+		filters = v
+	}
+
+	for _, fl := range filters {
+		name, _ := f.resolve(fl).(pdfName)
+		var err error
+		switch name {
+		case "FlateDecode", "Fl":
+			data, err = f.inflate(data)
+		case "ASCIIHexDecode", "AHx":
+			data, err = asciiHexDecode(data)
+		case "ASCII85Decode", "A85":
+			data, err = ascii85Decode(data)
+		default:
+			return nil, fmt.Errorf("unsupported stream filter %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate decompresses a Flate stream within what is left of the file's
+// inflate budget.
+func (f *pdfFile) inflate(data []byte) ([]byte, error) {
+	limit := min(maxPDFStreamSize, maxPDFInflatedTotal-f.inflated)
+	if limit <= 0 {
+		f.tooLarge = true
+		return nil, errPDFTooLarge
+	}
+	out, err := inflate(data, limit)
+	if errors.Is(err, errPDFStreamTooLarge) {
+		f.inflated += limit
+		if limit < maxPDFStreamSize {
+			f.tooLarge = true
+			return nil, errPDFTooLarge
+		}
+		return nil, err
+	}
+	f.inflated += len(out)
+	return out, err
+}
+
+// inflate decompresses zlib or raw deflate data, failing once the output
+// exceeds limit bytes.
+func inflate(data []byte, limit int) ([]byte, error) {
+	read := func(r io.Reader) ([]byte, error) {
+		out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+		if len(out) > limit {
+			return nil, errPDFStreamTooLarge
+		}
+		return out, err
+	}
+
+	if r, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		out, err := read(r)
+		if err == errPDFStreamTooLarge {
+			return nil, err
+		}
+		if len(out) > 0 || err == nil {
+			// Truncated streams are common; keep whatever decoded.
+			return out, nil
+		}
+	}
+	out, err := read(flate.NewReader(bytes.NewReader(data)))
+	if err == errPDFStreamTooLarge {
+		return nil, err
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return nil, err
+}
+
+func asciiHexDecode(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	return hex.DecodeString(string(digits))
+}
+
+func ascii85Decode(data []byte) ([]byte, error) {
+	var out []byte
+	var group [5]byte
+	n := 0
+	flush := func(count int) {
+		var v uint32
+		for i := 0; i < 5; i++ {
+			c := byte('u')
+			if i < count {
+				c = group[i]
+			}
+			v = v*85 + uint32(c-'!')
+		}
+		b := []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		out = append(out, b[:count-1]...)
+	}
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case isPDFWhitespace(c):
+			continue
+		case c == '~':
+			if n > 0 {
+				flush(n)
+			}
+			return out, nil
+		case c == 'z' && n == 0:
+			out = append(out, 0, 0, 0, 0)
+		case c >= '!' && c <= 'u':
+			group[n] = c
+			n++
+			if n == 5 {
+				flush(5)
+				n = 0
+			}
+		default:
+			return nil, errPDFSyntax
+		}
+	}
+	if n > 0 {
+		flush(n)
+	}
+	return out, nil
+}
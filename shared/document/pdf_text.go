@@ -0,0 +1,870 @@
+package document
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	maxPDFPages     = 500
+	maxFormDepth    = 8
+	minThumbnailDim = 300
+)
+
+// parsePDF extracts paragraphs, the document title and (when page 1 embeds a
+// large JPEG, as scanned filings do) a page-1 image usable as a thumbnail.
+func parsePDF(data []byte) (*Document, error) {
+	f, err := openPDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root := f.dict(f.trailer["Root"])
+	if root == nil {
+		// Some xref-stream files only reference /Root from the stream dict we
+		// could not locate; fall back to any catalog object.
+		for num := range f.offsets {
+			if d := f.dict(pdfRef{num: num}); d != nil && d["Type"] == pdfName("Catalog") {
+				root = d
+				break
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("PDF has no document catalog")
+	}
+
+	var pages []pdfDict
+	f.collectPages(f.dict(root["Pages"]), nil, &pages, 0)
+	if len(pages) == 0 {
+		return nil, errors.New("PDF has no pages")
+	}
+
+	doc := &Document{Kind: KindPDF, Title: f.infoTitle()}
+	fonts := map[interface{}]*pdfFont{}
+	for i, page := range pages {
+		tc := &textCollector{f: f, fonts: fonts}
+		tc.runContents(page["Contents"], f.dict(page["Resources"]), 0)
+		for _, text := range tc.paragraphs() {
+			doc.Paragraphs = append(doc.Paragraphs, Paragraph{Text: text})
+		}
+
+		if i == 0 {
+			doc.Thumbnail = f.pageImage(f.dict(page["Resources"]))
+			if doc.Thumbnail != nil {
+				doc.ThumbnailType = "image/jpeg"
+			}
+		}
+	}
+	if f.tooLarge {
+		return nil, errPDFTooLarge
+	}
+	doc.PageCount = len(pages)
+	return doc, nil
+}
+
+// collectPages walks the page tree in order, applying inherited /Resources.
+func (f *pdfFile) collectPages(node pdfDict, inherited pdfDict, out *[]pdfDict, depth int) {
+	if node == nil || depth > 64 || len(*out) >= maxPDFPages {
+		return
+	}
+	resources := inherited
+	if r := f.dict(node["Resources"]); r != nil {
+		resources = r
+	}
+
+	if node["Type"] == pdfName("Page") || (node["Kids"] == nil && node["Contents"] != nil) {
+		page := pdfDict{}
+		for k, v := range node {
+			page[k] = v
+		}
+		page["Resources"] = resources
+		*out = append(*out, page)
+		return
+	}
+
+	kids, _ := f.resolve(node["Kids"]).(pdfArray)
+	for _, kid := range kids {
+		f.collectPages(f.dict(kid), resources, out, depth+1)
+	}
+}
+
+func (f *pdfFile) infoTitle() string {
+	info := f.dict(f.trailer["Info"])
+	if info == nil {
+		return ""
+	}
+	s, _ := f.resolve(info["Title"]).(pdfString)
+	return strings.TrimSpace(decodeTextString(s))
+}
+
+// pageImage returns the largest JPEG image XObject on the page if it is big
+// enough to serve as a thumbnail (logos and icons are ignored).
+func (f *pdfFile) pageImage(resources pdfDict) []byte {
+	xobjects := f.dict(resources["XObject"])
+	var best []byte
+	bestArea := int64(0)
+	for _, ref := range xobjects {
+		s, ok := f.resolve(ref).(*pdfStream)
+		if !ok || s.dict["Subtype"] != pdfName("Image") {
+			continue
+		}
+		filter := f.resolve(s.dict["Filter"])
+		if arr, ok := filter.(pdfArray); ok && len(arr) == 1 {
+			filter = f.resolve(arr[0])
+		}
+		if filter != pdfName("DCTDecode") && filter != pdfName("DCT") {
+			continue
+		}
+		w, _ := f.resolve(s.dict["Width"]).(int64)
+		h, _ := f.resolve(s.dict["Height"]).(int64)
+		if w < minThumbnailDim || h < minThumbnailDim {
+			continue
+		}
+		if w*h > bestArea {
+			best, bestArea = s.raw, w*h
+		}
+	}
+	return best
+}
+
+// ── Fonts ────────────────────────────────────────────────────────────────────
+
+type pdfFont struct {
+	cmap     *toUnicodeCMap
+	encoding map[byte]rune // simple fonts without a ToUnicode map
+	twoByte  bool          // composite (Type0) font: 2-byte codes
+	widths   map[int]float64
+	defaultW float64
+}
+
+func (f *pdfFile) loadFont(ref interface{}, cache map[interface{}]*pdfFont) *pdfFont {
+	var key interface{}
+	if r, ok := ref.(pdfRef); ok {
+		key = r.num
+		if font, ok := cache[key]; ok {
+			return font
+		}
+	}
+
+	font := &pdfFont{widths: map[int]float64{}, defaultW: 500}
+	dict := f.dict(ref)
+	if dict != nil {
+		if s, ok := f.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+			if data, err := f.decodeStream(s); err == nil {
+				font.cmap = parseToUnicode(data)
+			}
+		}
+
+		if dict["Subtype"] == pdfName("Type0") {
+			font.twoByte = true
+			font.defaultW = 1000
+			if descendants, ok := f.resolve(dict["DescendantFonts"]).(pdfArray); ok && len(descendants) > 0 {
+				f.loadCIDWidths(f.dict(descendants[0]), font)
+			}
+		} else {
+			font.encoding = f.simpleEncoding(dict["Encoding"])
+			first, _ := f.resolve(dict["FirstChar"]).(int64)
+			if widths, ok := f.resolve(dict["Widths"]).(pdfArray); ok {
+				for i, w := range widths {
+					font.widths[int(first)+i] = toFloat(f.resolve(w))
+				}
+			}
+		}
+	}
+
+	if key != nil {
+		cache[key] = font
+	}
+	return font
+}
+
+func (f *pdfFile) loadCIDWidths(desc pdfDict, font *pdfFont) {
+	if desc == nil {
+		return
+	}
+	if dw := toFloat(f.resolve(desc["DW"])); dw > 0 {
+		font.defaultW = dw
+	}
+	w, _ := f.resolve(desc["W"]).(pdfArray)
+	for i := 0; i < len(w); {
+		start, ok := f.resolve(w[i]).(int64)
+		if !ok || i+1 >= len(w) {
+			return
+		}
+		switch next := f.resolve(w[i+1]).(type) {
+		case pdfArray:
+			for j, v := range next {
+				font.widths[int(start)+j] = toFloat(f.resolve(v))
+			}
+			i += 2
+		default:
+			if i+2 >= len(w) {
+				return
+			}
+			end, _ := next.(int64)
+			width := toFloat(f.resolve(w[i+2]))
+			for c := start; c <= end && c-start < 65536; c++ {
+				font.widths[int(c)] = width
+			}
+			i += 3
+		}
+	}
+}
+
+func (f *pdfFile) simpleEncoding(enc interface{}) map[byte]rune {
+	table := make(map[byte]rune, 256)
+	for i := 0; i < 256; i++ {
+		table[byte(i)] = winAnsiRune(byte(i))
+	}
+
+	var differences pdfArray
+	switch v := f.resolve(enc).(type) {
+	case pdfDict:
+		differences, _ = f.resolve(v["Differences"]).(pdfArray)
+	}
+
+	code := 0
+	for _, item := range differences {
+		switch v := f.resolve(item).(type) {
+		case int64:
+			code = int(v)
+		case pdfName:
+			if r, ok := glyphRune(string(v)); ok && code >= 0 && code < 256 {
+				table[byte(code)] = r
+			}
+			code++
+		}
+	}
+	return table
+}
+
+// decode converts a shown string into text and its advance in glyph-space
+// units (thousandths of the font size), plus the number of space codes.
+func (font *pdfFont) decode(s []byte) (string, float64, int) {
+	var sb strings.Builder
+	advance := 0.0
+	spaces := 0
+
+	step := 1
+	if font.twoByte {
+		step = 2
+	}
+	if font.cmap != nil && font.cmap.codeLen > 0 {
+		step = font.cmap.codeLen
+	}
+
+	for i := 0; i < len(s); {
+		n := min(step, len(s)-i)
+		code := 0
+		for _, b := range s[i : i+n] {
+			code = code<<8 | int(b)
+		}
+
+		var text string
+		if font.cmap != nil {
+			if t, ok := font.cmap.lookup(s[i:i+n], code); ok {
+				text = t
+			}
+		}
+		if text == "" && !font.twoByte && n == 1 {
+			if r, ok := font.encoding[s[i]]; ok && r != 0 {
+				text = string(r)
+			}
+		}
+		sb.WriteString(text)
+
+		if w, ok := font.widths[code]; ok {
+			advance += w
+		} else {
+			advance += font.defaultW
+		}
+		if n == 1 && s[i] == ' ' {
+			spaces++
+		}
+		i += n
+	}
+	return sb.String(), advance, spaces
+}
+
+// ── ToUnicode CMaps ──────────────────────────────────────────────────────────
+
+type cmapRange struct {
+	lo, hi int
+	dst    []byte   // UTF-16BE start value, incremented across the range
+	arr    []string // explicit per-code destinations
+}
+
+type toUnicodeCMap struct {
+	codeLen int
+	chars   map[string]string
+	ranges  []cmapRange
+}
+
+func parseToUnicode(data []byte) *toUnicodeCMap {
+	cm := &toUnicodeCMap{chars: map[string]string{}}
+	l := &pdfLexer{data: data}
+	var operands []interface{}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			break
+		}
+		kw, isKeyword := tok.(pdfKeyword)
+		if !isKeyword {
+			operands = append(operands, tok)
+			continue
+		}
+		switch kw {
+		case "[":
+			if obj, err := l.finishObject(tok); err == nil {
+				operands = append(operands, obj)
+			}
+			continue
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].(pdfString); ok && len(lo) > cm.codeLen {
+					cm.codeLen = len(lo)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					cm.chars[string(src)] = decodeUTF16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				r := cmapRange{lo: bytesToInt(lo), hi: bytesToInt(hi)}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					r.dst = dst
+				case pdfArray:
+					for _, d := range dst {
+						s, _ := d.(pdfString)
+						r.arr = append(r.arr, decodeUTF16BE(s))
+					}
+				}
+				cm.ranges = append(cm.ranges, r)
+			}
+		}
+		operands = operands[:0]
+	}
+	return cm
+}
+
+func (cm *toUnicodeCMap) lookup(raw []byte, code int) (string, bool) {
+	if t, ok := cm.chars[string(raw)]; ok {
+		return t, true
+	}
+	for _, r := range cm.ranges {
+		if code < r.lo || code > r.hi {
+			continue
+		}
+		offset := code - r.lo
+		if r.arr != nil {
+			if offset < len(r.arr) {
+				return r.arr[offset], true
+			}
+			return "", false
+		}
+		if len(r.dst) == 0 {
+			return "", false
+		}
+		dst := append([]byte(nil), r.dst...)
+		v := bytesToInt(dst[len(dst)-2:]) + offset
+		dst[len(dst)-2], dst[len(dst)-1] = byte(v>>8), byte(v)
+		return decodeUTF16BE(dst), true
+	}
+	return "", false
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func decodeUTF16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		b = append(b, 0)
+	}
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodeTextString decodes a PDF "text string" (Info dict values): UTF-16BE
+// with BOM, UTF-8 with BOM, or PDFDocEncoding (approximated by WinAnsi).
+func decodeTextString(s []byte) string {
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		return decodeUTF16BE(s[2:])
+	}
+	if len(s) >= 3 && s[0] == 0xEF && s[1] == 0xBB && s[2] == 0xBF && utf8.Valid(s[3:]) {
+		return string(s[3:])
+	}
+	var sb strings.Builder
+	for _, c := range s {
+		if r := winAnsiRune(c); r != 0 {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ── Content streams ──────────────────────────────────────────────────────────
+
+// matrix is a PDF transformation matrix [a b c d e f].
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m × n (PDF row-vector convention: apply m, then n).
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func translate(tx, ty float64) matrix { return matrix{1, 0, 0, 1, tx, ty} }
+
+type textLine struct {
+	y        float64
+	size     float64
+	text     strings.Builder
+	endX     float64
+	hasStart bool
+}
+
+type textCollector struct {
+	f     *pdfFile
+	fonts map[interface{}]*pdfFont
+	lines []*textLine
+
+	ctm   matrix
+	stack []matrix
+
+	font      *pdfFont
+	fontSize  float64
+	charSpace float64
+	wordSpace float64
+	hScale    float64
+	leading   float64
+
+	tm, tlm matrix
+}
+
+func (tc *textCollector) runContents(contents interface{}, resources pdfDict, depth int) {
+	var data []byte
+	switch v := tc.f.resolve(contents).(type) {
+	case *pdfStream:
+		data, _ = tc.f.decodeStream(v)
+	case pdfArray:
+		// Content arrays are concatenated; operators may span streams.
+		for _, part := range v {
+			if s, ok := tc.f.resolve(part).(*pdfStream); ok {
+				if d, err := tc.f.decodeStream(s); err == nil {
+					data = append(data, d...)
+					data = append(data, '\n')
+				}
+			}
+		}
+	}
+	if len(data) > 0 {
+		tc.ctm, tc.hScale = identity, 1
+		tc.run(data, resources, depth)
+	}
+}
+
+func (tc *textCollector) run(data []byte, resources pdfDict, depth int) {
+	fontsDict := tc.f.dict(resources["Font"])
+	xobjects := tc.f.dict(resources["XObject"])
+
+	l := &pdfLexer{data: data}
+	var ops []interface{}
+	for {
+		tok, err := l.next()
+		if err != nil {
+			if err == errPDFSyntax {
+				continue
+			}
+			return
+		}
+		kw, isKeyword := tok.(pdfKeyword)
+		if !isKeyword || kw == "[" || kw == "<<" {
+			obj, err := l.finishObject(tok)
+			if err != nil {
+				return
+			}
+			ops = append(ops, obj)
+			continue
+		}
+
+		switch kw {
+		case "q":
+			tc.stack = append(tc.stack, tc.ctm)
+		case "Q":
+			if n := len(tc.stack); n > 0 {
+				tc.ctm = tc.stack[n-1]
+				tc.stack = tc.stack[:n-1]
+			}
+		case "cm":
+			if m, ok := matrixOperand(ops); ok {
+				tc.ctm = m.mul(tc.ctm)
+			}
+		case "BT":
+			tc.tm, tc.tlm = identity, identity
+		case "Tf":
+			if len(ops) >= 2 {
+				if name, ok := ops[len(ops)-2].(pdfName); ok && fontsDict != nil {
+					tc.font = tc.f.loadFont(fontsDict[name], tc.fonts)
+				}
+				tc.fontSize = toFloat(ops[len(ops)-1])
+			}
+		case "Tc":
+			tc.charSpace = lastFloat(ops)
+		case "Tw":
+			tc.wordSpace = lastFloat(ops)
+		case "Tz":
+			tc.hScale = lastFloat(ops) / 100
+		case "TL":
+			tc.leading = lastFloat(ops)
+		case "Td", "TD":
+			if len(ops) >= 2 {
+				tx, ty := toFloat(ops[len(ops)-2]), toFloat(ops[len(ops)-1])
+				if kw == "TD" {
+					tc.leading = -ty
+				}
+				tc.moveLine(tx, ty)
+			}
+		case "Tm":
+			if m, ok := matrixOperand(ops); ok {
+				tc.tm, tc.tlm = m, m
+			}
+		case "T*":
+			tc.moveLine(0, -tc.leading)
+		case "Tj":
+			if s, ok := lastString(ops); ok {
+				tc.show(s)
+			}
+		case "'":
+			tc.moveLine(0, -tc.leading)
+			if s, ok := lastString(ops); ok {
+				tc.show(s)
+			}
+		case "\"":
+			if len(ops) >= 3 {
+				tc.wordSpace, tc.charSpace = toFloat(ops[len(ops)-3]), toFloat(ops[len(ops)-2])
+				tc.moveLine(0, -tc.leading)
+				if s, ok := lastString(ops); ok {
+					tc.show(s)
+				}
+			}
+		case "TJ":
+			if len(ops) >= 1 {
+				if arr, ok := ops[len(ops)-1].(pdfArray); ok {
+					for _, item := range arr {
+						switch v := item.(type) {
+						case pdfString:
+							tc.show(v)
+						case int64, float64:
+							adj := toFloat(v)
+							// Large negative kerning is how many producers encode spaces.
+							if adj < -200 {
+								tc.appendSpace()
+							}
+							tc.tm = translate(-adj/1000*tc.fontSize*tc.hScale, 0).mul(tc.tm)
+						}
+					}
+				}
+			}
+		case "Do":
+			if depth < maxFormDepth && len(ops) >= 1 && xobjects != nil {
+				if name, ok := ops[len(ops)-1].(pdfName); ok {
+					tc.runForm(xobjects[name], resources, depth)
+				}
+			}
+		case "ID":
+			skipInlineImage(l)
+		}
+		ops = ops[:0]
+	}
+}
+
+func (tc *textCollector) runForm(ref interface{}, resources pdfDict, depth int) {
+	s, ok := tc.f.resolve(ref).(*pdfStream)
+	if !ok || s.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := tc.f.decodeStream(s)
+	if err != nil {
+		return
+	}
+	formResources := tc.f.dict(s.dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+
+	saved := tc.ctm
+	if arr, ok := tc.f.resolve(s.dict["Matrix"]).(pdfArray); ok {
+		if m, ok := matrixOperand([]interface{}(arr)); ok {
+			tc.ctm = m.mul(tc.ctm)
+		}
+	}
+	tc.run(data, formResources, depth+1)
+	tc.ctm = saved
+}
+
+func matrixOperand(ops []interface{}) (matrix, bool) {
+	if len(ops) < 6 {
+		return identity, false
+	}
+	var m matrix
+	for i, op := range ops[len(ops)-6:] {
+		m[i] = toFloat(op)
+	}
+	return m, true
+}
+
+func lastString(ops []interface{}) (pdfString, bool) {
+	if len(ops) == 0 {
+		return nil, false
+	}
+	s, ok := ops[len(ops)-1].(pdfString)
+	return s, ok
+}
+
+func skipInlineImage(l *pdfLexer) {
+	for l.pos+2 < len(l.data) {
+		if isPDFWhitespace(l.data[l.pos]) && l.data[l.pos+1] == 'E' && l.data[l.pos+2] == 'I' &&
+			(l.pos+3 >= len(l.data) || isPDFWhitespace(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+func (tc *textCollector) moveLine(tx, ty float64) {
+	tc.tlm = translate(tx, ty).mul(tc.tlm)
+	tc.tm = tc.tlm
+}
+
+// position returns the current text origin in device space and the effective
+// font size after the text and current transformation matrices.
+func (tc *textCollector) position() (x, y, size float64) {
+	trm := tc.tm.mul(tc.ctm)
+	size = math.Abs(tc.fontSize) * math.Hypot(trm[2], trm[3])
+	if size == 0 {
+		size = 10
+	}
+	return trm[4], trm[5], size
+}
+
+func (tc *textCollector) show(s pdfString) {
+	if tc.font == nil {
+		tc.font = &pdfFont{encoding: defaultEncoding(), widths: map[int]float64{}, defaultW: 500}
+	}
+	text, advance, spaces := tc.font.decode(s)
+	x, y, size := tc.position()
+
+	line := tc.lineAt(y, size)
+	if text != "" {
+		// Positioned text after a visible horizontal gap is a new word.
+		if line.hasStart && x-line.endX > size*0.2 {
+			tc.appendSpace()
+		}
+		line.text.WriteString(text)
+		line.hasStart = true
+	}
+
+	width := (advance/1000*tc.fontSize + float64(len(s))*tc.charSpace + float64(spaces)*tc.wordSpace) * tc.hScale
+	tc.tm = translate(width, 0).mul(tc.tm)
+	line.endX, _, _ = tc.position()
+}
+
+// lineAt returns the line at baseline y, starting a new one when the baseline
+// moved by more than half the font size.
+func (tc *textCollector) lineAt(y, size float64) *textLine {
+	if n := len(tc.lines); n > 0 {
+		last := tc.lines[n-1]
+		if math.Abs(last.y-y) < size*0.5 {
+			return last
+		}
+	}
+	line := &textLine{y: y, size: size}
+	tc.lines = append(tc.lines, line)
+	return line
+}
+
+func (tc *textCollector) appendSpace() {
+	if n := len(tc.lines); n > 0 {
+		line := tc.lines[n-1]
+		s := line.text.String()
+		if s != "" && !strings.HasSuffix(s, " ") {
+			line.text.WriteByte(' ')
+		}
+	}
+}
+
+// paragraphs groups consecutive lines into paragraphs, breaking where the
+// vertical gap between baselines exceeds ~1.6x the font size or the font size
+// changes noticeably (headings).
+func (tc *textCollector) paragraphs() []string {
+	var out []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			out = append(out, joinLines(current))
+			current = current[:0]
+		}
+	}
+
+	var prev *textLine
+	for _, line := range tc.lines {
+		text := strings.Join(strings.Fields(line.text.String()), " ")
+		if text == "" {
+			continue
+		}
+		if prev != nil {
+			gap := math.Abs(prev.y - line.y)
+			sizeChange := math.Abs(prev.size-line.size) > math.Max(prev.size, line.size)*0.15
+			if gap > math.Max(prev.size, line.size)*1.6 || sizeChange {
+				flush()
+			}
+		}
+		current = append(current, text)
+		prev = line
+	}
+	flush()
+	return out
+}
+
+// joinLines joins wrapped lines, re-attaching words hyphenated at line end.
+func joinLines(lines []string) string {
+	var sb strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			if strings.HasSuffix(prev, "-") && len(prev) > 1 && isLower(prev[len(prev)-2]) && len(l) > 0 && isLower(l[0]) {
+				s := sb.String()
+				sb.Reset()
+				sb.WriteString(s[:len(s)-1])
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteString(l)
+	}
+	return sb.String()
+}
+
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+func lastFloat(ops []interface{}) float64 {
+	if len(ops) == 0 {
+		return 0
+	}
+	return toFloat(ops[len(ops)-1])
+}
+
+// ── Encodings ────────────────────────────────────────────────────────────────
+
+var cp1252 = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+func winAnsiRune(c byte) rune {
+	switch {
+	case c == '\t' || c == '\n' || c == '\r':
+		return ' '
+	case c < 0x20 || c == 0x7F:
+		return 0
+	case c < 0x80:
+		return rune(c)
+	case c >= 0xA0:
+		return rune(c)
+	}
+	return cp1252[c]
+}
+
+func defaultEncoding() map[byte]rune {
+	table := make(map[byte]rune, 256)
+	for i := 0; i < 256; i++ {
+		table[byte(i)] = winAnsiRune(byte(i))
+	}
+	return table
+}
+
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "quoteright": '’', "quoteleft": '‘',
+	"parenleft": '(', "parenright": ')', "asterisk": '*', "plus": '+', "comma": ',',
+	"hyphen": '-', "minus": '−', "period": '.', "slash": '/', "colon": ':', "semicolon": ';',
+	"less": '<', "equal": '=', "greater": '>', "question": '?', "at": '@',
+	"bracketleft": '[', "backslash": '\\', "bracketright": ']', "underscore": '_',
+	"braceleft": '{', "bar": '|', "braceright": '}', "asciitilde": '~',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"endash": '–', "emdash": '—', "bullet": '•', "ellipsis": '…', "quotedblleft": '“',
+	"quotedblright": '”', "quotesinglbase": '‚', "quotedblbase": '„', "dagger": '†',
+	"daggerdbl": '‡', "trademark": '™', "registered": '®', "copyright": '©', "degree": '°',
+	"Euro": '€', "sterling": '£', "yen": '¥', "cent": '¢', "section": '§', "paragraph": '¶',
+	"fi": 'ﬁ', "fl": 'ﬂ', "ff": 'ﬀ', "ffi": 'ﬃ', "ffl": 'ﬄ', "nbspace": ' ',
+	"eacute": 'é', "egrave": 'è', "aacute": 'á', "agrave": 'à', "oacute": 'ó', "uacute": 'ú',
+	"iacute": 'í', "ntilde": 'ñ', "ccedilla": 'ç', "udieresis": 'ü', "odieresis": 'ö',
+	"adieresis": 'ä', "germandbls": 'ß', "Udieresis": 'Ü', "Odieresis": 'Ö', "Adieresis": 'Ä',
+}
+
+// glyphRune maps a glyph name from an /Encoding /Differences array to a rune.
+func glyphRune(name string) (rune, bool) {
+	if r, ok := glyphNames[name]; ok {
+		return r, true
+	}
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if v, err := strconv.ParseUint(name[3:], 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	if base, _, found := strings.Cut(name, "."); found {
+		return glyphRune(base)
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package html
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ContentBlock is a run of visible text and the block element it sits in.
+// Blocks are the unit content-first change detection compares.
+type ContentBlock struct {
+	Tag  string `json:"tag"`
+	Text string `json:"text"`
+}
+
+// containerTags start a new content block in addition to blockTags.
+var containerTags = map[string]bool{
+	"ul": true, "ol": true, "dl": true, "dt": true, "dd": true,
+	"table": true, "thead": true, "tbody": true, "tfoot": true, "caption": true,
+	"figure": true, "details": true, "summary": true, "form": true,
+	"fieldset": true, "legend": true, "address": true, "body": true,
+}
+
+// ExtractContentBlocks splits an HTML document into content blocks in
+// document order. Each block is the inline text directly inside one block
+// element, with whitespace collapsed; nested block elements form blocks of
+// their own. Empty blocks are dropped.
+func ExtractContentBlocks(htmlContent string) []ContentBlock {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+	var blocks []ContentBlock
+	collectBlocks(doc, "body", &blocks)
+	return blocks
+}
+
+// collectBlocks gathers the inline text of n's subtree into a block tagged
+// tag, flushing around every nested block element.
+func collectBlocks(n *html.Node, tag string, blocks *[]ContentBlock) {
+	var sb strings.Builder
+	flush := func() {
+		if text := normalizeSpace(sb.String()); text != "" {
+			*blocks = append(*blocks, ContentBlock{Tag: tag, Text: text})
+		}
+		sb.Reset()
+	}
+
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		for ; c != nil; c = c.NextSibling {
+			switch c.Type {
+			case html.TextNode:
+				sb.WriteString(c.Data)
+			case html.ElementNode:
+				if skipTags[c.Data] || c.Data == "svg" || c.Data == "template" {
+					continue
+				}
+				if blockTags[c.Data] || containerTags[c.Data] {
+					flush()
+					collectBlocks(c, c.Data, blocks)
+					continue
+				}
+				if c.Data == "br" {
+					sb.WriteString(" ")
+				}
+				walk(c.FirstChild)
+			default:
+				walk(c.FirstChild)
+			}
+		}
+	}
+	walk(n.FirstChild)
+	flush()
+}
+
+// HashContentBlocks returns a SHA-256 over the blocks' tags and text. Equal
+// hashes mean the visible content is the same, however the markup around it
+// changed.
+func HashContentBlocks(blocks []ContentBlock) string {
+	h := sha256.New()
+	for _, b := range blocks {
+		h.Write([]byte(b.Tag))
+		h.Write([]byte{0x1f})
+		h.Write([]byte(b.Text))
+		h.Write([]byte{0x1e})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
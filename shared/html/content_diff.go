@@ -0,0 +1,309 @@
+package html
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Diff operations on content blocks.
+const (
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+	DiffModify = "modify" // same block with edited words; Words holds the inline diff
+	DiffMove   = "move"   // identical block at a new position
+)
+
+// Word operations inside a modified block.
+const (
+	WordEqual  = "equal"
+	WordInsert = "insert"
+	WordDelete = "delete"
+)
+
+const (
+	// maxDiffCells caps the LCS table for block and word diffs. Larger inputs
+	// are treated as a wholesale replacement of the differing range.
+	maxDiffCells = 4_000_000
+	// minModifySimilarity is the word overlap above which a removed and an
+	// added block are reported as one edited block.
+	minModifySimilarity = 0.5
+)
+
+// WordEdit is a run of words that is unchanged, inserted or deleted.
+type WordEdit struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffOp is one change between two versions of a page's content blocks.
+// OldIndex and NewIndex are block positions in the previous and current
+// version, -1 when the block does not exist on that side.
+type DiffOp struct {
+	Op       string     `json:"op"`
+	Tag      string     `json:"tag"`
+	OldIndex int        `json:"old_index"`
+	NewIndex int        `json:"new_index"`
+	OldText  string     `json:"old_text,omitempty"`
+	NewText  string     `json:"new_text,omitempty"`
+	Words    []WordEdit `json:"words,omitempty"`
+}
+
+// ContentDiff is the structural difference between two versions of a page,
+// as ordered block operations.
+type ContentDiff struct {
+	HasChanges   bool     `json:"has_changes"`
+	TotalChanges int      `json:"total_changes"`
+	Added        int      `json:"added"`
+	Removed      int      `json:"removed"`
+	Modified     int      `json:"modified"`
+	Moved        int      `json:"moved"`
+	Ops          []DiffOp `json:"ops"`
+}
+
+// editKind is a step of an edit script.
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+type edit struct {
+	kind editKind
+	a, b int // indices into the old and new sequence; -1 when absent
+}
+
+// DiffContentBlocks compares two versions of a page's content blocks. A block
+// that reappears unchanged elsewhere is a move rather than a deletion plus an
+// insertion, and a block whose words were edited is a modification carrying
+// a word-level diff.
+func DiffContentBlocks(prev, curr []ContentBlock) *ContentDiff {
+	keyOf := func(b ContentBlock) string { return b.Tag + "\x00" + b.Text }
+	prevKeys := make([]string, len(prev))
+	for i, b := range prev {
+		prevKeys[i] = keyOf(b)
+	}
+	currKeys := make([]string, len(curr))
+	for i, b := range curr {
+		currKeys[i] = keyOf(b)
+	}
+	script := diffSequences(prevKeys, currKeys)
+
+	// pair[i] links script step i to the step it was matched with.
+	pair := make([]int, len(script))
+	for i := range pair {
+		pair[i] = -1
+	}
+	pairOp := make([]string, len(script))
+
+	// Moves: a deleted block that was inserted unchanged elsewhere.
+	deleted := map[string][]int{}
+	for i, e := range script {
+		if e.kind == editDelete {
+			deleted[prevKeys[e.a]] = append(deleted[prevKeys[e.a]], i)
+		}
+	}
+	for i, e := range script {
+		if e.kind != editInsert {
+			continue
+		}
+		if pool := deleted[currKeys[e.b]]; len(pool) > 0 {
+			j := pool[0]
+			deleted[currKeys[e.b]] = pool[1:]
+			pair[i], pair[j] = j, i
+			pairOp[i], pairOp[j] = DiffMove, DiffMove
+		}
+	}
+
+	// Modifications: within each run of changes between unchanged blocks,
+	// match removed blocks to the most similar added block with the same tag.
+	for start := 0; start < len(script); {
+		if script[start].kind == editEqual {
+			start++
+			continue
+		}
+		end := start
+		for end < len(script) && script[end].kind != editEqual {
+			end++
+		}
+		for i := start; i < end; i++ {
+			if script[i].kind != editDelete || pair[i] >= 0 {
+				continue
+			}
+			old := prev[script[i].a]
+			best, bestScore := -1, minModifySimilarity
+			for j := start; j < end; j++ {
+				if script[j].kind != editInsert || pair[j] >= 0 || curr[script[j].b].Tag != old.Tag {
+					continue
+				}
+				if score := wordSimilarity(old.Text, curr[script[j].b].Text); score >= bestScore {
+					best, bestScore = j, score
+				}
+			}
+			if best >= 0 {
+				pair[i], pair[best] = best, i
+				pairOp[i], pairOp[best] = DiffModify, DiffModify
+			}
+		}
+		start = end
+	}
+
+	diff := &ContentDiff{}
+	for i, e := range script {
+		switch {
+		case e.kind == editEqual:
+			continue
+		case e.kind == editDelete && pairOp[i] == DiffModify:
+			b := script[pair[i]].b
+			diff.Ops = append(diff.Ops, DiffOp{
+				Op: DiffModify, Tag: prev[e.a].Tag, OldIndex: e.a, NewIndex: b,
+				OldText: prev[e.a].Text, NewText: curr[b].Text,
+				Words: diffWords(prev[e.a].Text, curr[b].Text),
+			})
+			diff.Modified++
+		case e.kind == editDelete && pairOp[i] == DiffMove:
+			continue // reported at its new position
+		case e.kind == editDelete:
+			diff.Ops = append(diff.Ops, DiffOp{Op: DiffDelete, Tag: prev[e.a].Tag, OldIndex: e.a, NewIndex: -1, OldText: prev[e.a].Text})
+			diff.Removed++
+		case pairOp[i] == DiffModify:
+			continue // reported with its removed counterpart
+		case pairOp[i] == DiffMove:
+			a := script[pair[i]].a
+			diff.Ops = append(diff.Ops, DiffOp{Op: DiffMove, Tag: curr[e.b].Tag, OldIndex: a, NewIndex: e.b, NewText: curr[e.b].Text})
+			diff.Moved++
+		default:
+			diff.Ops = append(diff.Ops, DiffOp{Op: DiffInsert, Tag: curr[e.b].Tag, OldIndex: -1, NewIndex: e.b, NewText: curr[e.b].Text})
+			diff.Added++
+		}
+	}
+	diff.TotalChanges = len(diff.Ops)
+	diff.HasChanges = diff.TotalChanges > 0
+	return diff
+}
+
+// diffSequences returns an edit script turning a into b, from a longest
+// common subsequence after trimming the common prefix and suffix.
+func diffSequences(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	script := make([]edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		script = append(script, edit{editEqual, i, i})
+	}
+
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(am), len(bm)
+	if n*m > maxDiffCells {
+		for i := range am {
+			script = append(script, edit{editDelete, prefix + i, -1})
+		}
+		for j := range bm {
+			script = append(script, edit{editInsert, -1, prefix + j})
+		}
+	} else {
+		// lcs[i*(m+1)+j] is the LCS length of am[i:] and bm[j:].
+		lcs := make([]int32, (n+1)*(m+1))
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if am[i] == bm[j] {
+					lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+				} else {
+					lcs[i*(m+1)+j] = max(lcs[(i+1)*(m+1)+j], lcs[i*(m+1)+j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && am[i] == bm[j]:
+				script = append(script, edit{editEqual, prefix + i, prefix + j})
+				i++
+				j++
+			case i < n && (j == m || lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]):
+				script = append(script, edit{editDelete, prefix + i, -1})
+				i++
+			default:
+				script = append(script, edit{editInsert, -1, prefix + j})
+				j++
+			}
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		script = append(script, edit{editEqual, len(a) - suffix + k, len(b) - suffix + k})
+	}
+	return script
+}
+
+// diffWords returns the word-level diff of two texts, merging consecutive
+// words with the same operation into one run.
+func diffWords(oldText, newText string) []WordEdit {
+	a, b := strings.Fields(oldText), strings.Fields(newText)
+	var edits []WordEdit
+	add := func(op, word string) {
+		if n := len(edits); n > 0 && edits[n-1].Op == op {
+			edits[n-1].Text += " " + word
+			return
+		}
+		edits = append(edits, WordEdit{Op: op, Text: word})
+	}
+	for _, e := range diffSequences(a, b) {
+		switch e.kind {
+		case editEqual:
+			add(WordEqual, a[e.a])
+		case editDelete:
+			add(WordDelete, a[e.a])
+		case editInsert:
+			add(WordInsert, b[e.b])
+		}
+	}
+	return edits
+}
+
+// wordSimilarity is the Dice coefficient of two texts' word sets.
+func wordSimilarity(a, b string) float64 {
+	aw, bw := wordSet(a), wordSet(b)
+	if len(aw) == 0 || len(bw) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range aw {
+		if bw[w] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(aw)+len(bw))
+}
+
+func wordSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		set[w] = true
+	}
+	return set
+}
+
+// Signature hashes the diff's operations without their block positions, so
+// the same edit on pages with different layouts signs alike. Returns "" for
+// a diff without changes.
+func (d *ContentDiff) Signature() string {
+	if d == nil || !d.HasChanges {
+		return ""
+	}
+	h := sha256.New()
+	for _, op := range d.Ops {
+		fmt.Fprintf(h, "%s\x1f%s\x1f%s\x1f%s\x1e", op.Op, op.Tag, op.OldText, op.NewText)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
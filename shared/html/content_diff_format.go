@@ -0,0 +1,149 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxAIDiffOps caps the operations FormatDiffForAI lists; the rest are
+	// only counted.
+	maxAIDiffOps = 40
+	// maxAIBlockRunes truncates added, removed and moved block text.
+	maxAIBlockRunes = 200
+	// aiContextWords is how many unchanged words are kept on each side of an
+	// edit inside a modified block.
+	aiContextWords = 3
+)
+
+// FormatDiffForAI renders a diff as compact text for insight prompts: one
+// line per operation, modified blocks reduced to their edits with a few words
+// of context, e.g. `~h2: Pro [-$29-]{+$39+} per month`.
+func FormatDiffForAI(d *ContentDiff) string {
+	if d == nil || !d.HasChanges {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d changes: %d added, %d removed, %d modified, %d moved\n",
+		d.TotalChanges, d.Added, d.Removed, d.Modified, d.Moved)
+	for i, op := range d.Ops {
+		if i == maxAIDiffOps {
+			fmt.Fprintf(&sb, "... and %d more changes\n", len(d.Ops)-maxAIDiffOps)
+			break
+		}
+		switch op.Op {
+		case DiffInsert:
+			fmt.Fprintf(&sb, "+%s: %s\n", op.Tag, truncateRunes(op.NewText, maxAIBlockRunes))
+		case DiffDelete:
+			fmt.Fprintf(&sb, "-%s: %s\n", op.Tag, truncateRunes(op.OldText, maxAIBlockRunes))
+		case DiffMove:
+			fmt.Fprintf(&sb, ">%s: %s (moved)\n", op.Tag, truncateRunes(op.NewText, maxAIBlockRunes))
+		case DiffModify:
+			fmt.Fprintf(&sb, "~%s: %s\n", op.Tag, compactWordDiff(op.Words))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// compactWordDiff renders word edits inline, eliding unchanged runs longer
+// than the context kept around each edit.
+func compactWordDiff(words []WordEdit) string {
+	parts := make([]string, 0, len(words))
+	for i, w := range words {
+		switch w.Op {
+		case WordDelete:
+			parts = append(parts, "[-"+w.Text+"-]")
+		case WordInsert:
+			parts = append(parts, "{+"+w.Text+"+}")
+		default:
+			fields := strings.Fields(w.Text)
+			keepBefore, keepAfter := aiContextWords, aiContextWords
+			if i == 0 {
+				keepBefore = 0
+			}
+			if i == len(words)-1 {
+				keepAfter = 0
+			}
+			if len(fields) > keepBefore+keepAfter+1 {
+				elided := append([]string{}, fields[:keepBefore]...)
+				elided = append(elided, "…")
+				fields = append(elided, fields[len(fields)-keepAfter:]...)
+			}
+			parts = append(parts, strings.Join(fields, " "))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatUnifiedDiff renders a diff in unified-diff style, one hunk per
+// operation, addressed by block index.
+func FormatUnifiedDiff(d *ContentDiff) string {
+	if d == nil || !d.HasChanges {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("--- previous\n+++ current\n")
+	for _, op := range d.Ops {
+		switch op.Op {
+		case DiffInsert:
+			fmt.Fprintf(&sb, "@@ +%d @@\n+[%s] %s\n", op.NewIndex, op.Tag, op.NewText)
+		case DiffDelete:
+			fmt.Fprintf(&sb, "@@ -%d @@\n-[%s] %s\n", op.OldIndex, op.Tag, op.OldText)
+		case DiffModify:
+			fmt.Fprintf(&sb, "@@ -%d +%d @@\n-[%s] %s\n+[%s] %s\n", op.OldIndex, op.NewIndex, op.Tag, op.OldText, op.Tag, op.NewText)
+		case DiffMove:
+			fmt.Fprintf(&sb, "@@ -%d +%d moved @@\n [%s] %s\n", op.OldIndex, op.NewIndex, op.Tag, op.NewText)
+		}
+	}
+	return sb.String()
+}
+
+// FormatHTMLRedline renders a diff as an HTML fragment: one element per
+// operation, with <del> and <ins> marking removed and added text. Elements
+// carry diff-<op> classes and the block tag for styling.
+func FormatHTMLRedline(d *ContentDiff) string {
+	if d == nil || !d.HasChanges {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(`<div class="diff">` + "\n")
+	for _, op := range d.Ops {
+		fmt.Fprintf(&sb, `<div class="diff-op diff-%s" data-tag="%s"`, op.Op, html.EscapeString(op.Tag))
+		switch op.Op {
+		case DiffInsert:
+			fmt.Fprintf(&sb, `><ins>%s</ins>`, html.EscapeString(op.NewText))
+		case DiffDelete:
+			fmt.Fprintf(&sb, `><del>%s</del>`, html.EscapeString(op.OldText))
+		case DiffMove:
+			fmt.Fprintf(&sb, ` data-from="%d" data-to="%d">%s`, op.OldIndex, op.NewIndex, html.EscapeString(op.NewText))
+		case DiffModify:
+			sb.WriteString(">")
+			for i, w := range op.Words {
+				if i > 0 {
+					sb.WriteString(" ")
+				}
+				switch w.Op {
+				case WordDelete:
+					fmt.Fprintf(&sb, "<del>%s</del>", html.EscapeString(w.Text))
+				case WordInsert:
+					fmt.Fprintf(&sb, "<ins>%s</ins>", html.EscapeString(w.Text))
+				default:
+					sb.WriteString(html.EscapeString(w.Text))
+				}
+			}
+		}
+		sb.WriteString("</div>\n")
+	}
+	sb.WriteString("</div>")
+	return sb.String()
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
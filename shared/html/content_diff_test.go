@@ -0,0 +1,131 @@
+package html
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractContentBlocks(t *testing.T) {
+	page := `<html><head><title>x</title><style>p{}</style></head><body>
+<header><nav><a href="/">Home</a> <a href="/pricing">Pricing</a></nav></header>
+<main><h1>Simple   pricing</h1><div>Intro <b>text</b><p>Pro plan</p>after</div>
+<ul><li>One</li><li>Two</li></ul><script>var x = 1</script></main>
+</body></html>`
+	got := ExtractContentBlocks(page)
+	want := []ContentBlock{
+		{"nav", "Home Pricing"},
+		{"h1", "Simple pricing"},
+		{"div", "Intro text"},
+		{"p", "Pro plan"},
+		{"div", "after"},
+		{"li", "One"},
+		{"li", "Two"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d blocks %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("block %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if HashContentBlocks(got) != HashContentBlocks(ExtractContentBlocks(strings.ReplaceAll(page, "<b>", "<i>"))) {
+		t.Error("markup-only changes should not change the block hash")
+	}
+}
+
+func TestDiffContentBlocks(t *testing.T) {
+	prev := []ContentBlock{
+		{"h1", "Pricing"},
+		{"p", "Customers love us"},
+		{"p", "Pro plan costs $29 per month billed yearly"},
+		{"li", "Unlimited projects"},
+		{"p", "Contact sales"},
+	}
+	curr := []ContentBlock{
+		{"h1", "Pricing"},
+		{"p", "Pro plan costs $39 per month billed yearly"},
+		{"li", "Unlimited projects"},
+		{"p", "Customers love us"},
+		{"p", "Free trial for 14 days"},
+	}
+	d := DiffContentBlocks(prev, curr)
+
+	if !d.HasChanges || d.Moved != 1 || d.Modified != 1 || d.Added != 1 || d.Removed != 1 {
+		t.Fatalf("diff = %+v, want 1 move, 1 modify, 1 insert, 1 delete", d)
+	}
+	for _, op := range d.Ops {
+		switch op.Op {
+		case DiffMove:
+			if op.NewText != "Customers love us" || op.OldIndex != 1 || op.NewIndex != 3 {
+				t.Errorf("move = %+v", op)
+			}
+		case DiffModify:
+			want := []WordEdit{{WordEqual, "Pro plan costs"}, {WordDelete, "$29"}, {WordInsert, "$39"}, {WordEqual, "per month billed yearly"}}
+			if len(op.Words) != len(want) {
+				t.Fatalf("words = %+v, want %+v", op.Words, want)
+			}
+			for i := range want {
+				if op.Words[i] != want[i] {
+					t.Errorf("word %d = %+v, want %+v", i, op.Words[i], want[i])
+				}
+			}
+		}
+	}
+
+	if same := DiffContentBlocks(prev, prev); same.HasChanges {
+		t.Errorf("identical blocks produced %+v", same)
+	}
+}
+
+func TestContentDiffFormats(t *testing.T) {
+	d := DiffContentBlocks(
+		[]ContentBlock{{"p", "Pro plan costs $29 per month"}, {"p", "Old <note>"}},
+		[]ContentBlock{{"p", "Pro plan costs $39 per month"}},
+	)
+
+	ai := FormatDiffForAI(d)
+	if !strings.Contains(ai, "~p: Pro plan costs [-$29-] {+$39+} per month") || !strings.Contains(ai, "-p: Old <note>") {
+		t.Errorf("FormatDiffForAI =\n%s", ai)
+	}
+
+	unified := FormatUnifiedDiff(d)
+	if !strings.HasPrefix(unified, "--- previous\n+++ current\n") || !strings.Contains(unified, "+[p] Pro plan costs $39 per month") {
+		t.Errorf("FormatUnifiedDiff =\n%s", unified)
+	}
+
+	redline := FormatHTMLRedline(d)
+	if !strings.Contains(redline, "<del>$29</del> <ins>$39</ins>") || !strings.Contains(redline, "<del>Old &lt;note&gt;</del>") {
+		t.Errorf("FormatHTMLRedline =\n%s", redline)
+	}
+}
+
+func TestCompactWordDiff(t *testing.T) {
+	words := []WordEdit{
+		{WordEqual, "one two three four five six seven eight"},
+		{WordDelete, "nine"},
+		{WordEqual, "ten eleven twelve thirteen fourteen fifteen sixteen seventeen"},
+	}
+	want := "… six seven eight [-nine-] ten eleven twelve …"
+	if got := compactWordDiff(words); got != want {
+		t.Errorf("compactWordDiff = %q, want %q", got, want)
+	}
+}
+
+func TestContentDiffSignature(t *testing.T) {
+	footer := ContentBlock{"footer", "Terms Privacy Careers"}
+	a := DiffContentBlocks(
+		[]ContentBlock{{"h1", "Pricing"}, {"footer", "Terms Privacy"}},
+		[]ContentBlock{{"h1", "Pricing"}, footer},
+	)
+	b := DiffContentBlocks(
+		[]ContentBlock{{"h1", "Blog"}, {"p", "Post"}, {"p", "Another"}, {"footer", "Terms Privacy"}},
+		[]ContentBlock{{"h1", "Blog"}, {"p", "Post"}, {"p", "Another"}, footer},
+	)
+	if a.Signature() == "" || a.Signature() != b.Signature() {
+		t.Errorf("the same footer edit on two pages should sign alike: %q vs %q", a.Signature(), b.Signature())
+	}
+	if DiffContentBlocks(nil, nil).Signature() != "" {
+		t.Error("an empty diff should have no signature")
+	}
+}
@@ -0,0 +1,122 @@
+package html
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Link is a hyperlink found in a page, resolved to an absolute URL.
+type Link struct {
+	URL        string `json:"url"`
+	AnchorText string `json:"anchor_text"`
+	Location   string `json:"location"` // landmark path, e.g. "footer > nav"
+	Internal   bool   `json:"internal"` // same host as the page
+}
+
+var landmarkTags = map[string]bool{
+	"header": true, "nav": true, "main": true, "footer": true,
+	"aside": true, "section": true, "article": true, "form": true,
+}
+
+// ExtractLinks returns the unique http(s) links of a page in document order.
+// Relative hrefs are resolved against <base href> or pageURL; fragments are
+// dropped so in-page anchors collapse onto the page itself. Non-navigational
+// schemes (mailto:, tel:, javascript:) are skipped.
+func ExtractLinks(htmlContent, pageURL string) []Link {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	if href := findBaseHref(doc); href != "" {
+		if b, err := base.Parse(href); err == nil {
+			base = b
+		}
+	}
+
+	seen := make(map[string]bool)
+	var links []Link
+	var walk func(n *html.Node, landmarks []string)
+	walk = func(n *html.Node, landmarks []string) {
+		if n.Type == html.ElementNode {
+			if skipTags[n.Data] {
+				return
+			}
+			if landmarkTags[n.Data] {
+				landmarks = append(landmarks[:len(landmarks):len(landmarks)], landmarkLabel(n))
+			}
+			if n.Data == "a" {
+				if link, ok := resolveLink(base, n, landmarks); ok && !seen[link.URL] {
+					seen[link.URL] = true
+					links = append(links, link)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, landmarks)
+		}
+	}
+	walk(doc, nil)
+
+	return links
+}
+
+func resolveLink(base *url.URL, n *html.Node, landmarks []string) (Link, bool) {
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" || strings.HasPrefix(href, "#") {
+		return Link{}, false
+	}
+
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Link{}, false
+	}
+	u.Fragment = ""
+
+	text := normalizeSpace(nodeText(n))
+	if text == "" {
+		text = strings.TrimSpace(attr(n, "aria-label"))
+	}
+	if text == "" {
+		text = strings.TrimSpace(attr(n, "title"))
+	}
+
+	location := "body"
+	if len(landmarks) > 0 {
+		location = strings.Join(landmarks, " > ")
+	}
+
+	return Link{
+		URL:        u.String(),
+		AnchorText: text,
+		Location:   location,
+		Internal:   strings.EqualFold(u.Hostname(), base.Hostname()),
+	}, true
+}
+
+// landmarkLabel renders a landmark element as tag#id when it has an id, which
+// makes locations such as "section#pricing" easy to find on the page.
+func landmarkLabel(n *html.Node) string {
+	if id := strings.TrimSpace(attr(n, "id")); id != "" {
+		return n.Data + "#" + id
+	}
+	return n.Data
+}
+
+func findBaseHref(n *html.Node) string {
+	if n.Type == html.ElementNode && n.Data == "base" {
+		return strings.TrimSpace(attr(n, "href"))
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if href := findBaseHref(c); href != "" {
+			return href
+		}
+	}
+	return ""
+}
@@ -0,0 +1,29 @@
+package html
+
+import "testing"
+
+func TestExtractLinks(t *testing.T) {
+	page := `<html><head><base href="https://acme.test/docs/"></head><body>
+<header><nav id="main"><a href="/pricing">Pricing</a><a href="#top">Top</a></nav></header>
+<main><p><a href="guide#install">Install <b>guide</b></a></p>
+<a href="mailto:hi@acme.test">Mail</a><a href="javascript:void(0)">JS</a>
+<a href="https://other.test/x" aria-label="Partner"></a></main>
+<footer><a href="/pricing">Pricing again</a></footer>
+</body></html>`
+
+	links := ExtractLinks(page, "https://acme.test/docs/index.html")
+
+	want := []Link{
+		{URL: "https://acme.test/pricing", AnchorText: "Pricing", Location: "header > nav#main", Internal: true},
+		{URL: "https://acme.test/docs/guide", AnchorText: "Install guide", Location: "main", Internal: true},
+		{URL: "https://other.test/x", AnchorText: "Partner", Location: "main", Internal: false},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("got %d links, want %d: %+v", len(links), len(want), links)
+	}
+	for i, w := range want {
+		if links[i] != w {
+			t.Errorf("links[%d] = %+v, want %+v", i, links[i], w)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package html
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// minStaticTextLength is the visible text below which a page that ships
+	// scripts is assumed to render its content client-side.
+	minStaticTextLength = 200
+	// maxNoscriptWarningTextLength bounds how much text a page may have while
+	// still being treated as a "please enable JavaScript" shell.
+	maxNoscriptWarningTextLength = 1000
+)
+
+// appRootIDs are the mount points of common client-side frameworks.
+var appRootIDs = map[string]bool{
+	"root": true, "app": true, "__next": true, "__nuxt": true,
+	"___gatsby": true, "svelte": true, "ember-app": true,
+}
+
+// NeedsJavaScript reports whether raw server HTML looks like a shell whose
+// content is rendered by JavaScript, so it must be captured by a browser:
+// an empty framework mount point, a <noscript> "enable JavaScript" warning on
+// a near-empty page, or scripts with almost no visible text.
+func NeedsJavaScript(htmlContent string) bool {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return true
+	}
+
+	var hasScript, emptyAppRoot, noscriptWarning bool
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script":
+				hasScript = true
+			case "noscript":
+				if strings.Contains(strings.ToLower(nodeText(n)), "javascript") {
+					noscriptWarning = true
+				}
+			}
+			if appRootIDs[attr(n, "id")] && isEmptyElement(n) {
+				emptyAppRoot = true
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	textLength := utf8.RuneCountInString(ExtractText(htmlContent))
+	switch {
+	case emptyAppRoot:
+		return true
+	case noscriptWarning && textLength < maxNoscriptWarningTextLength:
+		return true
+	default:
+		return hasScript && textLength < minStaticTextLength
+	}
+}
+
+// isEmptyElement reports whether n has no child elements and no visible text.
+func isEmptyElement(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.ElementNode:
+			return false
+		case html.TextNode:
+			if strings.TrimSpace(c.Data) != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package html
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNeedsJavaScript(t *testing.T) {
+	article := "<p>" + strings.Repeat("Static documentation paragraph with enough words. ", 10) + "</p>"
+
+	tests := []struct {
+		name string
+		page string
+		want bool
+	}{
+		{
+			name: "static article",
+			page: `<html><head><title>Docs</title></head><body><main>` + article + `</main></body></html>`,
+			want: false,
+		},
+		{
+			name: "static article with analytics script and noscript pixel",
+			page: `<html><body>` + article + `<script src="/analytics.js"></script><noscript><img src="/pixel.gif"></noscript></body></html>`,
+			want: false,
+		},
+		{
+			name: "empty react mount point",
+			page: `<html><body><div id="root"></div><script src="/static/js/main.js"></script></body></html>`,
+			want: true,
+		},
+		{
+			name: "next.js mount point with server-rendered content",
+			page: `<html><body><div id="__next"><main>` + article + `</main></div><script src="/_next/main.js"></script></body></html>`,
+			want: false,
+		},
+		{
+			name: "enable javascript warning",
+			page: `<html><body><noscript>You need to enable JavaScript to run this app.</noscript><div id="main-container"></div><script src="/bundle.js"></script></body></html>`,
+			want: true,
+		},
+		{
+			name: "scripts with almost no text",
+			page: `<html><body><div class="loading">Loading…</div><script src="/bundle.js"></script></body></html>`,
+			want: true,
+		},
+		{
+			name: "short page without scripts",
+			page: `<html><body><h1>Coming soon</h1></body></html>`,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsJavaScript(tt.page); got != tt.want {
+				t.Errorf("NeedsJavaScript() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
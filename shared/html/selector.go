@@ -0,0 +1,328 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// selector is a parsed CSS selector of the subset RemoveElements supports:
+// type, universal, #id, .class and attribute selectors ([a], [a=v], [a~=v],
+// [a^=v], [a$=v], [a*=v]) joined by descendant or child combinators.
+type selector struct {
+	parts       []compoundSelector
+	combinators []byte // combinators[i] joins parts[i] and parts[i+1]: ' ' or '>'
+}
+
+type compoundSelector struct {
+	tag     string // "" matches any element
+	id      string
+	classes []string
+	attrs   []attrSelector
+}
+
+type attrSelector struct {
+	name, op, value string // op is "" when only presence is tested
+}
+
+// RemoveElements deletes the elements matching any of the CSS selectors from
+// an HTML document, with their content, and returns the re-rendered document
+// along with the selectors it could not parse. Pseudo-classes and sibling
+// combinators are not supported. The input is returned unchanged when no
+// selector parses.
+func RemoveElements(htmlContent string, selectors []string) (string, []string) {
+	var parsed []selector
+	var unsupported []string
+	for _, raw := range selectors {
+		for _, part := range splitSelectorList(raw) {
+			sel, err := parseSelector(part)
+			if err != nil {
+				unsupported = append(unsupported, strings.TrimSpace(part))
+				continue
+			}
+			parsed = append(parsed, sel)
+		}
+	}
+	if len(parsed) == 0 {
+		return htmlContent, unsupported
+	}
+
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent, unsupported
+	}
+	var matched []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && matchesAny(parsed, c) {
+				matched = append(matched, c)
+				continue // removed with its parent
+			}
+			walk(c)
+		}
+	}
+	walk(doc)
+	if len(matched) == 0 {
+		return htmlContent, unsupported
+	}
+	for _, n := range matched {
+		n.Parent.RemoveChild(n)
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent, unsupported
+	}
+	return buf.String(), unsupported
+}
+
+func matchesAny(selectors []selector, n *html.Node) bool {
+	for _, sel := range selectors {
+		if sel.matchAt(n, len(sel.parts)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAt reports whether n matches parts[i] and its ancestors match the
+// parts before it.
+func (s selector) matchAt(n *html.Node, i int) bool {
+	if !s.parts[i].matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	if s.combinators[i-1] == '>' {
+		p := n.Parent
+		return p != nil && p.Type == html.ElementNode && s.matchAt(p, i-1)
+	}
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		if s.matchAt(p, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c compoundSelector) matches(n *html.Node) bool {
+	if c.tag != "" && c.tag != n.Data {
+		return false
+	}
+	if c.id != "" && attr(n, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(attr(n, "class"))
+		for _, want := range c.classes {
+			if !containsString(classes, want) {
+				return false
+			}
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.matches(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) matches(n *html.Node) bool {
+	for _, at := range n.Attr {
+		if at.Key != a.name {
+			continue
+		}
+		switch a.op {
+		case "":
+			return true
+		case "=":
+			return at.Val == a.value
+		case "~=":
+			return containsString(strings.Fields(at.Val), a.value)
+		case "^=":
+			return a.value != "" && strings.HasPrefix(at.Val, a.value)
+		case "$=":
+			return a.value != "" && strings.HasSuffix(at.Val, a.value)
+		case "*=":
+			return a.value != "" && strings.Contains(at.Val, a.value)
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSelectorList splits a selector group on commas outside attribute
+// brackets and quotes.
+func splitSelectorList(s string) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '[':
+			depth++
+		case ch == ']':
+			depth--
+		case ch == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func parseSelector(src string) (selector, error) {
+	var sel selector
+	s := strings.TrimSpace(src)
+	var pending byte
+	for i := 0; i < len(s); {
+		space := false
+		for i < len(s) && isSelectorSpace(s[i]) {
+			i++
+			space = true
+		}
+		if i == len(s) {
+			break
+		}
+		switch s[i] {
+		case '>':
+			if len(sel.parts) == 0 || pending == '>' {
+				return selector{}, fmt.Errorf("misplaced combinator in %q", src)
+			}
+			pending = '>'
+			i++
+			continue
+		case '+', '~':
+			return selector{}, fmt.Errorf("sibling combinators are not supported: %q", src)
+		}
+		if len(sel.parts) > 0 {
+			if pending == 0 && space {
+				pending = ' '
+			}
+			sel.combinators = append(sel.combinators, pending)
+		}
+		pending = 0
+		part, n, err := parseCompound(s[i:])
+		if err != nil {
+			return selector{}, fmt.Errorf("%v in %q", err, src)
+		}
+		sel.parts = append(sel.parts, part)
+		i += n
+	}
+	if len(sel.parts) == 0 || pending != 0 {
+		return selector{}, fmt.Errorf("incomplete selector %q", src)
+	}
+	return sel, nil
+}
+
+// parseCompound parses one compound selector at the start of s and returns
+// it with the number of bytes consumed.
+func parseCompound(s string) (compoundSelector, int, error) {
+	var c compoundSelector
+	i := 0
+	if s[0] == '*' {
+		i++
+	} else if name := readIdent(s); name != "" {
+		c.tag = strings.ToLower(name)
+		i += len(name)
+	}
+	for i < len(s) {
+		switch s[i] {
+		case '#', '.':
+			name := readIdent(s[i+1:])
+			if name == "" {
+				return c, 0, fmt.Errorf("empty name after %q", s[i])
+			}
+			if s[i] == '#' {
+				c.id = name
+			} else {
+				c.classes = append(c.classes, name)
+			}
+			i += 1 + len(name)
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return c, 0, fmt.Errorf("unclosed attribute selector")
+			}
+			a, err := parseAttr(s[i+1 : i+end])
+			if err != nil {
+				return c, 0, err
+			}
+			c.attrs = append(c.attrs, a)
+			i += end + 1
+		case ':':
+			return c, 0, fmt.Errorf("pseudo-classes are not supported")
+		default:
+			if isSelectorSpace(s[i]) || s[i] == '>' || s[i] == '+' || s[i] == '~' {
+				return c, i, nil
+			}
+			return c, 0, fmt.Errorf("unexpected %q", s[i])
+		}
+	}
+	if i == 0 {
+		return c, 0, fmt.Errorf("empty selector")
+	}
+	return c, i, nil
+}
+
+func parseAttr(s string) (attrSelector, error) {
+	var a attrSelector
+	eq := strings.IndexByte(s, '=')
+	if eq < 0 {
+		a.name = strings.ToLower(strings.TrimSpace(s))
+	} else {
+		name := s[:eq]
+		a.op = "="
+		if eq > 0 && strings.ContainsRune("~^$*", rune(s[eq-1])) {
+			a.op = s[eq-1 : eq+1]
+			name = s[:eq-1]
+		}
+		a.name = strings.ToLower(strings.TrimSpace(name))
+		a.value = strings.TrimSpace(s[eq+1:])
+		if n := len(a.value); n >= 2 && (a.value[0] == '"' || a.value[0] == '\'') && a.value[n-1] == a.value[0] {
+			a.value = a.value[1 : n-1]
+		}
+	}
+	if a.name == "" || readIdent(a.name) != a.name {
+		return a, fmt.Errorf("invalid attribute selector [%s]", s)
+	}
+	return a, nil
+}
+
+// readIdent returns the CSS identifier at the start of s.
+func readIdent(s string) string {
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		if ch == '-' || ch == '_' || ch >= 0x80 ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') {
+			i++
+			continue
+		}
+		break
+	}
+	return s[:i]
+}
+
+func isSelectorSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == '\f'
+}
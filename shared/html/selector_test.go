@@ -0,0 +1,71 @@
+package html
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemoveElements(t *testing.T) {
+	page := `<html><body>
+		<header id="top"><p>Logo</p></header>
+		<main>
+			<div class="ad banner"><p>Buy now</p></div>
+			<p data-testid="price-tag">$29</p>
+			<section><ul><li class="ts">Updated 5 minutes ago</li></ul></section>
+			<p>Keep me</p>
+		</main>
+		<footer><span class="ts">Footer time</span></footer>
+	</body></html>`
+
+	tests := []struct {
+		name        string
+		selectors   []string
+		want        []string
+		unsupported []string
+	}{
+		{
+			name:      "id and compound class",
+			selectors: []string{"#top", "div.ad.banner"},
+			want:      []string{"$29", "Updated 5 minutes ago", "Keep me", "Footer time"},
+		},
+		{
+			name:      "descendant and child combinators in one group",
+			selectors: []string{"main .ts, body > footer"},
+			want:      []string{"Logo", "Buy now", "$29", "Keep me"},
+		},
+		{
+			name:      "attribute operators",
+			selectors: []string{`[data-testid^="price"]`, "[class~=banner]"},
+			want:      []string{"Logo", "Updated 5 minutes ago", "Keep me", "Footer time"},
+		},
+		{
+			name:        "unsupported selectors are reported and skipped",
+			selectors:   []string{"li:first-child", "header + main", "footer span"},
+			want:        []string{"Logo", "Buy now", "$29", "Updated 5 minutes ago", "Keep me"},
+			unsupported: []string{"li:first-child", "header + main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, unsupported := RemoveElements(page, tt.selectors)
+			var got []string
+			for _, b := range ExtractContentBlocks(out) {
+				got = append(got, b.Text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("blocks = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(unsupported, tt.unsupported) {
+				t.Errorf("unsupported = %q, want %q", unsupported, tt.unsupported)
+			}
+		})
+	}
+}
+
+func TestRemoveElementsNoMatch(t *testing.T) {
+	page := `<p>unchanged</p>`
+	if out, _ := RemoveElements(page, []string{".missing"}); out != page {
+		t.Errorf("RemoveElements() = %q, want input unchanged", out)
+	}
+}
@@ -0,0 +1,212 @@
+package html
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// SEOMetadata holds the search-relevant metadata of a page as found in its
+// HTML. Multi-valued fields are kept in document order so that diffs are stable.
+type SEOMetadata struct {
+	Title           string            `json:"title"`
+	MetaDescription string            `json:"meta_description"`
+	Canonical       string            `json:"canonical"`
+	Robots          string            `json:"robots"`
+	Hreflang        map[string]string `json:"hreflang,omitempty"`
+	OpenGraph       map[string]string `json:"open_graph,omitempty"`
+	Twitter         map[string]string `json:"twitter,omitempty"`
+	H1              []string          `json:"h1,omitempty"`
+	StructuredData  []string          `json:"structured_data,omitempty"`
+}
+
+// SEOFieldChange describes a single SEO field whose value changed between two
+// snapshots. Field uses dotted names for keyed values (e.g. "og:title",
+// "hreflang.de-DE").
+type SEOFieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ExtractSEOMetadata parses the given HTML and returns its SEO metadata.
+// Returns nil when the document cannot be parsed.
+func ExtractSEOMetadata(htmlContent string) *SEOMetadata {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	meta := &SEOMetadata{
+		Hreflang:  map[string]string{},
+		OpenGraph: map[string]string{},
+		Twitter:   map[string]string{},
+	}
+	walkSEO(meta, doc, false)
+	return meta
+}
+
+func walkSEO(meta *SEOMetadata, n *html.Node, inHead bool) {
+	if n.Type == html.ElementNode {
+		switch n.Data {
+		case "head":
+			inHead = true
+		case "title":
+			// Only the document title counts; <title> inside inline SVG does not.
+			if inHead && meta.Title == "" {
+				meta.Title = normalizeSpace(nodeText(n))
+			}
+			return
+		case "meta":
+			collectMeta(meta, n)
+			return
+		case "link":
+			collectLink(meta, n)
+			return
+		case "h1":
+			if text := normalizeSpace(nodeText(n)); text != "" {
+				meta.H1 = append(meta.H1, text)
+			}
+			return
+		case "script":
+			if strings.EqualFold(attr(n, "type"), "application/ld+json") {
+				if ld := compactJSON(nodeText(n)); ld != "" {
+					meta.StructuredData = append(meta.StructuredData, ld)
+				}
+			}
+			return
+		case "style", "noscript":
+			return
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkSEO(meta, c, inHead)
+	}
+}
+
+func collectMeta(meta *SEOMetadata, n *html.Node) {
+	content := strings.TrimSpace(attr(n, "content"))
+	name := strings.ToLower(strings.TrimSpace(attr(n, "name")))
+	property := strings.ToLower(strings.TrimSpace(attr(n, "property")))
+
+	switch {
+	case name == "description":
+		meta.MetaDescription = content
+	case name == "robots":
+		meta.Robots = content
+	case strings.HasPrefix(property, "og:"):
+		meta.OpenGraph[property] = content
+	case strings.HasPrefix(name, "twitter:"):
+		meta.Twitter[name] = content
+	case strings.HasPrefix(property, "twitter:"):
+		// Some sites use property= instead of name= for Twitter cards.
+		meta.Twitter[property] = content
+	}
+}
+
+func collectLink(meta *SEOMetadata, n *html.Node) {
+	href := strings.TrimSpace(attr(n, "href"))
+	for _, rel := range strings.Fields(strings.ToLower(attr(n, "rel"))) {
+		switch rel {
+		case "canonical":
+			meta.Canonical = href
+		case "alternate":
+			if lang := strings.TrimSpace(attr(n, "hreflang")); lang != "" {
+				meta.Hreflang[lang] = href
+			}
+		}
+	}
+}
+
+// DiffSEOMetadata compares two SEO snapshots field by field. Keyed fields
+// (hreflang, Open Graph, Twitter) are compared per key so that added or removed
+// tags show up as individual changes. Returns nil when either side is nil.
+func DiffSEOMetadata(prev, curr *SEOMetadata) []SEOFieldChange {
+	if prev == nil || curr == nil {
+		return nil
+	}
+
+	var changes []SEOFieldChange
+	add := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, SEOFieldChange{Field: field, Before: before, After: after})
+		}
+	}
+
+	add("title", prev.Title, curr.Title)
+	add("meta_description", prev.MetaDescription, curr.MetaDescription)
+	add("canonical", prev.Canonical, curr.Canonical)
+	add("robots", prev.Robots, curr.Robots)
+	diffKeyed(prev.Hreflang, curr.Hreflang, "hreflang.", add)
+	diffKeyed(prev.OpenGraph, curr.OpenGraph, "", add)
+	diffKeyed(prev.Twitter, curr.Twitter, "", add)
+	add("h1", strings.Join(prev.H1, "\n"), strings.Join(curr.H1, "\n"))
+	add("structured_data", strings.Join(prev.StructuredData, "\n"), strings.Join(curr.StructuredData, "\n"))
+
+	return changes
+}
+
+func diffKeyed(prev, curr map[string]string, prefix string, add func(field, before, after string)) {
+	keys := make(map[string]struct{}, len(prev)+len(curr))
+	for k := range prev {
+		keys[k] = struct{}{}
+	}
+	for k := range curr {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		add(prefix+k, prev[k], curr[k])
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// compactJSON minifies a JSON-LD payload so whitespace-only edits are not
+// reported as changes. Invalid JSON is returned whitespace-normalized.
+func compactJSON(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(raw)); err != nil {
+		return normalizeSpace(raw)
+	}
+	return buf.String()
+}
@@ -0,0 +1,97 @@
+package html
+
+import "testing"
+
+const seoPageV1 = `<!DOCTYPE html>
+<html><head>
+<title> Acme  Pricing </title>
+<meta name="description" content="Plans for every team">
+<meta name="robots" content="index,follow">
+<link rel="canonical" href="https://acme.test/pricing">
+<link rel="alternate" hreflang="de" href="https://acme.test/de/pricing">
+<meta property="og:title" content="Acme Pricing">
+<meta name="twitter:card" content="summary">
+<script type="application/ld+json">{ "@type": "Product",  "name": "Acme" }</script>
+</head><body>
+<svg><title>icon</title></svg>
+<h1>Simple  pricing</h1>
+</body></html>`
+
+const seoPageV2 = `<!DOCTYPE html>
+<html><head>
+<title>Acme Pricing</title>
+<meta name="description" content="Plans for every team, now cheaper">
+<meta name="robots" content="noindex">
+<link rel="canonical" href="https://acme.test/pricing">
+<meta property="og:title" content="Acme Pricing">
+<meta name="twitter:card" content="summary">
+<script type="application/ld+json">{"@type":"Product","name":"Acme"}</script>
+</head><body>
+<h1>Simple pricing</h1>
+</body></html>`
+
+func TestExtractSEOMetadata(t *testing.T) {
+	meta := ExtractSEOMetadata(seoPageV1)
+	if meta == nil {
+		t.Fatal("expected metadata, got nil")
+	}
+	if meta.Title != "Acme Pricing" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Acme Pricing")
+	}
+	if meta.MetaDescription != "Plans for every team" {
+		t.Errorf("MetaDescription = %q", meta.MetaDescription)
+	}
+	if meta.Canonical != "https://acme.test/pricing" {
+		t.Errorf("Canonical = %q", meta.Canonical)
+	}
+	if meta.Robots != "index,follow" {
+		t.Errorf("Robots = %q", meta.Robots)
+	}
+	if meta.Hreflang["de"] != "https://acme.test/de/pricing" {
+		t.Errorf("Hreflang[de] = %q", meta.Hreflang["de"])
+	}
+	if meta.OpenGraph["og:title"] != "Acme Pricing" {
+		t.Errorf("OpenGraph[og:title] = %q", meta.OpenGraph["og:title"])
+	}
+	if meta.Twitter["twitter:card"] != "summary" {
+		t.Errorf("Twitter[twitter:card] = %q", meta.Twitter["twitter:card"])
+	}
+	if len(meta.H1) != 1 || meta.H1[0] != "Simple pricing" {
+		t.Errorf("H1 = %v", meta.H1)
+	}
+	if len(meta.StructuredData) != 1 || meta.StructuredData[0] != `{"@type":"Product","name":"Acme"}` {
+		t.Errorf("StructuredData = %v", meta.StructuredData)
+	}
+}
+
+func TestDiffSEOMetadata(t *testing.T) {
+	changes := DiffSEOMetadata(ExtractSEOMetadata(seoPageV1), ExtractSEOMetadata(seoPageV2))
+
+	want := map[string][2]string{
+		"meta_description": {"Plans for every team", "Plans for every team, now cheaper"},
+		"robots":           {"index,follow", "noindex"},
+		"hreflang.de":      {"https://acme.test/de/pricing", ""},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for _, c := range changes {
+		w, ok := want[c.Field]
+		if !ok {
+			t.Errorf("unexpected change on field %q", c.Field)
+			continue
+		}
+		if c.Before != w[0] || c.After != w[1] {
+			t.Errorf("%s = (%q -> %q), want (%q -> %q)", c.Field, c.Before, c.After, w[0], w[1])
+		}
+	}
+}
+
+func TestDiffSEOMetadataIdentical(t *testing.T) {
+	if changes := DiffSEOMetadata(ExtractSEOMetadata(seoPageV2), ExtractSEOMetadata(seoPageV2)); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+	if changes := DiffSEOMetadata(nil, ExtractSEOMetadata(seoPageV2)); changes != nil {
+		t.Errorf("expected nil for missing previous snapshot, got %+v", changes)
+	}
+}
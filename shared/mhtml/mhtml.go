@@ -0,0 +1,128 @@
+// Package mhtml reads MHTML page archives (RFC 2557), the single-file format
+// browsers save a page and its subresources in, and rewrites their documents
+// to load subresources from the archive instead of the live site.
+package mhtml
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// ErrNotArchive is returned for data that is not a multipart/related archive.
+var ErrNotArchive = errors.New("not an MHTML archive")
+
+// Part is one archived resource.
+type Part struct {
+	Location    string // absolute URL the resource was loaded from
+	ContentID   string
+	ContentType string
+	Body        []byte
+}
+
+// Archive is a parsed MHTML file. The first part is the page itself.
+type Archive struct {
+	Parts      []*Part
+	byLocation map[string]int
+	byCID      map[string]int
+}
+
+// Parse reads an MHTML file.
+func Parse(data []byte) (*Archive, error) {
+	msg, err := mail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotArchive, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.EqualFold(mediaType, "multipart/related") || params["boundary"] == "" {
+		return nil, ErrNotArchive
+	}
+
+	a := &Archive{byLocation: map[string]int{}, byCID: map[string]int{}}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive part: %w", err)
+		}
+		part, err := readPart(p)
+		if err != nil {
+			return nil, err
+		}
+		i := len(a.Parts)
+		a.Parts = append(a.Parts, part)
+		if _, seen := a.byLocation[part.Location]; part.Location != "" && !seen {
+			a.byLocation[part.Location] = i
+		}
+		if part.ContentID != "" {
+			a.byCID[part.ContentID] = i
+		}
+	}
+	if len(a.Parts) == 0 {
+		return nil, ErrNotArchive
+	}
+	return a, nil
+}
+
+func readPart(p *multipart.Part) (*Part, error) {
+	var body io.Reader = p
+	switch strings.ToLower(strings.TrimSpace(p.Header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		// The decoder skips the line breaks base64 bodies are wrapped with.
+		body = base64.NewDecoder(base64.StdEncoding, p)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(p)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("decode archive part: %w", err)
+	}
+
+	location := p.Header.Get("Content-Location")
+	if u, err := url.Parse(location); err == nil {
+		u.Fragment = ""
+		location = u.String()
+	}
+	return &Part{
+		Location:    location,
+		ContentID:   strings.Trim(p.Header.Get("Content-ID"), "<>"),
+		ContentType: p.Header.Get("Content-Type"),
+		Body:        data,
+	}, nil
+}
+
+// Resolve returns the index of the part a reference found in part base points
+// to, or false when the resource was not archived.
+func (a *Archive) Resolve(base int, ref string) (int, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "#") {
+		return 0, false
+	}
+	if cid, ok := strings.CutPrefix(ref, "cid:"); ok {
+		i, found := a.byCID[cid]
+		return i, found
+	}
+	baseURL, err := url.Parse(a.Parts[base].Location)
+	if err != nil {
+		return 0, false
+	}
+	u, err := baseURL.Parse(ref)
+	if err != nil {
+		return 0, false
+	}
+	u.Fragment = ""
+	i, found := a.byLocation[u.String()]
+	return i, found
+}
@@ -0,0 +1,103 @@
+package mhtml
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func sampleArchive() string {
+	pixel := base64.StdEncoding.EncodeToString([]byte("PNGDATA"))
+	return strings.ReplaceAll(`From: <Saved by Blink>
+Subject: Acme
+MIME-Version: 1.0
+Content-Type: multipart/related; type="text/html"; boundary="----B"
+
+------B
+Content-Type: text/html
+Content-Transfer-Encoding: quoted-printable
+Content-Location: https://acme.test/docs/index.html
+
+<html><head><link rel=3D"stylesheet" href=3D"../site.css"><style>body{backgr=
+ound:url('bg.png')}</style></head><body><img src=3D"logo.png" srcset=3D"logo.=
+png 1x, https://cdn.test/missing.png 2x"><a href=3D"logo.png">Logo</a><img sr=
+c=3D"cid:inline@acme"></body></html>
+------B
+Content-Type: text/css
+Content-Transfer-Encoding: quoted-printable
+Content-Location: https://acme.test/site.css
+
+@import "print.css"; h1{background:url(docs/bg.png#x)}
+------B
+Content-Type: image/png
+Content-Transfer-Encoding: base64
+Content-Location: https://acme.test/docs/logo.png
+
+`+pixel+`
+------B
+Content-Type: image/png
+Content-Transfer-Encoding: base64
+Content-Location: https://acme.test/docs/bg.png
+
+`+pixel+`
+------B
+Content-Type: image/png
+Content-Transfer-Encoding: base64
+Content-ID: <inline@acme>
+
+`+pixel+`
+------B--
+`, "\n", "\r\n")
+}
+
+func TestParse(t *testing.T) {
+	a, err := Parse([]byte(sampleArchive()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(a.Parts) != 5 {
+		t.Fatalf("got %d parts, want 5", len(a.Parts))
+	}
+	if got := string(a.Parts[2].Body); got != "PNGDATA" {
+		t.Errorf("base64 body = %q", got)
+	}
+	if !strings.Contains(string(a.Parts[0].Body), `background:url('bg.png')`) {
+		t.Errorf("quoted-printable body not decoded: %q", a.Parts[0].Body)
+	}
+
+	if _, err := Parse([]byte("Content-Type: text/html\r\n\r\n<html></html>")); !errors.Is(err, ErrNotArchive) {
+		t.Errorf("Parse(html) error = %v, want ErrNotArchive", err)
+	}
+}
+
+func TestArchive_Rewrite(t *testing.T) {
+	a, err := Parse([]byte(sampleArchive()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	link := func(j int) string { return fmt.Sprintf("r/%d", j) }
+
+	page := string(a.Rewrite(0, link))
+	for _, want := range []string{
+		`href="r/1"`,
+		`background:url("r/3")`,
+		`src="r/2"`,
+		`srcset="r/2 1x, https://cdn.test/missing.png 2x"`,
+		`<a href="logo.png">`,
+		`src="r/4"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("rewritten page lacks %s:\n%s", want, page)
+		}
+	}
+
+	css := string(a.Rewrite(1, link))
+	if !strings.Contains(css, `@import "print.css"`) || !strings.Contains(css, `url("r/3")`) {
+		t.Errorf("rewritten stylesheet = %q", css)
+	}
+	if got := string(a.Rewrite(2, link)); got != "PNGDATA" {
+		t.Errorf("image rewritten to %q", got)
+	}
+}
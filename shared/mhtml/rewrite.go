@@ -0,0 +1,150 @@
+package mhtml
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	cssURLPattern    = regexp.MustCompile(`url\(\s*(['"]?)([^'")]+)(['"]?)\s*\)`)
+	cssImportPattern = regexp.MustCompile(`@import\s+(['"])([^'"]+)(['"])`)
+)
+
+// subresourceAttrs are the attributes whose URLs a page loads while
+// rendering. Navigation targets such as <a href> are left alone.
+var subresourceAttrs = map[string]bool{
+	"src": true, "srcset": true, "poster": true, "background": true, "data": true,
+}
+
+// Rewrite returns part i's body with every reference to an archived resource
+// replaced by link(j), where j is the resource's part index. HTML documents
+// and stylesheets are rewritten; other parts are returned unchanged.
+func (a *Archive) Rewrite(i int, link func(j int) string) []byte {
+	part := a.Parts[i]
+	resolve := func(ref string) (string, bool) {
+		j, ok := a.Resolve(i, ref)
+		if !ok {
+			return "", false
+		}
+		return link(j), true
+	}
+	switch mediaType(part.ContentType) {
+	case "text/html", "application/xhtml+xml":
+		return rewriteHTML(part.Body, resolve)
+	case "text/css":
+		return rewriteCSS(part.Body, resolve)
+	default:
+		return part.Body
+	}
+}
+
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mt
+}
+
+// rewriteHTML rewrites subresource attributes, inline styles and <style>
+// blocks. Everything else is copied byte for byte.
+func rewriteHTML(doc []byte, resolve func(string) (string, bool)) []byte {
+	z := html.NewTokenizer(bytes.NewReader(doc))
+	var out bytes.Buffer
+	inStyle := false
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return out.Bytes()
+		}
+		raw := z.Raw()
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			inStyle = tt == html.StartTagToken && tok.DataAtom == atom.Style
+			if rewriteAttrs(&tok, resolve) {
+				out.WriteString(tok.String())
+			} else {
+				out.Write(raw)
+			}
+		case html.TextToken:
+			if inStyle {
+				out.Write(rewriteCSS(raw, resolve))
+			} else {
+				out.Write(raw)
+			}
+		case html.EndTagToken:
+			inStyle = false
+			out.Write(raw)
+		default:
+			out.Write(raw)
+		}
+	}
+}
+
+func rewriteAttrs(tok *html.Token, resolve func(string) (string, bool)) bool {
+	changed := false
+	for k, attr := range tok.Attr {
+		var val string
+		switch {
+		case attr.Key == "href" && (tok.DataAtom == atom.Link || tok.DataAtom == atom.Image):
+			val = rewriteRef(attr.Val, resolve)
+		case attr.Key == "srcset":
+			val = rewriteSrcset(attr.Val, resolve)
+		case subresourceAttrs[attr.Key]:
+			val = rewriteRef(attr.Val, resolve)
+		case attr.Key == "style":
+			val = string(rewriteCSS([]byte(attr.Val), resolve))
+		default:
+			continue
+		}
+		if val != attr.Val {
+			tok.Attr[k].Val = val
+			changed = true
+		}
+	}
+	return changed
+}
+
+func rewriteRef(ref string, resolve func(string) (string, bool)) string {
+	if link, ok := resolve(ref); ok {
+		return link
+	}
+	return ref
+}
+
+// rewriteSrcset rewrites each candidate of "url 1x, url 2x".
+func rewriteSrcset(srcset string, resolve func(string) (string, bool)) string {
+	candidates := strings.Split(srcset, ",")
+	for k, c := range candidates {
+		fields := strings.Fields(c)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = rewriteRef(fields[0], resolve)
+		candidates[k] = strings.Join(fields, " ")
+	}
+	return strings.Join(candidates, ", ")
+}
+
+func rewriteCSS(css []byte, resolve func(string) (string, bool)) []byte {
+	css = cssURLPattern.ReplaceAllFunc(css, func(m []byte) []byte {
+		sub := cssURLPattern.FindSubmatch(m)
+		if link, ok := resolve(string(sub[2])); ok {
+			return []byte(`url("` + link + `")`)
+		}
+		return m
+	})
+	return cssImportPattern.ReplaceAllFunc(css, func(m []byte) []byte {
+		sub := cssImportPattern.FindSubmatch(m)
+		if link, ok := resolve(string(sub[2])); ok {
+			return []byte(`@import "` + link + `"`)
+		}
+		return m
+	})
+}
@@ -0,0 +1,31 @@
+// Package netguard keeps requests made on behalf of tenants (link checks,
+// plain page fetches, document downloads) from reaching hosts inside our own
+// network.
+package netguard
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+var ErrPrivateAddress = errors.New("address is private or loopback")
+
+// DenyPrivateAddresses is a net.Dialer Control func refusing connections to
+// loopback, private, link-local and unspecified addresses. It runs on the
+// resolved address of every dial, so redirects and DNS answers pointing
+// inside the network are refused too.
+func DenyPrivateAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package netguard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDenyPrivateAddresses(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:80":       true,
+		"10.0.0.5:443":       true,
+		"192.168.1.1:80":     true,
+		"169.254.169.254:80": true,
+		"[::1]:80":           true,
+		"0.0.0.0:80":         true,
+		"93.184.216.34:80":   false,
+		"[2606:4700::1]:443": false,
+	}
+	for addr, wantDenied := range tests {
+		err := DenyPrivateAddresses("tcp", addr, nil)
+		if denied := errors.Is(err, ErrPrivateAddress); denied != wantDenied {
+			t.Errorf("%s: err = %v, want denied %v", addr, err, wantDenied)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	masterKeySize = 32
+	sealedPrefix  = "v1:"
+)
+
+var (
+	// ErrNoKey is returned when no master key is configured.
+	ErrNoKey = errors.New("secrets: encryption key not configured")
+	// ErrDecrypt is returned when a sealed value cannot be opened, either
+	// because it was tampered with or because it belongs to another tenant.
+	ErrDecrypt = errors.New("secrets: unable to decrypt value")
+)
+
+// Vault encrypts small secrets with AES-256-GCM. Every tenant gets its own key
+// derived from the master key with HKDF, so a value sealed for one tenant can
+// never be opened under another.
+type Vault struct {
+	master []byte
+}
+
+// NewVault parses a base64-encoded 32-byte master key. An empty key yields a
+// vault that refuses every operation with ErrNoKey, so deployments without
+// authenticated monitoring keep working.
+func NewVault(encodedKey string) (*Vault, error) {
+	if encodedKey == "" {
+		return &Vault{}, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: invalid encryption key: %w", err)
+	}
+	if len(key) != masterKeySize {
+		return nil, fmt.Errorf("secrets: encryption key must be %d bytes, got %d", masterKeySize, len(key))
+	}
+	return &Vault{master: key}, nil
+}
+
+// Enabled reports whether a master key is configured.
+func (v *Vault) Enabled() bool {
+	return v != nil && len(v.master) > 0
+}
+
+// Seal encrypts plaintext for tenant. associatedData (e.g. the owning record's
+// ID) is authenticated but not stored, so the sealed value only opens when the
+// same data is supplied again.
+func (v *Vault) Seal(tenant string, plaintext, associatedData []byte) (string, error) {
+	aead, err := v.aead(tenant)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, associatedData)
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value produced by Seal for the same tenant and associated data.
+func (v *Vault) Open(tenant, sealed string, associatedData []byte) ([]byte, error) {
+	aead, err := v.aead(tenant)
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := strings.CutPrefix(sealed, sealedPrefix)
+	if !ok {
+		return nil, ErrDecrypt
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, associatedData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func (v *Vault) aead(tenant string) (cipher.AEAD, error) {
+	if !v.Enabled() {
+		return nil, ErrNoKey
+	}
+	if tenant == "" {
+		return nil, errors.New("secrets: tenant is required")
+	}
+	key, err := hkdf.Key(sha256.New, v.master, nil, "pulzifi/tenant/"+tenant, masterKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
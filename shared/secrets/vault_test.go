@@ -0,0 +1,72 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey() string {
+	return base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	v, err := NewVault(testKey())
+	if err != nil {
+		t.Fatalf("NewVault: %v", err)
+	}
+
+	sealed, err := v.Seal("tenant_a", []byte("hunter2"), []byte("page-1"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if strings.Contains(sealed, "hunter2") {
+		t.Fatal("sealed value contains the plaintext")
+	}
+
+	got, err := v.Open("tenant_a", sealed, []byte("page-1"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if string(got) != "hunter2" {
+		t.Errorf("Open = %q, want %q", got, "hunter2")
+	}
+}
+
+func TestOpenRejectsOtherTenantOrRecord(t *testing.T) {
+	v, _ := NewVault(testKey())
+	sealed, err := v.Seal("tenant_a", []byte("hunter2"), []byte("page-1"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	if _, err := v.Open("tenant_b", sealed, []byte("page-1")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Open with other tenant: err = %v, want ErrDecrypt", err)
+	}
+	if _, err := v.Open("tenant_a", sealed, []byte("page-2")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Open with other associated data: err = %v, want ErrDecrypt", err)
+	}
+	if _, err := v.Open("tenant_a", sealed[:len(sealed)-4]+"AAAA", []byte("page-1")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Open tampered value: err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestVaultWithoutKey(t *testing.T) {
+	v, err := NewVault("")
+	if err != nil {
+		t.Fatalf("NewVault: %v", err)
+	}
+	if v.Enabled() {
+		t.Fatal("vault without key reports enabled")
+	}
+	if _, err := v.Seal("tenant_a", []byte("x"), nil); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Seal: err = %v, want ErrNoKey", err)
+	}
+}
+
+func TestNewVaultRejectsShortKey(t *testing.T) {
+	if _, err := NewVault(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Fatal("expected error for short key")
+	}
+}